@@ -8,6 +8,13 @@ import (
 	cartmodel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/infrastructure/postgresql"
 	checkoutmodel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/infrastructure/postgresql"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/config"
+	invoicingmodel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/infrastructure/postgresql"
+	kioskmodel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/kiosk/infrastructure/postgresql"
+	loyaltymodel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/loyalty/infrastructure/postgresql"
+	notificationmodel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/infrastructure/postgresql"
+	segmentmodel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/infrastructure/postgresql"
+	webhookmodel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/infrastructure/postgresql"
+	wishlistmodel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/wishlist/infrastructure/postgresql"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -38,14 +45,76 @@ func main() {
 	// Auto migrate the models
 	err = db.AutoMigrate(
 		&cartmodel.CartModel{},
+		&cartmodel.CartItemModel{},
+		&cartmodel.CartMemberModel{},
+		&cartmodel.CartInvitationModel{},
+		&cartmodel.CartActivityModel{},
+		&cartmodel.CartSnapshotModel{},
+		&cartmodel.ProductBarcodeModel{},
+		&cartmodel.PurchaseRuleModel{},
 		&checkoutmodel.ShippingAddressModel{},
 		&checkoutmodel.ShippingMethodModel{},
+		&checkoutmodel.StreetSegmentModel{},
 		&checkoutmodel.CheckoutModel{},
+		&checkoutmodel.InstallmentPlanModel{},
+		&checkoutmodel.GiftCardModel{},
+		&checkoutmodel.GiftCardTransactionModel{},
+		&loyaltymodel.LoyaltyEntryModel{},
+		&segmentmodel.RosterMemberModel{},
+		&segmentmodel.MembershipModel{},
+		&segmentmodel.PriceRuleModel{},
+		&wishlistmodel.WishlistModel{},
+		&wishlistmodel.WishlistItemModel{},
+		&kioskmodel.TerminalModel{},
+		&kioskmodel.TerminalSessionModel{},
+		&invoicingmodel.InvoiceModel{},
+		&invoicingmodel.InvoiceSequenceModel{},
+		&invoicingmodel.TaxProfileModel{},
+		&notificationmodel.NotificationModel{},
+		&notificationmodel.ContactModel{},
+		&webhookmodel.SubscriptionModel{},
+		&webhookmodel.DeliveryModel{},
 	)
 	if err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
+	// Move cart items from the legacy JSONB column to the cart_items table
+	if err := cartmodel.MigrateJSONItems(db); err != nil {
+		log.Fatalf("Failed to migrate cart items: %v", err)
+	}
+
+	// Give every user with carts an active cart
+	if err := cartmodel.MigrateActiveCarts(db); err != nil {
+		log.Fatalf("Failed to migrate active carts: %v", err)
+	}
+
+	// Record the owner as the contributor of the lines of carts created before sharing, and the
+	// contributions of the lines created before they were split among contributors
+	if err := cartmodel.MigrateItemContributors(db); err != nil {
+		log.Fatalf("Failed to migrate cart item contributors: %v", err)
+	}
+
+	// Index the street names for autocomplete
+	if err := checkoutmodel.MigrateStreetSearch(db); err != nil {
+		log.Fatalf("Failed to migrate street search index: %v", err)
+	}
+
+	// Only keep payment codes unique among the checkouts awaiting payment
+	if err := checkoutmodel.MigrateOpenPaymentCodes(db); err != nil {
+		log.Fatalf("Failed to migrate payment code index: %v", err)
+	}
+
+	// Keep shoppers out of the carts of kiosk sessions started before kiosk carts were marked
+	if err := kioskmodel.MigrateKioskCarts(db); err != nil {
+		log.Fatalf("Failed to migrate kiosk carts: %v", err)
+	}
+
+	// Remove the free-form payment details stored before payment methods were validated
+	if err := checkoutmodel.MigrateLegacyPaymentDetails(db); err != nil {
+		log.Fatalf("Failed to migrate legacy payment details: %v", err)
+	}
+
 	log.Println("Migrations completed successfully")
 	log.Println("Migration process completed successfully")
 	os.Exit(0)
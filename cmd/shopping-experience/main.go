@@ -50,6 +50,9 @@ func main() {
 	// Initialize API server
 	srv := api.NewServer(db, cfg)
 
+	// Start background jobs; they stop when the server shuts down
+	srv.StartJobs(ctx)
+
 	// Start server
 	go func() {
 		if err := srv.Start(); err != nil {
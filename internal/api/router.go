@@ -8,6 +8,13 @@ import (
 	_ "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/docs" // Import generated Swagger docs
 	cartHttp "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/infrastructure/http"
 	checkoutHttp "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/infrastructure/http"
+	invoicingHttp "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/infrastructure/http"
+	kioskHttp "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/kiosk/infrastructure/http"
+	loyaltyHttp "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/loyalty/infrastructure/http"
+	notificationHttp "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/infrastructure/http"
+	segmentHttp "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/infrastructure/http"
+	webhookHttp "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/infrastructure/http"
+	wishlistHttp "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/wishlist/infrastructure/http"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
@@ -15,8 +22,22 @@ import (
 func RegisterRoutes(
 	router *mux.Router,
 	cartHandler *cartHttp.CartHandler,
+	purchaseRuleHandler *cartHttp.PurchaseRuleHandler,
+	barcodeHandler *cartHttp.BarcodeHandler,
+	cartMemberHandler *cartHttp.CartMemberHandler,
+	cartSnapshotHandler *cartHttp.CartSnapshotHandler,
 	checkoutHandler *checkoutHttp.CheckoutHandler,
 	shippingHandler *checkoutHttp.ShippingHandler,
+	giftCardHandler *checkoutHttp.GiftCardHandler,
+	receiptHandler *checkoutHttp.ReceiptHandler,
+	installmentPlanHandler *checkoutHttp.InstallmentPlanHandler,
+	loyaltyHandler *loyaltyHttp.LoyaltyHandler,
+	segmentHandler *segmentHttp.SegmentHandler,
+	wishlistHandler *wishlistHttp.WishlistHandler,
+	kioskHandler *kioskHttp.KioskHandler,
+	invoicingHandler *invoicingHttp.InvoicingHandler,
+	notificationHandler *notificationHttp.NotificationHandler,
+	webhookHandler *webhookHttp.WebhookHandler,
 ) {
 	// Create an API subrouter
 	apiRouter := router.PathPrefix("/api").Subrouter()
@@ -49,6 +70,20 @@ func RegisterRoutes(
 
 	// Register routes for each handler
 	cartHandler.RegisterRoutes(apiRouter)
+	purchaseRuleHandler.RegisterRoutes(apiRouter)
+	barcodeHandler.RegisterRoutes(apiRouter)
+	cartMemberHandler.RegisterRoutes(apiRouter)
+	cartSnapshotHandler.RegisterRoutes(apiRouter)
 	checkoutHandler.RegisterRoutes(apiRouter)
 	shippingHandler.RegisterRoutes(apiRouter)
+	giftCardHandler.RegisterRoutes(apiRouter)
+	receiptHandler.RegisterRoutes(apiRouter)
+	installmentPlanHandler.RegisterRoutes(apiRouter)
+	loyaltyHandler.RegisterRoutes(apiRouter)
+	segmentHandler.RegisterRoutes(apiRouter)
+	wishlistHandler.RegisterRoutes(apiRouter)
+	kioskHandler.RegisterRoutes(apiRouter)
+	invoicingHandler.RegisterRoutes(apiRouter)
+	notificationHandler.RegisterRoutes(apiRouter)
+	webhookHandler.RegisterRoutes(apiRouter)
 }
@@ -9,17 +9,52 @@ import (
 
 	"github.com/gorilla/mux"
 	cartService "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services"
+	cartClients "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/infrastructure/clients"
 	cartHttp "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/infrastructure/http"
 	cartRepo "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/infrastructure/postgresql"
 	checkoutService "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/app/services"
+	checkoutGeocoding "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/infrastructure/geocoding"
 	checkoutHttp "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/infrastructure/http"
 	checkoutRepo "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/infrastructure/postgresql"
+	checkoutReceipt "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/infrastructure/receipt"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/auth"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/config"
+	invoicingService "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/app/services"
+	invoicingModel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/domain/model"
+	invoicingClients "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/infrastructure/clients"
+	invoicingHttp "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/infrastructure/http"
+	invoicingRepo "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/infrastructure/postgresql"
+	kioskService "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/kiosk/app/services"
+	kioskHttp "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/kiosk/infrastructure/http"
+	kioskRepo "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/kiosk/infrastructure/postgresql"
+	loyaltyService "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/loyalty/app/services"
+	loyaltyModel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/loyalty/domain/model"
+	loyaltyHttp "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/loyalty/infrastructure/http"
+	loyaltyRepo "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/loyalty/infrastructure/postgresql"
+	notificationService "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/app/services"
+	notificationModel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/domain/model"
+	notificationHttp "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/infrastructure/http"
+	notificationRepo "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/infrastructure/postgresql"
+	notificationSenders "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/infrastructure/senders"
+	notificationTemplates "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/infrastructure/templates"
+	segmentService "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/app/services"
+	segmentHttp "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/infrastructure/http"
+	segmentRepo "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/infrastructure/postgresql"
+	webhookService "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/app/services"
+	webhookModel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/domain/model"
+	webhookClients "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/infrastructure/clients"
+	webhookHttp "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/infrastructure/http"
+	webhookRepo "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/infrastructure/postgresql"
+	wishlistService "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/wishlist/app/services"
+	wishlistHttp "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/wishlist/infrastructure/http"
+	wishlistRepo "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/wishlist/infrastructure/postgresql"
 )
 
 // Server represents the API server
 type Server struct {
 	server *http.Server
+	// jobs run in the background while the server is up
+	jobs []func(ctx context.Context)
 }
 
 // NewServer creates a new API server with all dependencies wired up
@@ -28,21 +63,143 @@ func NewServer(db *sql.DB, cfg *config.Config) *Server {
 
 	// Initialize repositories
 	cartRepository := cartRepo.NewPostgreSQLCartRepository(db)
+	purchaseRuleRepository := cartRepo.NewPostgreSQLPurchaseRuleRepository(db)
+	purchaseHistory := cartRepo.NewPostgreSQLPurchaseHistory(db)
+	cartInvitationRepository := cartRepo.NewPostgreSQLCartInvitationRepository(db)
+	cartActivityRepository := cartRepo.NewPostgreSQLCartActivityRepository(db)
+	cartSnapshotRepository := cartRepo.NewPostgreSQLCartSnapshotRepository(db)
+	productBarcodeRepository := cartRepo.NewPostgreSQLProductBarcodeRepository(db)
 	checkoutRepository := checkoutRepo.NewPostgreSQLCheckoutRepository(db)
 	shippingRepository := checkoutRepo.NewPostgreSQLShippingRepository(db)
+	installmentPlanRepository := checkoutRepo.NewPostgreSQLInstallmentPlanRepository(db)
+	giftCardRepository := checkoutRepo.NewPostgreSQLGiftCardRepository(db)
+	streetRepository := checkoutRepo.NewPostgreSQLStreetRepository(db)
+	loyaltyRepository := loyaltyRepo.NewPostgreSQLLoyaltyRepository(db)
+	rosterRepository := segmentRepo.NewPostgreSQLRosterRepository(db)
+	membershipRepository := segmentRepo.NewPostgreSQLMembershipRepository(db)
+	priceRuleRepository := segmentRepo.NewPostgreSQLPriceRuleRepository(db)
+	wishlistRepository := wishlistRepo.NewPostgreSQLWishlistRepository(db)
+	terminalRepository := kioskRepo.NewPostgreSQLTerminalRepository(db)
+	terminalSessionRepository := kioskRepo.NewPostgreSQLTerminalSessionRepository(db)
+	invoiceRepository := invoicingRepo.NewPostgreSQLInvoiceRepository(db)
+	taxProfileRepository := invoicingRepo.NewPostgreSQLTaxProfileRepository(db)
+	notificationRepository := notificationRepo.NewPostgreSQLNotificationRepository(db)
+	contactRepository := notificationRepo.NewPostgreSQLContactRepository(db)
+	subscriptionRepository := webhookRepo.NewPostgreSQLSubscriptionRepository(db)
+	deliveryRepository := webhookRepo.NewPostgreSQLDeliveryRepository(db)
+
+	// Initialize external service clients
+	productCatalog := cartClients.NewProductCatalogClient(cfg.ProductCatalogServiceURL)
+	fiscalAuthority := invoicingClients.NewStubFiscalAuthority()
+	webhookClient := webhookClients.NewHTTPWebhookClient(cfg.WebhookRequestTimeout)
+	notificationSender := notificationSenders.NewOutboxSender(cfg.NotificationOutboxDir, cfg.NotificationFrom)
+	if cfg.NotificationSender == "smtp" {
+		notificationSender = notificationSenders.NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.NotificationFrom)
+	}
 
 	// Initialize services
-	cartSvc := cartService.NewCartService(cartRepository)
-	checkoutSvc := checkoutService.NewCheckoutService(checkoutRepository, shippingRepository)
-	shippingSvc := checkoutService.NewShippingService(shippingRepository)
+	segmentSvc := segmentService.NewSegmentService(rosterRepository, membershipRepository, priceRuleRepository)
+	notificationSvc := notificationService.NewNotificationService(
+		notificationRepository,
+		contactRepository,
+		notificationTemplates.NewTemplateRenderer(),
+		notificationSender,
+		notificationModel.RetryPolicy{
+			MaxAttempts: cfg.NotificationMaxAttempts,
+			BaseDelay:   cfg.NotificationRetryBaseDelay,
+		},
+	)
+	webhookSvc := webhookService.NewWebhookService(
+		subscriptionRepository,
+		deliveryRepository,
+		webhookClient,
+		webhookModel.RetryPolicy{
+			MaxAttempts: cfg.WebhookMaxAttempts,
+			BaseDelay:   cfg.WebhookRetryBaseDelay,
+		},
+	)
+	cartSvc := cartService.NewCartService(
+		cartRepository,
+		cartActivityRepository,
+		productBarcodeRepository,
+		purchaseRuleRepository,
+		purchaseHistory,
+		productCatalog,
+		segmentSvc,
+		notificationSvc,
+		webhookSvc,
+		cfg.CartMaxDistinctLines,
+	)
+	purchaseRuleSvc := cartService.NewPurchaseRuleService(purchaseRuleRepository)
+	barcodeSvc := cartService.NewBarcodeService(productBarcodeRepository)
+	cartMemberSvc := cartService.NewCartMemberService(cartRepository, cartInvitationRepository)
+	cartSnapshotSvc := cartService.NewCartSnapshotService(cartSnapshotRepository, cartRepository, cartSvc)
+	loyaltySvc := loyaltyService.NewLoyaltyService(loyaltyRepository, loyaltyModel.LoyaltyRules{
+		DefaultEarnRate:   cfg.LoyaltyDefaultEarnRate,
+		CategoryEarnRates: cfg.LoyaltyCategoryEarnRates,
+		PointValue:        cfg.LoyaltyPointValue,
+		PointsLifetime:    cfg.LoyaltyPointsLifetime,
+	})
+	invoicingSvc := invoicingService.NewInvoicingService(
+		invoiceRepository,
+		taxProfileRepository,
+		fiscalAuthority,
+		invoicingModel.TaxCondition(cfg.InvoicingSellerTaxCondition),
+		cfg.InvoicingPointOfSale,
+	)
+	checkoutSvc := checkoutService.NewCheckoutService(
+		checkoutRepository,
+		shippingRepository,
+		installmentPlanRepository,
+		giftCardRepository,
+		cartSvc,
+		loyaltySvc,
+		segmentSvc,
+		invoicingSvc,
+		notificationSvc,
+		webhookSvc,
+		cfg.CheckoutCashPaymentWindow,
+	)
+	shippingSvc := checkoutService.NewShippingService(
+		shippingRepository,
+		streetRepository,
+		checkoutGeocoding.NewStreetGeocoder(streetRepository),
+	)
+	giftCardSvc := checkoutService.NewGiftCardService(giftCardRepository)
+	receiptSvc := checkoutService.NewReceiptService(
+		checkoutRepository,
+		shippingRepository,
+		checkoutReceipt.NewPDFRenderer(),
+		checkoutReceipt.NewESCPOSRenderer(),
+	)
+	wishlistSvc := wishlistService.NewWishlistService(wishlistRepository, cartSvc)
+	kioskSvc := kioskService.NewKioskService(terminalRepository, terminalSessionRepository, cartSvc, cfg.KioskSessionIdleTimeout)
+
+	// Back-office routes require the admin API key
+	requireAdmin := auth.RequireAdmin(cfg.AdminAPIKey)
+	requireUser := auth.RequireUserOrAdmin(cfg.AdminAPIKey)
 
 	// Initialize handlers
 	cartHandler := cartHttp.NewCartHandler(cartSvc)
-	checkoutHandler := checkoutHttp.NewCheckoutHandler(checkoutSvc)
-	shippingHandler := checkoutHttp.NewShippingHandler(shippingSvc)
+	purchaseRuleHandler := cartHttp.NewPurchaseRuleHandler(purchaseRuleSvc, requireAdmin)
+	barcodeHandler := cartHttp.NewBarcodeHandler(barcodeSvc, requireAdmin)
+	cartMemberHandler := cartHttp.NewCartMemberHandler(cartMemberSvc)
+	cartSnapshotHandler := cartHttp.NewCartSnapshotHandler(cartSnapshotSvc)
+	checkoutHandler := checkoutHttp.NewCheckoutHandler(checkoutSvc, requireUser, requireAdmin)
+	shippingHandler := checkoutHttp.NewShippingHandler(shippingSvc, requireAdmin)
+	giftCardHandler := checkoutHttp.NewGiftCardHandler(giftCardSvc, requireAdmin)
+	receiptHandler := checkoutHttp.NewReceiptHandler(receiptSvc, requireUser)
+	installmentPlanHandler := checkoutHttp.NewInstallmentPlanHandler(checkoutSvc, requireAdmin)
+	loyaltyHandler := loyaltyHttp.NewLoyaltyHandler(loyaltySvc, requireUser)
+	segmentHandler := segmentHttp.NewSegmentHandler(segmentSvc, requireAdmin)
+	wishlistHandler := wishlistHttp.NewWishlistHandler(wishlistSvc)
+	kioskHandler := kioskHttp.NewKioskHandler(kioskSvc, requireAdmin)
+	invoicingHandler := invoicingHttp.NewInvoicingHandler(invoicingSvc, requireUser, requireAdmin)
+	notificationHandler := notificationHttp.NewNotificationHandler(notificationSvc, requireUser)
+	webhookHandler := webhookHttp.NewWebhookHandler(webhookSvc, requireAdmin)
 
 	// Register routes
-	RegisterRoutes(router, cartHandler, checkoutHandler, shippingHandler)
+	RegisterRoutes(router, cartHandler, purchaseRuleHandler, barcodeHandler, cartMemberHandler, cartSnapshotHandler, checkoutHandler, shippingHandler, giftCardHandler, receiptHandler, installmentPlanHandler, loyaltyHandler, segmentHandler, wishlistHandler, kioskHandler, invoicingHandler, notificationHandler, webhookHandler)
 
 	// Create HTTP server
 	httpServer := &http.Server{
@@ -55,6 +212,23 @@ func NewServer(db *sql.DB, cfg *config.Config) *Server {
 
 	return &Server{
 		server: httpServer,
+		jobs: []func(ctx context.Context){
+			func(ctx context.Context) { checkoutSvc.RunPaymentExpiry(ctx, cfg.CheckoutCashPaymentSweepInterval) },
+			func(ctx context.Context) { kioskSvc.RunSessionExpiry(ctx, cfg.KioskSessionSweepInterval) },
+			func(ctx context.Context) { invoicingSvc.RunAuthorization(ctx, cfg.InvoicingAuthorizationRetryInterval) },
+			func(ctx context.Context) { notificationSvc.RunDelivery(ctx, cfg.NotificationDeliveryInterval) },
+			func(ctx context.Context) { webhookSvc.RunDelivery(ctx, cfg.WebhookDeliveryInterval) },
+			func(ctx context.Context) {
+				cartSvc.RunAbandonedCartReminders(ctx, cfg.NotificationAbandonedCartSweepInterval, cfg.NotificationAbandonedCartAfter)
+			},
+		},
+	}
+}
+
+// StartJobs starts the background jobs. They stop when the context is cancelled.
+func (s *Server) StartJobs(ctx context.Context) {
+	for _, job := range s.jobs {
+		go job(ctx)
 	}
 }
 
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+)
+
+// actorKey is the context key of the user acting on carts
+type actorKey struct{}
+
+// kioskKey is the context key marking calls made by an authenticated kiosk terminal
+type kioskKey struct{}
+
+// WithActor returns a context carrying the user on whose behalf cart operations are performed
+func WithActor(ctx context.Context, userID uuid.UUID) context.Context {
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+// AsKioskTerminal returns a context whose cart operations come from a kiosk terminal the
+// caller already authenticated. Carts created with it are kiosk carts, which only terminals
+// can use: naming the session as the acting user is not enough.
+func AsKioskTerminal(ctx context.Context) context.Context {
+	return context.WithValue(ctx, kioskKey{}, true)
+}
+
+// ActorFrom returns the user acting on carts, and false if the context carries none
+func ActorFrom(ctx context.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Value(actorKey{}).(uuid.UUID)
+	return userID, ok
+}
+
+// fromKioskTerminal returns true if the context was created with AsKioskTerminal
+func fromKioskTerminal(ctx context.Context) bool {
+	kiosk, _ := ctx.Value(kioskKey{}).(bool)
+	return kiosk
+}
+
+// authorizeKiosk checks that kiosk carts are only used by kiosk terminals
+func authorizeKiosk(ctx context.Context, carts ...*model.Cart) error {
+	if fromKioskTerminal(ctx) {
+		return nil
+	}
+	for _, cart := range carts {
+		if cart.Kiosk {
+			return errors.New("cart access denied")
+		}
+	}
+	return nil
+}
+
+// authorize checks that the acting user may perform an operation on the cart and returns it.
+// The user is also recorded as the author of the changes to the cart.
+func authorize(ctx context.Context, cart *model.Cart, permission model.CartPermission) (uuid.UUID, error) {
+	if err := authorizeKiosk(ctx, cart); err != nil {
+		return uuid.Nil, err
+	}
+
+	actor, ok := ActorFrom(ctx)
+	if !ok {
+		return uuid.Nil, errors.New("user ID is required")
+	}
+	if err := cart.Authorize(actor, permission); err != nil {
+		return uuid.Nil, err
+	}
+
+	cart.SetActor(actor)
+	return actor, nil
+}
+
+// authorizeUser checks that the acting user is the given user, for the operations on all
+// the carts of a user
+func authorizeUser(ctx context.Context, userID uuid.UUID) error {
+	actor, ok := ActorFrom(ctx)
+	if !ok {
+		return errors.New("user ID is required")
+	}
+	if actor != userID {
+		return errors.New("cart access denied")
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/repository"
+)
+
+// BarcodeService handles the barcodes and SKUs the kiosk scanner resolves to products
+type BarcodeService struct {
+	barcodeRepository repository.ProductBarcodeRepository
+}
+
+// NewBarcodeService creates a new barcode service
+func NewBarcodeService(barcodeRepository repository.ProductBarcodeRepository) *BarcodeService {
+	return &BarcodeService{
+		barcodeRepository: barcodeRepository,
+	}
+}
+
+// ListBarcodes retrieves every barcode and SKU
+func (s *BarcodeService) ListBarcodes(ctx context.Context) ([]*dto.ProductBarcodeDTO, error) {
+	barcodes, err := s.barcodeRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.ProductBarcodeDTO, len(barcodes))
+	for i, barcode := range barcodes {
+		result[i] = dto.ProductBarcodeFromDomain(barcode)
+	}
+
+	return result, nil
+}
+
+// GetBarcode retrieves the product of a barcode or SKU
+func (s *BarcodeService) GetBarcode(ctx context.Context, code string) (*dto.ProductBarcodeDTO, error) {
+	normalized, _, err := model.NormalizeBarcode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	barcode, err := s.barcodeRepository.FindByCode(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ProductBarcodeFromDomain(barcode), nil
+}
+
+// SetBarcode maps a barcode or SKU to a product, replacing the previous mapping
+func (s *BarcodeService) SetBarcode(ctx context.Context, code string, req *dto.ProductBarcodeRequest) (*dto.ProductBarcodeDTO, error) {
+	productID, err := uuid.Parse(req.ProductID)
+	if err != nil {
+		return nil, errors.New("invalid product ID format")
+	}
+
+	barcode, err := model.NewProductBarcode(code, productID, req.VariantID, req.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.barcodeRepository.Save(ctx, barcode); err != nil {
+		return nil, err
+	}
+
+	return dto.ProductBarcodeFromDomain(barcode), nil
+}
+
+// DeleteBarcode removes a barcode or SKU
+func (s *BarcodeService) DeleteBarcode(ctx context.Context, code string) error {
+	normalized, _, err := model.NormalizeBarcode(code)
+	if err != nil {
+		return err
+	}
+
+	return s.barcodeRepository.Delete(ctx, normalized)
+}
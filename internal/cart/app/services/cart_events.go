@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+	webhookModel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/domain/model"
+)
+
+// changedCartResponse converts a saved cart to a response DTO and publishes it to the
+// webhook subscribers of the event type
+func (s *CartService) changedCartResponse(ctx context.Context, eventType webhookModel.EventType, cart *model.Cart) (*dto.CartResponse, error) {
+	response, err := s.cartResponse(ctx, cart)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishEvent(ctx, eventType, response)
+	return response, nil
+}
+
+// publishCart publishes a saved cart to the webhook subscribers of the event type, for the
+// operations that do not return the cart. The cart is already saved, so a failure is only logged.
+func (s *CartService) publishCart(ctx context.Context, eventType webhookModel.EventType, cart *model.Cart) {
+	response, err := s.cartResponse(ctx, cart)
+	if err != nil {
+		log.Printf("Failed to publish %s for cart %s: %v", eventType, cart.ID, err)
+		return
+	}
+
+	s.publishEvent(ctx, eventType, response)
+}
+
+// publishEvent queues a cart event for the webhook subscribers, with the cart as
+// GET /carts/{cartId} returns it. The cart is already saved, so a failure is only logged.
+func (s *CartService) publishEvent(ctx context.Context, eventType webhookModel.EventType, cart *dto.CartResponse) {
+	if err := s.webhookService.Publish(ctx, eventType, cart); err != nil {
+		log.Printf("Failed to publish %s for cart %s: %v", eventType, cart.ID, err)
+	}
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+	webhookModel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/domain/model"
+)
+
+// maxImportLines limits the number of lines imported into a cart at once
+const maxImportLines = 500
+
+// ExportCart retrieves the lines of a cart to be exported to a file
+func (s *CartService) ExportCart(ctx context.Context, cartID string) (*dto.CartExport, error) {
+	id, err := uuid.Parse(cartID)
+	if err != nil {
+		return nil, errors.New("invalid cart ID format")
+	}
+
+	cart, err := s.cartRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := authorize(ctx, cart, model.CartPermissionView); err != nil {
+		return nil, err
+	}
+
+	return dto.CartExportFromDomain(cart), nil
+}
+
+// ImportCart adds the lines of an exported cart to a cart, revalidating each one against the
+// catalog. With replace, the cart items are removed first. Rejected lines are reported and
+// do not stop the others.
+func (s *CartService) ImportCart(ctx context.Context, cartID string, export *dto.CartExport, replace bool) (*dto.CartImportResponse, error) {
+	id, err := uuid.Parse(cartID)
+	if err != nil {
+		return nil, errors.New("invalid cart ID format")
+	}
+
+	cart, err := s.cartRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := authorize(ctx, cart, model.CartPermissionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(export.Items) == 0 {
+		return nil, errors.New("the file has no lines")
+	}
+	if len(export.Items) > maxImportLines {
+		return nil, errors.New("the file has too many lines")
+	}
+
+	lines := make([]*model.ImportLine, 0, len(export.Items))
+	rejected := make([]*model.RejectedLine, 0)
+	for i, item := range export.Items {
+		productID, err := uuid.Parse(item.ProductID)
+		if err != nil {
+			rejected = append(rejected, model.NewRejectedLine(i+1, item.ProductID, item.VariantID, item.Quantity, "invalid product ID format"))
+			continue
+		}
+		lines = append(lines, &model.ImportLine{
+			Line:      i + 1,
+			ProductID: productID,
+			VariantID: item.VariantID,
+			Options:   dto.ItemOptionsToDomain(item.Options),
+			Quantity:  item.Quantity,
+			ImageURL:  item.ImageURL,
+			Category:  item.Category,
+		})
+	}
+
+	return s.importLines(ctx, cart, actor, lines, rejected, replace)
+}
+
+// importLines revalidates the lines against the catalog and the purchase rules, adds the valid
+// ones to the cart and saves it. Lines rejected beforehand are reported with the others.
+func (s *CartService) importLines(ctx context.Context, cart *model.Cart, actor uuid.UUID, lines []*model.ImportLine, rejected []*model.RejectedLine, replace bool) (*dto.CartImportResponse, error) {
+	productIDs := make([]uuid.UUID, len(lines))
+	for i, line := range lines {
+		productIDs[i] = line.ProductID
+	}
+
+	products := make(map[uuid.UUID]*model.CatalogProduct)
+	if len(productIDs) > 0 {
+		var err error
+		products, err = s.productCatalog.FindProducts(ctx, productIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rules, err := s.purchaseRules(ctx, cart.UserID, productIDs...)
+	if err != nil {
+		return nil, err
+	}
+	cart.SetPurchaseRules(rules)
+
+	imported, lineRejections := cart.ImportLines(actor, lines, products, replace)
+	rejected = append(rejected, lineRejections...)
+	sort.Slice(rejected, func(i, j int) bool { return rejected[i].Line < rejected[j].Line })
+
+	if err := s.cartRepository.Save(ctx, cart); err != nil {
+		return nil, err
+	}
+
+	response, err := s.cartResponse(ctx, cart)
+	if err != nil {
+		return nil, err
+	}
+	s.publishEvent(ctx, webhookModel.EventCartUpdated, response)
+
+	return &dto.CartImportResponse{
+		Cart:     response,
+		Imported: imported,
+		Rejected: dto.RejectedLinesFromDomain(rejected),
+	}, nil
+}
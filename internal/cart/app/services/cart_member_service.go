@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/repository"
+)
+
+// CartMemberService handles the members and invitations of shared carts
+type CartMemberService struct {
+	cartRepository       repository.CartRepository
+	invitationRepository repository.CartInvitationRepository
+}
+
+// NewCartMemberService creates a new cart member service
+func NewCartMemberService(
+	cartRepository repository.CartRepository,
+	invitationRepository repository.CartInvitationRepository,
+) *CartMemberService {
+	return &CartMemberService{
+		cartRepository:       cartRepository,
+		invitationRepository: invitationRepository,
+	}
+}
+
+// findCart loads a cart and checks that the acting user may perform the operation
+func (s *CartMemberService) findCart(ctx context.Context, cartID string, permission model.CartPermission) (*model.Cart, uuid.UUID, error) {
+	id, err := uuid.Parse(cartID)
+	if err != nil {
+		return nil, uuid.Nil, errors.New("invalid cart ID format")
+	}
+
+	cart, err := s.cartRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	actor, err := authorize(ctx, cart, permission)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	return cart, actor, nil
+}
+
+// membersResponse lists the owner followed by the members of a cart
+func membersResponse(cart *model.Cart) []dto.CartMemberDTO {
+	owner := dto.CartMemberDTO{
+		UserID:   cart.UserID.String(),
+		Role:     string(model.MemberRoleOwner),
+		JoinedAt: cart.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	return append([]dto.CartMemberDTO{owner}, dto.CartMembersFromDomain(cart.Members)...)
+}
+
+// ListMembers retrieves the owner and the members of a cart
+func (s *CartMemberService) ListMembers(ctx context.Context, cartID string) ([]dto.CartMemberDTO, error) {
+	cart, _, err := s.findCart(ctx, cartID, model.CartPermissionView)
+	if err != nil {
+		return nil, err
+	}
+
+	return membersResponse(cart), nil
+}
+
+// ChangeMemberRole changes the role of a member of a cart
+func (s *CartMemberService) ChangeMemberRole(ctx context.Context, cartID string, userID string, req *dto.CartMemberUpdateRequest) ([]dto.CartMemberDTO, error) {
+	cart, _, err := s.findCart(ctx, cartID, model.CartPermissionManage)
+	if err != nil {
+		return nil, err
+	}
+
+	memberID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+
+	if err := cart.ChangeMemberRole(memberID, model.MemberRole(req.Role)); err != nil {
+		return nil, err
+	}
+
+	if err := s.cartRepository.Save(ctx, cart); err != nil {
+		return nil, err
+	}
+
+	return membersResponse(cart), nil
+}
+
+// RemoveMember revokes the access of a member to a cart. The owner can remove any member;
+// members can remove themselves to leave the cart.
+func (s *CartMemberService) RemoveMember(ctx context.Context, cartID string, userID string) error {
+	cart, actor, err := s.findCart(ctx, cartID, model.CartPermissionView)
+	if err != nil {
+		return err
+	}
+
+	memberID, err := uuid.Parse(userID)
+	if err != nil {
+		return errors.New("invalid user ID format")
+	}
+
+	if memberID == cart.UserID {
+		return errors.New("cannot remove the cart owner")
+	}
+	if memberID != actor {
+		if err := cart.Authorize(actor, model.CartPermissionManage); err != nil {
+			return err
+		}
+	}
+
+	if err := cart.RemoveMember(memberID); err != nil {
+		return err
+	}
+
+	return s.cartRepository.Save(ctx, cart)
+}
+
+// InviteMember invites a user to join a cart with a role
+func (s *CartMemberService) InviteMember(ctx context.Context, cartID string, req *dto.CartInvitationRequest) (*dto.CartInvitationDTO, error) {
+	cart, actor, err := s.findCart(ctx, cartID, model.CartPermissionManage)
+	if err != nil {
+		return nil, err
+	}
+
+	inviteeID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+
+	invitation, err := model.NewCartInvitation(cart, inviteeID, model.MemberRole(req.Role), actor)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.invitationRepository.FindByCartID(ctx, cart.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range invitations {
+		if existing.InviteeID == inviteeID && existing.IsPending() {
+			return nil, errors.New("user already has a pending invitation")
+		}
+	}
+
+	if err := s.invitationRepository.Save(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	return dto.CartInvitationFromDomain(invitation), nil
+}
+
+// ListInvitations retrieves every invitation to a cart
+func (s *CartMemberService) ListInvitations(ctx context.Context, cartID string) ([]*dto.CartInvitationDTO, error) {
+	cart, _, err := s.findCart(ctx, cartID, model.CartPermissionManage)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.invitationRepository.FindByCartID(ctx, cart.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return invitationsResponse(invitations), nil
+}
+
+// RevokeInvitation cancels a pending invitation to a cart
+func (s *CartMemberService) RevokeInvitation(ctx context.Context, cartID string, invitationID string) (*dto.CartInvitationDTO, error) {
+	cart, _, err := s.findCart(ctx, cartID, model.CartPermissionManage)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := s.findInvitation(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.CartID != cart.ID {
+		return nil, errors.New("invitation not found")
+	}
+
+	if err := invitation.Revoke(); err != nil {
+		return nil, err
+	}
+
+	if err := s.invitationRepository.Save(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	return dto.CartInvitationFromDomain(invitation), nil
+}
+
+// ListUserInvitations retrieves the invitations a user has not answered yet
+func (s *CartMemberService) ListUserInvitations(ctx context.Context, userID string) ([]*dto.CartInvitationDTO, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+
+	if err := authorizeUser(ctx, id); err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.invitationRepository.FindPendingByInviteeID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return invitationsResponse(invitations), nil
+}
+
+// AcceptInvitation adds the acting user to the cart of an invitation addressed to them
+func (s *CartMemberService) AcceptInvitation(ctx context.Context, invitationID string) (*dto.CartInvitationDTO, error) {
+	invitation, err := s.inviteeInvitation(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := s.cartRepository.FindByID(ctx, invitation.CartID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := invitation.Accept(cart); err != nil {
+		return nil, err
+	}
+
+	if err := s.cartRepository.Save(ctx, cart); err != nil {
+		return nil, err
+	}
+
+	if err := s.invitationRepository.Save(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	return dto.CartInvitationFromDomain(invitation), nil
+}
+
+// DeclineInvitation declines an invitation addressed to the acting user
+func (s *CartMemberService) DeclineInvitation(ctx context.Context, invitationID string) (*dto.CartInvitationDTO, error) {
+	invitation, err := s.inviteeInvitation(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := invitation.Decline(); err != nil {
+		return nil, err
+	}
+
+	if err := s.invitationRepository.Save(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	return dto.CartInvitationFromDomain(invitation), nil
+}
+
+// findInvitation loads an invitation by ID
+func (s *CartMemberService) findInvitation(ctx context.Context, invitationID string) (*model.CartInvitation, error) {
+	id, err := uuid.Parse(invitationID)
+	if err != nil {
+		return nil, errors.New("invalid invitation ID format")
+	}
+
+	return s.invitationRepository.FindByID(ctx, id)
+}
+
+// inviteeInvitation loads an invitation and checks that it is addressed to the acting user.
+// Answering an invitation requires naming the user.
+func (s *CartMemberService) inviteeInvitation(ctx context.Context, invitationID string) (*model.CartInvitation, error) {
+	actor, ok := ActorFrom(ctx)
+	if !ok {
+		return nil, errors.New("user ID is required")
+	}
+
+	invitation, err := s.findInvitation(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if invitation.InviteeID != actor {
+		return nil, errors.New("cart access denied")
+	}
+
+	return invitation, nil
+}
+
+// invitationsResponse converts invitations to DTOs
+func invitationsResponse(invitations []*model.CartInvitation) []*dto.CartInvitationDTO {
+	result := make([]*dto.CartInvitationDTO, len(invitations))
+	for i, invitation := range invitations {
+		result[i] = dto.CartInvitationFromDomain(invitation)
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+	notificationModel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/domain/model"
+)
+
+// abandonedCartLookback is how far past the idle threshold carts are still reminded, so a
+// sweep that did not run for a while catches up without reminding very old carts
+const abandonedCartLookback = 24 * time.Hour
+
+// RemindAbandonedCarts reminds the owners of the carts left with items for idleFor and not
+// checked out. Each change of a cart is reminded once. It returns the number of carts reminded.
+func (s *CartService) RemindAbandonedCarts(ctx context.Context, idleFor time.Duration) (int, error) {
+	to := time.Now().Add(-idleFor)
+	carts, err := s.cartRepository.FindAbandoned(ctx, to.Add(-abandonedCartLookback), to)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, cart := range carts {
+		if err := s.applySegmentPrices(ctx, cart); err != nil {
+			return i, err
+		}
+
+		if err := s.notificationService.NotifyAbandonedCart(ctx, cartSummary(cart)); err != nil {
+			return i, err
+		}
+	}
+
+	return len(carts), nil
+}
+
+// RunAbandonedCartReminders reminds abandoned carts every interval until the context is cancelled
+func (s *CartService) RunAbandonedCartReminders(ctx context.Context, interval, idleFor time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.RemindAbandonedCarts(ctx, idleFor); err != nil {
+				log.Printf("Failed to remind abandoned carts: %v", err)
+			}
+		}
+	}
+}
+
+// cartSummary returns a cart as abandoned cart reminders show it, at the owner's prices
+func cartSummary(cart *model.Cart) *notificationModel.CartSummary {
+	summary := &notificationModel.CartSummary{
+		CartID:    cart.ID,
+		UserID:    cart.UserID,
+		CartName:  cart.Name,
+		Lines:     make([]notificationModel.SummaryLine, len(cart.Items)),
+		Subtotal:  cart.Subtotal(),
+		UpdatedAt: cart.UpdatedAt,
+	}
+	for i, item := range cart.Items {
+		summary.Lines[i] = notificationModel.SummaryLine{
+			Name:     item.Name,
+			Quantity: item.Quantity,
+			Amount:   item.Subtotal(),
+		}
+	}
+	return summary
+}
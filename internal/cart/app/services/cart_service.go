@@ -3,81 +3,677 @@ package services
 import (
 	"context"
 	"errors"
+	"log"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services/dto"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/repository"
+	notificationServices "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/app/services"
+	segmentServices "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/app/services"
+	segmentModel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/domain/model"
+	webhookServices "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/app/services"
+	webhookModel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/domain/model"
 )
 
 // CartService handles operations related to shopping carts
 type CartService struct {
-	cartRepository repository.CartRepository
+	cartRepository         repository.CartRepository
+	activityRepository     repository.CartActivityRepository
+	barcodeRepository      repository.ProductBarcodeRepository
+	purchaseRuleRepository repository.PurchaseRuleRepository
+	purchaseHistory        repository.PurchaseHistory
+	productCatalog         repository.ProductCatalog
+	segmentService         *segmentServices.SegmentService
+	notificationService    *notificationServices.NotificationService
+	webhookService         *webhookServices.WebhookService
+	maxDistinctLines       int
 }
 
 // NewCartService creates a new cart service
-func NewCartService(cartRepository repository.CartRepository) *CartService {
+func NewCartService(
+	cartRepository repository.CartRepository,
+	activityRepository repository.CartActivityRepository,
+	barcodeRepository repository.ProductBarcodeRepository,
+	purchaseRuleRepository repository.PurchaseRuleRepository,
+	purchaseHistory repository.PurchaseHistory,
+	productCatalog repository.ProductCatalog,
+	segmentService *segmentServices.SegmentService,
+	notificationService *notificationServices.NotificationService,
+	webhookService *webhookServices.WebhookService,
+	maxDistinctLines int,
+) *CartService {
 	return &CartService{
-		cartRepository: cartRepository,
+		cartRepository:         cartRepository,
+		activityRepository:     activityRepository,
+		barcodeRepository:      barcodeRepository,
+		purchaseRuleRepository: purchaseRuleRepository,
+		purchaseHistory:        purchaseHistory,
+		productCatalog:         productCatalog,
+		segmentService:         segmentService,
+		notificationService:    notificationService,
+		webhookService:         webhookService,
+		maxDistinctLines:       maxDistinctLines,
 	}
 }
 
-// CreateCart creates a new empty cart for a user
+// purchaseRules loads the rules that apply when the user adds, changes or buys lines of the products
+func (s *CartService) purchaseRules(ctx context.Context, userID uuid.UUID, productIDs ...uuid.UUID) (*model.PurchaseRules, error) {
+	rules := &model.PurchaseRules{
+		Products:         make(map[uuid.UUID]*model.ProductPurchaseRule),
+		Purchased:        make(map[uuid.UUID]int),
+		MaxDistinctLines: s.maxDistinctLines,
+	}
+
+	for _, productID := range productIDs {
+		if _, loaded := rules.Products[productID]; loaded {
+			continue
+		}
+
+		rule, err := s.purchaseRuleRepository.FindByProductID(ctx, productID)
+		if err != nil {
+			if err.Error() == "purchase rule not found" {
+				continue
+			}
+			return nil, err
+		}
+		rules.Products[productID] = rule
+
+		if rule.MaxPerUser > 0 {
+			purchased, err := s.purchaseHistory.QuantityPurchased(ctx, userID, productID, time.Now().Add(-rule.MaxPerUserWindow))
+			if err != nil {
+				return nil, err
+			}
+			rules.Purchased[productID] = purchased
+		}
+	}
+
+	return rules, nil
+}
+
+// CheckPurchase checks the product quantities of an order about to be placed by the user against
+// the purchase rules and the user's purchase history
+func (s *CartService) CheckPurchase(ctx context.Context, userID uuid.UUID, quantities map[uuid.UUID]int) error {
+	productIDs := make([]uuid.UUID, 0, len(quantities))
+	for productID := range quantities {
+		productIDs = append(productIDs, productID)
+	}
+
+	rules, err := s.purchaseRules(ctx, userID, productIDs...)
+	if err != nil {
+		return err
+	}
+	return rules.CheckPurchase(quantities)
+}
+
+// cartResponse resolves the segment prices of the cart owner and converts the cart to a response DTO
+func (s *CartService) cartResponse(ctx context.Context, cart *model.Cart) (*dto.CartResponse, error) {
+	if err := s.applySegmentPrices(ctx, cart); err != nil {
+		return nil, err
+	}
+
+	return dto.CartFromDomain(cart), nil
+}
+
+// applySegmentPrices resolves the segment prices of the cart owner for the cart and saved items
+func (s *CartService) applySegmentPrices(ctx context.Context, cart *model.Cart) error {
+	items := append(append([]*model.CartItem{}, cart.Items...), cart.SavedItems...)
+	lines := make([]segmentModel.PriceLine, len(items))
+	for i, item := range items {
+		lines[i] = segmentModel.PriceLine{
+			ProductID: item.ProductID,
+			Category:  item.Category,
+			Price:     item.Price,
+		}
+	}
+
+	prices, err := s.segmentService.PriceLines(ctx, cart.UserID, lines)
+	if err != nil {
+		return err
+	}
+
+	for i, item := range items {
+		item.SegmentPrice = nil
+		if prices[i] != nil {
+			item.SegmentPrice = &model.SegmentPrice{
+				Segment: string(prices[i].Segment),
+				Price:   prices[i].Price,
+			}
+		}
+	}
+
+	return nil
+}
+
+// CreateCart creates a new cart for a user. Without a name, the user's active cart is
+// returned if there is one. The user's first cart becomes the active cart.
 func (s *CartService) CreateCart(ctx context.Context, req *dto.CartCreateRequest) (*dto.CartResponse, error) {
 	userID, err := uuid.Parse(req.UserID)
 	if err != nil {
-		return nil, errors.New("invalid user ID format")
+		return nil, errors.New("invalid user ID format")
+	}
+
+	if err := authorizeUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	carts, err := s.cartRepository.FindAllByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeKiosk(ctx, carts...); err != nil {
+		return nil, err
+	}
+
+	if req.Name == "" {
+		for _, existing := range carts {
+			if existing.Active {
+				// Return existing cart
+				return s.cartResponse(ctx, existing)
+			}
+		}
+	}
+
+	// Create a new cart
+	cart, err := newCart(carts, userID, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	cart.Kiosk = fromKioskTerminal(ctx)
+
+	if err := s.cartRepository.Save(ctx, cart); err != nil {
+		return nil, err
+	}
+
+	return s.changedCartResponse(ctx, webhookModel.EventCartCreated, cart)
+}
+
+// newCart creates a cart for a user with a name not used by the user's other carts. The
+// user's first cart becomes the active cart.
+func newCart(carts []*model.Cart, userID uuid.UUID, name string) (*model.Cart, error) {
+	cart, err := model.NewCart(userID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	hasActive := false
+	for _, existing := range carts {
+		if strings.EqualFold(existing.Name, cart.Name) {
+			return nil, errors.New("cart name already in use")
+		}
+		hasActive = hasActive || existing.Active
+	}
+	cart.Active = !hasActive
+
+	return cart, nil
+}
+
+// ListCarts retrieves a summary of every cart of a user, followed by the carts other users shared with them
+func (s *CartService) ListCarts(ctx context.Context, userID string) ([]*dto.CartSummary, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+
+	if err := authorizeUser(ctx, id); err != nil {
+		return nil, err
+	}
+
+	carts, err := s.cartRepository.FindAllByUserID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeKiosk(ctx, carts...); err != nil {
+		return nil, err
+	}
+
+	shared, err := s.cartRepository.FindSharedWithUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	carts = append(carts, shared...)
+
+	result := make([]*dto.CartSummary, len(carts))
+	for i, cart := range carts {
+		if err := s.applySegmentPrices(ctx, cart); err != nil {
+			return nil, err
+		}
+		role, _ := cart.RoleOf(id)
+		result[i] = dto.CartSummaryFromDomain(cart, role)
+	}
+
+	return result, nil
+}
+
+// GetActiveCart retrieves the active cart of a user
+func (s *CartService) GetActiveCart(ctx context.Context, userID string) (*dto.CartResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+
+	if err := authorizeUser(ctx, id); err != nil {
+		return nil, err
+	}
+
+	cart, err := s.cartRepository.FindByUserID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetCart(ctx, cart.ID.String())
+}
+
+// RenameCart changes the name of a cart
+func (s *CartService) RenameCart(ctx context.Context, cartID string, req *dto.CartUpdateRequest) (*dto.CartResponse, error) {
+	id, err := uuid.Parse(cartID)
+	if err != nil {
+		return nil, errors.New("invalid cart ID format")
+	}
+
+	cart, err := s.cartRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = authorize(ctx, cart, model.CartPermissionManage); err != nil {
+		return nil, err
+	}
+
+	if err := cart.Rename(req.Name); err != nil {
+		return nil, err
+	}
+
+	carts, err := s.cartRepository.FindAllByUserID(ctx, cart.UserID)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range carts {
+		if existing.ID != cart.ID && strings.EqualFold(existing.Name, cart.Name) {
+			return nil, errors.New("cart name already in use")
+		}
+	}
+
+	if err := s.cartRepository.Save(ctx, cart); err != nil {
+		return nil, err
+	}
+
+	return s.changedCartResponse(ctx, webhookModel.EventCartUpdated, cart)
+}
+
+// ActivateCart makes a cart the active cart of its user
+func (s *CartService) ActivateCart(ctx context.Context, cartID string) (*dto.CartResponse, error) {
+	id, err := uuid.Parse(cartID)
+	if err != nil {
+		return nil, errors.New("invalid cart ID format")
+	}
+
+	cart, err := s.cartRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = authorize(ctx, cart, model.CartPermissionManage); err != nil {
+		return nil, err
+	}
+
+	if err := s.cartRepository.SetActive(ctx, cart.UserID, cart.ID); err != nil {
+		return nil, err
+	}
+	cart.Active = true
+
+	return s.cartResponse(ctx, cart)
+}
+
+// MoveCartItem moves a cart item to another cart of the same user
+func (s *CartService) MoveCartItem(ctx context.Context, cartID string, itemID string, req *dto.CartItemMoveRequest) (*dto.CartMoveResponse, error) {
+	cartUUID, err := uuid.Parse(cartID)
+	if err != nil {
+		return nil, errors.New("invalid cart ID format")
+	}
+
+	itemUUID, err := uuid.Parse(itemID)
+	if err != nil {
+		return nil, errors.New("invalid item ID format")
+	}
+
+	targetUUID, err := uuid.Parse(req.TargetCartID)
+	if err != nil {
+		return nil, errors.New("invalid target cart ID format")
+	}
+
+	source, err := s.cartRepository.FindByID(ctx, cartUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := authorize(ctx, source, model.CartPermissionManage); err != nil {
+		return nil, err
+	}
+
+	target, err := s.cartRepository.FindByID(ctx, targetUUID)
+	if err != nil {
+		if err.Error() == "cart not found" {
+			return nil, errors.New("target cart not found")
+		}
+		return nil, err
+	}
+
+	if _, err := authorize(ctx, target, model.CartPermissionManage); err != nil {
+		return nil, err
+	}
+
+	item, err := source.GetItem(itemUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.purchaseRules(ctx, target.UserID, item.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	target.SetPurchaseRules(rules)
+
+	if err := source.MoveItemTo(target, itemUUID, req.Quantity); err != nil {
+		return nil, err
+	}
+
+	if err := s.cartRepository.SaveAll(ctx, source, target); err != nil {
+		return nil, err
+	}
+
+	sourceResponse, err := s.cartResponse(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	targetResponse, err := s.cartResponse(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	s.publishEvent(ctx, webhookModel.EventCartUpdated, sourceResponse)
+	s.publishEvent(ctx, webhookModel.EventCartUpdated, targetResponse)
+
+	return &dto.CartMoveResponse{
+		Source: sourceResponse,
+		Target: targetResponse,
+	}, nil
+}
+
+// GetCart retrieves a cart by ID
+func (s *CartService) GetCart(ctx context.Context, cartID string) (*dto.CartResponse, error) {
+	return s.getCart(ctx, cartID, model.CartPermissionView)
+}
+
+// GetCartForCheckout retrieves a cart the acting user is about to check out
+func (s *CartService) GetCartForCheckout(ctx context.Context, cartID string) (*dto.CartResponse, error) {
+	return s.getCart(ctx, cartID, model.CartPermissionCheckout)
+}
+
+// getCart retrieves a cart by ID once the acting user was checked to have the permission
+func (s *CartService) getCart(ctx context.Context, cartID string, permission model.CartPermission) (*dto.CartResponse, error) {
+	id, err := uuid.Parse(cartID)
+	if err != nil {
+		return nil, errors.New("invalid cart ID format")
+	}
+
+	cart, err := s.cartRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = authorize(ctx, cart, permission); err != nil {
+		return nil, err
+	}
+
+	// Revalidate the cart against the catalog; an unavailable catalog must not hide the cart
+	notices := make([]*model.CartNotice, 0)
+	reduced := false
+	if !cart.IsEmpty() {
+		products, err := s.productCatalog.FindProducts(ctx, cart.ProductIDs())
+		if err != nil {
+			log.Printf("Failed to revalidate cart %s: %v", cart.ID, err)
+		} else {
+			notices, reduced = cart.Revalidate(products)
+			// Only reduced quantities change the cart; price notices are recomputed on every read
+			if reduced {
+				if err := s.cartRepository.Save(ctx, cart); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	response, err := s.cartResponse(ctx, cart)
+	if err != nil {
+		return nil, err
+	}
+	if reduced {
+		s.publishEvent(ctx, webhookModel.EventCartUpdated, response)
+	}
+	response.Notices = dto.CartNoticesFromDomain(notices)
+
+	return response, nil
+}
+
+// AcceptPrices updates the cart items to the current catalog prices
+func (s *CartService) AcceptPrices(ctx context.Context, cartID string) (*dto.CartResponse, error) {
+	id, err := uuid.Parse(cartID)
+	if err != nil {
+		return nil, errors.New("invalid cart ID format")
+	}
+
+	cart, err := s.cartRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = authorize(ctx, cart, model.CartPermissionEdit); err != nil {
+		return nil, err
+	}
+
+	if cart.IsEmpty() {
+		return s.cartResponse(ctx, cart)
+	}
+
+	products, err := s.productCatalog.FindProducts(ctx, cart.ProductIDs())
+	if err != nil {
+		return nil, err
+	}
+
+	if cart.AcceptPrices(products) > 0 {
+		if err := s.cartRepository.Save(ctx, cart); err != nil {
+			return nil, err
+		}
+		return s.changedCartResponse(ctx, webhookModel.EventCartUpdated, cart)
+	}
+
+	return s.cartResponse(ctx, cart)
+}
+
+// AddCartItem adds a product to a cart
+func (s *CartService) AddCartItem(ctx context.Context, cartID string, req *dto.CartItemRequest) (*dto.CartResponse, error) {
+	id, err := uuid.Parse(cartID)
+	if err != nil {
+		return nil, errors.New("invalid cart ID format")
+	}
+
+	cart, err := s.cartRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := authorize(ctx, cart, model.CartPermissionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	productID, err := uuid.Parse(req.ProductID)
+	if err != nil {
+		return nil, errors.New("invalid product ID format")
+	}
+
+	rules, err := s.purchaseRules(ctx, cart.UserID, productID)
+	if err != nil {
+		return nil, err
+	}
+	cart.SetPurchaseRules(rules)
+
+	if err := cart.AddItem(actor, productID, req.VariantID, dto.ItemOptionsToDomain(req.Options), req.Name, req.Price, req.Quantity, req.ImageURL, req.Category); err != nil {
+		return nil, err
+	}
+
+	if err := s.cartRepository.Save(ctx, cart); err != nil {
+		return nil, err
+	}
+
+	return s.changedCartResponse(ctx, webhookModel.EventCartUpdated, cart)
+}
+
+// ScanItem adds one unit of the product identified by a barcode or SKU read by the kiosk
+// scanner. The name and price come from the catalog.
+func (s *CartService) ScanItem(ctx context.Context, cartID string, req *dto.CartScanRequest) (*dto.CartScanResponse, error) {
+	id, err := uuid.Parse(cartID)
+	if err != nil {
+		return nil, errors.New("invalid cart ID format")
+	}
+
+	cart, err := s.cartRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := authorize(ctx, cart, model.CartPermissionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	code, format, err := model.NormalizeBarcode(req.Code)
+	if err != nil {
+		return nil, err
 	}
 
-	// Check if user already has an active cart
-	existingCart, err := s.cartRepository.FindByUserID(ctx, userID)
-	if err == nil && existingCart != nil {
-		// Return existing cart
-		return dto.CartFromDomain(existingCart), nil
+	barcode, err := s.barcodeRepository.FindByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := s.productCatalog.FindProducts(ctx, []uuid.UUID{barcode.ProductID})
+	if err != nil {
+		return nil, err
+	}
+	product, ok := products[barcode.ProductID]
+	if !ok || product.Discontinued {
+		return nil, errors.New("product is no longer available")
+	}
+	if cart.ProductQuantity(barcode.ProductID) >= product.Stock {
+		return nil, errors.New("product is out of stock")
+	}
+
+	rules, err := s.purchaseRules(ctx, cart.UserID, barcode.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	cart.SetPurchaseRules(rules)
+
+	price := math.Round(product.Price*100) / 100
+	if err := cart.AddItem(actor, barcode.ProductID, barcode.VariantID, nil, product.Name, price, 1, "", barcode.Category); err != nil {
+		return nil, err
 	}
 
-	// Create a new cart
-	cart := model.NewCart(userID)
 	if err := s.cartRepository.Save(ctx, cart); err != nil {
 		return nil, err
 	}
 
-	return dto.CartFromDomain(cart), nil
+	response, err := s.cartResponse(ctx, cart)
+	if err != nil {
+		return nil, err
+	}
+	s.publishEvent(ctx, webhookModel.EventCartUpdated, response)
+
+	result := &dto.CartScanResponse{
+		Code:      code,
+		Format:    string(format),
+		ProductID: barcode.ProductID.String(),
+		Cart:      response,
+	}
+	for _, item := range cart.Items {
+		if item.IsSameLine(barcode.ProductID, barcode.VariantID, nil) {
+			result.ItemID = item.ID.String()
+		}
+	}
+
+	return result, nil
 }
 
-// GetCart retrieves a cart by ID
-func (s *CartService) GetCart(ctx context.Context, cartID string) (*dto.CartResponse, error) {
-	id, err := uuid.Parse(cartID)
+// UpdateCartItem updates the quantity of a cart item
+func (s *CartService) UpdateCartItem(ctx context.Context, cartID string, itemID string, req *dto.CartItemUpdateRequest) (*dto.CartResponse, error) {
+	cartUUID, err := uuid.Parse(cartID)
 	if err != nil {
 		return nil, errors.New("invalid cart ID format")
 	}
 
-	cart, err := s.cartRepository.FindByID(ctx, id)
+	itemUUID, err := uuid.Parse(itemID)
+	if err != nil {
+		return nil, errors.New("invalid item ID format")
+	}
+
+	cart, err := s.cartRepository.FindByID(ctx, cartUUID)
 	if err != nil {
 		return nil, err
 	}
 
-	return dto.CartFromDomain(cart), nil
+	if _, err = authorize(ctx, cart, model.CartPermissionEdit); err != nil {
+		return nil, err
+	}
+
+	item, err := cart.GetItem(itemUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.purchaseRules(ctx, cart.UserID, item.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	cart.SetPurchaseRules(rules)
+
+	if err := cart.UpdateItemQuantity(itemUUID, req.Quantity); err != nil {
+		return nil, err
+	}
+
+	if err := s.cartRepository.Save(ctx, cart); err != nil {
+		return nil, err
+	}
+
+	return s.changedCartResponse(ctx, webhookModel.EventCartUpdated, cart)
 }
 
-// AddCartItem adds a product to a cart
-func (s *CartService) AddCartItem(ctx context.Context, cartID string, req *dto.CartItemRequest) (*dto.CartResponse, error) {
-	id, err := uuid.Parse(cartID)
+// SaveForLater moves a cart item to the saved-for-later list
+func (s *CartService) SaveForLater(ctx context.Context, cartID string, itemID string) (*dto.CartResponse, error) {
+	cartUUID, err := uuid.Parse(cartID)
 	if err != nil {
 		return nil, errors.New("invalid cart ID format")
 	}
 
-	cart, err := s.cartRepository.FindByID(ctx, id)
+	itemUUID, err := uuid.Parse(itemID)
 	if err != nil {
-		return nil, err
+		return nil, errors.New("invalid item ID format")
 	}
 
-	productID, err := uuid.Parse(req.ProductID)
+	cart, err := s.cartRepository.FindByID(ctx, cartUUID)
 	if err != nil {
-		return nil, errors.New("invalid product ID format")
+		return nil, err
+	}
+
+	if _, err = authorize(ctx, cart, model.CartPermissionEdit); err != nil {
+		return nil, err
 	}
 
-	if err := cart.AddItem(productID, req.Name, req.Price, req.Quantity, req.ImageURL); err != nil {
+	if err := cart.MoveToSaved(itemUUID); err != nil {
 		return nil, err
 	}
 
@@ -85,11 +681,11 @@ func (s *CartService) AddCartItem(ctx context.Context, cartID string, req *dto.C
 		return nil, err
 	}
 
-	return dto.CartFromDomain(cart), nil
+	return s.changedCartResponse(ctx, webhookModel.EventCartUpdated, cart)
 }
 
-// UpdateCartItem updates the quantity of a cart item
-func (s *CartService) UpdateCartItem(ctx context.Context, cartID string, itemID string, req *dto.CartItemUpdateRequest) (*dto.CartResponse, error) {
+// MoveToCart moves a saved item back to the cart
+func (s *CartService) MoveToCart(ctx context.Context, cartID string, itemID string) (*dto.CartResponse, error) {
 	cartUUID, err := uuid.Parse(cartID)
 	if err != nil {
 		return nil, errors.New("invalid cart ID format")
@@ -105,7 +701,22 @@ func (s *CartService) UpdateCartItem(ctx context.Context, cartID string, itemID
 		return nil, err
 	}
 
-	if err := cart.UpdateItemQuantity(itemUUID, req.Quantity); err != nil {
+	if _, err = authorize(ctx, cart, model.CartPermissionEdit); err != nil {
+		return nil, err
+	}
+
+	item, err := cart.GetSavedItem(itemUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.purchaseRules(ctx, cart.UserID, item.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	cart.SetPurchaseRules(rules)
+
+	if err := cart.MoveToCart(itemUUID); err != nil {
 		return nil, err
 	}
 
@@ -113,7 +724,40 @@ func (s *CartService) UpdateCartItem(ctx context.Context, cartID string, itemID
 		return nil, err
 	}
 
-	return dto.CartFromDomain(cart), nil
+	return s.changedCartResponse(ctx, webhookModel.EventCartUpdated, cart)
+}
+
+// RemoveSavedItem removes an item from the saved-for-later list
+func (s *CartService) RemoveSavedItem(ctx context.Context, cartID string, itemID string) error {
+	cartUUID, err := uuid.Parse(cartID)
+	if err != nil {
+		return errors.New("invalid cart ID format")
+	}
+
+	itemUUID, err := uuid.Parse(itemID)
+	if err != nil {
+		return errors.New("invalid item ID format")
+	}
+
+	cart, err := s.cartRepository.FindByID(ctx, cartUUID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := authorize(ctx, cart, model.CartPermissionEdit); err != nil {
+		return err
+	}
+
+	if err := cart.RemoveSavedItem(itemUUID); err != nil {
+		return err
+	}
+
+	if err := s.cartRepository.Save(ctx, cart); err != nil {
+		return err
+	}
+
+	s.publishCart(ctx, webhookModel.EventCartUpdated, cart)
+	return nil
 }
 
 // RemoveCartItem removes an item from a cart
@@ -133,19 +777,246 @@ func (s *CartService) RemoveCartItem(ctx context.Context, cartID string, itemID
 		return err
 	}
 
+	if _, err := authorize(ctx, cart, model.CartPermissionEdit); err != nil {
+		return err
+	}
+
 	if err := cart.RemoveItem(itemUUID); err != nil {
 		return err
 	}
 
-	return s.cartRepository.Save(ctx, cart)
+	if err := s.cartRepository.Save(ctx, cart); err != nil {
+		return err
+	}
+
+	s.publishCart(ctx, webhookModel.EventCartUpdated, cart)
+	return nil
+}
+
+// ClearCart removes every item from a cart. Saved items are kept.
+func (s *CartService) ClearCart(ctx context.Context, cartID string) error {
+	id, err := uuid.Parse(cartID)
+	if err != nil {
+		return errors.New("invalid cart ID format")
+	}
+
+	cart, err := s.cartRepository.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := authorize(ctx, cart, model.CartPermissionEdit); err != nil {
+		return err
+	}
+
+	if cart.IsEmpty() {
+		return nil
+	}
+
+	cart.Clear()
+
+	if err := s.cartRepository.Save(ctx, cart); err != nil {
+		return err
+	}
+
+	s.publishCart(ctx, webhookModel.EventCartUpdated, cart)
+	return nil
+}
+
+// ApplyItemOperations adds, updates and removes several cart items at once. The operations
+// are applied in order to a single load of the cart, which is saved only if all of them succeed.
+func (s *CartService) ApplyItemOperations(ctx context.Context, cartID string, req *dto.CartItemBatchRequest) (*dto.CartItemBatchResponse, error) {
+	id, err := uuid.Parse(cartID)
+	if err != nil {
+		return nil, errors.New("invalid cart ID format")
+	}
+
+	cart, err := s.cartRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := authorize(ctx, cart, model.CartPermissionEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	operations, err := itemOperationsToDomain(req.Operations)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the purchase rules of every product the batch adds or changes
+	productIDs := make([]uuid.UUID, 0, len(operations))
+	for _, operation := range operations {
+		if operation.Type == model.ItemOperationAdd {
+			productIDs = append(productIDs, operation.ProductID)
+		} else if item, err := cart.GetItem(operation.ItemID); err == nil {
+			productIDs = append(productIDs, item.ProductID)
+		}
+	}
+	rules, err := s.purchaseRules(ctx, cart.UserID, productIDs...)
+	if err != nil {
+		return nil, err
+	}
+	cart.SetPurchaseRules(rules)
+
+	results, err := cart.ApplyItemOperations(actor, operations)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cartRepository.Save(ctx, cart); err != nil {
+		return nil, err
+	}
+
+	response, err := s.cartResponse(ctx, cart)
+	if err != nil {
+		return nil, err
+	}
+	s.publishEvent(ctx, webhookModel.EventCartUpdated, response)
+
+	return &dto.CartItemBatchResponse{
+		Cart:    response,
+		Results: dto.ItemOperationResultsFromDomain(results, false),
+	}, nil
+}
+
+// itemOperationsToDomain converts the operations of a batch request. Operations with malformed
+// IDs fail the batch before it is applied, reported like the failures of the cart.
+func itemOperationsToDomain(requests []dto.CartItemOperationRequest) ([]*model.ItemOperation, error) {
+	operations := make([]*model.ItemOperation, len(requests))
+	results := make([]*model.ItemOperationResult, len(requests))
+	failed := false
+
+	for i, request := range requests {
+		operation := &model.ItemOperation{
+			Type:      model.ItemOperationType(strings.ToUpper(request.Op)),
+			VariantID: request.VariantID,
+			Options:   dto.ItemOptionsToDomain(request.Options),
+			Name:      request.Name,
+			Price:     request.Price,
+			Quantity:  request.Quantity,
+			ImageURL:  request.ImageURL,
+			Category:  request.Category,
+		}
+		results[i] = &model.ItemOperationResult{Type: operation.Type}
+
+		switch operation.Type {
+		case model.ItemOperationAdd:
+			productID, err := uuid.Parse(request.ProductID)
+			if err != nil {
+				results[i].Err = errors.New("invalid product ID format")
+			}
+			operation.ProductID = productID
+		case model.ItemOperationUpdate, model.ItemOperationRemove:
+			itemID, err := uuid.Parse(request.ItemID)
+			if err != nil {
+				results[i].Err = errors.New("invalid item ID format")
+			}
+			operation.ItemID = itemID
+			results[i].ItemID = itemID
+		}
+
+		if results[i].Err != nil {
+			failed = true
+		}
+		operations[i] = operation
+	}
+
+	if failed {
+		return nil, &model.ItemBatchError{Results: results}
+	}
+	return operations, nil
+}
+
+// GetActivity retrieves the changes made to the lines of a cart, newest first
+func (s *CartService) GetActivity(ctx context.Context, cartID string) ([]*dto.CartActivityDTO, error) {
+	id, err := uuid.Parse(cartID)
+	if err != nil {
+		return nil, errors.New("invalid cart ID format")
+	}
+
+	cart, err := s.cartRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := authorize(ctx, cart, model.CartPermissionView); err != nil {
+		return nil, err
+	}
+
+	activities, err := s.activityRepository.FindByCartID(ctx, cart.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.CartActivityFromDomain(activities), nil
+}
+
+// UndoLastChange reverts the most recent change to the lines of a cart that was not undone yet
+func (s *CartService) UndoLastChange(ctx context.Context, cartID string) (*dto.CartResponse, error) {
+	id, err := uuid.Parse(cartID)
+	if err != nil {
+		return nil, errors.New("invalid cart ID format")
+	}
+
+	cart, err := s.cartRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := authorize(ctx, cart, model.CartPermissionEdit); err != nil {
+		return nil, err
+	}
+
+	history, err := s.activityRepository.FindByCartID(ctx, cart.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The lines the undo restores are subject to the purchase rules, as when they are added
+	target, err := model.NextUndo(history)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := s.purchaseRules(ctx, cart.UserID, target.RestoredProductIDs()...)
+	if err != nil {
+		return nil, err
+	}
+	cart.SetPurchaseRules(rules)
+
+	if err := cart.Undo(history); err != nil {
+		return nil, err
+	}
+
+	if err := s.cartRepository.Save(ctx, cart); err != nil {
+		return nil, err
+	}
+
+	return s.changedCartResponse(ctx, webhookModel.EventCartUpdated, cart)
 }
 
-// DeleteCart removes a cart
+// DeleteCart removes a cart. Only its owner can delete it.
 func (s *CartService) DeleteCart(ctx context.Context, cartID string) error {
 	id, err := uuid.Parse(cartID)
 	if err != nil {
 		return errors.New("invalid cart ID format")
 	}
 
-	return s.cartRepository.Delete(ctx, id)
+	cart, err := s.cartRepository.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := authorize(ctx, cart, model.CartPermissionManage); err != nil {
+		return err
+	}
+
+	if err := s.cartRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.publishCart(ctx, webhookModel.EventCartDeleted, cart)
+	return nil
 }
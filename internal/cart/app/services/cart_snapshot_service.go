@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/repository"
+)
+
+// CartSnapshotService handles the shareable snapshots of carts
+type CartSnapshotService struct {
+	snapshotRepository repository.CartSnapshotRepository
+	cartRepository     repository.CartRepository
+	cartService        *CartService
+}
+
+// NewCartSnapshotService creates a new cart snapshot service
+func NewCartSnapshotService(
+	snapshotRepository repository.CartSnapshotRepository,
+	cartRepository repository.CartRepository,
+	cartService *CartService,
+) *CartSnapshotService {
+	return &CartSnapshotService{
+		snapshotRepository: snapshotRepository,
+		cartRepository:     cartRepository,
+		cartService:        cartService,
+	}
+}
+
+// CreateSnapshot takes a snapshot of the lines of a cart that anyone with its token can clone
+func (s *CartSnapshotService) CreateSnapshot(ctx context.Context, cartID string, req *dto.CartSnapshotRequest) (*dto.CartSnapshotDTO, error) {
+	cart, actor, err := s.findCart(ctx, cartID, model.CartPermissionView)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := model.NewCartSnapshot(cart, actor, req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.snapshotRepository.Create(ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	return dto.CartSnapshotFromDomain(snapshot), nil
+}
+
+// ListSnapshots retrieves the snapshots taken of a cart, newest first
+func (s *CartSnapshotService) ListSnapshots(ctx context.Context, cartID string) ([]*dto.CartSnapshotDTO, error) {
+	cart, _, err := s.findCart(ctx, cartID, model.CartPermissionView)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := s.snapshotRepository.FindByCartID(ctx, cart.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.CartSnapshotDTO, len(snapshots))
+	for i, snapshot := range snapshots {
+		result[i] = dto.CartSnapshotFromDomain(snapshot)
+	}
+	return result, nil
+}
+
+// GetSnapshot retrieves a snapshot by its share token
+func (s *CartSnapshotService) GetSnapshot(ctx context.Context, token string) (*dto.CartSnapshotDTO, error) {
+	snapshot, err := s.findSnapshot(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.CartSnapshotFromDomain(snapshot), nil
+}
+
+// CloneSnapshot copies the lines of a snapshot into a cart of the acting user, revalidating
+// each one against the catalog. Without a cart ID, a new cart is created for the user.
+func (s *CartSnapshotService) CloneSnapshot(ctx context.Context, token string, req *dto.CartSnapshotCloneRequest) (*dto.CartImportResponse, error) {
+	snapshot, err := s.findSnapshot(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		cart  *model.Cart
+		actor uuid.UUID
+	)
+	if req.CartID != "" {
+		cart, actor, err = s.findCart(ctx, req.CartID, model.CartPermissionEdit)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		cart, err = s.newCart(ctx, snapshot, req)
+		if err != nil {
+			return nil, err
+		}
+		actor = cart.UserID
+	}
+
+	return s.cartService.importLines(ctx, cart, actor, snapshot.ImportLines(), nil, false)
+}
+
+// newCart creates the cart a snapshot is cloned into for the acting user, named after the
+// snapshot unless the request names it
+func (s *CartSnapshotService) newCart(ctx context.Context, snapshot *model.CartSnapshot, req *dto.CartSnapshotCloneRequest) (*model.Cart, error) {
+	userID, ok := ActorFrom(ctx)
+	if !ok {
+		return nil, errors.New("user ID is required")
+	}
+
+	carts, err := s.cartRepository.FindAllByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeKiosk(ctx, carts...); err != nil {
+		return nil, err
+	}
+
+	name := req.Name
+	if name == "" {
+		name = snapshot.Name
+	}
+
+	return newCart(carts, userID, name)
+}
+
+// findCart loads a cart and checks that the acting user may perform the operation
+func (s *CartSnapshotService) findCart(ctx context.Context, cartID string, permission model.CartPermission) (*model.Cart, uuid.UUID, error) {
+	id, err := uuid.Parse(cartID)
+	if err != nil {
+		return nil, uuid.Nil, errors.New("invalid cart ID format")
+	}
+
+	cart, err := s.cartRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	actor, err := authorize(ctx, cart, permission)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	return cart, actor, nil
+}
+
+// findSnapshot loads a snapshot by its share token
+func (s *CartSnapshotService) findSnapshot(ctx context.Context, token string) (*model.CartSnapshot, error) {
+	if token == "" {
+		return nil, errors.New("snapshot not found")
+	}
+
+	return s.snapshotRepository.FindByToken(ctx, token)
+}
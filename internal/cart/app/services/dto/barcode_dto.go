@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+)
+
+// ProductBarcodeDTO represents the product a barcode or SKU identifies
+type ProductBarcodeDTO struct {
+	Code      string `json:"code"`
+	Format    string `json:"format"`
+	ProductID string `json:"productId"`
+	VariantID string `json:"variantId,omitempty"`
+	Category  string `json:"category,omitempty"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// ProductBarcodeRequest represents the request to map a barcode or SKU to a product
+type ProductBarcodeRequest struct {
+	ProductID string `json:"productId" validate:"required,uuid"`
+	VariantID string `json:"variantId,omitempty"`
+	Category  string `json:"category,omitempty"`
+}
+
+// CartScanRequest represents a code read by the kiosk scanner
+type CartScanRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// CartScanResponse represents the cart after a scanned product was added to it
+type CartScanResponse struct {
+	Code      string        `json:"code"`
+	Format    string        `json:"format"`
+	ProductID string        `json:"productId"`
+	ItemID    string        `json:"itemId"`
+	Cart      *CartResponse `json:"cart"`
+}
+
+// ProductBarcodeFromDomain converts a product barcode to a DTO
+func ProductBarcodeFromDomain(barcode *model.ProductBarcode) *ProductBarcodeDTO {
+	return &ProductBarcodeDTO{
+		Code:      barcode.Code,
+		Format:    string(barcode.Format),
+		ProductID: barcode.ProductID.String(),
+		VariantID: barcode.VariantID,
+		Category:  barcode.Category,
+		UpdatedAt: barcode.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
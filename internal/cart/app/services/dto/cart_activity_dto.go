@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+)
+
+// CartActivityDTO represents a change to the lines of a cart. ActorID is omitted for changes
+// made by the system, such as stock adjustments.
+type CartActivityDTO struct {
+	ID        string          `json:"id"`
+	CartID    string          `json:"cartId"`
+	ActorID   string          `json:"actorId,omitempty"`
+	Operation string          `json:"operation"`
+	Changes   []LineChangeDTO `json:"changes"`
+	RevertsID string          `json:"revertsId,omitempty"`
+	Undoable  bool            `json:"undoable"`
+	CreatedAt string          `json:"createdAt"`
+}
+
+// LineChangeDTO represents a line before and after a change. Before is omitted for added
+// lines and After for removed lines.
+type LineChangeDTO struct {
+	ItemID string        `json:"itemId"`
+	Before *LineStateDTO `json:"before,omitempty"`
+	After  *LineStateDTO `json:"after,omitempty"`
+}
+
+// LineStateDTO represents a snapshot of a line, in the cart or in the saved-for-later list
+type LineStateDTO struct {
+	Item     CartItemDTO `json:"item"`
+	Saved    bool        `json:"saved"`
+	Position int         `json:"position"`
+}
+
+// CartActivityFromDomain converts cart activity entries to DTOs
+func CartActivityFromDomain(activities []*model.CartActivity) []*CartActivityDTO {
+	result := make([]*CartActivityDTO, len(activities))
+	for i, activity := range activities {
+		result[i] = &CartActivityDTO{
+			ID:        activity.ID.String(),
+			CartID:    activity.CartID.String(),
+			Operation: string(activity.Operation),
+			Changes:   lineChangesFromDomain(activity.Changes),
+			Undoable:  activity.Operation.Undoable(),
+			CreatedAt: activity.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		}
+		if activity.ActorID != uuid.Nil {
+			result[i].ActorID = activity.ActorID.String()
+		}
+		if activity.RevertsID != nil {
+			result[i].RevertsID = activity.RevertsID.String()
+		}
+	}
+	return result
+}
+
+// lineChangesFromDomain converts line changes to DTOs
+func lineChangesFromDomain(changes []*model.LineChange) []LineChangeDTO {
+	result := make([]LineChangeDTO, len(changes))
+	for i, change := range changes {
+		result[i] = LineChangeDTO{
+			ItemID: change.ItemID.String(),
+			Before: lineStateFromDomain(change.Before),
+			After:  lineStateFromDomain(change.After),
+		}
+	}
+	return result
+}
+
+// lineStateFromDomain converts a line snapshot to a DTO
+func lineStateFromDomain(state *model.LineState) *LineStateDTO {
+	if state == nil {
+		return nil
+	}
+	item := state.Item
+	return &LineStateDTO{
+		Item:     cartItemsFromDomain([]*model.CartItem{&item})[0],
+		Saved:    state.Saved,
+		Position: state.Position,
+	}
+}
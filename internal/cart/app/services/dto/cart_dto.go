@@ -1,43 +1,124 @@
 package dto
 
 import (
+	"github.com/google/uuid"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
 )
 
-// CartItemDTO represents cart item data for API responses
+// CartItemDTO represents cart item data for API responses. Price is the list price of the
+// product; Surcharge is what the options add per unit. OriginalPrice is the list unit price
+// with the surcharge, and DiscountedPrice is the unit price charged, which differs when a
+// segment price applies.
 type CartItemDTO struct {
-	ID        string  `json:"id"`
-	ProductID string  `json:"productId"`
+	ID               string          `json:"id"`
+	ProductID        string          `json:"productId"`
+	VariantID        string          `json:"variantId,omitempty"`
+	Options          []ItemOptionDTO `json:"options"`
+	Name             string          `json:"name"`
+	Category         string          `json:"category,omitempty"`
+	Price            float64         `json:"price"`
+	Surcharge        float64         `json:"surcharge"`
+	OriginalPrice    float64         `json:"originalPrice"`
+	DiscountedPrice  float64         `json:"discountedPrice"`
+	Segment          string          `json:"segment,omitempty"`
+	Quantity         int             `json:"quantity"`
+	OriginalSubtotal float64         `json:"originalSubtotal"`
+	Subtotal         float64         `json:"subtotal"`
+	ImageURL         string          `json:"imageUrl"`
+	AddedBy          string          `json:"addedBy"`
+	// Contributions splits the quantity among the users who added it to a shared cart
+	Contributions []ItemContributionDTO `json:"contributions"`
+}
+
+// ItemContributionDTO represents the part of the quantity of a cart item added by one user
+type ItemContributionDTO struct {
+	UserID   string `json:"userId"`
+	Quantity int    `json:"quantity"`
+}
+
+// ItemOptionDTO represents a customization option of a cart item (size, color, engraving text)
+type ItemOptionDTO struct {
 	Name      string  `json:"name"`
-	Price     float64 `json:"price"`
-	Quantity  int     `json:"quantity"`
-	Subtotal  float64 `json:"subtotal"`
-	ImageURL  string  `json:"imageUrl"`
+	Value     string  `json:"value"`
+	Surcharge float64 `json:"surcharge"`
 }
 
-// CartResponse represents cart data for API responses
+// CartResponse represents cart data for API responses. Saved items are not part of the totals.
 type CartResponse struct {
-	ID         string        `json:"id"`
-	UserID     string        `json:"userId"`
-	Items      []CartItemDTO `json:"items"`
-	TotalItems int           `json:"totalItems"`
-	Subtotal   float64       `json:"subtotal"`
-	CreatedAt  string        `json:"createdAt"`
-	UpdatedAt  string        `json:"updatedAt"`
+	ID               string          `json:"id"`
+	UserID           string          `json:"userId"`
+	Name             string          `json:"name"`
+	Active           bool            `json:"active"`
+	Items            []CartItemDTO   `json:"items"`
+	SavedItems       []CartItemDTO   `json:"savedItems"`
+	Members          []CartMemberDTO `json:"members"`
+	TotalItems       int             `json:"totalItems"`
+	OriginalSubtotal float64         `json:"originalSubtotal"`
+	Subtotal         float64         `json:"subtotal"`
+	Notices          []CartNotice    `json:"notices"`
+	CreatedAt        string          `json:"createdAt"`
+	UpdatedAt        string          `json:"updatedAt"`
+}
+
+// CartNotice represents a change of a cart item detected when the cart was read
+type CartNotice struct {
+	Type              string  `json:"type"`
+	ItemID            string  `json:"itemId"`
+	ProductID         string  `json:"productId"`
+	Message           string  `json:"message"`
+	OldPrice          float64 `json:"oldPrice,omitempty"`
+	NewPrice          float64 `json:"newPrice,omitempty"`
+	RequestedQuantity int     `json:"requestedQuantity,omitempty"`
+	AvailableQuantity int     `json:"availableQuantity,omitempty"`
+}
+
+// CartSummary represents a cart in the list of a user's carts. Role is the role of the user
+// in the cart: OWNER for their own carts, EDITOR or VIEWER for carts shared with them.
+type CartSummary struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Active     bool    `json:"active"`
+	Role       string  `json:"role"`
+	TotalItems int     `json:"totalItems"`
+	Subtotal   float64 `json:"subtotal"`
+	UpdatedAt  string  `json:"updatedAt"`
 }
 
-// CartCreateRequest represents the request to create a new cart
+// CartCreateRequest represents the request to create a new cart. Without a name, the
+// user's active cart is returned if there is one.
 type CartCreateRequest struct {
 	UserID string `json:"userId" validate:"required,uuid"`
+	Name   string `json:"name,omitempty"`
+}
+
+// CartUpdateRequest represents the request to rename a cart
+type CartUpdateRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+// CartItemMoveRequest represents the request to move a cart item to another cart.
+// A quantity of zero moves the whole line.
+type CartItemMoveRequest struct {
+	TargetCartID string `json:"targetCartId" validate:"required,uuid"`
+	Quantity     int    `json:"quantity,omitempty" validate:"gte=0"`
+}
+
+// CartMoveResponse represents both carts after an item was moved between them
+type CartMoveResponse struct {
+	Source *CartResponse `json:"source"`
+	Target *CartResponse `json:"target"`
 }
 
 // CartItemRequest represents the request to add a product to a cart
 type CartItemRequest struct {
-	ProductID string  `json:"productId" validate:"required,uuid"`
-	Name      string  `json:"name" validate:"required"`
-	Price     float64 `json:"price" validate:"gte=0"`
-	Quantity  int     `json:"quantity" validate:"required,gt=0"`
-	ImageURL  string  `json:"imageUrl"`
+	ProductID string          `json:"productId" validate:"required,uuid"`
+	VariantID string          `json:"variantId,omitempty"`
+	Options   []ItemOptionDTO `json:"options,omitempty"`
+	Name      string          `json:"name" validate:"required"`
+	Price     float64         `json:"price" validate:"gte=0"`
+	Quantity  int             `json:"quantity" validate:"required,gt=0"`
+	ImageURL  string          `json:"imageUrl"`
+	Category  string          `json:"category,omitempty"`
 }
 
 // CartItemUpdateRequest represents the request to update a cart item
@@ -45,28 +126,182 @@ type CartItemUpdateRequest struct {
 	Quantity int `json:"quantity" validate:"required,gt=0"`
 }
 
+// CartItemOperationRequest represents an operation of a batch. ADD takes the product fields,
+// UPDATE takes itemId and quantity and REMOVE takes itemId.
+type CartItemOperationRequest struct {
+	Op        string          `json:"op" validate:"required,oneof=ADD UPDATE REMOVE"`
+	ItemID    string          `json:"itemId,omitempty"`
+	ProductID string          `json:"productId,omitempty"`
+	VariantID string          `json:"variantId,omitempty"`
+	Options   []ItemOptionDTO `json:"options,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Price     float64         `json:"price,omitempty"`
+	Quantity  int             `json:"quantity,omitempty"`
+	ImageURL  string          `json:"imageUrl,omitempty"`
+	Category  string          `json:"category,omitempty"`
+}
+
+// CartItemBatchRequest represents the request to apply several item operations at once
+type CartItemBatchRequest struct {
+	Operations []CartItemOperationRequest `json:"operations" validate:"required,min=1,max=100,dive"`
+}
+
+// CartItemOperationResult represents the outcome of an operation of a batch. Status is
+// APPLIED, FAILED, or NOT_APPLIED for operations rolled back because another one failed.
+type CartItemOperationResult struct {
+	Index      int                `json:"index"`
+	Op         string             `json:"op"`
+	ItemID     string             `json:"itemId,omitempty"`
+	Status     string             `json:"status"`
+	Message    string             `json:"message,omitempty"`
+	Violations []RuleViolationDTO `json:"violations,omitempty"`
+}
+
+// CartItemBatchResponse represents the cart after a batch of item operations was applied
+type CartItemBatchResponse struct {
+	Cart    *CartResponse             `json:"cart"`
+	Results []CartItemOperationResult `json:"results"`
+}
+
 // CartFromDomain converts a cart domain model to a response DTO
 func CartFromDomain(cart *model.Cart) *CartResponse {
-	items := make([]CartItemDTO, len(cart.Items))
-	for i, item := range cart.Items {
-		items[i] = CartItemDTO{
-			ID:        item.ID.String(),
-			ProductID: item.ProductID.String(),
-			Name:      item.Name,
-			Price:     item.Price,
-			Quantity:  item.Quantity,
-			Subtotal:  item.Subtotal(),
-			ImageURL:  item.ImageURL,
-		}
+	return &CartResponse{
+		ID:               cart.ID.String(),
+		UserID:           cart.UserID.String(),
+		Name:             cart.Name,
+		Active:           cart.Active,
+		Items:            cartItemsFromDomain(cart.Items),
+		SavedItems:       cartItemsFromDomain(cart.SavedItems),
+		Members:          CartMembersFromDomain(cart.Members),
+		TotalItems:       cart.TotalItems(),
+		OriginalSubtotal: cart.OriginalSubtotal(),
+		Subtotal:         cart.Subtotal(),
+		Notices:          make([]CartNotice, 0),
+		CreatedAt:        cart.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:        cart.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 	}
+}
 
-	return &CartResponse{
+// CartSummaryFromDomain converts a cart domain model to a summary DTO for a user with the given role
+func CartSummaryFromDomain(cart *model.Cart, role model.MemberRole) *CartSummary {
+	return &CartSummary{
 		ID:         cart.ID.String(),
-		UserID:     cart.UserID.String(),
-		Items:      items,
+		Name:       cart.Name,
+		Active:     cart.Active,
+		Role:       string(role),
 		TotalItems: cart.TotalItems(),
 		Subtotal:   cart.Subtotal(),
-		CreatedAt:  cart.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt:  cart.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 	}
 }
+
+// cartItemsFromDomain converts cart items to DTOs
+func cartItemsFromDomain(cartItems []*model.CartItem) []CartItemDTO {
+	items := make([]CartItemDTO, len(cartItems))
+	for i, item := range cartItems {
+		items[i] = CartItemDTO{
+			ID:               item.ID.String(),
+			ProductID:        item.ProductID.String(),
+			VariantID:        item.VariantID,
+			Options:          ItemOptionsFromDomain(item.Options),
+			Name:             item.Name,
+			Category:         item.Category,
+			Price:            item.Price,
+			Surcharge:        item.Surcharge(),
+			OriginalPrice:    item.Price + item.Surcharge(),
+			DiscountedPrice:  item.UnitPrice(),
+			Quantity:         item.Quantity,
+			OriginalSubtotal: item.OriginalSubtotal(),
+			Subtotal:         item.Subtotal(),
+			ImageURL:         item.ImageURL,
+			AddedBy:          item.AddedBy.String(),
+			Contributions:    itemContributionsFromDomain(item.Contributors()),
+		}
+		if item.SegmentPrice != nil {
+			items[i].Segment = item.SegmentPrice.Segment
+		}
+	}
+	return items
+}
+
+// itemContributionsFromDomain converts the contributions to a cart item to DTOs
+func itemContributionsFromDomain(contributions []model.ItemContribution) []ItemContributionDTO {
+	result := make([]ItemContributionDTO, len(contributions))
+	for i, contribution := range contributions {
+		result[i] = ItemContributionDTO{
+			UserID:   contribution.UserID.String(),
+			Quantity: contribution.Quantity,
+		}
+	}
+	return result
+}
+
+// CartNoticesFromDomain converts cart notices to DTOs
+func CartNoticesFromDomain(notices []*model.CartNotice) []CartNotice {
+	result := make([]CartNotice, len(notices))
+	for i, notice := range notices {
+		result[i] = CartNotice{
+			Type:              string(notice.Type),
+			ItemID:            notice.ItemID.String(),
+			ProductID:         notice.ProductID.String(),
+			Message:           notice.Message,
+			OldPrice:          notice.OldPrice,
+			NewPrice:          notice.NewPrice,
+			RequestedQuantity: notice.RequestedQuantity,
+			AvailableQuantity: notice.AvailableQuantity,
+		}
+	}
+	return result
+}
+
+// ItemOptionsFromDomain converts cart item options to DTOs
+func ItemOptionsFromDomain(options []model.ItemOption) []ItemOptionDTO {
+	result := make([]ItemOptionDTO, len(options))
+	for i, option := range options {
+		result[i] = ItemOptionDTO{
+			Name:      option.Name,
+			Value:     option.Value,
+			Surcharge: option.Surcharge,
+		}
+	}
+	return result
+}
+
+// ItemOptionsToDomain converts option DTOs to cart item options
+func ItemOptionsToDomain(options []ItemOptionDTO) []model.ItemOption {
+	result := make([]model.ItemOption, len(options))
+	for i, option := range options {
+		result[i] = model.ItemOption{
+			Name:      option.Name,
+			Value:     option.Value,
+			Surcharge: option.Surcharge,
+		}
+	}
+	return result
+}
+
+// ItemOperationResultsFromDomain converts the outcome of a batch of item operations to DTOs.
+// If the batch failed, the operations that succeeded were not applied.
+func ItemOperationResultsFromDomain(results []*model.ItemOperationResult, failed bool) []CartItemOperationResult {
+	result := make([]CartItemOperationResult, len(results))
+	for i, operation := range results {
+		result[i] = CartItemOperationResult{
+			Index:  i,
+			Op:     string(operation.Type),
+			Status: "APPLIED",
+		}
+		if operation.ItemID != uuid.Nil {
+			result[i].ItemID = operation.ItemID.String()
+		}
+		if operation.Err != nil {
+			result[i].Status = "FAILED"
+			result[i].Message = operation.Err.Error()
+			if violations, ok := operation.Err.(*model.RuleViolationError); ok {
+				result[i].Violations = RuleViolationsFromDomain(violations)
+			}
+		} else if failed {
+			result[i].Status = "NOT_APPLIED"
+		}
+	}
+	return result
+}
@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// csvExportHeader is the header row of a CSV cart export. Options are only exported to JSON.
+var csvExportHeader = []string{"product_id", "variant_id", "quantity", "name", "category"}
+
+// WriteCSV writes the lines of the export as CSV
+func (e *CartExport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write(csvExportHeader)
+	for _, line := range e.Items {
+		writer.Write([]string{line.ProductID, line.VariantID, strconv.Itoa(line.Quantity), line.Name, line.Category})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// CartExportFromCSV reads the lines of a CSV cart export. Columns are matched by the header
+// names, so they can come in any order; product_id and quantity are required. A quantity
+// that is not a number is read as zero, so the line is rejected by the import.
+func CartExportFromCSV(r io.Reader) (*CartExport, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("invalid CSV file")
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["product_id"]; !ok {
+		return nil, errors.New("CSV header must have a product_id column")
+	}
+	if _, ok := columns["quantity"]; !ok {
+		return nil, errors.New("CSV header must have a quantity column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	export := &CartExport{Items: make([]CartExportLine, 0)}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.New("invalid CSV file")
+		}
+
+		quantity, _ := strconv.Atoi(field(record, "quantity"))
+		export.Items = append(export.Items, CartExportLine{
+			ProductID: field(record, "product_id"),
+			VariantID: field(record, "variant_id"),
+			Name:      field(record, "name"),
+			Quantity:  quantity,
+			Category:  field(record, "category"),
+		})
+	}
+
+	return export, nil
+}
@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+)
+
+// CartMemberDTO represents a user with access to a shared cart
+type CartMemberDTO struct {
+	UserID   string `json:"userId"`
+	Role     string `json:"role"`
+	JoinedAt string `json:"joinedAt"`
+}
+
+// CartInvitationDTO represents an invitation to join a shared cart
+type CartInvitationDTO struct {
+	ID          string `json:"id"`
+	CartID      string `json:"cartId"`
+	InviteeID   string `json:"inviteeId"`
+	InvitedBy   string `json:"invitedBy"`
+	Role        string `json:"role"`
+	Status      string `json:"status"`
+	CreatedAt   string `json:"createdAt"`
+	ExpiresAt   string `json:"expiresAt"`
+	RespondedAt string `json:"respondedAt,omitempty"`
+}
+
+// CartInvitationRequest represents the request to invite a user to a cart
+type CartInvitationRequest struct {
+	UserID string `json:"userId" validate:"required,uuid"`
+	Role   string `json:"role" validate:"required,oneof=EDITOR VIEWER"`
+}
+
+// CartMemberUpdateRequest represents the request to change the role of a member
+type CartMemberUpdateRequest struct {
+	Role string `json:"role" validate:"required,oneof=EDITOR VIEWER"`
+}
+
+// CartMembersFromDomain converts cart members to DTOs
+func CartMembersFromDomain(members []*model.CartMember) []CartMemberDTO {
+	result := make([]CartMemberDTO, len(members))
+	for i, member := range members {
+		result[i] = CartMemberDTO{
+			UserID:   member.UserID.String(),
+			Role:     string(member.Role),
+			JoinedAt: member.JoinedAt.Format("2006-01-02T15:04:05Z"),
+		}
+	}
+	return result
+}
+
+// CartInvitationFromDomain converts a cart invitation to a DTO
+func CartInvitationFromDomain(invitation *model.CartInvitation) *CartInvitationDTO {
+	result := &CartInvitationDTO{
+		ID:        invitation.ID.String(),
+		CartID:    invitation.CartID.String(),
+		InviteeID: invitation.InviteeID.String(),
+		InvitedBy: invitation.InvitedBy.String(),
+		Role:      string(invitation.Role),
+		Status:    string(invitation.Status),
+		CreatedAt: invitation.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		ExpiresAt: invitation.ExpiresAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if invitation.RespondedAt != nil {
+		result.RespondedAt = invitation.RespondedAt.Format("2006-01-02T15:04:05Z")
+	}
+	return result
+}
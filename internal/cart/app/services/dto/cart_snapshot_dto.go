@@ -0,0 +1,144 @@
+package dto
+
+import (
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+)
+
+// CartExport represents the lines of a cart as exported to and imported from a file
+type CartExport struct {
+	Name  string           `json:"name,omitempty"`
+	Items []CartExportLine `json:"items"`
+}
+
+// CartExportLine represents a line of an exported cart. Name is informative; imported lines
+// take the name and price from the catalog.
+type CartExportLine struct {
+	ProductID string          `json:"productId"`
+	VariantID string          `json:"variantId,omitempty"`
+	Options   []ItemOptionDTO `json:"options,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Quantity  int             `json:"quantity"`
+	ImageURL  string          `json:"imageUrl,omitempty"`
+	Category  string          `json:"category,omitempty"`
+}
+
+// CartImportResponse represents the cart after a file or a snapshot was imported into it
+type CartImportResponse struct {
+	Cart     *CartResponse     `json:"cart"`
+	Imported int               `json:"imported"`
+	Rejected []RejectedLineDTO `json:"rejected"`
+}
+
+// RejectedLineDTO represents a line that could not be imported. Line is its position in the
+// file, starting at 1.
+type RejectedLineDTO struct {
+	Line      int    `json:"line"`
+	ProductID string `json:"productId"`
+	VariantID string `json:"variantId,omitempty"`
+	Quantity  int    `json:"quantity"`
+	Reason    string `json:"reason"`
+}
+
+// CartSnapshotDTO represents an immutable copy of the lines of a cart
+type CartSnapshotDTO struct {
+	ID         string            `json:"id"`
+	CartID     string            `json:"cartId"`
+	CreatedBy  string            `json:"createdBy"`
+	Name       string            `json:"name"`
+	Token      string            `json:"token"`
+	Items      []SnapshotItemDTO `json:"items"`
+	TotalItems int               `json:"totalItems"`
+	CreatedAt  string            `json:"createdAt"`
+}
+
+// SnapshotItemDTO represents a line of a cart snapshot, with the price it had when it was taken
+type SnapshotItemDTO struct {
+	ProductID string          `json:"productId"`
+	VariantID string          `json:"variantId,omitempty"`
+	Options   []ItemOptionDTO `json:"options"`
+	Name      string          `json:"name"`
+	Price     float64         `json:"price"`
+	Quantity  int             `json:"quantity"`
+	ImageURL  string          `json:"imageUrl"`
+	Category  string          `json:"category,omitempty"`
+}
+
+// CartSnapshotRequest represents the request to take a snapshot of a cart
+type CartSnapshotRequest struct {
+	Name string `json:"name,omitempty"`
+}
+
+// CartSnapshotCloneRequest represents the request to clone a snapshot. With a cartId, the lines
+// are added to that cart; otherwise a new cart is created for the acting user, named after
+// the snapshot unless a name is given.
+type CartSnapshotCloneRequest struct {
+	CartID string `json:"cartId,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+// CartExportFromDomain converts the lines of a cart to an export. Saved items are left out.
+func CartExportFromDomain(cart *model.Cart) *CartExport {
+	lines := make([]CartExportLine, len(cart.Items))
+	for i, item := range cart.Items {
+		lines[i] = CartExportLine{
+			ProductID: item.ProductID.String(),
+			VariantID: item.VariantID,
+			Name:      item.Name,
+			Quantity:  item.Quantity,
+			ImageURL:  item.ImageURL,
+			Category:  item.Category,
+		}
+		if len(item.Options) > 0 {
+			lines[i].Options = ItemOptionsFromDomain(item.Options)
+		}
+	}
+	return &CartExport{
+		Name:  cart.Name,
+		Items: lines,
+	}
+}
+
+// RejectedLinesFromDomain converts rejected import lines to DTOs
+func RejectedLinesFromDomain(rejected []*model.RejectedLine) []RejectedLineDTO {
+	result := make([]RejectedLineDTO, len(rejected))
+	for i, line := range rejected {
+		result[i] = RejectedLineDTO{
+			Line:      line.Line,
+			ProductID: line.ProductID,
+			VariantID: line.VariantID,
+			Quantity:  line.Quantity,
+			Reason:    line.Reason,
+		}
+	}
+	return result
+}
+
+// CartSnapshotFromDomain converts a cart snapshot to a DTO
+func CartSnapshotFromDomain(snapshot *model.CartSnapshot) *CartSnapshotDTO {
+	items := make([]SnapshotItemDTO, len(snapshot.Items))
+	totalItems := 0
+	for i, item := range snapshot.Items {
+		items[i] = SnapshotItemDTO{
+			ProductID: item.ProductID.String(),
+			VariantID: item.VariantID,
+			Options:   ItemOptionsFromDomain(item.Options),
+			Name:      item.Name,
+			Price:     item.Price,
+			Quantity:  item.Quantity,
+			ImageURL:  item.ImageURL,
+			Category:  item.Category,
+		}
+		totalItems += item.Quantity
+	}
+
+	return &CartSnapshotDTO{
+		ID:         snapshot.ID.String(),
+		CartID:     snapshot.CartID.String(),
+		CreatedBy:  snapshot.CreatedBy.String(),
+		Name:       snapshot.Name,
+		Token:      snapshot.Token,
+		Items:      items,
+		TotalItems: totalItems,
+		CreatedAt:  snapshot.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
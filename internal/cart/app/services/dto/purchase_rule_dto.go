@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+)
+
+// PurchaseRuleDTO represents a product purchase rule for API responses
+type PurchaseRuleDTO struct {
+	ProductID            string `json:"productId"`
+	MaxPerOrder          int    `json:"maxPerOrder"`
+	MaxPerUser           int    `json:"maxPerUser"`
+	MaxPerUserWindowDays int    `json:"maxPerUserWindowDays"`
+	MinQuantity          int    `json:"minQuantity"`
+	PackSize             int    `json:"packSize"`
+	UpdatedAt            string `json:"updatedAt"`
+}
+
+// PurchaseRuleRequest represents the request to set the purchase rule of a product. Zero disables a limit.
+type PurchaseRuleRequest struct {
+	MaxPerOrder          int `json:"maxPerOrder" validate:"gte=0"`
+	MaxPerUser           int `json:"maxPerUser" validate:"gte=0"`
+	MaxPerUserWindowDays int `json:"maxPerUserWindowDays" validate:"gte=0"`
+	MinQuantity          int `json:"minQuantity" validate:"gte=0"`
+	PackSize             int `json:"packSize" validate:"gte=0"`
+}
+
+// RuleViolationDTO represents a broken purchase rule in a validation error response
+type RuleViolationDTO struct {
+	Code      string `json:"code"`
+	ProductID string `json:"productId"`
+	Message   string `json:"message"`
+	Limit     int    `json:"limit"`
+	Quantity  int    `json:"quantity"`
+}
+
+// PurchaseRuleFromDomain converts a purchase rule domain model to a DTO
+func PurchaseRuleFromDomain(rule *model.ProductPurchaseRule) *PurchaseRuleDTO {
+	return &PurchaseRuleDTO{
+		ProductID:            rule.ProductID.String(),
+		MaxPerOrder:          rule.MaxPerOrder,
+		MaxPerUser:           rule.MaxPerUser,
+		MaxPerUserWindowDays: int(rule.MaxPerUserWindow / (24 * time.Hour)),
+		MinQuantity:          rule.MinQuantity,
+		PackSize:             rule.PackSize,
+		UpdatedAt:            rule.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// RuleViolationsFromDomain converts the violations of a purchase rule error to DTOs
+func RuleViolationsFromDomain(err *model.RuleViolationError) []RuleViolationDTO {
+	violations := make([]RuleViolationDTO, len(err.Violations))
+	for i, violation := range err.Violations {
+		violations[i] = RuleViolationDTO{
+			Code:      string(violation.Code),
+			ProductID: violation.ProductID.String(),
+			Message:   violation.Message,
+			Limit:     violation.Limit,
+			Quantity:  violation.Quantity,
+		}
+	}
+	return violations
+}
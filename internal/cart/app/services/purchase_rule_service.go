@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/repository"
+)
+
+// PurchaseRuleService handles operations related to product purchase rules
+type PurchaseRuleService struct {
+	purchaseRuleRepository repository.PurchaseRuleRepository
+}
+
+// NewPurchaseRuleService creates a new purchase rule service
+func NewPurchaseRuleService(purchaseRuleRepository repository.PurchaseRuleRepository) *PurchaseRuleService {
+	return &PurchaseRuleService{
+		purchaseRuleRepository: purchaseRuleRepository,
+	}
+}
+
+// ListRules retrieves every purchase rule
+func (s *PurchaseRuleService) ListRules(ctx context.Context) ([]*dto.PurchaseRuleDTO, error) {
+	rules, err := s.purchaseRuleRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.PurchaseRuleDTO, len(rules))
+	for i, rule := range rules {
+		result[i] = dto.PurchaseRuleFromDomain(rule)
+	}
+
+	return result, nil
+}
+
+// GetRule retrieves the purchase rule of a product
+func (s *PurchaseRuleService) GetRule(ctx context.Context, productID string) (*dto.PurchaseRuleDTO, error) {
+	id, err := uuid.Parse(productID)
+	if err != nil {
+		return nil, errors.New("invalid product ID format")
+	}
+
+	rule, err := s.purchaseRuleRepository.FindByProductID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.PurchaseRuleFromDomain(rule), nil
+}
+
+// SetRule creates or replaces the purchase rule of a product
+func (s *PurchaseRuleService) SetRule(ctx context.Context, productID string, req *dto.PurchaseRuleRequest) (*dto.PurchaseRuleDTO, error) {
+	id, err := uuid.Parse(productID)
+	if err != nil {
+		return nil, errors.New("invalid product ID format")
+	}
+
+	window := time.Duration(req.MaxPerUserWindowDays) * 24 * time.Hour
+	rule, err := model.NewProductPurchaseRule(id, req.MaxPerOrder, req.MaxPerUser, window, req.MinQuantity, req.PackSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.purchaseRuleRepository.Save(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	return dto.PurchaseRuleFromDomain(rule), nil
+}
+
+// DeleteRule removes the purchase rule of a product
+func (s *PurchaseRuleService) DeleteRule(ctx context.Context, productID string) error {
+	id, err := uuid.Parse(productID)
+	if err != nil {
+		return errors.New("invalid product ID format")
+	}
+
+	return s.purchaseRuleRepository.Delete(ctx, id)
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BarcodeFormat identifies the kind of code read by the kiosk scanner
+type BarcodeFormat string
+
+const (
+	BarcodeFormatEAN13 BarcodeFormat = "EAN_13"
+	BarcodeFormatUPCA  BarcodeFormat = "UPC_A"
+	BarcodeFormatEAN8  BarcodeFormat = "EAN_8"
+	// BarcodeFormatSKU is an internal code printed by the kiosk, without a check digit
+	BarcodeFormatSKU BarcodeFormat = "SKU"
+)
+
+// maxSKULength limits the length of an internal SKU code
+const maxSKULength = 32
+
+// ProductBarcode maps a barcode or internal SKU to the product, and variant, it identifies
+type ProductBarcode struct {
+	// Code is the normalized code: UPC-A codes are stored in their EAN-13 form and SKUs in upper case
+	Code      string        `json:"code"`
+	Format    BarcodeFormat `json:"format"`
+	ProductID uuid.UUID     `json:"productId"`
+	VariantID string        `json:"variantId,omitempty"`
+	// Category is the product category given to the cart lines added by scanning the code
+	Category  string    `json:"category,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// NewProductBarcode creates the mapping of a code to a product, validating the code
+func NewProductBarcode(code string, productID uuid.UUID, variantID string, category string) (*ProductBarcode, error) {
+	normalized, format, err := NormalizeBarcode(code)
+	if err != nil {
+		return nil, err
+	}
+	if productID == uuid.Nil {
+		return nil, errors.New("product ID is required")
+	}
+
+	return &ProductBarcode{
+		Code:      normalized,
+		Format:    format,
+		ProductID: productID,
+		VariantID: strings.TrimSpace(variantID),
+		Category:  strings.TrimSpace(category),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// NormalizeBarcode validates a scanned code and returns it in the form it is stored with.
+// Codes of 8, 12 or 13 digits are EAN-8, UPC-A and EAN-13 barcodes and must have a valid
+// check digit; UPC-A codes are returned in their EAN-13 form. Any other code is an internal
+// SKU of letters, digits and dashes, compared without case.
+func NormalizeBarcode(code string) (string, BarcodeFormat, error) {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return "", "", errors.New("barcode is required")
+	}
+
+	if isDigits(code) {
+		var format BarcodeFormat
+		switch len(code) {
+		case 13:
+			format = BarcodeFormatEAN13
+		case 12:
+			format = BarcodeFormatUPCA
+		case 8:
+			format = BarcodeFormatEAN8
+		}
+		if format != "" {
+			if !validCheckDigit(code) {
+				return "", "", errors.New("invalid barcode checksum")
+			}
+			if format == BarcodeFormatUPCA {
+				code = "0" + code
+			}
+			return code, format, nil
+		}
+	}
+
+	if len(code) > maxSKULength {
+		return "", "", errors.New("invalid barcode")
+	}
+	for _, r := range code {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+			return "", "", errors.New("invalid barcode")
+		}
+	}
+	return strings.ToUpper(code), BarcodeFormatSKU, nil
+}
+
+// isDigits returns true if the code only has decimal digits
+func isDigits(code string) bool {
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// validCheckDigit verifies the GS1 check digit of an EAN or UPC code: from the right, the
+// digits before the check digit are weighted 3 and 1 alternately, and the check digit
+// brings the sum to a multiple of ten.
+func validCheckDigit(code string) bool {
+	sum := 0
+	weight := 3
+	for i := len(code) - 2; i >= 0; i-- {
+		sum += int(code[i]-'0') * weight
+		weight = 4 - weight
+	}
+	return (10-sum%10)%10 == int(code[len(code)-1]-'0')
+}
@@ -0,0 +1,69 @@
+package model
+
+import "testing"
+
+func TestValidCheckDigit(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{code: "4006381333931", want: true},
+		{code: "4006381333932", want: false},
+		{code: "5901234123457", want: true},
+		{code: "5901234123450", want: false},
+		{code: "036000291452", want: true},
+		{code: "036000291453", want: false},
+		{code: "96385074", want: true},
+		{code: "96385075", want: false},
+		{code: "0000000000000", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := validCheckDigit(tt.code); got != tt.want {
+				t.Errorf("validCheckDigit(%q) = %v, want %v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeBarcode(t *testing.T) {
+	tests := []struct {
+		name       string
+		code       string
+		wantCode   string
+		wantFormat BarcodeFormat
+		wantErr    string
+	}{
+		{name: "ean-13", code: "4006381333931", wantCode: "4006381333931", wantFormat: BarcodeFormatEAN13},
+		{name: "ean-13 with spaces around", code: " 4006381333931 ", wantCode: "4006381333931", wantFormat: BarcodeFormatEAN13},
+		{name: "ean-13 with wrong check digit", code: "4006381333932", wantErr: "invalid barcode checksum"},
+		{name: "upc-a stored as ean-13", code: "036000291452", wantCode: "0036000291452", wantFormat: BarcodeFormatUPCA},
+		{name: "upc-a with wrong check digit", code: "036000291453", wantErr: "invalid barcode checksum"},
+		{name: "ean-8", code: "96385074", wantCode: "96385074", wantFormat: BarcodeFormatEAN8},
+		{name: "ean-8 with wrong check digit", code: "96385075", wantErr: "invalid barcode checksum"},
+		{name: "digits of another length are a sku", code: "1234567890", wantCode: "1234567890", wantFormat: BarcodeFormatSKU},
+		{name: "sku in upper case", code: "kio-cafe-01", wantCode: "KIO-CAFE-01", wantFormat: BarcodeFormatSKU},
+		{name: "sku with invalid characters", code: "KIO CAFE", wantErr: "invalid barcode"},
+		{name: "sku too long", code: "KIO-0123456789-0123456789-0123456789", wantErr: "invalid barcode"},
+		{name: "empty code", code: "  ", wantErr: "barcode is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, format, err := NormalizeBarcode(tt.code)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("NormalizeBarcode(%q) error = %v, want %q", tt.code, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeBarcode(%q) error = %v", tt.code, err)
+			}
+			if code != tt.wantCode || format != tt.wantFormat {
+				t.Errorf("NormalizeBarcode(%q) = %q, %s, want %q, %s", tt.code, code, format, tt.wantCode, tt.wantFormat)
+			}
+		})
+	}
+}
@@ -2,39 +2,123 @@ package model
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// DefaultCartName is the name given to carts created without one
+const DefaultCartName = "My cart"
+
+// maxCartNameLength limits the length of a cart name
+const maxCartNameLength = 100
+
 // Cart represents the Cart aggregate root in the Cart Management bounded context
 type Cart struct {
-	ID        uuid.UUID   `json:"id"`
-	UserID    uuid.UUID   `json:"userId"`
-	Items     []*CartItem `json:"items"`
-	CreatedAt time.Time   `json:"createdAt"`
-	UpdatedAt time.Time   `json:"updatedAt"`
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"userId"`
+	Name   string    `json:"name"`
+	// Active marks the cart the user is currently shopping with. It is changed through
+	// the repository, which keeps a single active cart per user.
+	Active bool `json:"active"`
+	// Kiosk marks the carts of kiosk terminal sessions, which are owned by the session and
+	// can only be used by the terminal that started it
+	Kiosk bool        `json:"kiosk"`
+	Items []*CartItem `json:"items"`
+	// SavedItems are the items set aside for later. They are not part of the subtotal or the checkout.
+	SavedItems []*CartItem `json:"savedItems"`
+	// Members are the users the owner shared the cart with, each with a role
+	Members   []*CartMember `json:"members"`
+	CreatedAt time.Time     `json:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+
+	// rules are the purchase rules consulted when lines are added or changed
+	rules *PurchaseRules
+	// actor is the user recorded as the author of the changes
+	actor uuid.UUID
+	// activity holds the changes recorded since the cart was loaded, until they are saved
+	activity  []*CartActivity
+	recording bool
+}
+
+// NewCart creates a new empty cart for a user. An empty name gets DefaultCartName.
+func NewCart(userID uuid.UUID, name string) (*Cart, error) {
+	cart := &Cart{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Name:       DefaultCartName,
+		Items:      make([]*CartItem, 0),
+		SavedItems: make([]*CartItem, 0),
+		Members:    make([]*CartMember, 0),
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+
+	if strings.TrimSpace(name) != "" {
+		if err := cart.Rename(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return cart, nil
 }
 
-// NewCart creates a new empty cart for a user
-func NewCart(userID uuid.UUID) *Cart {
-	return &Cart{
-		ID:        uuid.New(),
-		UserID:    userID,
-		Items:     make([]*CartItem, 0),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+// Rename changes the name of the cart
+func (c *Cart) Rename(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("cart name is required")
+	}
+	if len(name) > maxCartNameLength {
+		return errors.New("cart name is too long")
 	}
+
+	c.Name = name
+	c.UpdatedAt = time.Now()
+	return nil
 }
 
-// AddItem adds a product to the cart
-func (c *Cart) AddItem(productID uuid.UUID, name string, price float64, quantity int, imageURL string) error {
-	// Check if the item already exists in the cart
+// SetPurchaseRules sets the purchase rules consulted by AddItem and UpdateItemQuantity
+func (c *Cart) SetPurchaseRules(rules *PurchaseRules) {
+	c.rules = rules
+}
+
+// checkRules validates the lines of a product against the purchase rules, if any
+func (c *Cart) checkRules(productID uuid.UUID) error {
+	if c.rules == nil {
+		return nil
+	}
+	return c.rules.Check(c, productID)
+}
+
+// AddItem adds a product to the cart on behalf of a user. The same product with another
+// variant or other options is added as a separate line; adding to an existing line credits
+// the units to the user in its contributions.
+func (c *Cart) AddItem(addedBy uuid.UUID, productID uuid.UUID, variantID string, options []ItemOption, name string, price float64, quantity int, imageURL string, category string) error {
+	return c.record(c.actor, CartOperationItemAdded, func() error {
+		return c.addItem(addedBy, productID, variantID, options, name, price, quantity, imageURL, category)
+	})
+}
+
+// addItem adds a product to the cart without recording the change
+func (c *Cart) addItem(addedBy uuid.UUID, productID uuid.UUID, variantID string, options []ItemOption, name string, price float64, quantity int, imageURL string, category string) error {
+	options, err := NormalizeOptions(options)
+	if err != nil {
+		return err
+	}
+	variantID = strings.TrimSpace(variantID)
+
+	// Check if the line already exists in the cart
 	for _, item := range c.Items {
-		if item.ProductID == productID {
-			// Update quantity instead of adding a new item
-			newQuantity := item.Quantity + quantity
-			if err := item.UpdateQuantity(newQuantity); err != nil {
+		if item.IsSameLine(productID, variantID, options) {
+			// Update quantity instead of adding a new item, crediting the user who added it
+			previous := *item
+			if err := item.UpdateQuantity(addedBy, item.Quantity+quantity); err != nil {
+				return err
+			}
+			if err := c.checkRules(productID); err != nil {
+				*item = previous
 				return err
 			}
 			c.UpdatedAt = time.Now()
@@ -43,22 +127,38 @@ func (c *Cart) AddItem(productID uuid.UUID, name string, price float64, quantity
 	}
 
 	// Create a new cart item
-	newItem, err := NewCartItem(productID, name, price, quantity, imageURL)
+	newItem, err := NewCartItem(addedBy, productID, variantID, options, name, price, quantity, imageURL, category)
 	if err != nil {
 		return err
 	}
 
 	// Add the new item to the cart
 	c.Items = append(c.Items, newItem)
+	if err := c.checkRules(productID); err != nil {
+		c.Items = c.Items[:len(c.Items)-1]
+		return err
+	}
 	c.UpdatedAt = time.Now()
 	return nil
 }
 
 // UpdateItemQuantity updates the quantity of an item in the cart
 func (c *Cart) UpdateItemQuantity(itemID uuid.UUID, quantity int) error {
+	return c.record(c.actor, CartOperationItemUpdated, func() error {
+		return c.updateItemQuantity(itemID, quantity)
+	})
+}
+
+// updateItemQuantity updates the quantity of an item without recording the change
+func (c *Cart) updateItemQuantity(itemID uuid.UUID, quantity int) error {
 	for _, item := range c.Items {
 		if item.ID == itemID {
-			if err := item.UpdateQuantity(quantity); err != nil {
+			previous := *item
+			if err := item.UpdateQuantity(c.actor, quantity); err != nil {
+				return err
+			}
+			if err := c.checkRules(item.ProductID); err != nil {
+				*item = previous
 				return err
 			}
 			c.UpdatedAt = time.Now()
@@ -70,6 +170,13 @@ func (c *Cart) UpdateItemQuantity(itemID uuid.UUID, quantity int) error {
 
 // RemoveItem removes an item from the cart
 func (c *Cart) RemoveItem(itemID uuid.UUID) error {
+	return c.record(c.actor, CartOperationItemRemoved, func() error {
+		return c.removeItem(itemID)
+	})
+}
+
+// removeItem removes an item from the cart without recording the change
+func (c *Cart) removeItem(itemID uuid.UUID) error {
 	for i, item := range c.Items {
 		if item.ID == itemID {
 			// Remove the item from the slice
@@ -83,8 +190,11 @@ func (c *Cart) RemoveItem(itemID uuid.UUID) error {
 
 // Clear empties the cart
 func (c *Cart) Clear() {
-	c.Items = make([]*CartItem, 0)
-	c.UpdatedAt = time.Now()
+	c.record(c.actor, CartOperationCartCleared, func() error {
+		c.Items = make([]*CartItem, 0)
+		c.UpdatedAt = time.Now()
+		return nil
+	})
 }
 
 // TotalItems returns the total number of items in the cart
@@ -105,11 +215,36 @@ func (c *Cart) Subtotal() float64 {
 	return total
 }
 
+// ProductQuantity returns the quantity of a product across all the cart lines
+func (c *Cart) ProductQuantity(productID uuid.UUID) int {
+	total := 0
+	for _, item := range c.Items {
+		if item.ProductID == productID {
+			total += item.Quantity
+		}
+	}
+	return total
+}
+
+// OriginalSubtotal calculates the subtotal of the cart without segment discounts
+func (c *Cart) OriginalSubtotal() float64 {
+	total := 0.0
+	for _, item := range c.Items {
+		total += item.OriginalSubtotal()
+	}
+	return total
+}
+
 // IsEmpty checks if the cart is empty
 func (c *Cart) IsEmpty() bool {
 	return len(c.Items) == 0
 }
 
+// touch records that the cart was modified
+func (c *Cart) touch() {
+	c.UpdatedAt = time.Now()
+}
+
 // GetItem returns a cart item by ID
 func (c *Cart) GetItem(itemID uuid.UUID) (*CartItem, error) {
 	for _, item := range c.Items {
@@ -0,0 +1,332 @@
+package model
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CartOperation represents the kind of change recorded in the cart activity
+type CartOperation string
+
+const (
+	CartOperationItemAdded        CartOperation = "ITEM_ADDED"
+	CartOperationItemUpdated      CartOperation = "ITEM_UPDATED"
+	CartOperationItemRemoved      CartOperation = "ITEM_REMOVED"
+	CartOperationCartCleared      CartOperation = "CART_CLEARED"
+	CartOperationItemsBatch       CartOperation = "ITEMS_BATCH"
+	CartOperationCartImported     CartOperation = "CART_IMPORTED"
+	CartOperationItemSaved        CartOperation = "ITEM_SAVED_FOR_LATER"
+	CartOperationItemMovedToCart  CartOperation = "ITEM_MOVED_TO_CART"
+	CartOperationSavedItemRemoved CartOperation = "SAVED_ITEM_REMOVED"
+	CartOperationPricesAccepted   CartOperation = "PRICES_ACCEPTED"
+	CartOperationStockAdjusted    CartOperation = "STOCK_ADJUSTED"
+	CartOperationItemMovedOut     CartOperation = "ITEM_MOVED_OUT"
+	CartOperationItemMovedIn      CartOperation = "ITEM_MOVED_IN"
+	CartOperationUndo             CartOperation = "UNDO"
+)
+
+// Undoable returns true if changes of this kind can be reverted. Stock adjustments follow the
+// inventory and moves between carts involve another cart, so they cannot be undone.
+func (o CartOperation) Undoable() bool {
+	switch o {
+	case CartOperationStockAdjusted, CartOperationItemMovedOut, CartOperationItemMovedIn, CartOperationUndo:
+		return false
+	}
+	return true
+}
+
+// CartActivity is an append-only record of a change to the lines of a cart. ActorID is
+// uuid.Nil for changes made by the system, such as stock adjustments.
+type CartActivity struct {
+	ID        uuid.UUID     `json:"id"`
+	CartID    uuid.UUID     `json:"cartId"`
+	ActorID   uuid.UUID     `json:"actorId"`
+	Operation CartOperation `json:"operation"`
+	Changes   []*LineChange `json:"changes"`
+	// RevertsID is the activity an UNDO entry reverted
+	RevertsID *uuid.UUID `json:"revertsId,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// LineChange represents the state of a line before and after a change. Before is nil for
+// added lines and After is nil for removed lines.
+type LineChange struct {
+	ItemID uuid.UUID  `json:"itemId"`
+	Before *LineState `json:"before,omitempty"`
+	After  *LineState `json:"after,omitempty"`
+}
+
+// LineState is a snapshot of a cart line, in the cart or in the saved-for-later list
+type LineState struct {
+	Item     CartItem `json:"item"`
+	Saved    bool     `json:"saved"`
+	Position int      `json:"position"`
+}
+
+// SetActor sets the user recorded as the author of the following changes
+func (c *Cart) SetActor(actorID uuid.UUID) {
+	c.actor = actorID
+}
+
+// PendingActivity returns the changes recorded since the cart was loaded or last saved
+func (c *Cart) PendingActivity() []*CartActivity {
+	return c.activity
+}
+
+// ActivitySaved clears the pending activity once it has been persisted
+func (c *Cart) ActivitySaved() {
+	c.activity = nil
+}
+
+// record runs a mutation and appends an activity entry with the lines it changed. Mutations
+// that call other mutations are recorded once, under the outermost operation.
+func (c *Cart) record(actorID uuid.UUID, operation CartOperation, mutate func() error) error {
+	if c.recording {
+		return mutate()
+	}
+	c.recording = true
+	defer func() { c.recording = false }()
+
+	before := c.lineStates()
+	if err := mutate(); err != nil {
+		return err
+	}
+
+	changes := diffLines(before, c.lineStates())
+	if len(changes) > 0 {
+		c.activity = append(c.activity, &CartActivity{
+			ID:        uuid.New(),
+			CartID:    c.ID,
+			ActorID:   actorID,
+			Operation: operation,
+			Changes:   changes,
+			CreatedAt: time.Now(),
+		})
+	}
+
+	return nil
+}
+
+// NextUndo returns the most recent change of the history that was not undone yet, the one
+// Undo reverts. The history must be ordered from the newest entry.
+func NextUndo(history []*CartActivity) (*CartActivity, error) {
+	reverted := make(map[uuid.UUID]bool)
+	var target *CartActivity
+	for _, activity := range history {
+		if activity.Operation == CartOperationUndo {
+			if activity.RevertsID != nil {
+				reverted[*activity.RevertsID] = true
+			}
+			continue
+		}
+		if !reverted[activity.ID] {
+			target = activity
+			break
+		}
+	}
+
+	if target == nil {
+		return nil, errors.New("nothing to undo")
+	}
+	if !target.Operation.Undoable() {
+		return nil, errors.New("the last change cannot be undone")
+	}
+	return target, nil
+}
+
+// RestoredProductIDs returns the distinct products of the lines undoing the change restores
+// to the cart, whose purchase rules Undo consults
+func (a *CartActivity) RestoredProductIDs() []uuid.UUID {
+	seen := make(map[uuid.UUID]bool)
+	ids := make([]uuid.UUID, 0)
+	for _, change := range a.Changes {
+		if change.Before != nil && !change.Before.Saved && !seen[change.Before.Item.ProductID] {
+			seen[change.Before.Item.ProductID] = true
+			ids = append(ids, change.Before.Item.ProductID)
+		}
+	}
+	return ids
+}
+
+// Undo reverts the most recent change of the history that was not undone yet. The history
+// must be ordered from the newest entry. The restored lines are checked against the purchase
+// rules as when they are added, and the cart is left unchanged if they break one. The undo
+// is recorded as a new activity entry.
+func (c *Cart) Undo(history []*CartActivity) error {
+	target, err := NextUndo(history)
+	if err != nil {
+		return err
+	}
+
+	// The lines must still be as the change left them
+	current := c.lineStates()
+	for _, change := range target.Changes {
+		state, exists := current[change.ItemID]
+		if change.After == nil {
+			if exists {
+				return errors.New("cart changed since the last change")
+			}
+		} else if !exists || !sameLineState(state, change.After) {
+			return errors.New("cart changed since the last change")
+		}
+	}
+
+	recorded := len(c.activity)
+	err = c.record(c.actor, CartOperationUndo, func() error {
+		items, savedItems := copyItems(c.Items), copyItems(c.SavedItems)
+
+		for _, change := range target.Changes {
+			c.Items = withoutItem(c.Items, change.ItemID)
+			c.SavedItems = withoutItem(c.SavedItems, change.ItemID)
+		}
+
+		restored := make([]*LineState, 0, len(target.Changes))
+		for _, change := range target.Changes {
+			if change.Before != nil {
+				restored = append(restored, change.Before)
+			}
+		}
+		sort.Slice(restored, func(i, j int) bool { return restored[i].Position < restored[j].Position })
+
+		for _, state := range restored {
+			item := state.Item
+			item.Options = append([]ItemOption(nil), state.Item.Options...)
+			item.Contributions = state.Item.Contributors()
+			if state.Saved {
+				c.SavedItems = withItemAt(c.SavedItems, &item, state.Position)
+			} else {
+				c.Items = withItemAt(c.Items, &item, state.Position)
+			}
+		}
+
+		for _, productID := range target.RestoredProductIDs() {
+			if err := c.checkRules(productID); err != nil {
+				c.Items, c.SavedItems = items, savedItems
+				return err
+			}
+		}
+
+		c.touch()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(c.activity) > recorded {
+		revertsID := target.ID
+		c.activity[len(c.activity)-1].RevertsID = &revertsID
+	}
+
+	return nil
+}
+
+// lineStates takes a snapshot of every line of the cart and the saved-for-later list
+func (c *Cart) lineStates() map[uuid.UUID]*LineState {
+	states := make(map[uuid.UUID]*LineState, len(c.Items)+len(c.SavedItems))
+	for position, item := range c.Items {
+		states[item.ID] = snapshotLine(item, false, position)
+	}
+	for position, item := range c.SavedItems {
+		states[item.ID] = snapshotLine(item, true, position)
+	}
+	return states
+}
+
+// snapshotLine copies a line, leaving out the segment price, which is resolved on every read
+func snapshotLine(item *CartItem, saved bool, position int) *LineState {
+	state := &LineState{
+		Item:     *item,
+		Saved:    saved,
+		Position: position,
+	}
+	state.Item.Options = append([]ItemOption(nil), item.Options...)
+	state.Item.Contributions = item.Contributors()
+	state.Item.SegmentPrice = nil
+	return state
+}
+
+// diffLines returns the lines that were added, changed or removed between two snapshots.
+// Lines that only moved because another line was added or removed are not changes.
+func diffLines(before, after map[uuid.UUID]*LineState) []*LineChange {
+	changes := make([]*LineChange, 0)
+
+	for itemID, previous := range before {
+		current, exists := after[itemID]
+		if !exists {
+			changes = append(changes, &LineChange{ItemID: itemID, Before: previous})
+		} else if !sameLineState(previous, current) {
+			changes = append(changes, &LineChange{ItemID: itemID, Before: previous, After: current})
+		}
+	}
+	for itemID, current := range after {
+		if _, existed := before[itemID]; !existed {
+			changes = append(changes, &LineChange{ItemID: itemID, After: current})
+		}
+	}
+
+	// Keep a stable order, so entries read the same every time
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].ItemID.String() < changes[j].ItemID.String()
+	})
+
+	return changes
+}
+
+// sameLineState returns true if both snapshots describe the same line content in the same list
+func sameLineState(a, b *LineState) bool {
+	if a.Saved != b.Saved || len(a.Item.Options) != len(b.Item.Options) {
+		return false
+	}
+	for i := range a.Item.Options {
+		if a.Item.Options[i] != b.Item.Options[i] {
+			return false
+		}
+	}
+	if !sameContributions(a.Item.Contributors(), b.Item.Contributors()) {
+		return false
+	}
+	return a.Item.AddedBy == b.Item.AddedBy &&
+		a.Item.ProductID == b.Item.ProductID &&
+		a.Item.VariantID == b.Item.VariantID &&
+		a.Item.Name == b.Item.Name &&
+		a.Item.Price == b.Item.Price &&
+		a.Item.Quantity == b.Item.Quantity &&
+		a.Item.ImageURL == b.Item.ImageURL &&
+		a.Item.Category == b.Item.Category
+}
+
+// sameContributions returns true if both lines were added by the same users in the same quantities
+func sameContributions(a, b []ItemContribution) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// withoutItem returns the list without the item with the given ID
+func withoutItem(items []*CartItem, itemID uuid.UUID) []*CartItem {
+	if index := indexOfItem(items, itemID); index >= 0 {
+		return append(items[:index], items[index+1:]...)
+	}
+	return items
+}
+
+// withItemAt returns the list with the item at the given position, or at the end if the list is shorter
+func withItemAt(items []*CartItem, item *CartItem, position int) []*CartItem {
+	if position >= len(items) {
+		return append(items, item)
+	}
+	items = append(items, nil)
+	copy(items[position+1:], items[position:])
+	items[position] = item
+	return items
+}
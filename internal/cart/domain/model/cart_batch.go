@@ -0,0 +1,202 @@
+package model
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// MaxItemOperations limits the number of operations applied to a cart in a single batch
+const MaxItemOperations = 100
+
+// ItemOperationType represents the kind of change an item operation makes to a cart
+type ItemOperationType string
+
+const (
+	ItemOperationAdd    ItemOperationType = "ADD"
+	ItemOperationUpdate ItemOperationType = "UPDATE"
+	ItemOperationRemove ItemOperationType = "REMOVE"
+)
+
+// ItemOperation is a single change of a batch. ADD uses the product fields, UPDATE uses
+// ItemID and Quantity and REMOVE uses ItemID.
+type ItemOperation struct {
+	Type      ItemOperationType
+	ItemID    uuid.UUID
+	ProductID uuid.UUID
+	VariantID string
+	Options   []ItemOption
+	Name      string
+	Price     float64
+	Quantity  int
+	ImageURL  string
+	Category  string
+}
+
+// ItemOperationResult is the outcome of an operation of a batch. ItemID is the line the
+// operation changed, and Err is nil if the operation succeeded.
+type ItemOperationResult struct {
+	Type   ItemOperationType
+	ItemID uuid.UUID
+	Err    error
+}
+
+// ItemBatchError is returned when an operation of a batch fails. No operation of the batch
+// is applied; Results reports the outcome of each one.
+type ItemBatchError struct {
+	Results []*ItemOperationResult
+}
+
+// Error implements the error interface
+func (e *ItemBatchError) Error() string {
+	return "item operations failed"
+}
+
+// ApplyItemOperations applies a batch of operations in order on behalf of a user. Either every
+// operation succeeds or the cart is left unchanged. Every operation is attempted, so the
+// results report all the failures of the batch, which is recorded as a single change. The
+// purchase rules are checked once on the cart the whole batch leaves, so a batch may pass
+// through intermediate states that break them (e.g. removing a line before adding another).
+func (c *Cart) ApplyItemOperations(addedBy uuid.UUID, operations []*ItemOperation) ([]*ItemOperationResult, error) {
+	if len(operations) == 0 {
+		return nil, errors.New("at least one operation is required")
+	}
+	if len(operations) > MaxItemOperations {
+		return nil, errors.New("too many operations")
+	}
+
+	results := make([]*ItemOperationResult, len(operations))
+	err := c.record(c.actor, CartOperationItemsBatch, func() error {
+		items := copyItems(c.Items)
+		updatedAt := c.UpdatedAt
+
+		// Apply every operation without the rules; the last operation changing each product
+		// is the one its violations are reported on
+		rules := c.rules
+		c.rules = nil
+		lastChange := make(map[uuid.UUID]int)
+		products := make([]uuid.UUID, 0)
+		lastAdd := -1
+
+		failed := false
+		for i, operation := range operations {
+			productID := operation.ProductID
+			if item, err := c.GetItem(operation.ItemID); err == nil && operation.Type != ItemOperationAdd {
+				productID = item.ProductID
+			}
+
+			results[i] = c.applyItemOperation(addedBy, operation)
+			if results[i].Err != nil {
+				failed = true
+				continue
+			}
+
+			if _, changed := lastChange[productID]; !changed {
+				products = append(products, productID)
+			}
+			lastChange[productID] = i
+			if operation.Type == ItemOperationAdd {
+				lastAdd = i
+			}
+		}
+		c.rules = rules
+
+		if !failed && rules != nil {
+			failed = !c.checkBatchRules(operations, results, products, lastChange, lastAdd)
+		}
+
+		if failed {
+			c.Items = items
+			c.UpdatedAt = updatedAt
+			// Lines the batch created no longer exist
+			for _, result := range results {
+				if result.Type == ItemOperationAdd && indexOfItem(c.Items, result.ItemID) < 0 {
+					result.ItemID = uuid.Nil
+				}
+			}
+			return &ItemBatchError{Results: results}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// checkBatchRules validates the cart left by a batch against the purchase rules. The
+// violations of a product are reported on the last operation that changed it, and too many
+// lines on the last ADD. Products the batch removed entirely are not checked, as when a
+// line is removed. It returns whether the cart is valid.
+func (c *Cart) checkBatchRules(operations []*ItemOperation, results []*ItemOperationResult, products []uuid.UUID, lastChange map[uuid.UUID]int, lastAdd int) bool {
+	valid := true
+	report := func(i int, violations []*RuleViolation) {
+		if len(violations) == 0 {
+			return
+		}
+		valid = false
+		if existing, ok := results[i].Err.(*RuleViolationError); ok {
+			existing.Violations = append(existing.Violations, violations...)
+			return
+		}
+		results[i].Err = &RuleViolationError{Violations: violations}
+	}
+
+	if lastAdd >= 0 {
+		report(lastAdd, c.rules.lineViolations(c, operations[lastAdd].ProductID))
+	}
+	for _, productID := range products {
+		if c.ProductQuantity(productID) > 0 {
+			report(lastChange[productID], c.rules.productViolations(c, productID))
+		}
+	}
+
+	return valid
+}
+
+// applyItemOperation applies a single operation of a batch without recording the change
+func (c *Cart) applyItemOperation(addedBy uuid.UUID, operation *ItemOperation) *ItemOperationResult {
+	result := &ItemOperationResult{Type: operation.Type, ItemID: operation.ItemID}
+
+	switch operation.Type {
+	case ItemOperationAdd:
+		options, err := NormalizeOptions(operation.Options)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		if err := c.addItem(addedBy, operation.ProductID, operation.VariantID, options, operation.Name, operation.Price, operation.Quantity, operation.ImageURL, operation.Category); err != nil {
+			result.Err = err
+			return result
+		}
+		// Report the line the product went to, a new one or the one it was merged into
+		variantID := strings.TrimSpace(operation.VariantID)
+		for _, item := range c.Items {
+			if item.IsSameLine(operation.ProductID, variantID, options) {
+				result.ItemID = item.ID
+			}
+		}
+	case ItemOperationUpdate:
+		result.Err = c.updateItemQuantity(operation.ItemID, operation.Quantity)
+	case ItemOperationRemove:
+		result.Err = c.removeItem(operation.ItemID)
+	default:
+		result.Err = errors.New("invalid operation type")
+	}
+
+	return result
+}
+
+// copyItems returns a deep copy of a list of lines
+func copyItems(items []*CartItem) []*CartItem {
+	result := make([]*CartItem, len(items))
+	for i, item := range items {
+		copied := *item
+		copied.Options = append([]ItemOption(nil), item.Options...)
+		copied.Contributions = append([]ItemContribution(nil), item.Contributions...)
+		result[i] = &copied
+	}
+	return result
+}
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// ImportLine is a line of an exported cart or a snapshot to be added to a cart. Line is its
+// position in the file, starting at 1. The name and price come from the catalog.
+type ImportLine struct {
+	Line      int
+	ProductID uuid.UUID
+	VariantID string
+	Options   []ItemOption
+	Quantity  int
+	ImageURL  string
+	Category  string
+}
+
+// RejectedLine reports a line that could not be imported and why
+type RejectedLine struct {
+	Line      int
+	ProductID string
+	VariantID string
+	Quantity  int
+	Reason    string
+}
+
+// NewRejectedLine creates the report of a line rejected before it reached the cart
+func NewRejectedLine(line int, productID string, variantID string, quantity int, reason string) *RejectedLine {
+	return &RejectedLine{
+		Line:      line,
+		ProductID: productID,
+		VariantID: variantID,
+		Quantity:  quantity,
+		Reason:    reason,
+	}
+}
+
+// ImportLines adds the lines to the cart on behalf of a user, revalidating each one against
+// the catalog, and returns how many were imported and the rejected ones. Lines of discontinued
+// or out-of-stock products, above the available stock or breaking the purchase rules are
+// rejected; the rest are imported. With replace, the cart items are removed first. The import
+// is recorded as a single change.
+func (c *Cart) ImportLines(addedBy uuid.UUID, lines []*ImportLine, products map[uuid.UUID]*CatalogProduct, replace bool) (int, []*RejectedLine) {
+	imported := 0
+	rejected := make([]*RejectedLine, 0)
+
+	c.record(c.actor, CartOperationCartImported, func() error {
+		if replace && len(c.Items) > 0 {
+			c.Items = make([]*CartItem, 0)
+			c.touch()
+		}
+
+		for _, line := range lines {
+			if err := c.importLine(addedBy, line, products[line.ProductID]); err != nil {
+				rejected = append(rejected, NewRejectedLine(line.Line, line.ProductID.String(), line.VariantID, line.Quantity, err.Error()))
+				continue
+			}
+			imported++
+		}
+		return nil
+	})
+
+	return imported, rejected
+}
+
+// importLine revalidates a line against its catalog product and adds it to the cart
+func (c *Cart) importLine(addedBy uuid.UUID, line *ImportLine, product *CatalogProduct) error {
+	if line.Quantity <= 0 {
+		return errors.New("quantity must be greater than zero")
+	}
+	if product == nil || product.Discontinued {
+		return errors.New("product is no longer available")
+	}
+	if product.Stock <= 0 {
+		return errors.New("product is out of stock")
+	}
+	if line.Quantity > product.Stock {
+		return fmt.Errorf("only %d units are available", product.Stock)
+	}
+
+	price := math.Round(product.Price*100) / 100
+	return c.addItem(addedBy, line.ProductID, line.VariantID, line.Options, product.Name, price, line.Quantity, line.ImageURL, line.Category)
+}
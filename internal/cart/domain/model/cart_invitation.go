@@ -0,0 +1,121 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvitationStatus represents the state of a cart invitation
+type InvitationStatus string
+
+const (
+	InvitationStatusPending  InvitationStatus = "PENDING"
+	InvitationStatusAccepted InvitationStatus = "ACCEPTED"
+	InvitationStatusDeclined InvitationStatus = "DECLINED"
+	InvitationStatusRevoked  InvitationStatus = "REVOKED"
+)
+
+// InvitationLifetime is how long an invitation can be accepted after it is sent
+const InvitationLifetime = 7 * 24 * time.Hour
+
+// CartInvitation represents the invitation of a user to join a shared cart with a role
+type CartInvitation struct {
+	ID          uuid.UUID        `json:"id"`
+	CartID      uuid.UUID        `json:"cartId"`
+	InviteeID   uuid.UUID        `json:"inviteeId"`
+	InvitedBy   uuid.UUID        `json:"invitedBy"`
+	Role        MemberRole       `json:"role"`
+	Status      InvitationStatus `json:"status"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	ExpiresAt   time.Time        `json:"expiresAt"`
+	RespondedAt *time.Time       `json:"respondedAt,omitempty"`
+}
+
+// NewCartInvitation creates a pending invitation for a user to join a cart
+func NewCartInvitation(cart *Cart, inviteeID uuid.UUID, role MemberRole, invitedBy uuid.UUID) (*CartInvitation, error) {
+	if inviteeID == uuid.Nil {
+		return nil, errors.New("invitee ID is required")
+	}
+	if err := validateMemberRole(role); err != nil {
+		return nil, err
+	}
+	if inviteeID == cart.UserID {
+		return nil, errors.New("cannot invite the cart owner")
+	}
+	if cart.GetMember(inviteeID) != nil {
+		return nil, errors.New("user is already a member of the cart")
+	}
+
+	now := time.Now()
+	return &CartInvitation{
+		ID:        uuid.New(),
+		CartID:    cart.ID,
+		InviteeID: inviteeID,
+		InvitedBy: invitedBy,
+		Role:      role,
+		Status:    InvitationStatusPending,
+		CreatedAt: now,
+		ExpiresAt: now.Add(InvitationLifetime),
+	}, nil
+}
+
+// IsPending returns true if the invitation can still be answered
+func (i *CartInvitation) IsPending() bool {
+	return i.checkPending() == nil
+}
+
+// checkPending returns an error if the invitation can no longer be answered
+func (i *CartInvitation) checkPending() error {
+	if i.Status != InvitationStatusPending {
+		return errors.New("invitation is no longer pending")
+	}
+	if !time.Now().Before(i.ExpiresAt) {
+		return errors.New("invitation has expired")
+	}
+	return nil
+}
+
+// respond records the answer to the invitation
+func (i *CartInvitation) respond(status InvitationStatus) {
+	now := time.Now()
+	i.Status = status
+	i.RespondedAt = &now
+}
+
+// Accept accepts the invitation and adds the invitee to the cart with the invited role
+func (i *CartInvitation) Accept(cart *Cart) error {
+	if cart.ID != i.CartID {
+		return errors.New("invitation does not belong to the cart")
+	}
+	if err := i.checkPending(); err != nil {
+		return err
+	}
+	if err := cart.AddMember(i.InviteeID, i.Role); err != nil {
+		return err
+	}
+
+	i.respond(InvitationStatusAccepted)
+	return nil
+}
+
+// Decline declines the invitation
+func (i *CartInvitation) Decline() error {
+	if err := i.checkPending(); err != nil {
+		return err
+	}
+
+	i.respond(InvitationStatusDeclined)
+	return nil
+}
+
+// Revoke cancels a pending invitation
+func (i *CartInvitation) Revoke() error {
+	if i.Status != InvitationStatusPending {
+		return errors.New("invitation is no longer pending")
+	}
+
+	i.respond(InvitationStatusRevoked)
+	return nil
+}
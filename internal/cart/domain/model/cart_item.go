@@ -10,19 +10,72 @@ import (
 type CartItem struct {
 	ID        uuid.UUID `json:"id"`
 	ProductID uuid.UUID `json:"productId"`
-	Name      string    `json:"name"`
-	Price     float64   `json:"price"`
-	Quantity  int       `json:"quantity"`
-	ImageURL  string    `json:"imageUrl"`
+	// VariantID is the SKU of the product variant, empty for products without variants
+	VariantID string       `json:"variantId,omitempty"`
+	Options   []ItemOption `json:"options,omitempty"`
+	Name      string       `json:"name"`
+	Price     float64      `json:"price"`
+	Quantity  int          `json:"quantity"`
+	ImageURL  string       `json:"imageUrl"`
+	Category  string       `json:"category,omitempty"`
+	// AddedBy is the user who first added the line, the owner or a member of a shared cart
+	AddedBy uuid.UUID `json:"addedBy"`
+	// Contributions splits the quantity among the users who added it, in the order they first did
+	Contributions []ItemContribution `json:"contributions,omitempty"`
+	// SegmentPrice is the price for the cart owner's customer segment. It is resolved
+	// when the cart is read and never persisted.
+	SegmentPrice *SegmentPrice `json:"-"`
 }
 
-// Subtotal calculates the subtotal for this cart item (price * quantity)
+// ItemContribution is the part of the quantity of a line added by one user of a shared cart
+type ItemContribution struct {
+	UserID   uuid.UUID `json:"userId"`
+	Quantity int       `json:"quantity"`
+}
+
+// SegmentPrice represents the discounted unit price granted to a customer segment
+type SegmentPrice struct {
+	Segment string
+	Price   float64
+}
+
+// Surcharge returns the amount the options add to the unit price
+func (i *CartItem) Surcharge() float64 {
+	total := 0.0
+	for _, option := range i.Options {
+		total += option.Surcharge
+	}
+	return total
+}
+
+// UnitPrice returns the price charged per unit, including the segment discount if any
+// and the option surcharges
+func (i *CartItem) UnitPrice() float64 {
+	if i.SegmentPrice != nil {
+		return i.SegmentPrice.Price + i.Surcharge()
+	}
+	return i.Price + i.Surcharge()
+}
+
+// IsSameLine returns true if the item is the line for the given product, variant and
+// normalized options. Items of the same product with other options are separate lines; a
+// line added to by several members of a shared cart records each one in its contributions.
+func (i *CartItem) IsSameLine(productID uuid.UUID, variantID string, options []ItemOption) bool {
+	return i.ProductID == productID && i.VariantID == variantID && sameOptions(i.Options, options)
+}
+
+// Subtotal calculates the subtotal for this cart item (unit price * quantity)
 func (i *CartItem) Subtotal() float64 {
-	return i.Price * float64(i.Quantity)
+	return i.UnitPrice() * float64(i.Quantity)
+}
+
+// OriginalSubtotal calculates the subtotal for this cart item without the segment discount
+func (i *CartItem) OriginalSubtotal() float64 {
+	return (i.Price + i.Surcharge()) * float64(i.Quantity)
 }
 
-// NewCartItem creates a new cart item
-func NewCartItem(productID uuid.UUID, name string, price float64, quantity int, imageURL string) (*CartItem, error) {
+// NewCartItem creates a new cart item. Options must be normalized with NormalizeOptions.
+func NewCartItem(addedBy uuid.UUID, productID uuid.UUID, variantID string, options []ItemOption, name string, price float64, quantity int, imageURL string, category string) (*CartItem, error) {
 	if quantity <= 0 {
 		return nil, errors.New("quantity must be greater than zero")
 	}
@@ -34,18 +87,94 @@ func NewCartItem(productID uuid.UUID, name string, price float64, quantity int,
 	return &CartItem{
 		ID:        uuid.New(),
 		ProductID: productID,
+		VariantID: variantID,
+		Options:   options,
 		Name:      name,
 		Price:     price,
 		Quantity:  quantity,
 		ImageURL:  imageURL,
+		Category:  category,
+		AddedBy:   addedBy,
+		Contributions: []ItemContribution{
+			{UserID: addedBy, Quantity: quantity},
+		},
 	}, nil
 }
 
-// UpdateQuantity updates the quantity of the cart item
-func (i *CartItem) UpdateQuantity(quantity int) error {
+// UpdateQuantity updates the quantity of the cart item on behalf of a user. Units added are
+// credited to the user; units removed are taken from the user's contribution first and then
+// from the latest contributions of others.
+func (i *CartItem) UpdateQuantity(userID uuid.UUID, quantity int) error {
 	if quantity <= 0 {
 		return errors.New("quantity must be greater than zero")
 	}
+
+	i.Contributions = i.Contributors()
+	if quantity > i.Quantity {
+		i.contribute(userID, quantity-i.Quantity)
+	} else if quantity < i.Quantity {
+		i.withdraw(userID, i.Quantity-quantity)
+	}
 	i.Quantity = quantity
 	return nil
 }
+
+// Contributors returns the contributions to the line. Lines stored before contributions
+// were tracked belong entirely to the user who added them.
+func (i *CartItem) Contributors() []ItemContribution {
+	total := 0
+	for _, contribution := range i.Contributions {
+		total += contribution.Quantity
+	}
+	if total != i.Quantity {
+		return []ItemContribution{{UserID: i.AddedBy, Quantity: i.Quantity}}
+	}
+	return append([]ItemContribution(nil), i.Contributions...)
+}
+
+// merge adds the quantity and the contributions of the same line from another list
+func (i *CartItem) merge(other *CartItem) {
+	i.Contributions = i.Contributors()
+	for _, contribution := range other.Contributors() {
+		i.contribute(contribution.UserID, contribution.Quantity)
+	}
+	i.Quantity += other.Quantity
+}
+
+// contribute credits units of the line to a user
+func (i *CartItem) contribute(userID uuid.UUID, quantity int) {
+	for j := range i.Contributions {
+		if i.Contributions[j].UserID == userID {
+			i.Contributions[j].Quantity += quantity
+			return
+		}
+	}
+	i.Contributions = append(i.Contributions, ItemContribution{UserID: userID, Quantity: quantity})
+}
+
+// withdraw removes units of the line from the user's contribution first, then from the
+// latest contributions
+func (i *CartItem) withdraw(userID uuid.UUID, quantity int) {
+	take := func(j int) {
+		taken := min(quantity, i.Contributions[j].Quantity)
+		i.Contributions[j].Quantity -= taken
+		quantity -= taken
+	}
+
+	for j := range i.Contributions {
+		if i.Contributions[j].UserID == userID {
+			take(j)
+		}
+	}
+	for j := len(i.Contributions) - 1; j >= 0 && quantity > 0; j-- {
+		take(j)
+	}
+
+	remaining := i.Contributions[:0]
+	for _, contribution := range i.Contributions {
+		if contribution.Quantity > 0 {
+			remaining = append(remaining, contribution)
+		}
+	}
+	i.Contributions = remaining
+}
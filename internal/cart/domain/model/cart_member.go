@@ -0,0 +1,143 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemberRole represents what a user can do with a shared cart
+type MemberRole string
+
+const (
+	// MemberRoleOwner is the user the cart belongs to. Owners manage the cart and its members.
+	MemberRoleOwner MemberRole = "OWNER"
+	// MemberRoleEditor members can add, change and remove items
+	MemberRoleEditor MemberRole = "EDITOR"
+	// MemberRoleViewer members can only read the cart
+	MemberRoleViewer MemberRole = "VIEWER"
+)
+
+// CartPermission represents an operation on a cart that depends on the member role
+type CartPermission string
+
+const (
+	// CartPermissionView allows reading the cart
+	CartPermissionView CartPermission = "VIEW"
+	// CartPermissionEdit allows changing the cart items
+	CartPermissionEdit CartPermission = "EDIT"
+	// CartPermissionManage allows renaming, deleting and sharing the cart
+	CartPermissionManage CartPermission = "MANAGE"
+	// CartPermissionCheckout allows checking out the cart, which is charged to the owner
+	CartPermissionCheckout CartPermission = "CHECKOUT"
+)
+
+// CartMember represents a user other than the owner who has access to a shared cart
+type CartMember struct {
+	UserID   uuid.UUID  `json:"userId"`
+	Role     MemberRole `json:"role"`
+	JoinedAt time.Time  `json:"joinedAt"`
+}
+
+// Allows returns true if the role grants the permission
+func (r MemberRole) Allows(permission CartPermission) bool {
+	switch r {
+	case MemberRoleOwner:
+		return true
+	case MemberRoleEditor:
+		return permission == CartPermissionView || permission == CartPermissionEdit
+	case MemberRoleViewer:
+		return permission == CartPermissionView
+	}
+	return false
+}
+
+// validateMemberRole checks that a role can be given to a member. The owner role cannot.
+func validateMemberRole(role MemberRole) error {
+	if role != MemberRoleEditor && role != MemberRoleViewer {
+		return errors.New("invalid member role")
+	}
+	return nil
+}
+
+// RoleOf returns the role of a user in the cart, and false if the user has no access
+func (c *Cart) RoleOf(userID uuid.UUID) (MemberRole, bool) {
+	if userID == c.UserID {
+		return MemberRoleOwner, true
+	}
+	if member := c.GetMember(userID); member != nil {
+		return member.Role, true
+	}
+	return "", false
+}
+
+// Authorize checks that a user is allowed to perform an operation on the cart
+func (c *Cart) Authorize(userID uuid.UUID, permission CartPermission) error {
+	role, ok := c.RoleOf(userID)
+	if !ok || !role.Allows(permission) {
+		return errors.New("cart access denied")
+	}
+	return nil
+}
+
+// IsShared returns true if other users have access to the cart
+func (c *Cart) IsShared() bool {
+	return len(c.Members) > 0
+}
+
+// GetMember returns the member with the given user ID, or nil if there is none
+func (c *Cart) GetMember(userID uuid.UUID) *CartMember {
+	for _, member := range c.Members {
+		if member.UserID == userID {
+			return member
+		}
+	}
+	return nil
+}
+
+// AddMember gives a user access to the cart with a role
+func (c *Cart) AddMember(userID uuid.UUID, role MemberRole) error {
+	if err := validateMemberRole(role); err != nil {
+		return err
+	}
+	if _, ok := c.RoleOf(userID); ok {
+		return errors.New("user is already a member of the cart")
+	}
+
+	c.Members = append(c.Members, &CartMember{
+		UserID:   userID,
+		Role:     role,
+		JoinedAt: time.Now(),
+	})
+	c.touch()
+	return nil
+}
+
+// ChangeMemberRole changes the role of a member
+func (c *Cart) ChangeMemberRole(userID uuid.UUID, role MemberRole) error {
+	if err := validateMemberRole(role); err != nil {
+		return err
+	}
+
+	member := c.GetMember(userID)
+	if member == nil {
+		return errors.New("member not found")
+	}
+
+	member.Role = role
+	c.touch()
+	return nil
+}
+
+// RemoveMember revokes the access of a member. The items the member added stay in the cart.
+func (c *Cart) RemoveMember(userID uuid.UUID) error {
+	for i, member := range c.Members {
+		if member.UserID == userID {
+			c.Members = append(c.Members[:i], c.Members[i+1:]...)
+			c.touch()
+			return nil
+		}
+	}
+	return errors.New("member not found")
+}
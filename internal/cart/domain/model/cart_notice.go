@@ -0,0 +1,159 @@
+package model
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// CartNoticeType represents the kind of change detected when revalidating a cart
+type CartNoticeType string
+
+const (
+	CartNoticePriceIncreased  CartNoticeType = "PRICE_INCREASED"
+	CartNoticePriceDecreased  CartNoticeType = "PRICE_DECREASED"
+	CartNoticeOutOfStock      CartNoticeType = "OUT_OF_STOCK"
+	CartNoticeDiscontinued    CartNoticeType = "DISCONTINUED"
+	CartNoticeQuantityReduced CartNoticeType = "QUANTITY_REDUCED"
+)
+
+// CatalogProduct represents the current catalog and inventory data of a product
+type CatalogProduct struct {
+	ProductID    uuid.UUID
+	Name         string
+	Price        float64
+	Discontinued bool
+	Stock        int
+}
+
+// CartNotice informs the shopper about a change of a cart item since it was added
+type CartNotice struct {
+	Type              CartNoticeType `json:"type"`
+	ItemID            uuid.UUID      `json:"itemId"`
+	ProductID         uuid.UUID      `json:"productId"`
+	Message           string         `json:"message"`
+	OldPrice          float64        `json:"oldPrice,omitempty"`
+	NewPrice          float64        `json:"newPrice,omitempty"`
+	RequestedQuantity int            `json:"requestedQuantity,omitempty"`
+	AvailableQuantity int            `json:"availableQuantity,omitempty"`
+}
+
+// Revalidate checks the items against the current catalog data. Quantities above the
+// available stock are reduced; price changes are only reported until AcceptPrices is called.
+// Products missing from the catalog are reported as discontinued. Reduced quantities are
+// recorded in the activity as a system change. It returns whether any quantity was reduced,
+// i.e. whether the cart has to be saved.
+func (c *Cart) Revalidate(products map[uuid.UUID]*CatalogProduct) ([]*CartNotice, bool) {
+	var (
+		notices []*CartNotice
+		reduced bool
+	)
+	c.record(uuid.Nil, CartOperationStockAdjusted, func() error {
+		notices, reduced = c.revalidate(products)
+		return nil
+	})
+	return notices, reduced
+}
+
+// revalidate checks the items against the catalog without recording the change. The stock
+// of a product is shared by all its lines (e.g. one per variant), so it is allotted to the
+// lines in cart order and the lines past it are reduced.
+func (c *Cart) revalidate(products map[uuid.UUID]*CatalogProduct) ([]*CartNotice, bool) {
+	notices := make([]*CartNotice, 0)
+	reduced := false
+	allotted := make(map[uuid.UUID]int)
+
+	for _, item := range c.Items {
+		product, ok := products[item.ProductID]
+		if !ok || product.Discontinued {
+			notices = append(notices, item.notice(CartNoticeDiscontinued, fmt.Sprintf("%s is no longer available", item.Name)))
+			continue
+		}
+
+		available := product.Stock - allotted[item.ProductID]
+		if available <= 0 {
+			notices = append(notices, item.notice(CartNoticeOutOfStock, fmt.Sprintf("%s is out of stock", item.Name)))
+		} else if item.Quantity > available {
+			notice := item.notice(CartNoticeQuantityReduced, fmt.Sprintf("Only %d units of %s are available", product.Stock, item.Name))
+			notice.RequestedQuantity = item.Quantity
+			notice.AvailableQuantity = available
+			notices = append(notices, notice)
+
+			item.UpdateQuantity(uuid.Nil, available)
+			reduced = true
+		}
+		allotted[item.ProductID] += item.Quantity
+
+		if price := math.Round(product.Price*100) / 100; price != item.Price {
+			noticeType, verb := CartNoticePriceIncreased, "increased"
+			if price < item.Price {
+				noticeType, verb = CartNoticePriceDecreased, "decreased"
+			}
+			notice := item.notice(noticeType, fmt.Sprintf("The price of %s %s from %.2f to %.2f", item.Name, verb, item.Price, price))
+			notice.OldPrice = item.Price
+			notice.NewPrice = price
+			notices = append(notices, notice)
+		}
+	}
+
+	if reduced {
+		c.touch()
+	}
+
+	return notices, reduced
+}
+
+// AcceptPrices updates the items to the current catalog prices and returns how many changed
+func (c *Cart) AcceptPrices(products map[uuid.UUID]*CatalogProduct) int {
+	changed := 0
+	c.record(c.actor, CartOperationPricesAccepted, func() error {
+		changed = c.acceptPrices(products)
+		return nil
+	})
+	return changed
+}
+
+// acceptPrices updates the items to the current catalog prices without recording the change
+func (c *Cart) acceptPrices(products map[uuid.UUID]*CatalogProduct) int {
+	changed := 0
+	for _, item := range c.Items {
+		product, ok := products[item.ProductID]
+		if !ok || product.Discontinued {
+			continue
+		}
+		if price := math.Round(product.Price*100) / 100; price != item.Price {
+			item.Price = price
+			changed++
+		}
+	}
+
+	if changed > 0 {
+		c.touch()
+	}
+
+	return changed
+}
+
+// ProductIDs returns the distinct products in the cart
+func (c *Cart) ProductIDs() []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(c.Items))
+	ids := make([]uuid.UUID, 0, len(c.Items))
+	for _, item := range c.Items {
+		if !seen[item.ProductID] {
+			seen[item.ProductID] = true
+			ids = append(ids, item.ProductID)
+		}
+	}
+	return ids
+}
+
+// notice creates a notice about the item
+func (i *CartItem) notice(noticeType CartNoticeType, message string) *CartNotice {
+	return &CartNotice{
+		Type:      noticeType,
+		ItemID:    i.ID,
+		ProductID: i.ProductID,
+		Message:   message,
+	}
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// snapshotTokenBytes is the amount of random bytes in a snapshot token
+const snapshotTokenBytes = 24
+
+// CartSnapshot is an immutable copy of the lines of a cart at a point in time. Anyone with
+// its token can read it and clone it into their own cart. It is kept when the cart changes
+// or is deleted.
+type CartSnapshot struct {
+	ID        uuid.UUID       `json:"id"`
+	CartID    uuid.UUID       `json:"cartId"`
+	CreatedBy uuid.UUID       `json:"createdBy"`
+	Name      string          `json:"name"`
+	Token     string          `json:"token"`
+	Items     []*SnapshotItem `json:"items"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// SnapshotItem is a line of a cart snapshot
+type SnapshotItem struct {
+	ProductID uuid.UUID    `json:"productId"`
+	VariantID string       `json:"variantId,omitempty"`
+	Options   []ItemOption `json:"options,omitempty"`
+	Name      string       `json:"name"`
+	Price     float64      `json:"price"`
+	Quantity  int          `json:"quantity"`
+	ImageURL  string       `json:"imageUrl"`
+	Category  string       `json:"category,omitempty"`
+}
+
+// NewCartSnapshot takes a snapshot of the cart lines. Without a name, the cart name is used.
+// Saved items are left out.
+func NewCartSnapshot(cart *Cart, createdBy uuid.UUID, name string) (*CartSnapshot, error) {
+	if cart.IsEmpty() {
+		return nil, errors.New("cart is empty")
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = cart.Name
+	}
+	if len(name) > maxCartNameLength {
+		return nil, errors.New("cart name is too long")
+	}
+
+	token := make([]byte, snapshotTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+
+	items := make([]*SnapshotItem, len(cart.Items))
+	for i, item := range cart.Items {
+		items[i] = &SnapshotItem{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Options:   append([]ItemOption(nil), item.Options...),
+			Name:      item.Name,
+			Price:     item.Price,
+			Quantity:  item.Quantity,
+			ImageURL:  item.ImageURL,
+			Category:  item.Category,
+		}
+	}
+
+	return &CartSnapshot{
+		ID:        uuid.New(),
+		CartID:    cart.ID,
+		CreatedBy: createdBy,
+		Name:      name,
+		Token:     base64.RawURLEncoding.EncodeToString(token),
+		Items:     items,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// ImportLines returns the snapshot lines to be cloned into a cart
+func (s *CartSnapshot) ImportLines() []*ImportLine {
+	lines := make([]*ImportLine, len(s.Items))
+	for i, item := range s.Items {
+		lines[i] = &ImportLine{
+			Line:      i + 1,
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Options:   append([]ItemOption(nil), item.Options...),
+			Quantity:  item.Quantity,
+			ImageURL:  item.ImageURL,
+			Category:  item.Category,
+		}
+	}
+	return lines
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// MoveItemTo moves a quantity of a cart line to another cart of the same user, keeping its
+// variant and options. A quantity of zero moves the whole line. The target cart's purchase
+// rules are consulted as when the item is added. Both carts record the move in their activity.
+func (c *Cart) MoveItemTo(target *Cart, itemID uuid.UUID, quantity int) error {
+	return c.record(c.actor, CartOperationItemMovedOut, func() error {
+		return c.moveItemTo(target, itemID, quantity)
+	})
+}
+
+// moveItemTo moves a quantity of a cart line to another cart, recording only the target change
+func (c *Cart) moveItemTo(target *Cart, itemID uuid.UUID, quantity int) error {
+	if target.ID == c.ID {
+		return errors.New("cannot move an item to the same cart")
+	}
+	if target.UserID != c.UserID {
+		return errors.New("carts belong to different users")
+	}
+
+	item, err := c.GetItem(itemID)
+	if err != nil {
+		return err
+	}
+
+	if quantity == 0 {
+		quantity = item.Quantity
+	}
+	if quantity < 0 || quantity > item.Quantity {
+		return errors.New("invalid quantity to move")
+	}
+
+	if err := target.record(c.actor, CartOperationItemMovedIn, func() error {
+		return target.addItem(
+			item.AddedBy,
+			item.ProductID,
+			item.VariantID,
+			item.Options,
+			item.Name,
+			item.Price,
+			quantity,
+			item.ImageURL,
+			item.Category,
+		)
+	}); err != nil {
+		return err
+	}
+
+	if quantity == item.Quantity {
+		return c.removeItem(itemID)
+	}
+
+	if err := item.UpdateQuantity(c.actor, item.Quantity-quantity); err != nil {
+		return err
+	}
+	c.touch()
+	return nil
+}
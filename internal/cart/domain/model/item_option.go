@@ -0,0 +1,72 @@
+package model
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// maxOptionValueLength limits free-text option values such as engravings
+const maxOptionValueLength = 100
+
+// ItemOption represents a customization chosen for a cart item (size, color, engraving text).
+// Surcharge is added to the unit price.
+type ItemOption struct {
+	Name      string  `json:"name"`
+	Value     string  `json:"value"`
+	Surcharge float64 `json:"surcharge,omitempty"`
+}
+
+// NormalizeOptions validates a set of item options and returns them sorted by name,
+// so that the same options always compare equal regardless of the order they were given in
+func NormalizeOptions(options []ItemOption) ([]ItemOption, error) {
+	normalized := make([]ItemOption, 0, len(options))
+	seen := make(map[string]bool, len(options))
+
+	for _, option := range options {
+		name := strings.ToLower(strings.TrimSpace(option.Name))
+		value := strings.TrimSpace(option.Value)
+
+		if name == "" {
+			return nil, errors.New("option name is required")
+		}
+		if value == "" {
+			return nil, errors.New("option value is required")
+		}
+		if len(value) > maxOptionValueLength {
+			return nil, errors.New("option value is too long")
+		}
+		if option.Surcharge < 0 {
+			return nil, errors.New("option surcharge cannot be negative")
+		}
+		if seen[name] {
+			return nil, errors.New("duplicate option")
+		}
+		seen[name] = true
+
+		normalized = append(normalized, ItemOption{
+			Name:      name,
+			Value:     value,
+			Surcharge: option.Surcharge,
+		})
+	}
+
+	sort.Slice(normalized, func(i, j int) bool {
+		return normalized[i].Name < normalized[j].Name
+	})
+
+	return normalized, nil
+}
+
+// sameOptions returns true if both normalized option sets have the same names and values
+func sameOptions(a, b []ItemOption) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || a[i].Value != b[i].Value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,181 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RuleViolationCode identifies the purchase rule broken by a cart change
+type RuleViolationCode string
+
+const (
+	RuleViolationMaxPerOrder      RuleViolationCode = "MAX_PER_ORDER"
+	RuleViolationMaxPerUser       RuleViolationCode = "MAX_PER_USER"
+	RuleViolationMinQuantity      RuleViolationCode = "MIN_QUANTITY"
+	RuleViolationPackMultiple     RuleViolationCode = "PACK_MULTIPLE"
+	RuleViolationMaxDistinctLines RuleViolationCode = "MAX_DISTINCT_LINES"
+)
+
+// ProductPurchaseRule limits the quantity of a product that can be bought. Zero values disable a limit.
+type ProductPurchaseRule struct {
+	ProductID uuid.UUID `json:"productId"`
+	// MaxPerOrder is the largest quantity allowed in a single cart
+	MaxPerOrder int `json:"maxPerOrder"`
+	// MaxPerUser is the largest quantity a user can buy within MaxPerUserWindow
+	MaxPerUser       int           `json:"maxPerUser"`
+	MaxPerUserWindow time.Duration `json:"maxPerUserWindow"`
+	// MinQuantity is the smallest quantity that can be ordered
+	MinQuantity int `json:"minQuantity"`
+	// PackSize forces quantities to be a multiple of it (e.g. sold in 6s)
+	PackSize  int       `json:"packSize"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// NewProductPurchaseRule creates a purchase rule for a product, validating that its limits are consistent
+func NewProductPurchaseRule(productID uuid.UUID, maxPerOrder, maxPerUser int, maxPerUserWindow time.Duration, minQuantity, packSize int) (*ProductPurchaseRule, error) {
+	if productID == uuid.Nil {
+		return nil, errors.New("product ID is required")
+	}
+	if maxPerOrder < 0 || maxPerUser < 0 || minQuantity < 0 || packSize < 0 || maxPerUserWindow < 0 {
+		return nil, errors.New("purchase limits cannot be negative")
+	}
+	if maxPerUser > 0 && maxPerUserWindow == 0 {
+		return nil, errors.New("max per user requires a time window")
+	}
+	if maxPerOrder > 0 && minQuantity > maxPerOrder {
+		return nil, errors.New("min quantity cannot be greater than max per order")
+	}
+	if packSize > 1 && maxPerOrder > 0 && maxPerOrder < packSize {
+		return nil, errors.New("max per order cannot be lower than the pack size")
+	}
+
+	return &ProductPurchaseRule{
+		ProductID:        productID,
+		MaxPerOrder:      maxPerOrder,
+		MaxPerUser:       maxPerUser,
+		MaxPerUserWindow: maxPerUserWindow,
+		MinQuantity:      minQuantity,
+		PackSize:         packSize,
+		UpdatedAt:        time.Now(),
+	}, nil
+}
+
+// PurchaseRules is the rule set consulted by the cart when a line is added or changed
+type PurchaseRules struct {
+	// Products holds the rules of the products involved in the change
+	Products map[uuid.UUID]*ProductPurchaseRule
+	// Purchased holds the quantity of each product the user bought within the rule's window
+	Purchased map[uuid.UUID]int
+	// MaxDistinctLines is the largest number of lines a cart can have; zero disables the limit
+	MaxDistinctLines int
+}
+
+// RuleViolation describes a purchase rule broken by a cart change
+type RuleViolation struct {
+	Code      RuleViolationCode `json:"code"`
+	ProductID uuid.UUID         `json:"productId"`
+	Message   string            `json:"message"`
+	Limit     int               `json:"limit"`
+	Quantity  int               `json:"quantity"`
+}
+
+// RuleViolationError is returned when a cart change breaks one or more purchase rules
+type RuleViolationError struct {
+	Violations []*RuleViolation
+}
+
+// Error implements the error interface
+func (e *RuleViolationError) Error() string {
+	return "cart violates purchase rules"
+}
+
+// Check validates the cart lines of a product, and the number of lines, against the rules
+func (r *PurchaseRules) Check(cart *Cart, productID uuid.UUID) error {
+	violations := append(r.lineViolations(cart, productID), r.productViolations(cart, productID)...)
+	if len(violations) > 0 {
+		return &RuleViolationError{Violations: violations}
+	}
+	return nil
+}
+
+// CheckPurchase validates the quantities of an order about to be placed, by product, against the
+// product rules and what the user already bought. A user can spread a product over several carts
+// that each respect the limits, so the order is checked again when it is placed.
+func (r *PurchaseRules) CheckPurchase(quantities map[uuid.UUID]int) error {
+	productIDs := make([]uuid.UUID, 0, len(quantities))
+	for productID := range quantities {
+		productIDs = append(productIDs, productID)
+	}
+	sort.Slice(productIDs, func(i, j int) bool { return productIDs[i].String() < productIDs[j].String() })
+
+	var violations []*RuleViolation
+	for _, productID := range productIDs {
+		if rule, ok := r.Products[productID]; ok {
+			violations = append(violations, rule.check(quantities[productID], r.Purchased[productID])...)
+		}
+	}
+	if len(violations) > 0 {
+		return &RuleViolationError{Violations: violations}
+	}
+	return nil
+}
+
+// lineViolations validates the number of lines of the cart, reporting the product that was changed
+func (r *PurchaseRules) lineViolations(cart *Cart, productID uuid.UUID) []*RuleViolation {
+	if r.MaxDistinctLines == 0 || len(cart.Items) <= r.MaxDistinctLines {
+		return nil
+	}
+	return []*RuleViolation{{
+		Code:      RuleViolationMaxDistinctLines,
+		ProductID: productID,
+		Message:   fmt.Sprintf("A cart can have at most %d different items", r.MaxDistinctLines),
+		Limit:     r.MaxDistinctLines,
+		Quantity:  len(cart.Items),
+	}}
+}
+
+// productViolations validates the cart lines of a product against its rule, if any
+func (r *PurchaseRules) productViolations(cart *Cart, productID uuid.UUID) []*RuleViolation {
+	rule, ok := r.Products[productID]
+	if !ok {
+		return nil
+	}
+	return rule.check(cart.ProductQuantity(productID), r.Purchased[productID])
+}
+
+// check validates the quantity of the product in a cart, given what the user already bought
+func (p *ProductPurchaseRule) check(quantity, purchased int) []*RuleViolation {
+	var violations []*RuleViolation
+	violation := func(code RuleViolationCode, limit int, message string) {
+		violations = append(violations, &RuleViolation{
+			Code:      code,
+			ProductID: p.ProductID,
+			Message:   message,
+			Limit:     limit,
+			Quantity:  quantity,
+		})
+	}
+
+	if p.MinQuantity > 0 && quantity < p.MinQuantity {
+		violation(RuleViolationMinQuantity, p.MinQuantity, fmt.Sprintf("At least %d units must be ordered", p.MinQuantity))
+	}
+	if p.PackSize > 1 && quantity%p.PackSize != 0 {
+		violation(RuleViolationPackMultiple, p.PackSize, fmt.Sprintf("Sold in packs of %d units", p.PackSize))
+	}
+	if p.MaxPerOrder > 0 && quantity > p.MaxPerOrder {
+		violation(RuleViolationMaxPerOrder, p.MaxPerOrder, fmt.Sprintf("At most %d units can be ordered", p.MaxPerOrder))
+	}
+	if p.MaxPerUser > 0 && purchased+quantity > p.MaxPerUser {
+		remaining := p.MaxPerUser - purchased
+		if remaining < 0 {
+			remaining = 0
+		}
+		violation(RuleViolationMaxPerUser, remaining, fmt.Sprintf("You can buy %d more units of this product for now", remaining))
+	}
+
+	return violations
+}
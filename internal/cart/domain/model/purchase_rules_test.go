@@ -0,0 +1,172 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// violationCodes returns the codes of the violations of a purchase rule error, or nil if there is none
+func violationCodes(t *testing.T, err error) []RuleViolationCode {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	violationErr, ok := err.(*RuleViolationError)
+	if !ok {
+		t.Fatalf("error = %v, want a RuleViolationError", err)
+	}
+	codes := make([]RuleViolationCode, len(violationErr.Violations))
+	for i, violation := range violationErr.Violations {
+		codes[i] = violation.Code
+	}
+	return codes
+}
+
+// equalCodes reports whether two lists of violation codes are the same
+func equalCodes(got, want []RuleViolationCode) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// cartWithLines returns a cart with a line per quantity of the given product, plus the extra lines
+func cartWithLines(productID uuid.UUID, quantities []int, extraLines int) *Cart {
+	cart := &Cart{ID: uuid.New(), UserID: uuid.New()}
+	for _, quantity := range quantities {
+		cart.Items = append(cart.Items, &CartItem{ID: uuid.New(), ProductID: productID, Quantity: quantity})
+	}
+	for i := 0; i < extraLines; i++ {
+		cart.Items = append(cart.Items, &CartItem{ID: uuid.New(), ProductID: uuid.New(), Quantity: 1})
+	}
+	return cart
+}
+
+func TestPurchaseRulesCheck(t *testing.T) {
+	productID := uuid.New()
+
+	tests := []struct {
+		name             string
+		rule             *ProductPurchaseRule
+		quantities       []int
+		extraLines       int
+		purchased        int
+		maxDistinctLines int
+		want             []RuleViolationCode
+	}{
+		{name: "no rule", quantities: []int{7}},
+		{name: "pack multiple", rule: &ProductPurchaseRule{PackSize: 6}, quantities: []int{12}},
+		{name: "not a pack multiple", rule: &ProductPurchaseRule{PackSize: 6}, quantities: []int{8}, want: []RuleViolationCode{RuleViolationPackMultiple}},
+		{name: "pack multiple across variant lines", rule: &ProductPurchaseRule{PackSize: 6}, quantities: []int{4, 2}},
+		{name: "lines adding up to a partial pack", rule: &ProductPurchaseRule{PackSize: 6}, quantities: []int{6, 3}, want: []RuleViolationCode{RuleViolationPackMultiple}},
+		{name: "pack size of one", rule: &ProductPurchaseRule{PackSize: 1}, quantities: []int{5}},
+		{name: "below min quantity", rule: &ProductPurchaseRule{MinQuantity: 3}, quantities: []int{2}, want: []RuleViolationCode{RuleViolationMinQuantity}},
+		{name: "above max per order", rule: &ProductPurchaseRule{MaxPerOrder: 12, PackSize: 6}, quantities: []int{18}, want: []RuleViolationCode{RuleViolationMaxPerOrder}},
+		{name: "partial pack above max per order", rule: &ProductPurchaseRule{MaxPerOrder: 12, PackSize: 6}, quantities: []int{13}, want: []RuleViolationCode{RuleViolationPackMultiple, RuleViolationMaxPerOrder}},
+		{name: "within max per user", rule: &ProductPurchaseRule{MaxPerUser: 10, MaxPerUserWindow: time.Hour}, quantities: []int{4}, purchased: 6},
+		{name: "above max per user", rule: &ProductPurchaseRule{MaxPerUser: 10, MaxPerUserWindow: time.Hour}, quantities: []int{5}, purchased: 6, want: []RuleViolationCode{RuleViolationMaxPerUser}},
+		{name: "lines at the limit", quantities: []int{1}, extraLines: 2, maxDistinctLines: 3},
+		{name: "too many lines", quantities: []int{1}, extraLines: 3, maxDistinctLines: 3, want: []RuleViolationCode{RuleViolationMaxDistinctLines}},
+		{name: "variant lines count as distinct lines", quantities: []int{1, 1}, extraLines: 2, maxDistinctLines: 3, want: []RuleViolationCode{RuleViolationMaxDistinctLines}},
+		{name: "line limit disabled", quantities: []int{1}, extraLines: 100},
+		{name: "too many lines and not a pack multiple", rule: &ProductPurchaseRule{PackSize: 6}, quantities: []int{5}, extraLines: 1, maxDistinctLines: 1, want: []RuleViolationCode{RuleViolationMaxDistinctLines, RuleViolationPackMultiple}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := &PurchaseRules{
+				Products:         map[uuid.UUID]*ProductPurchaseRule{},
+				Purchased:        map[uuid.UUID]int{productID: tt.purchased},
+				MaxDistinctLines: tt.maxDistinctLines,
+			}
+			if tt.rule != nil {
+				tt.rule.ProductID = productID
+				rules.Products[productID] = tt.rule
+			}
+
+			err := rules.Check(cartWithLines(productID, tt.quantities, tt.extraLines), productID)
+			if got := violationCodes(t, err); !equalCodes(got, tt.want) {
+				t.Errorf("Check() violations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPurchaseRulesCheckPurchase(t *testing.T) {
+	limited := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	packed := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	free := uuid.MustParse("00000000-0000-0000-0000-000000000003")
+
+	rules := &PurchaseRules{
+		Products: map[uuid.UUID]*ProductPurchaseRule{
+			limited: {ProductID: limited, MaxPerUser: 4, MaxPerUserWindow: 24 * time.Hour},
+			packed:  {ProductID: packed, PackSize: 6},
+		},
+		Purchased:        map[uuid.UUID]int{limited: 3},
+		MaxDistinctLines: 1,
+	}
+
+	tests := []struct {
+		name       string
+		quantities map[uuid.UUID]int
+		want       []RuleViolationCode
+	}{
+		{name: "within the limits", quantities: map[uuid.UUID]int{limited: 1, packed: 6, free: 50}},
+		{name: "per user limit reached with another cart", quantities: map[uuid.UUID]int{limited: 2}, want: []RuleViolationCode{RuleViolationMaxPerUser}},
+		{name: "partial pack", quantities: map[uuid.UUID]int{packed: 9}, want: []RuleViolationCode{RuleViolationPackMultiple}},
+		{
+			name:       "violations in product order",
+			quantities: map[uuid.UUID]int{packed: 1, limited: 5},
+			want:       []RuleViolationCode{RuleViolationMaxPerUser, RuleViolationPackMultiple},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := rules.CheckPurchase(tt.quantities)
+			if got := violationCodes(t, err); !equalCodes(got, tt.want) {
+				t.Errorf("CheckPurchase() violations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewProductPurchaseRule(t *testing.T) {
+	tests := []struct {
+		name        string
+		productID   uuid.UUID
+		maxPerOrder int
+		maxPerUser  int
+		window      time.Duration
+		minQuantity int
+		packSize    int
+		wantErr     string
+	}{
+		{name: "every limit", productID: uuid.New(), maxPerOrder: 12, maxPerUser: 24, window: 24 * time.Hour, minQuantity: 6, packSize: 6},
+		{name: "no limits", productID: uuid.New()},
+		{name: "missing product", wantErr: "product ID is required"},
+		{name: "negative pack size", productID: uuid.New(), packSize: -6, wantErr: "purchase limits cannot be negative"},
+		{name: "max per user without window", productID: uuid.New(), maxPerUser: 5, wantErr: "max per user requires a time window"},
+		{name: "min above max per order", productID: uuid.New(), maxPerOrder: 2, minQuantity: 3, wantErr: "min quantity cannot be greater than max per order"},
+		{name: "max per order below pack size", productID: uuid.New(), maxPerOrder: 4, packSize: 6, wantErr: "max per order cannot be lower than the pack size"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewProductPurchaseRule(tt.productID, tt.maxPerOrder, tt.maxPerUser, tt.window, tt.minQuantity, tt.packSize)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("NewProductPurchaseRule() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("NewProductPurchaseRule() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
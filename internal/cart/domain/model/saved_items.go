@@ -0,0 +1,119 @@
+package model
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// MoveToSaved moves a cart item to the saved-for-later list, keeping its quantity and options.
+// If the same line is already saved, the quantities are merged.
+func (c *Cart) MoveToSaved(itemID uuid.UUID) error {
+	return c.record(c.actor, CartOperationItemSaved, func() error {
+		return c.moveToSaved(itemID)
+	})
+}
+
+// moveToSaved moves a cart item to the saved-for-later list without recording the change
+func (c *Cart) moveToSaved(itemID uuid.UUID) error {
+	index := indexOfItem(c.Items, itemID)
+	if index < 0 {
+		return errors.New("item not found in cart")
+	}
+	item := c.Items[index]
+
+	c.Items = append(c.Items[:index], c.Items[index+1:]...)
+	if saved := findLine(c.SavedItems, item); saved != nil {
+		saved.merge(item)
+	} else {
+		c.SavedItems = append(c.SavedItems, item)
+	}
+
+	c.touch()
+	return nil
+}
+
+// MoveToCart moves a saved item back to the cart, keeping its quantity and options. If the
+// same line is already in the cart, the quantities are merged. The purchase rules are
+// consulted as when the item is added.
+func (c *Cart) MoveToCart(itemID uuid.UUID) error {
+	return c.record(c.actor, CartOperationItemMovedToCart, func() error {
+		return c.moveToCart(itemID)
+	})
+}
+
+// moveToCart moves a saved item back to the cart without recording the change
+func (c *Cart) moveToCart(itemID uuid.UUID) error {
+	index := indexOfItem(c.SavedItems, itemID)
+	if index < 0 {
+		return errors.New("item not found in saved items")
+	}
+	item := c.SavedItems[index]
+
+	if line := findLine(c.Items, item); line != nil {
+		previous := *line
+		line.merge(item)
+		if err := c.checkRules(item.ProductID); err != nil {
+			*line = previous
+			return err
+		}
+	} else {
+		c.Items = append(c.Items, item)
+		if err := c.checkRules(item.ProductID); err != nil {
+			c.Items = c.Items[:len(c.Items)-1]
+			return err
+		}
+	}
+
+	c.SavedItems = append(c.SavedItems[:index], c.SavedItems[index+1:]...)
+	c.touch()
+	return nil
+}
+
+// RemoveSavedItem removes an item from the saved-for-later list
+func (c *Cart) RemoveSavedItem(itemID uuid.UUID) error {
+	return c.record(c.actor, CartOperationSavedItemRemoved, func() error {
+		return c.removeSavedItem(itemID)
+	})
+}
+
+// removeSavedItem removes an item from the saved-for-later list without recording the change
+func (c *Cart) removeSavedItem(itemID uuid.UUID) error {
+	index := indexOfItem(c.SavedItems, itemID)
+	if index < 0 {
+		return errors.New("item not found in saved items")
+	}
+
+	c.SavedItems = append(c.SavedItems[:index], c.SavedItems[index+1:]...)
+	c.touch()
+	return nil
+}
+
+// GetSavedItem returns a saved item by ID
+func (c *Cart) GetSavedItem(itemID uuid.UUID) (*CartItem, error) {
+	index := indexOfItem(c.SavedItems, itemID)
+	if index < 0 {
+		return nil, errors.New("item not found in saved items")
+	}
+	return c.SavedItems[index], nil
+}
+
+// indexOfItem returns the position of an item in a list, or -1 if it is not there
+func indexOfItem(items []*CartItem, itemID uuid.UUID) int {
+	for i, item := range items {
+		if item.ID == itemID {
+			return i
+		}
+	}
+	return -1
+}
+
+// findLine returns the item of a list that is the same line (product, variant and options) as the given one
+func findLine(items []*CartItem, item *CartItem) *CartItem {
+	for _, candidate := range items {
+		if candidate.IsSameLine(item.ProductID, item.VariantID, item.Options) {
+			return candidate
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
@@ -15,9 +16,78 @@ type CartRepository interface {
 	// FindByUserID retrieves the current active cart for a user
 	FindByUserID(ctx context.Context, userID uuid.UUID) (*model.Cart, error)
 
-	// Save persists a cart (creates or updates)
+	// FindAllByUserID retrieves every cart of a user
+	FindAllByUserID(ctx context.Context, userID uuid.UUID) ([]*model.Cart, error)
+
+	// FindSharedWithUser retrieves the carts other users shared with the user
+	FindSharedWithUser(ctx context.Context, userID uuid.UUID) ([]*model.Cart, error)
+
+	// FindByProductID retrieves every cart that contains the product
+	FindByProductID(ctx context.Context, productID uuid.UUID) ([]*model.Cart, error)
+
+	// FindAbandoned retrieves the carts with items last changed in [from, to) that were not
+	// checked out since
+	FindAbandoned(ctx context.Context, from, to time.Time) ([]*model.Cart, error)
+
+	// Save persists a cart (creates or updates), its items and its members
 	Save(ctx context.Context, cart *model.Cart) error
 
+	// SaveAll persists several carts in a single transaction
+	SaveAll(ctx context.Context, carts ...*model.Cart) error
+
+	// SetActive makes a cart the active cart of its user, deactivating the others
+	SetActive(ctx context.Context, userID uuid.UUID, cartID uuid.UUID) error
+
 	// Delete removes a cart
 	Delete(ctx context.Context, id uuid.UUID) error
 }
+
+// CartInvitationRepository defines the interface for cart invitation persistence operations
+type CartInvitationRepository interface {
+	// FindByID retrieves an invitation by its ID
+	FindByID(ctx context.Context, id uuid.UUID) (*model.CartInvitation, error)
+
+	// FindByCartID retrieves every invitation to a cart
+	FindByCartID(ctx context.Context, cartID uuid.UUID) ([]*model.CartInvitation, error)
+
+	// FindPendingByInviteeID retrieves the invitations a user has not answered yet
+	FindPendingByInviteeID(ctx context.Context, inviteeID uuid.UUID) ([]*model.CartInvitation, error)
+
+	// Save persists an invitation (creates or updates)
+	Save(ctx context.Context, invitation *model.CartInvitation) error
+}
+
+// CartActivityRepository defines the interface for reading the activity of carts. Entries
+// are appended when the cart is saved.
+type CartActivityRepository interface {
+	// FindByCartID retrieves the activity of a cart, newest first
+	FindByCartID(ctx context.Context, cartID uuid.UUID) ([]*model.CartActivity, error)
+}
+
+// CartSnapshotRepository defines the interface for cart snapshot persistence operations.
+// Snapshots are immutable, so they are only created.
+type CartSnapshotRepository interface {
+	// FindByToken retrieves a snapshot by its share token
+	FindByToken(ctx context.Context, token string) (*model.CartSnapshot, error)
+
+	// FindByCartID retrieves the snapshots taken of a cart, newest first
+	FindByCartID(ctx context.Context, cartID uuid.UUID) ([]*model.CartSnapshot, error)
+
+	// Create persists a new snapshot
+	Create(ctx context.Context, snapshot *model.CartSnapshot) error
+}
+
+// ProductBarcodeRepository defines the interface for the barcodes and SKUs read by the kiosk scanner
+type ProductBarcodeRepository interface {
+	// FindByCode retrieves the product of a normalized code
+	FindByCode(ctx context.Context, code string) (*model.ProductBarcode, error)
+
+	// FindAll retrieves every code
+	FindAll(ctx context.Context) ([]*model.ProductBarcode, error)
+
+	// Save persists a code (creates or updates)
+	Save(ctx context.Context, barcode *model.ProductBarcode) error
+
+	// Delete removes a code
+	Delete(ctx context.Context, code string) error
+}
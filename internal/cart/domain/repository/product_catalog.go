@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+)
+
+// ProductCatalog defines the interface to read current product prices and stock
+type ProductCatalog interface {
+	// FindProducts retrieves the catalog data of the given products. Products that do not
+	// exist anymore are left out of the result.
+	FindProducts(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]*model.CatalogProduct, error)
+}
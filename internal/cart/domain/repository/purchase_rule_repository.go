@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+)
+
+// PurchaseRuleRepository defines the interface for product purchase rule persistence operations
+type PurchaseRuleRepository interface {
+	// FindByProductID retrieves the purchase rule of a product
+	FindByProductID(ctx context.Context, productID uuid.UUID) (*model.ProductPurchaseRule, error)
+
+	// FindAll retrieves every purchase rule
+	FindAll(ctx context.Context) ([]*model.ProductPurchaseRule, error)
+
+	// Save persists a purchase rule (creates or updates)
+	Save(ctx context.Context, rule *model.ProductPurchaseRule) error
+
+	// Delete removes the purchase rule of a product
+	Delete(ctx context.Context, productID uuid.UUID) error
+}
+
+// PurchaseHistory gives access to the quantities a user already bought
+type PurchaseHistory interface {
+	// QuantityPurchased returns the quantity of a product the user bought since the given time
+	QuantityPurchased(ctx context.Context, userID, productID uuid.UUID, since time.Time) (int, error)
+}
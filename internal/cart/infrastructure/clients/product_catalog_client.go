@@ -0,0 +1,125 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/repository"
+)
+
+// productResponse is the product representation returned by the product catalog service
+type productResponse struct {
+	ID     uuid.UUID `json:"id"`
+	Name   string    `json:"name"`
+	Price  float64   `json:"price"`
+	Stock  int       `json:"stock"`
+	Status string    `json:"status"`
+}
+
+// maxConcurrentLookups bounds the product requests sent to the catalog at the same time
+const maxConcurrentLookups = 8
+
+// ProductCatalogClient implements the ProductCatalog interface using the product catalog HTTP API
+type ProductCatalogClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewProductCatalogClient creates a new client for the product catalog service
+func NewProductCatalogClient(baseURL string) repository.ProductCatalog {
+	return &ProductCatalogClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+}
+
+// FindProducts retrieves the catalog data of the given products. The catalog has no batch
+// endpoint, so the products are requested in parallel; the first failure cancels the rest.
+func (c *ProductCatalogClient) FindProducts(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]*model.CatalogProduct, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	products := make(map[uuid.UUID]*model.CatalogProduct, len(productIDs))
+	slots := make(chan struct{}, maxConcurrentLookups)
+
+	for _, productID := range productIDs {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(productID uuid.UUID) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			product, err := c.findProduct(ctx, productID)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			if product != nil {
+				products[productID] = product
+			}
+		}(productID)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return products, nil
+}
+
+// findProduct retrieves a single product, returning nil if the catalog does not know it
+func (c *ProductCatalogClient) findProduct(ctx context.Context, productID uuid.UUID) (*model.CatalogProduct, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/products/%s", c.baseURL, productID), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("product catalog request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("product catalog returned status %d", resp.StatusCode)
+	}
+
+	var body productResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid product catalog response: %w", err)
+	}
+
+	status := strings.ToUpper(body.Status)
+	return &model.CatalogProduct{
+		ProductID:    productID,
+		Name:         body.Name,
+		Price:        body.Price,
+		Discontinued: status == "DISCONTINUED" || status == "INACTIVE",
+		Stock:        body.Stock,
+	}, nil
+}
@@ -0,0 +1,32 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
+)
+
+// actorHeader names the user on whose behalf a cart request is made
+const actorHeader = "X-User-ID"
+
+// withActor puts the user named in the actor header in the request context. Requests
+// without it are rejected, so they never act as the cart owner by default.
+func withActor(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get(actorHeader)
+		if header == "" {
+			errors.WriteErrorResponse(w, http.StatusUnauthorized, "user ID is required")
+			return
+		}
+
+		userID, err := uuid.Parse(header)
+		if err != nil {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, "invalid user ID format")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(services.WithActor(r.Context(), userID)))
+	})
+}
@@ -0,0 +1,161 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
+)
+
+// barcodeBadRequestErrors are the barcode errors caused by an invalid request
+var barcodeBadRequestErrors = map[string]bool{
+	"barcode is required":       true,
+	"invalid barcode":           true,
+	"invalid barcode checksum":  true,
+	"invalid product ID format": true,
+	"product ID is required":    true,
+}
+
+// BarcodeHandler handles HTTP requests for the barcodes and SKUs of the kiosk scanner
+type BarcodeHandler struct {
+	barcodeService *services.BarcodeService
+	requireAdmin   func(http.Handler) http.Handler
+}
+
+// NewBarcodeHandler creates a new barcode handler. Setting and deleting barcodes go through
+// requireAdmin, since the catalog codes are managed from the back office.
+func NewBarcodeHandler(barcodeService *services.BarcodeService, requireAdmin func(http.Handler) http.Handler) *BarcodeHandler {
+	return &BarcodeHandler{
+		barcodeService: barcodeService,
+		requireAdmin:   requireAdmin,
+	}
+}
+
+// RegisterRoutes registers the barcode routes on the given router
+func (h *BarcodeHandler) RegisterRoutes(router *mux.Router) {
+	barcodeRouter := router.PathPrefix("/barcodes").Subrouter()
+
+	barcodeRouter.HandleFunc("", h.ListBarcodes).Methods("GET")
+	barcodeRouter.HandleFunc("/{code}", h.GetBarcode).Methods("GET")
+
+	// Barcodes are only changed from the back office
+	adminRouter := barcodeRouter.NewRoute().Subrouter()
+	adminRouter.Use(h.requireAdmin)
+
+	adminRouter.HandleFunc("/{code}", h.SetBarcode).Methods("PUT")
+	adminRouter.HandleFunc("/{code}", h.DeleteBarcode).Methods("DELETE")
+}
+
+// ListBarcodes handles the request to list the barcodes
+// @Summary List barcodes
+// @Description List the barcodes and internal SKUs the kiosk scanner resolves to products
+// @Tags barcodes
+// @Produce json
+// @Success 200 {array} dto.ProductBarcodeDTO "Barcodes retrieved successfully"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/barcodes [get]
+func (h *BarcodeHandler) ListBarcodes(w http.ResponseWriter, r *http.Request) {
+	barcodes, err := h.barcodeService.ListBarcodes(r.Context())
+	if err != nil {
+		errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(barcodes)
+}
+
+// GetBarcode handles the request to get the product of a barcode
+// @Summary Get a barcode
+// @Description Get the product an EAN-13, UPC-A, EAN-8 barcode or internal SKU identifies
+// @Tags barcodes
+// @Produce json
+// @Param code path string true "Barcode or SKU"
+// @Success 200 {object} dto.ProductBarcodeDTO "Barcode retrieved successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid barcode"
+// @Failure 404 {object} errors.ErrorResponse "Unknown barcode"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/barcodes/{code} [get]
+func (h *BarcodeHandler) GetBarcode(w http.ResponseWriter, r *http.Request) {
+	barcode, err := h.barcodeService.GetBarcode(r.Context(), mux.Vars(r)["code"])
+	if err != nil {
+		if err.Error() == "unknown barcode" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if barcodeBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(barcode)
+}
+
+// SetBarcode handles the request to map a barcode to a product
+// @Summary Set a barcode
+// @Description Map an EAN-13, UPC-A, EAN-8 barcode or internal SKU to a product and variant, replacing the previous mapping. Barcodes must have a valid check digit.
+// @Tags barcodes
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param code path string true "Barcode or SKU"
+// @Param request body dto.ProductBarcodeRequest true "Product"
+// @Success 200 {object} dto.ProductBarcodeDTO "Barcode saved successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/barcodes/{code} [put]
+func (h *BarcodeHandler) SetBarcode(w http.ResponseWriter, r *http.Request) {
+	var req dto.ProductBarcodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	barcode, err := h.barcodeService.SetBarcode(r.Context(), mux.Vars(r)["code"], &req)
+	if err != nil {
+		if barcodeBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(barcode)
+}
+
+// DeleteBarcode handles the request to delete a barcode
+// @Summary Delete a barcode
+// @Description Remove the mapping of a barcode or SKU to its product
+// @Tags barcodes
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param code path string true "Barcode or SKU"
+// @Success 204 "Barcode deleted successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid barcode"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 404 {object} errors.ErrorResponse "Unknown barcode"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/barcodes/{code} [delete]
+func (h *BarcodeHandler) DeleteBarcode(w http.ResponseWriter, r *http.Request) {
+	if err := h.barcodeService.DeleteBarcode(r.Context(), mux.Vars(r)["code"]); err != nil {
+		if err.Error() == "unknown barcode" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if barcodeBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,148 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
+)
+
+// Export file formats
+const (
+	exportFormatJSON = "json"
+	exportFormatCSV  = "csv"
+)
+
+// cartImportBadRequestErrors are the import errors caused by an invalid file
+var cartImportBadRequestErrors = map[string]bool{
+	"invalid cart ID format":      true,
+	"invalid format":              true,
+	"the file has no lines":       true,
+	"the file has too many lines": true,
+}
+
+// ExportCart handles the request to export the lines of a cart to a file
+// @Summary Export cart
+// @Description Export the cart lines (product, variant, quantity) to JSON or CSV. Options are only included in JSON; saved items are left out.
+// @Tags carts
+// @Produce json
+// @Produce text/csv
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Param format query string false "File format" Enums(json, csv) default(json)
+// @Success 200 {object} dto.CartExport "Exported cart"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/export [get]
+func (h *CartHandler) ExportCart(w http.ResponseWriter, r *http.Request) {
+	cartID := mux.Vars(r)["cartId"]
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = exportFormatJSON
+	}
+	if format != exportFormatJSON && format != exportFormatCSV {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "invalid format")
+		return
+	}
+
+	export, err := h.cartService.ExportCart(r.Context(), cartID)
+	if err != nil {
+		if err.Error() == "cart not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, "Cart not found")
+		} else if err.Error() == "invalid cart ID format" {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"cart-%s.%s\"", cartID, format))
+	if format == exportFormatCSV {
+		w.Header().Set("Content-Type", "text/csv")
+		export.WriteCSV(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(export)
+}
+
+// ImportCart handles the request to add the lines of an exported file to a cart
+// @Summary Import cart
+// @Description Add the lines of a JSON or CSV export to a cart. Each line is revalidated against the catalog and the purchase rules, taking the current name and price; rejected lines are reported and do not stop the others.
+// @Tags carts
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Param format query string false "File format; defaults to csv for text/csv bodies and json otherwise" Enums(json, csv)
+// @Param replace query bool false "Remove the cart items before importing"
+// @Param request body dto.CartExport true "Exported cart"
+// @Success 200 {object} dto.CartImportResponse "Cart imported"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/import [post]
+func (h *CartHandler) ImportCart(w http.ResponseWriter, r *http.Request) {
+	cartID := mux.Vars(r)["cartId"]
+	replace := r.URL.Query().Get("replace") == "true"
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = exportFormatJSON
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
+			format = exportFormatCSV
+		}
+	}
+
+	var export *dto.CartExport
+	switch format {
+	case exportFormatJSON:
+		export = &dto.CartExport{}
+		if err := json.NewDecoder(r.Body).Decode(export); err != nil {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+	case exportFormatCSV:
+		var err error
+		export, err = dto.CartExportFromCSV(r.Body)
+		if err != nil {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	default:
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "invalid format")
+		return
+	}
+
+	response, err := h.cartService.ImportCart(r.Context(), cartID, export, replace)
+	if err != nil {
+		if err.Error() == "cart not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, "Cart not found")
+		} else if cartImportBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -7,9 +7,39 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
 )
 
+// scanConflictErrors are the scan errors caused by the catalog state of the product
+var scanConflictErrors = map[string]bool{
+	"product is no longer available": true,
+	"product is out of stock":        true,
+}
+
+// undoConflictErrors are the undo errors caused by the state of the cart activity
+var undoConflictErrors = map[string]bool{
+	"nothing to undo":                    true,
+	"the last change cannot be undone":   true,
+	"cart changed since the last change": true,
+}
+
+// cartItemBadRequestErrors are the cart item errors caused by an invalid request
+var cartItemBadRequestErrors = map[string]bool{
+	"invalid cart ID format":              true,
+	"invalid item ID format":              true,
+	"invalid product ID format":           true,
+	"quantity must be greater than zero":  true,
+	"price cannot be negative":            true,
+	"option name is required":             true,
+	"option value is required":            true,
+	"option value is too long":            true,
+	"option surcharge cannot be negative": true,
+	"duplicate option":                    true,
+	"at least one operation is required":  true,
+	"too many operations":                 true,
+}
+
 // CartHandler handles HTTP requests for cart operations
 type CartHandler struct {
 	cartService *services.CartService
@@ -27,24 +57,47 @@ func (h *CartHandler) RegisterRoutes(router *mux.Router) {
 	// Create a subrouter for cart routes
 	cartRouter := router.PathPrefix("/carts").Subrouter()
 
+	// Requests act on behalf of the user named in the X-User-ID header
+	cartRouter.Use(withActor)
+
 	// Register routes
 	cartRouter.HandleFunc("", h.CreateCart).Methods("POST")
+	cartRouter.HandleFunc("", h.ListCarts).Methods("GET")
+	cartRouter.HandleFunc("/active", h.GetActiveCart).Methods("GET")
 	cartRouter.HandleFunc("/{cartId}", h.GetCart).Methods("GET")
+	cartRouter.HandleFunc("/{cartId}", h.RenameCart).Methods("PATCH")
+	cartRouter.HandleFunc("/{cartId}/activate", h.ActivateCart).Methods("POST")
 	cartRouter.HandleFunc("/{cartId}", h.DeleteCart).Methods("DELETE")
+	cartRouter.HandleFunc("/{cartId}/accept-prices", h.AcceptPrices).Methods("POST")
+	cartRouter.HandleFunc("/{cartId}/activity", h.GetActivity).Methods("GET")
+	cartRouter.HandleFunc("/{cartId}/undo", h.UndoLastChange).Methods("POST")
+	cartRouter.HandleFunc("/{cartId}/export", h.ExportCart).Methods("GET")
+	cartRouter.HandleFunc("/{cartId}/import", h.ImportCart).Methods("POST")
 	cartRouter.HandleFunc("/{cartId}/items", h.AddCartItem).Methods("POST")
+	cartRouter.HandleFunc("/{cartId}/items", h.ApplyItemOperations).Methods("PATCH")
+	cartRouter.HandleFunc("/{cartId}/items", h.ClearCart).Methods("DELETE")
+	cartRouter.HandleFunc("/{cartId}/scan", h.ScanItem).Methods("POST")
 	cartRouter.HandleFunc("/{cartId}/items/{itemId}", h.UpdateCartItem).Methods("PUT")
 	cartRouter.HandleFunc("/{cartId}/items/{itemId}", h.RemoveCartItem).Methods("DELETE")
+	cartRouter.HandleFunc("/{cartId}/items/{itemId}/move", h.MoveCartItem).Methods("POST")
+	cartRouter.HandleFunc("/{cartId}/items/{itemId}/save-for-later", h.SaveForLater).Methods("POST")
+	cartRouter.HandleFunc("/{cartId}/saved-items/{itemId}/move-to-cart", h.MoveToCart).Methods("POST")
+	cartRouter.HandleFunc("/{cartId}/saved-items/{itemId}", h.RemoveSavedItem).Methods("DELETE")
 }
 
 // CreateCart handles the request to create a new cart
 // @Summary Create a new cart
-// @Description Create a new shopping cart for a user
+// @Description Create a new shopping cart for a user. Without a name, the user's active cart is returned if there is one.
 // @Tags carts
 // @Accept json
 // @Produce json
+// @Param X-User-ID header string true "Acting user; must be the cart user" format(uuid)
 // @Param request body dto.CartCreateRequest true "Cart creation request"
 // @Success 201 {object} dto.CartResponse "Cart created successfully"
 // @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 409 {object} errors.ErrorResponse "Cart name already in use"
 // @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /api/carts [post]
 func (h *CartHandler) CreateCart(w http.ResponseWriter, r *http.Request) {
@@ -56,7 +109,15 @@ func (h *CartHandler) CreateCart(w http.ResponseWriter, r *http.Request) {
 
 	cart, err := h.cartService.CreateCart(r.Context(), &req)
 	if err != nil {
-		errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		if err.Error() == "cart name already in use" {
+			errors.WriteErrorResponse(w, http.StatusConflict, err.Error())
+		} else if cartItemBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
 
@@ -65,15 +126,164 @@ func (h *CartHandler) CreateCart(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(cart)
 }
 
+// ListCarts handles the request to list the carts of a user
+// @Summary List a user's carts
+// @Description List every named cart of a user, marking the active one
+// @Tags carts
+// @Produce json
+// @Param X-User-ID header string true "Acting user; must be the listed user" format(uuid)
+// @Param userId query string true "User ID" format(uuid)
+// @Success 200 {array} dto.CartSummary "Carts retrieved successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts [get]
+func (h *CartHandler) ListCarts(w http.ResponseWriter, r *http.Request) {
+	carts, err := h.cartService.ListCarts(r.Context(), r.URL.Query().Get("userId"))
+	if err != nil {
+		if cartItemBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(carts)
+}
+
+// GetActiveCart handles the request to get the active cart of a user
+// @Summary Get a user's active cart
+// @Description Get the cart the user is currently shopping with
+// @Tags carts
+// @Produce json
+// @Param userId query string true "User ID" format(uuid)
+// @Param X-User-ID header string true "Acting user; must be the cart user" format(uuid)
+// @Success 200 {object} dto.CartResponse "Cart retrieved successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/active [get]
+func (h *CartHandler) GetActiveCart(w http.ResponseWriter, r *http.Request) {
+	cart, err := h.cartService.GetActiveCart(r.Context(), r.URL.Query().Get("userId"))
+	if err != nil {
+		if err.Error() == "cart not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, "Cart not found")
+		} else if cartItemBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cart)
+}
+
+// RenameCart handles the request to rename a cart
+// @Summary Rename a cart
+// @Description Change the name of a shopping cart
+// @Tags carts
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Param request body dto.CartUpdateRequest true "New cart name"
+// @Success 200 {object} dto.CartResponse "Cart renamed successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart not found"
+// @Failure 409 {object} errors.ErrorResponse "Cart name already in use"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId} [patch]
+func (h *CartHandler) RenameCart(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	cartID := vars["cartId"]
+
+	var req dto.CartUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	cart, err := h.cartService.RenameCart(r.Context(), cartID, &req)
+	if err != nil {
+		if err.Error() == "cart not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, "Cart not found")
+		} else if err.Error() == "cart name already in use" {
+			errors.WriteErrorResponse(w, http.StatusConflict, err.Error())
+		} else if cartItemBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cart)
+}
+
+// ActivateCart handles the request to switch the active cart of a user
+// @Summary Switch the active cart
+// @Description Make a cart the active cart of its user
+// @Tags carts
+// @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Success 200 {object} dto.CartResponse "Cart activated successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/activate [post]
+func (h *CartHandler) ActivateCart(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	cartID := vars["cartId"]
+
+	cart, err := h.cartService.ActivateCart(r.Context(), cartID)
+	if err != nil {
+		if err.Error() == "cart not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, "Cart not found")
+		} else if cartItemBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cart)
+}
+
 // GetCart handles the request to get a cart by ID
 // @Summary Get a cart by ID
 // @Description Get details of a shopping cart by its ID
 // @Tags carts
 // @Accept json
 // @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
 // @Param cartId path string true "Cart ID" format(uuid)
 // @Success 200 {object} dto.CartResponse "Cart retrieved successfully"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
 // @Failure 404 {object} errors.ErrorResponse "Cart not found"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
 // @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /api/carts/{cartId} [get]
 func (h *CartHandler) GetCart(w http.ResponseWriter, r *http.Request) {
@@ -84,6 +294,8 @@ func (h *CartHandler) GetCart(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		if err.Error() == "cart not found" {
 			errors.WriteErrorResponse(w, http.StatusNotFound, "Cart not found")
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
 		} else {
 			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
 		}
@@ -100,9 +312,12 @@ func (h *CartHandler) GetCart(w http.ResponseWriter, r *http.Request) {
 // @Tags carts
 // @Accept json
 // @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
 // @Param cartId path string true "Cart ID" format(uuid)
 // @Success 204 "Cart deleted successfully"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
 // @Failure 404 {object} errors.ErrorResponse "Cart not found"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
 // @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /api/carts/{cartId} [delete]
 func (h *CartHandler) DeleteCart(w http.ResponseWriter, r *http.Request) {
@@ -113,6 +328,8 @@ func (h *CartHandler) DeleteCart(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		if err.Error() == "cart not found" {
 			errors.WriteErrorResponse(w, http.StatusNotFound, "Cart not found")
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
 		} else {
 			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
 		}
@@ -122,17 +339,138 @@ func (h *CartHandler) DeleteCart(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// AcceptPrices handles the request to update a cart to the current catalog prices
+// @Summary Accept updated prices
+// @Description Update the price of every cart item to the current catalog price, acknowledging the price change notices
+// @Tags carts
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Success 200 {object} dto.CartResponse "Prices updated successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/accept-prices [post]
+func (h *CartHandler) AcceptPrices(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	cartID := vars["cartId"]
+
+	cart, err := h.cartService.AcceptPrices(r.Context(), cartID)
+	if err != nil {
+		if err.Error() == "cart not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, "Cart not found")
+		} else if err.Error() == "invalid cart ID format" {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cart)
+}
+
+// GetActivity handles the request to retrieve the activity of a cart
+// @Summary Get cart activity
+// @Description Get every change made to the lines of a cart, newest first, with the user who made it and the lines before and after
+// @Tags carts
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Success 200 {array} dto.CartActivityDTO "Cart activity"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/activity [get]
+func (h *CartHandler) GetActivity(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	cartID := vars["cartId"]
+
+	activity, err := h.cartService.GetActivity(r.Context(), cartID)
+	if err != nil {
+		if err.Error() == "cart not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, "Cart not found")
+		} else if err.Error() == "invalid cart ID format" {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(activity)
+}
+
+// UndoLastChange handles the request to revert the last change to a cart
+// @Summary Undo last change
+// @Description Revert the most recent change to the lines of a cart that was not undone yet. Stock adjustments and moves between carts cannot be undone.
+// @Tags carts
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Success 200 {object} dto.CartResponse "Change undone successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart not found"
+// @Failure 409 {object} errors.ErrorResponse "Nothing to undo, or the change cannot be undone"
+// @Failure 422 {object} errors.ValidationErrorResponse "Purchase rules violated"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/undo [post]
+func (h *CartHandler) UndoLastChange(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	cartID := vars["cartId"]
+
+	cart, err := h.cartService.UndoLastChange(r.Context(), cartID)
+	if err != nil {
+		if violations, ok := err.(*model.RuleViolationError); ok {
+			errors.WriteValidationErrorResponse(w, http.StatusUnprocessableEntity, "Purchase rules violated", dto.RuleViolationsFromDomain(violations))
+		} else if err.Error() == "cart not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, "Cart not found")
+		} else if err.Error() == "invalid cart ID format" {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else if undoConflictErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusConflict, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cart)
+}
+
 // AddCartItem handles the request to add an item to a cart
 // @Summary Add item to cart
 // @Description Add a product item to a shopping cart
 // @Tags carts
 // @Accept json
 // @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
 // @Param cartId path string true "Cart ID" format(uuid)
 // @Param request body dto.CartItemRequest true "Item details"
 // @Success 200 {object} dto.CartResponse "Item added successfully"
 // @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
 // @Failure 404 {object} errors.ErrorResponse "Cart not found"
+// @Failure 422 {object} errors.ValidationErrorResponse "Purchase rules violated"
 // @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /api/carts/{cartId}/items [post]
 func (h *CartHandler) AddCartItem(w http.ResponseWriter, r *http.Request) {
@@ -147,8 +485,16 @@ func (h *CartHandler) AddCartItem(w http.ResponseWriter, r *http.Request) {
 
 	cart, err := h.cartService.AddCartItem(r.Context(), cartID, &req)
 	if err != nil {
+		if violations, ok := err.(*model.RuleViolationError); ok {
+			errors.WriteValidationErrorResponse(w, http.StatusUnprocessableEntity, "Purchase rules violated", dto.RuleViolationsFromDomain(violations))
+			return
+		}
 		if err.Error() == "cart not found" {
 			errors.WriteErrorResponse(w, http.StatusNotFound, "Cart not found")
+		} else if cartItemBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
 		} else {
 			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
 		}
@@ -165,12 +511,16 @@ func (h *CartHandler) AddCartItem(w http.ResponseWriter, r *http.Request) {
 // @Tags carts
 // @Accept json
 // @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
 // @Param cartId path string true "Cart ID" format(uuid)
 // @Param itemId path string true "Item ID" format(uuid)
 // @Param request body dto.CartItemUpdateRequest true "Updated item details"
 // @Success 200 {object} dto.CartResponse "Item updated successfully"
 // @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
 // @Failure 404 {object} errors.ErrorResponse "Cart or item not found"
+// @Failure 422 {object} errors.ValidationErrorResponse "Purchase rules violated"
 // @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /api/carts/{cartId}/items/{itemId} [put]
 func (h *CartHandler) UpdateCartItem(w http.ResponseWriter, r *http.Request) {
@@ -186,8 +536,16 @@ func (h *CartHandler) UpdateCartItem(w http.ResponseWriter, r *http.Request) {
 
 	cart, err := h.cartService.UpdateCartItem(r.Context(), cartID, itemID, &req)
 	if err != nil {
+		if violations, ok := err.(*model.RuleViolationError); ok {
+			errors.WriteValidationErrorResponse(w, http.StatusUnprocessableEntity, "Purchase rules violated", dto.RuleViolationsFromDomain(violations))
+			return
+		}
 		if err.Error() == "cart not found" || err.Error() == "item not found in cart" {
 			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if cartItemBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
 		} else {
 			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
 		}
@@ -204,10 +562,13 @@ func (h *CartHandler) UpdateCartItem(w http.ResponseWriter, r *http.Request) {
 // @Tags carts
 // @Accept json
 // @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
 // @Param cartId path string true "Cart ID" format(uuid)
 // @Param itemId path string true "Item ID" format(uuid)
 // @Success 204 "Item removed successfully"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
 // @Failure 404 {object} errors.ErrorResponse "Cart or item not found"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
 // @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /api/carts/{cartId}/items/{itemId} [delete]
 func (h *CartHandler) RemoveCartItem(w http.ResponseWriter, r *http.Request) {
@@ -219,6 +580,8 @@ func (h *CartHandler) RemoveCartItem(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		if err.Error() == "cart not found" || err.Error() == "item not found in cart" {
 			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
 		} else {
 			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
 		}
@@ -227,3 +590,311 @@ func (h *CartHandler) RemoveCartItem(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// ScanItem handles the request to add a product by its barcode
+// @Summary Scan item into cart
+// @Description Add one unit of the product identified by an EAN-13, UPC-A or EAN-8 barcode or an internal SKU read by the kiosk scanner. Barcodes must have a valid check digit; the name and price come from the catalog.
+// @Tags carts
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Param request body dto.CartScanRequest true "Scanned code"
+// @Success 200 {object} dto.CartScanResponse "Item added successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request or barcode checksum"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart not found or unknown barcode"
+// @Failure 409 {object} errors.ErrorResponse "Product discontinued or out of stock"
+// @Failure 422 {object} errors.ValidationErrorResponse "Purchase rules violated"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/scan [post]
+func (h *CartHandler) ScanItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	cartID := vars["cartId"]
+
+	var req dto.CartScanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := h.cartService.ScanItem(r.Context(), cartID, &req)
+	if err != nil {
+		if violations, ok := err.(*model.RuleViolationError); ok {
+			errors.WriteValidationErrorResponse(w, http.StatusUnprocessableEntity, "Purchase rules violated", dto.RuleViolationsFromDomain(violations))
+			return
+		}
+		if err.Error() == "cart not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, "Cart not found")
+		} else if err.Error() == "unknown barcode" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if cartItemBadRequestErrors[err.Error()] || barcodeBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else if scanConflictErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusConflict, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// ApplyItemOperations handles the request to change several cart items at once
+// @Summary Apply item operations
+// @Description Apply a batch of ADD, UPDATE and REMOVE item operations in order. Either all of them are applied or none is, and the result of each operation is reported.
+// @Tags carts
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Param request body dto.CartItemBatchRequest true "Item operations"
+// @Success 200 {object} dto.CartItemBatchResponse "Operations applied successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart not found"
+// @Failure 422 {object} errors.ValidationErrorResponse "An operation failed; no operation was applied"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/items [patch]
+func (h *CartHandler) ApplyItemOperations(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	cartID := vars["cartId"]
+
+	var req dto.CartItemBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := h.cartService.ApplyItemOperations(r.Context(), cartID, &req)
+	if err != nil {
+		if batch, ok := err.(*model.ItemBatchError); ok {
+			errors.WriteValidationErrorResponse(w, http.StatusUnprocessableEntity, "Item operations failed", dto.ItemOperationResultsFromDomain(batch.Results, true))
+			return
+		}
+		if err.Error() == "cart not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, "Cart not found")
+		} else if cartItemBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// ClearCart handles the request to remove every item from a cart
+// @Summary Clear cart
+// @Description Remove every item from a shopping cart. Saved items are kept.
+// @Tags carts
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Success 204 "Cart cleared successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/items [delete]
+func (h *CartHandler) ClearCart(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	cartID := vars["cartId"]
+
+	err := h.cartService.ClearCart(r.Context(), cartID)
+	if err != nil {
+		if err.Error() == "cart not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, "Cart not found")
+		} else if err.Error() == "invalid cart ID format" {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SaveForLater handles the request to move a cart item to the saved-for-later list
+// @Summary Save item for later
+// @Description Move an item out of the cart into the saved-for-later list, keeping its quantity and options
+// @Tags carts
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Param itemId path string true "Item ID" format(uuid)
+// @Success 200 {object} dto.CartResponse "Item saved for later"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart or item not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/items/{itemId}/save-for-later [post]
+func (h *CartHandler) SaveForLater(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	cartID := vars["cartId"]
+	itemID := vars["itemId"]
+
+	cart, err := h.cartService.SaveForLater(r.Context(), cartID, itemID)
+	if err != nil {
+		if err.Error() == "cart not found" || err.Error() == "item not found in cart" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if cartItemBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cart)
+}
+
+// MoveToCart handles the request to move a saved item back to the cart
+// @Summary Move saved item to cart
+// @Description Move an item from the saved-for-later list back into the cart, keeping its quantity and options
+// @Tags carts
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Param itemId path string true "Saved item ID" format(uuid)
+// @Success 200 {object} dto.CartResponse "Item moved to the cart"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart or saved item not found"
+// @Failure 422 {object} errors.ValidationErrorResponse "Purchase rules violated"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/saved-items/{itemId}/move-to-cart [post]
+func (h *CartHandler) MoveToCart(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	cartID := vars["cartId"]
+	itemID := vars["itemId"]
+
+	cart, err := h.cartService.MoveToCart(r.Context(), cartID, itemID)
+	if err != nil {
+		if violations, ok := err.(*model.RuleViolationError); ok {
+			errors.WriteValidationErrorResponse(w, http.StatusUnprocessableEntity, "Purchase rules violated", dto.RuleViolationsFromDomain(violations))
+			return
+		}
+		if err.Error() == "cart not found" || err.Error() == "item not found in saved items" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if cartItemBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cart)
+}
+
+// RemoveSavedItem handles the request to remove an item from the saved-for-later list
+// @Summary Remove saved item
+// @Description Remove an item from the saved-for-later list
+// @Tags carts
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Param itemId path string true "Saved item ID" format(uuid)
+// @Success 204 "Saved item removed successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart or saved item not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/saved-items/{itemId} [delete]
+func (h *CartHandler) RemoveSavedItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	cartID := vars["cartId"]
+	itemID := vars["itemId"]
+
+	if err := h.cartService.RemoveSavedItem(r.Context(), cartID, itemID); err != nil {
+		if err.Error() == "cart not found" || err.Error() == "item not found in saved items" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if cartItemBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MoveCartItem handles the request to move a cart item to another cart
+// @Summary Move item to another cart
+// @Description Move a cart item, or part of its quantity, to another cart of the same user, keeping its variant and options
+// @Tags carts
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Param itemId path string true "Item ID" format(uuid)
+// @Param request body dto.CartItemMoveRequest true "Target cart and quantity"
+// @Success 200 {object} dto.CartMoveResponse "Item moved successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart, target cart or item not found"
+// @Failure 422 {object} errors.ValidationErrorResponse "Purchase rules violated"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/items/{itemId}/move [post]
+func (h *CartHandler) MoveCartItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	cartID := vars["cartId"]
+	itemID := vars["itemId"]
+
+	var req dto.CartItemMoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.cartService.MoveCartItem(r.Context(), cartID, itemID, &req)
+	if err != nil {
+		if violations, ok := err.(*model.RuleViolationError); ok {
+			errors.WriteValidationErrorResponse(w, http.StatusUnprocessableEntity, "Purchase rules violated", dto.RuleViolationsFromDomain(violations))
+			return
+		}
+		if err.Error() == "cart not found" || err.Error() == "target cart not found" || err.Error() == "item not found in cart" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if cartItemBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,331 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
+)
+
+// cartMemberBadRequestErrors are the member and invitation errors caused by an invalid request
+var cartMemberBadRequestErrors = map[string]bool{
+	"invalid cart ID format":                 true,
+	"invalid user ID format":                 true,
+	"invalid invitation ID format":           true,
+	"invalid member role":                    true,
+	"invitee ID is required":                 true,
+	"user ID is required":                    true,
+	"cannot invite the cart owner":           true,
+	"cannot remove the cart owner":           true,
+	"invitation does not belong to the cart": true,
+}
+
+// cartMemberConflictErrors are the member and invitation errors caused by the current state
+var cartMemberConflictErrors = map[string]bool{
+	"user is already a member of the cart":  true,
+	"user already has a pending invitation": true,
+	"invitation is no longer pending":       true,
+	"invitation has expired":                true,
+}
+
+// CartMemberHandler handles HTTP requests for the members and invitations of shared carts
+type CartMemberHandler struct {
+	cartMemberService *services.CartMemberService
+}
+
+// NewCartMemberHandler creates a new cart member handler
+func NewCartMemberHandler(cartMemberService *services.CartMemberService) *CartMemberHandler {
+	return &CartMemberHandler{
+		cartMemberService: cartMemberService,
+	}
+}
+
+// RegisterRoutes registers the cart member and invitation routes on the given router
+func (h *CartMemberHandler) RegisterRoutes(router *mux.Router) {
+	memberRouter := router.PathPrefix("/carts/{cartId}").Subrouter()
+	memberRouter.Use(withActor)
+
+	memberRouter.HandleFunc("/members", h.ListMembers).Methods("GET")
+	memberRouter.HandleFunc("/members/{userId}", h.ChangeMemberRole).Methods("PATCH")
+	memberRouter.HandleFunc("/members/{userId}", h.RemoveMember).Methods("DELETE")
+	memberRouter.HandleFunc("/invitations", h.InviteMember).Methods("POST")
+	memberRouter.HandleFunc("/invitations", h.ListInvitations).Methods("GET")
+	memberRouter.HandleFunc("/invitations/{invitationId}", h.RevokeInvitation).Methods("DELETE")
+
+	invitationRouter := router.PathPrefix("/cart-invitations").Subrouter()
+	invitationRouter.Use(withActor)
+
+	invitationRouter.HandleFunc("", h.ListUserInvitations).Methods("GET")
+	invitationRouter.HandleFunc("/{invitationId}/accept", h.AcceptInvitation).Methods("POST")
+	invitationRouter.HandleFunc("/{invitationId}/decline", h.DeclineInvitation).Methods("POST")
+}
+
+// writeCartMemberError maps a member or invitation error to its HTTP response
+func writeCartMemberError(w http.ResponseWriter, err error) {
+	switch {
+	case err.Error() == "cart not found" || err.Error() == "member not found" || err.Error() == "invitation not found":
+		errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+	case err.Error() == "cart access denied":
+		errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+	case cartMemberBadRequestErrors[err.Error()]:
+		errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+	case cartMemberConflictErrors[err.Error()]:
+		errors.WriteErrorResponse(w, http.StatusConflict, err.Error())
+	default:
+		errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// ListMembers handles the request to list the members of a cart
+// @Summary List cart members
+// @Description List the owner and the members of a cart with their roles
+// @Tags cart-members
+// @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Success 200 {array} dto.CartMemberDTO "Members retrieved successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/members [get]
+func (h *CartMemberHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	members, err := h.cartMemberService.ListMembers(r.Context(), mux.Vars(r)["cartId"])
+	if err != nil {
+		writeCartMemberError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(members)
+}
+
+// ChangeMemberRole handles the request to change the role of a member
+// @Summary Change a member's role
+// @Description Make a member of a shared cart an editor or a viewer. Only the owner can change roles.
+// @Tags cart-members
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Param userId path string true "Member user ID" format(uuid)
+// @Param request body dto.CartMemberUpdateRequest true "New role"
+// @Success 200 {array} dto.CartMemberDTO "Role changed successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart or member not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/members/{userId} [patch]
+func (h *CartMemberHandler) ChangeMemberRole(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var req dto.CartMemberUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	members, err := h.cartMemberService.ChangeMemberRole(r.Context(), vars["cartId"], vars["userId"], &req)
+	if err != nil {
+		writeCartMemberError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(members)
+}
+
+// RemoveMember handles the request to remove a member from a cart
+// @Summary Remove a cart member
+// @Description Revoke the access of a member to a shared cart. The owner can remove any member; members can remove themselves to leave the cart.
+// @Tags cart-members
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Param userId path string true "Member user ID" format(uuid)
+// @Success 204 "Member removed successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart or member not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/members/{userId} [delete]
+func (h *CartMemberHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if err := h.cartMemberService.RemoveMember(r.Context(), vars["cartId"], vars["userId"]); err != nil {
+		writeCartMemberError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// InviteMember handles the request to invite a user to a cart
+// @Summary Invite a user to a cart
+// @Description Invite a user to join a shared cart as an editor or a viewer. The invitation expires after seven days. Only the owner can invite.
+// @Tags cart-members
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Param request body dto.CartInvitationRequest true "Invited user and role"
+// @Success 201 {object} dto.CartInvitationDTO "Invitation created successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart not found"
+// @Failure 409 {object} errors.ErrorResponse "User already a member or already invited"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/invitations [post]
+func (h *CartMemberHandler) InviteMember(w http.ResponseWriter, r *http.Request) {
+	var req dto.CartInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	invitation, err := h.cartMemberService.InviteMember(r.Context(), mux.Vars(r)["cartId"], &req)
+	if err != nil {
+		writeCartMemberError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(invitation)
+}
+
+// ListInvitations handles the request to list the invitations to a cart
+// @Summary List cart invitations
+// @Description List every invitation to a cart. Only the owner can list them.
+// @Tags cart-members
+// @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Success 200 {array} dto.CartInvitationDTO "Invitations retrieved successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/invitations [get]
+func (h *CartMemberHandler) ListInvitations(w http.ResponseWriter, r *http.Request) {
+	invitations, err := h.cartMemberService.ListInvitations(r.Context(), mux.Vars(r)["cartId"])
+	if err != nil {
+		writeCartMemberError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(invitations)
+}
+
+// RevokeInvitation handles the request to revoke an invitation to a cart
+// @Summary Revoke a cart invitation
+// @Description Cancel a pending invitation. Only the owner can revoke invitations.
+// @Tags cart-members
+// @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Param invitationId path string true "Invitation ID" format(uuid)
+// @Success 200 {object} dto.CartInvitationDTO "Invitation revoked successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart or invitation not found"
+// @Failure 409 {object} errors.ErrorResponse "Invitation no longer pending"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/invitations/{invitationId} [delete]
+func (h *CartMemberHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	invitation, err := h.cartMemberService.RevokeInvitation(r.Context(), vars["cartId"], vars["invitationId"])
+	if err != nil {
+		writeCartMemberError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(invitation)
+}
+
+// ListUserInvitations handles the request to list the pending invitations of a user
+// @Summary List a user's cart invitations
+// @Description List the cart invitations a user has not answered yet
+// @Tags cart-members
+// @Produce json
+// @Param X-User-ID header string true "Acting user; must be the listed user" format(uuid)
+// @Param userId query string true "User ID" format(uuid)
+// @Success 200 {array} dto.CartInvitationDTO "Invitations retrieved successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/cart-invitations [get]
+func (h *CartMemberHandler) ListUserInvitations(w http.ResponseWriter, r *http.Request) {
+	invitations, err := h.cartMemberService.ListUserInvitations(r.Context(), r.URL.Query().Get("userId"))
+	if err != nil {
+		writeCartMemberError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(invitations)
+}
+
+// AcceptInvitation handles the request to accept a cart invitation
+// @Summary Accept a cart invitation
+// @Description Join a shared cart with the invited role. The invited user must be named in the X-User-ID header.
+// @Tags cart-members
+// @Produce json
+// @Param X-User-ID header string true "Invited user" format(uuid)
+// @Param invitationId path string true "Invitation ID" format(uuid)
+// @Success 200 {object} dto.CartInvitationDTO "Invitation accepted successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Invitation addressed to another user"
+// @Failure 404 {object} errors.ErrorResponse "Invitation not found"
+// @Failure 409 {object} errors.ErrorResponse "Invitation no longer pending or expired"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/cart-invitations/{invitationId}/accept [post]
+func (h *CartMemberHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	invitation, err := h.cartMemberService.AcceptInvitation(r.Context(), mux.Vars(r)["invitationId"])
+	if err != nil {
+		writeCartMemberError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(invitation)
+}
+
+// DeclineInvitation handles the request to decline a cart invitation
+// @Summary Decline a cart invitation
+// @Description Decline an invitation to a shared cart. The invited user must be named in the X-User-ID header.
+// @Tags cart-members
+// @Produce json
+// @Param X-User-ID header string true "Invited user" format(uuid)
+// @Param invitationId path string true "Invitation ID" format(uuid)
+// @Success 200 {object} dto.CartInvitationDTO "Invitation declined successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Invitation addressed to another user"
+// @Failure 404 {object} errors.ErrorResponse "Invitation not found"
+// @Failure 409 {object} errors.ErrorResponse "Invitation no longer pending or expired"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/cart-invitations/{invitationId}/decline [post]
+func (h *CartMemberHandler) DeclineInvitation(w http.ResponseWriter, r *http.Request) {
+	invitation, err := h.cartMemberService.DeclineInvitation(r.Context(), mux.Vars(r)["invitationId"])
+	if err != nil {
+		writeCartMemberError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(invitation)
+}
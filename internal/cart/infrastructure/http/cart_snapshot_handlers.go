@@ -0,0 +1,178 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
+)
+
+// cartSnapshotBadRequestErrors are the snapshot errors caused by an invalid request
+var cartSnapshotBadRequestErrors = map[string]bool{
+	"invalid cart ID format": true,
+	"invalid user ID format": true,
+	"user ID is required":    true,
+	"cart is empty":          true,
+	"cart name is too long":  true,
+}
+
+// CartSnapshotHandler handles HTTP requests for shareable cart snapshots
+type CartSnapshotHandler struct {
+	cartSnapshotService *services.CartSnapshotService
+}
+
+// NewCartSnapshotHandler creates a new cart snapshot handler
+func NewCartSnapshotHandler(cartSnapshotService *services.CartSnapshotService) *CartSnapshotHandler {
+	return &CartSnapshotHandler{
+		cartSnapshotService: cartSnapshotService,
+	}
+}
+
+// RegisterRoutes registers the cart snapshot routes on the given router
+func (h *CartSnapshotHandler) RegisterRoutes(router *mux.Router) {
+	cartRouter := router.PathPrefix("/carts/{cartId}/snapshots").Subrouter()
+	cartRouter.Use(withActor)
+
+	cartRouter.HandleFunc("", h.CreateSnapshot).Methods("POST")
+	cartRouter.HandleFunc("", h.ListSnapshots).Methods("GET")
+
+	// Anyone with the share token can read a snapshot; cloning it needs the acting user
+	snapshotRouter := router.PathPrefix("/cart-snapshots").Subrouter()
+	snapshotRouter.HandleFunc("/{token}", h.GetSnapshot).Methods("GET")
+	snapshotRouter.Handle("/{token}/clone", withActor(http.HandlerFunc(h.CloneSnapshot))).Methods("POST")
+}
+
+// writeCartSnapshotError maps a snapshot error to its HTTP response
+func writeCartSnapshotError(w http.ResponseWriter, err error) {
+	switch {
+	case err.Error() == "cart not found" || err.Error() == "snapshot not found":
+		errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+	case err.Error() == "cart access denied":
+		errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+	case cartSnapshotBadRequestErrors[err.Error()]:
+		errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+	case err.Error() == "cart name already in use":
+		errors.WriteErrorResponse(w, http.StatusConflict, err.Error())
+	default:
+		errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// CreateSnapshot handles the request to take a snapshot of a cart
+// @Summary Create cart snapshot
+// @Description Take an immutable copy of the cart lines with a share token that any user can use to read and clone it. Later changes to the cart do not affect the snapshot.
+// @Tags cart-snapshots
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Param request body dto.CartSnapshotRequest false "Snapshot name; defaults to the cart name"
+// @Success 201 {object} dto.CartSnapshotDTO "Snapshot created successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/snapshots [post]
+func (h *CartSnapshotHandler) CreateSnapshot(w http.ResponseWriter, r *http.Request) {
+	// The body is optional
+	var req dto.CartSnapshotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	snapshot, err := h.cartSnapshotService.CreateSnapshot(r.Context(), mux.Vars(r)["cartId"], &req)
+	if err != nil {
+		writeCartSnapshotError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(snapshot)
+}
+
+// ListSnapshots handles the request to list the snapshots of a cart
+// @Summary List cart snapshots
+// @Description List the snapshots taken of a cart, newest first
+// @Tags cart-snapshots
+// @Produce json
+// @Param X-User-ID header string true "User acting on the cart" format(uuid)
+// @Param cartId path string true "Cart ID" format(uuid)
+// @Success 200 {array} dto.CartSnapshotDTO "Snapshots retrieved successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Cart not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/carts/{cartId}/snapshots [get]
+func (h *CartSnapshotHandler) ListSnapshots(w http.ResponseWriter, r *http.Request) {
+	snapshots, err := h.cartSnapshotService.ListSnapshots(r.Context(), mux.Vars(r)["cartId"])
+	if err != nil {
+		writeCartSnapshotError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(snapshots)
+}
+
+// GetSnapshot handles the request to read a snapshot through its share token
+// @Summary Get cart snapshot
+// @Description Get a cart snapshot by its share token. Any user with the token can read it.
+// @Tags cart-snapshots
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} dto.CartSnapshotDTO "Snapshot retrieved successfully"
+// @Failure 404 {object} errors.ErrorResponse "Snapshot not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/cart-snapshots/{token} [get]
+func (h *CartSnapshotHandler) GetSnapshot(w http.ResponseWriter, r *http.Request) {
+	snapshot, err := h.cartSnapshotService.GetSnapshot(r.Context(), mux.Vars(r)["token"])
+	if err != nil {
+		writeCartSnapshotError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(snapshot)
+}
+
+// CloneSnapshot handles the request to copy a snapshot into a cart of the user
+// @Summary Clone cart snapshot
+// @Description Copy the lines of a snapshot into a new cart of the user, or into an existing cart given its cartId. Each line is revalidated against the catalog like an import; rejected lines are reported.
+// @Tags cart-snapshots
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "User cloning the snapshot" format(uuid)
+// @Param token path string true "Share token"
+// @Param request body dto.CartSnapshotCloneRequest true "Target cart or name of the new cart"
+// @Success 200 {object} dto.CartImportResponse "Snapshot cloned"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "User ID is required"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Snapshot or cart not found"
+// @Failure 409 {object} errors.ErrorResponse "Cart name already in use"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/cart-snapshots/{token}/clone [post]
+func (h *CartSnapshotHandler) CloneSnapshot(w http.ResponseWriter, r *http.Request) {
+	var req dto.CartSnapshotCloneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := h.cartSnapshotService.CloneSnapshot(r.Context(), mux.Vars(r)["token"], &req)
+	if err != nil {
+		writeCartSnapshotError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
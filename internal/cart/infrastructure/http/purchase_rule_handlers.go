@@ -0,0 +1,168 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
+)
+
+// purchaseRuleBadRequestErrors are the purchase rule errors caused by an invalid request
+var purchaseRuleBadRequestErrors = map[string]bool{
+	"invalid product ID format":                         true,
+	"product ID is required":                            true,
+	"purchase limits cannot be negative":                true,
+	"max per user requires a time window":               true,
+	"min quantity cannot be greater than max per order": true,
+	"max per order cannot be lower than the pack size":  true,
+}
+
+// PurchaseRuleHandler handles HTTP requests for product purchase rules
+type PurchaseRuleHandler struct {
+	purchaseRuleService *services.PurchaseRuleService
+	requireAdmin        func(http.Handler) http.Handler
+}
+
+// NewPurchaseRuleHandler creates a new purchase rule handler. Setting and deleting rules go
+// through requireAdmin, since purchase limits are managed from the back office.
+func NewPurchaseRuleHandler(purchaseRuleService *services.PurchaseRuleService, requireAdmin func(http.Handler) http.Handler) *PurchaseRuleHandler {
+	return &PurchaseRuleHandler{
+		purchaseRuleService: purchaseRuleService,
+		requireAdmin:        requireAdmin,
+	}
+}
+
+// RegisterRoutes registers the purchase rule routes on the given router
+func (h *PurchaseRuleHandler) RegisterRoutes(router *mux.Router) {
+	ruleRouter := router.PathPrefix("/purchase-rules").Subrouter()
+
+	ruleRouter.HandleFunc("", h.ListRules).Methods("GET")
+	ruleRouter.HandleFunc("/{productId}", h.GetRule).Methods("GET")
+
+	// Rules are only changed from the back office
+	adminRouter := ruleRouter.NewRoute().Subrouter()
+	adminRouter.Use(h.requireAdmin)
+
+	adminRouter.HandleFunc("/{productId}", h.SetRule).Methods("PUT")
+	adminRouter.HandleFunc("/{productId}", h.DeleteRule).Methods("DELETE")
+}
+
+// ListRules handles the request to list the purchase rules
+// @Summary List purchase rules
+// @Description List the quantity limits configured for products
+// @Tags purchase-rules
+// @Produce json
+// @Success 200 {array} dto.PurchaseRuleDTO "Purchase rules retrieved successfully"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/purchase-rules [get]
+func (h *PurchaseRuleHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.purchaseRuleService.ListRules(r.Context())
+	if err != nil {
+		errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rules)
+}
+
+// GetRule handles the request to get the purchase rule of a product
+// @Summary Get a purchase rule
+// @Description Get the quantity limits configured for a product
+// @Tags purchase-rules
+// @Produce json
+// @Param productId path string true "Product ID" format(uuid)
+// @Success 200 {object} dto.PurchaseRuleDTO "Purchase rule retrieved successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 404 {object} errors.ErrorResponse "Purchase rule not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/purchase-rules/{productId} [get]
+func (h *PurchaseRuleHandler) GetRule(w http.ResponseWriter, r *http.Request) {
+	productID := mux.Vars(r)["productId"]
+
+	rule, err := h.purchaseRuleService.GetRule(r.Context(), productID)
+	if err != nil {
+		if err.Error() == "purchase rule not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, "Purchase rule not found")
+		} else if purchaseRuleBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+// SetRule handles the request to set the purchase rule of a product
+// @Summary Set a purchase rule
+// @Description Create or replace the quantity limits of a product: max per order, max per user over a time window, min quantity and pack size. Zero disables a limit.
+// @Tags purchase-rules
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param productId path string true "Product ID" format(uuid)
+// @Param request body dto.PurchaseRuleRequest true "Purchase rule"
+// @Success 200 {object} dto.PurchaseRuleDTO "Purchase rule saved successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/purchase-rules/{productId} [put]
+func (h *PurchaseRuleHandler) SetRule(w http.ResponseWriter, r *http.Request) {
+	productID := mux.Vars(r)["productId"]
+
+	var req dto.PurchaseRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	rule, err := h.purchaseRuleService.SetRule(r.Context(), productID, &req)
+	if err != nil {
+		if purchaseRuleBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+// DeleteRule handles the request to delete the purchase rule of a product
+// @Summary Delete a purchase rule
+// @Description Remove the quantity limits of a product
+// @Tags purchase-rules
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param productId path string true "Product ID" format(uuid)
+// @Success 204 "Purchase rule deleted successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 404 {object} errors.ErrorResponse "Purchase rule not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/purchase-rules/{productId} [delete]
+func (h *PurchaseRuleHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	productID := mux.Vars(r)["productId"]
+
+	if err := h.purchaseRuleService.DeleteRule(r.Context(), productID); err != nil {
+		if err.Error() == "purchase rule not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, "Purchase rule not found")
+		} else if purchaseRuleBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,80 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/repository"
+)
+
+// PostgreSQLCartActivityRepository implements the CartActivityRepository interface using PostgreSQL
+type PostgreSQLCartActivityRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLCartActivityRepository creates a new PostgreSQL repository for cart activity
+func NewPostgreSQLCartActivityRepository(db *sql.DB) repository.CartActivityRepository {
+	return &PostgreSQLCartActivityRepository{
+		db: db,
+	}
+}
+
+// FindByCartID retrieves the activity of a cart, newest first
+func (r *PostgreSQLCartActivityRepository) FindByCartID(ctx context.Context, cartID uuid.UUID) ([]*model.CartActivity, error) {
+	query := `
+		SELECT id, cart_id, actor_id, operation, changes, reverts_id, created_at
+		FROM cart_activity
+		WHERE cart_id = $1
+		ORDER BY created_at DESC, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, cartID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	activities := make([]*model.CartActivity, 0)
+
+	for rows.Next() {
+		var (
+			activity  model.CartActivity
+			actorID   uuid.NullUUID
+			operation string
+			changes   []byte
+			revertsID uuid.NullUUID
+		)
+
+		if err := rows.Scan(
+			&activity.ID,
+			&activity.CartID,
+			&actorID,
+			&operation,
+			&changes,
+			&revertsID,
+			&activity.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(changes, &activity.Changes); err != nil {
+			return nil, err
+		}
+		activity.ActorID = actorID.UUID
+		activity.Operation = model.CartOperation(operation)
+		if revertsID.Valid {
+			activity.RevertsID = &revertsID.UUID
+		}
+
+		activities = append(activities, &activity)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return activities, nil
+}
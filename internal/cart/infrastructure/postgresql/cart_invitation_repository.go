@@ -0,0 +1,149 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/repository"
+)
+
+// PostgreSQLCartInvitationRepository implements the CartInvitationRepository interface using PostgreSQL
+type PostgreSQLCartInvitationRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLCartInvitationRepository creates a new PostgreSQL repository for cart invitations
+func NewPostgreSQLCartInvitationRepository(db *sql.DB) repository.CartInvitationRepository {
+	return &PostgreSQLCartInvitationRepository{
+		db: db,
+	}
+}
+
+// FindByID retrieves an invitation by its ID
+func (r *PostgreSQLCartInvitationRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.CartInvitation, error) {
+	query := `
+		SELECT id, cart_id, invitee_id, invited_by, role, status, created_at, expires_at, responded_at
+		FROM cart_invitations
+		WHERE id = $1
+	`
+
+	invitation, err := scanCartInvitation(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("invitation not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return invitation, nil
+}
+
+// FindByCartID retrieves every invitation to a cart
+func (r *PostgreSQLCartInvitationRepository) FindByCartID(ctx context.Context, cartID uuid.UUID) ([]*model.CartInvitation, error) {
+	query := `
+		SELECT id, cart_id, invitee_id, invited_by, role, status, created_at, expires_at, responded_at
+		FROM cart_invitations
+		WHERE cart_id = $1
+		ORDER BY created_at DESC
+	`
+
+	return r.findMany(ctx, query, cartID)
+}
+
+// FindPendingByInviteeID retrieves the invitations a user has not answered yet
+func (r *PostgreSQLCartInvitationRepository) FindPendingByInviteeID(ctx context.Context, inviteeID uuid.UUID) ([]*model.CartInvitation, error) {
+	query := `
+		SELECT id, cart_id, invitee_id, invited_by, role, status, created_at, expires_at, responded_at
+		FROM cart_invitations
+		WHERE invitee_id = $1 AND status = $2 AND expires_at > now()
+		ORDER BY created_at DESC
+	`
+
+	return r.findMany(ctx, query, inviteeID, string(model.InvitationStatusPending))
+}
+
+// findMany runs a multi-row invitation query
+func (r *PostgreSQLCartInvitationRepository) findMany(ctx context.Context, query string, args ...interface{}) ([]*model.CartInvitation, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := make([]*model.CartInvitation, 0)
+
+	for rows.Next() {
+		invitation, err := scanCartInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// Save persists an invitation (creates or updates)
+func (r *PostgreSQLCartInvitationRepository) Save(ctx context.Context, invitation *model.CartInvitation) error {
+	query := `
+		INSERT INTO cart_invitations (id, cart_id, invitee_id, invited_by, role, status, created_at, expires_at, responded_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (id) DO UPDATE
+		SET status = $6, responded_at = $9
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		invitation.ID,
+		invitation.CartID,
+		invitation.InviteeID,
+		invitation.InvitedBy,
+		string(invitation.Role),
+		string(invitation.Status),
+		invitation.CreatedAt,
+		invitation.ExpiresAt,
+		invitation.RespondedAt,
+	)
+
+	return err
+}
+
+// scanCartInvitation reads an invitation from a row
+func scanCartInvitation(row rowScanner) (*model.CartInvitation, error) {
+	var (
+		invitation  model.CartInvitation
+		role        string
+		status      string
+		respondedAt sql.NullTime
+	)
+
+	if err := row.Scan(
+		&invitation.ID,
+		&invitation.CartID,
+		&invitation.InviteeID,
+		&invitation.InvitedBy,
+		&role,
+		&status,
+		&invitation.CreatedAt,
+		&invitation.ExpiresAt,
+		&respondedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	invitation.Role = model.MemberRole(role)
+	invitation.Status = model.InvitationStatus(status)
+	if respondedAt.Valid {
+		invitation.RespondedAt = &respondedAt.Time
+	}
+
+	return &invitation, nil
+}
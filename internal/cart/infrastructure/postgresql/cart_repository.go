@@ -5,10 +5,12 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/repository"
+	"github.com/lib/pq"
 )
 
 // PostgreSQLCartRepository implements the CartRepository interface using PostgreSQL
@@ -26,27 +28,126 @@ func NewPostgreSQLCartRepository(db *sql.DB) repository.CartRepository {
 // FindByID retrieves a cart by its ID
 func (r *PostgreSQLCartRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Cart, error) {
 	query := `
-		SELECT id, user_id, items, created_at, updated_at
+		SELECT id, user_id, name, active, kiosk, created_at, updated_at
 		FROM carts
 		WHERE id = $1
 	`
 
-	var (
-		cartID    uuid.UUID
-		userID    uuid.UUID
-		itemsJSON []byte
-		createdAt sql.NullTime
-		updatedAt sql.NullTime
-	)
+	return r.findOne(ctx, query, id)
+}
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&cartID,
-		&userID,
-		&itemsJSON,
-		&createdAt,
-		&updatedAt,
-	)
+// FindByUserID retrieves the current active cart for a user
+func (r *PostgreSQLCartRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*model.Cart, error) {
+	query := `
+		SELECT id, user_id, name, active, kiosk, created_at, updated_at
+		FROM carts
+		WHERE user_id = $1
+		ORDER BY active DESC, created_at DESC
+		LIMIT 1
+	`
+
+	return r.findOne(ctx, query, userID)
+}
+
+// FindAllByUserID retrieves every cart of a user
+func (r *PostgreSQLCartRepository) FindAllByUserID(ctx context.Context, userID uuid.UUID) ([]*model.Cart, error) {
+	query := `
+		SELECT id, user_id, name, active, kiosk, created_at, updated_at
+		FROM carts
+		WHERE user_id = $1
+		ORDER BY created_at ASC
+	`
+
+	return r.findMany(ctx, query, userID)
+}
+
+// FindSharedWithUser retrieves the carts other users shared with the user
+func (r *PostgreSQLCartRepository) FindSharedWithUser(ctx context.Context, userID uuid.UUID) ([]*model.Cart, error) {
+	query := `
+		SELECT c.id, c.user_id, c.name, c.active, c.kiosk, c.created_at, c.updated_at
+		FROM carts c
+		JOIN cart_members m ON m.cart_id = c.id
+		WHERE m.user_id = $1
+		ORDER BY m.joined_at ASC
+	`
+
+	return r.findMany(ctx, query, userID)
+}
+
+// FindByProductID retrieves every cart that contains the product
+func (r *PostgreSQLCartRepository) FindByProductID(ctx context.Context, productID uuid.UUID) ([]*model.Cart, error) {
+	query := `
+		SELECT c.id, c.user_id, c.name, c.active, c.kiosk, c.created_at, c.updated_at
+		FROM carts c
+		WHERE EXISTS (
+			SELECT 1 FROM cart_items i WHERE i.cart_id = c.id AND i.product_id = $1
+		)
+		ORDER BY c.updated_at DESC
+	`
+
+	return r.findMany(ctx, query, productID)
+}
+
+// FindAbandoned retrieves the carts with items last changed in [from, to) that were not
+// checked out since. A checkout counts once it is placed, even if it was refunded later.
+// Kiosk carts are left out, since their owner is a terminal session rather than a user.
+func (r *PostgreSQLCartRepository) FindAbandoned(ctx context.Context, from, to time.Time) ([]*model.Cart, error) {
+	query := `
+		SELECT c.id, c.user_id, c.name, c.active, c.kiosk, c.created_at, c.updated_at
+		FROM carts c
+		WHERE c.updated_at >= $1 AND c.updated_at < $2
+		AND NOT c.kiosk
+		AND EXISTS (
+			SELECT 1 FROM cart_items i WHERE i.cart_id = c.id AND NOT i.saved
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM checkouts k
+			WHERE k.cart_id = c.id
+			AND k.status IN ('AWAITING_PAYMENT', 'COMPLETED', 'REFUNDED')
+			AND k.updated_at >= c.updated_at
+		)
+		ORDER BY c.updated_at
+	`
+
+	return r.findMany(ctx, query, from, to)
+}
+
+// findMany runs a multi-row cart query and loads the items and members of every cart
+func (r *PostgreSQLCartRepository) findMany(ctx context.Context, query string, args ...interface{}) ([]*model.Cart, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	carts := make([]*model.Cart, 0)
+
+	for rows.Next() {
+		cart, err := scanCart(rows)
+		if err != nil {
+			return nil, err
+		}
+		carts = append(carts, cart)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadItems(ctx, carts...); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadMembers(ctx, carts...); err != nil {
+		return nil, err
+	}
+
+	return carts, nil
+}
 
+// findOne runs a single-row cart query and loads the cart items and members
+func (r *PostgreSQLCartRepository) findOne(ctx context.Context, query string, arg interface{}) (*model.Cart, error) {
+	cart, err := scanCart(r.db.QueryRowContext(ctx, query, arg))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors.New("cart not found")
@@ -54,102 +155,466 @@ func (r *PostgreSQLCartRepository) FindByID(ctx context.Context, id uuid.UUID) (
 		return nil, err
 	}
 
-	// Deserialize items from JSON
-	var items []*model.CartItem
-	if err := json.Unmarshal(itemsJSON, &items); err != nil {
+	if err := r.loadItems(ctx, cart); err != nil {
 		return nil, err
 	}
 
-	cart := &model.Cart{
-		ID:        cartID,
-		UserID:    userID,
-		Items:     items,
-		CreatedAt: createdAt.Time,
-		UpdatedAt: updatedAt.Time,
+	if err := r.loadMembers(ctx, cart); err != nil {
+		return nil, err
 	}
 
 	return cart, nil
 }
 
-// FindByUserID retrieves the current active cart for a user
-func (r *PostgreSQLCartRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*model.Cart, error) {
-	query := `
-		SELECT id, user_id, items, created_at, updated_at
-		FROM carts
-		WHERE user_id = $1
-		ORDER BY created_at DESC
-		LIMIT 1
-	`
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
+// scanCart reads the cart columns of a row. Items and members are loaded separately.
+func scanCart(row rowScanner) (*model.Cart, error) {
 	var (
-		cartID    uuid.UUID
-		dbUserID  uuid.UUID
-		itemsJSON []byte
+		cart      model.Cart
 		createdAt sql.NullTime
 		updatedAt sql.NullTime
 	)
 
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(
-		&cartID,
-		&dbUserID,
-		&itemsJSON,
-		&createdAt,
-		&updatedAt,
-	)
+	if err := row.Scan(&cart.ID, &cart.UserID, &cart.Name, &cart.Active, &cart.Kiosk, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	cart.Items = make([]*model.CartItem, 0)
+	cart.SavedItems = make([]*model.CartItem, 0)
+	cart.Members = make([]*model.CartMember, 0)
+	cart.CreatedAt = createdAt.Time
+	cart.UpdatedAt = updatedAt.Time
+
+	return &cart, nil
+}
 
+// loadItems fills the items of the given carts with a single query
+func (r *PostgreSQLCartRepository) loadItems(ctx context.Context, carts ...*model.Cart) error {
+	if len(carts) == 0 {
+		return nil
+	}
+
+	cartsByID := make(map[uuid.UUID]*model.Cart, len(carts))
+	cartIDs := make([]string, len(carts))
+	for i, cart := range carts {
+		cartsByID[cart.ID] = cart
+		cartIDs[i] = cart.ID.String()
+	}
+
+	query := `
+		SELECT cart_id, id, product_id, variant_id, options, name, price, quantity, image_url, category, saved, added_by, contributions
+		FROM cart_items
+		WHERE cart_id = ANY($1::uuid[])
+		ORDER BY cart_id, position
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(cartIDs))
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("cart not found")
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cartID        uuid.UUID
+			item          model.CartItem
+			options       ItemOptionsJSON
+			saved         bool
+			addedBy       uuid.NullUUID
+			contributions ItemContributionsJSON
+		)
+
+		if err := rows.Scan(
+			&cartID,
+			&item.ID,
+			&item.ProductID,
+			&item.VariantID,
+			&options,
+			&item.Name,
+			&item.Price,
+			&item.Quantity,
+			&item.ImageURL,
+			&item.Category,
+			&saved,
+			&addedBy,
+			&contributions,
+		); err != nil {
+			return err
+		}
+		item.Options = optionsFromJSON(options)
+		item.Contributions = contributionsFromJSON(contributions)
+
+		cart := cartsByID[cartID]
+		// Lines from before carts could be shared were added by the owner
+		item.AddedBy = cart.UserID
+		if addedBy.Valid {
+			item.AddedBy = addedBy.UUID
+		}
+		if saved {
+			cart.SavedItems = append(cart.SavedItems, &item)
+		} else {
+			cart.Items = append(cart.Items, &item)
 		}
-		return nil, err
 	}
 
-	// Deserialize items from JSON
-	var items []*model.CartItem
-	if err := json.Unmarshal(itemsJSON, &items); err != nil {
-		return nil, err
+	return rows.Err()
+}
+
+// loadMembers fills the members of the given carts with a single query
+func (r *PostgreSQLCartRepository) loadMembers(ctx context.Context, carts ...*model.Cart) error {
+	if len(carts) == 0 {
+		return nil
 	}
 
-	cart := &model.Cart{
-		ID:        cartID,
-		UserID:    dbUserID,
-		Items:     items,
-		CreatedAt: createdAt.Time,
-		UpdatedAt: updatedAt.Time,
+	cartsByID := make(map[uuid.UUID]*model.Cart, len(carts))
+	cartIDs := make([]string, len(carts))
+	for i, cart := range carts {
+		cartsByID[cart.ID] = cart
+		cartIDs[i] = cart.ID.String()
 	}
 
-	return cart, nil
+	query := `
+		SELECT cart_id, user_id, role, joined_at
+		FROM cart_members
+		WHERE cart_id = ANY($1::uuid[])
+		ORDER BY cart_id, joined_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(cartIDs))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cartID uuid.UUID
+			member model.CartMember
+			role   string
+		)
+
+		if err := rows.Scan(&cartID, &member.UserID, &role, &member.JoinedAt); err != nil {
+			return err
+		}
+		member.Role = model.MemberRole(role)
+
+		cart := cartsByID[cartID]
+		cart.Members = append(cart.Members, &member)
+	}
+
+	return rows.Err()
+}
+
+// storedItem is the persisted state of a cart item, used to compute the changes to save
+type storedItem struct {
+	item     model.CartItem
+	position int
+	saved    bool
 }
 
-// Save persists a cart (creates or updates)
+// Save persists a cart (creates or updates). Only the items that were added, changed
+// or removed since the cart was loaded are written.
 func (r *PostgreSQLCartRepository) Save(ctx context.Context, cart *model.Cart) error {
-	// Serialize items to JSON
-	itemsJSON, err := json.Marshal(cart.Items)
+	return r.SaveAll(ctx, cart)
+}
+
+// SaveAll persists several carts in a single transaction
+func (r *PostgreSQLCartRepository) SaveAll(ctx context.Context, carts ...*model.Cart) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	query := `
-		INSERT INTO carts (id, user_id, items, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+	for _, cart := range carts {
+		if err := saveCart(ctx, tx, cart); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, cart := range carts {
+		cart.ActivitySaved()
+	}
+
+	return nil
+}
+
+// saveCart writes a cart, its changed items, its members and its new activity within a transaction
+func saveCart(ctx context.Context, tx *sql.Tx, cart *model.Cart) error {
+	cartQuery := `
+		INSERT INTO carts (id, user_id, name, active, kiosk, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		ON CONFLICT (id) DO UPDATE
-		SET items = $3, updated_at = $5
+		SET name = $3, updated_at = $7
 	`
 
-	_, err = r.db.ExecContext(
+	if _, err := tx.ExecContext(ctx, cartQuery, cart.ID, cart.UserID, cart.Name, cart.Active, cart.Kiosk, cart.CreatedAt, cart.UpdatedAt); err != nil {
+		return err
+	}
+
+	stored, err := storedItems(ctx, tx, cart.ID)
+	if err != nil {
+		return err
+	}
+
+	upsertQuery := `
+		INSERT INTO cart_items (id, cart_id, product_id, name, price, quantity, image_url, category, position, variant_id, options, saved, added_by, contributions)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (id) DO UPDATE
+		SET product_id = $3, name = $4, price = $5, quantity = $6, image_url = $7, category = $8, position = $9,
+			variant_id = $10, options = $11, saved = $12, added_by = $13, contributions = $14
+	`
+
+	lists := []struct {
+		items []*model.CartItem
+		saved bool
+	}{
+		{items: cart.Items, saved: false},
+		{items: cart.SavedItems, saved: true},
+	}
+
+	for _, list := range lists {
+		for position, item := range list.items {
+			previous, exists := stored[item.ID]
+			delete(stored, item.ID)
+			if exists && previous.position == position && previous.saved == list.saved && sameItem(&previous.item, item) {
+				continue
+			}
+
+			if _, err := tx.ExecContext(
+				ctx,
+				upsertQuery,
+				item.ID,
+				cart.ID,
+				item.ProductID,
+				item.Name,
+				item.Price,
+				item.Quantity,
+				item.ImageURL,
+				item.Category,
+				position,
+				item.VariantID,
+				optionsToJSON(item.Options),
+				list.saved,
+				item.AddedBy,
+				contributionsToJSON(item.Contributors()),
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Whatever is left in stored was removed from the cart
+	for itemID := range stored {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM cart_items WHERE id = $1`, itemID); err != nil {
+			return err
+		}
+	}
+
+	if err := saveMembers(ctx, tx, cart); err != nil {
+		return err
+	}
+
+	return saveActivity(ctx, tx, cart)
+}
+
+// saveMembers writes the members of a cart and removes the ones that left
+func saveMembers(ctx context.Context, tx *sql.Tx, cart *model.Cart) error {
+	memberIDs := make([]string, len(cart.Members))
+	for i, member := range cart.Members {
+		memberIDs[i] = member.UserID.String()
+	}
+
+	if _, err := tx.ExecContext(
 		ctx,
-		query,
+		`DELETE FROM cart_members WHERE cart_id = $1 AND NOT (user_id = ANY($2::uuid[]))`,
 		cart.ID,
-		cart.UserID,
-		itemsJSON,
-		cart.CreatedAt,
-		cart.UpdatedAt,
-	)
+		pq.Array(memberIDs),
+	); err != nil {
+		return err
+	}
 
-	return err
+	query := `
+		INSERT INTO cart_members (cart_id, user_id, role, joined_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (cart_id, user_id) DO UPDATE
+		SET role = $3
+	`
+
+	for _, member := range cart.Members {
+		if _, err := tx.ExecContext(ctx, query, cart.ID, member.UserID, string(member.Role), member.JoinedAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveActivity appends the changes recorded since the cart was loaded to its activity
+func saveActivity(ctx context.Context, tx *sql.Tx, cart *model.Cart) error {
+	query := `
+		INSERT INTO cart_activity (id, cart_id, actor_id, operation, changes, reverts_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	for _, activity := range cart.PendingActivity() {
+		changes, err := json.Marshal(activity.Changes)
+		if err != nil {
+			return err
+		}
+
+		var actorID *uuid.UUID
+		if activity.ActorID != uuid.Nil {
+			actorID = &activity.ActorID
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			query,
+			activity.ID,
+			activity.CartID,
+			actorID,
+			string(activity.Operation),
+			changes,
+			activity.RevertsID,
+			activity.CreatedAt,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetActive makes a cart the active cart of its user, deactivating the others
+func (r *PostgreSQLCartRepository) SetActive(ctx context.Context, userID uuid.UUID, cartID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Deactivate first, so the one-active-cart-per-user index is never violated
+	if _, err := tx.ExecContext(ctx, `UPDATE carts SET active = false WHERE user_id = $1 AND id <> $2 AND active`, userID, cartID); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `UPDATE carts SET active = true WHERE user_id = $1 AND id = $2`, userID, cartID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("cart not found")
+	}
+
+	return tx.Commit()
+}
+
+// storedItems reads the persisted items of a cart, locking them until the transaction ends
+func storedItems(ctx context.Context, tx *sql.Tx, cartID uuid.UUID) (map[uuid.UUID]*storedItem, error) {
+	query := `
+		SELECT id, product_id, variant_id, options, name, price, quantity, image_url, category, position, saved, added_by, contributions
+		FROM cart_items
+		WHERE cart_id = $1
+		FOR UPDATE
+	`
+
+	rows, err := tx.QueryContext(ctx, query, cartID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stored := make(map[uuid.UUID]*storedItem)
+
+	for rows.Next() {
+		var (
+			s             storedItem
+			options       ItemOptionsJSON
+			addedBy       uuid.NullUUID
+			contributions ItemContributionsJSON
+		)
+		if err := rows.Scan(
+			&s.item.ID,
+			&s.item.ProductID,
+			&s.item.VariantID,
+			&options,
+			&s.item.Name,
+			&s.item.Price,
+			&s.item.Quantity,
+			&s.item.ImageURL,
+			&s.item.Category,
+			&s.position,
+			&s.saved,
+			&addedBy,
+			&contributions,
+		); err != nil {
+			return nil, err
+		}
+		s.item.Options = optionsFromJSON(options)
+		s.item.AddedBy = addedBy.UUID
+		s.item.Contributions = contributionsFromJSON(contributions)
+		stored[s.item.ID] = &s
+	}
+
+	return stored, rows.Err()
+}
+
+// sameItem returns true if the persisted columns of both items are equal
+func sameItem(a, b *model.CartItem) bool {
+	return a.ProductID == b.ProductID &&
+		a.VariantID == b.VariantID &&
+		sameOptions(a.Options, b.Options) &&
+		a.Name == b.Name &&
+		a.Price == b.Price &&
+		a.Quantity == b.Quantity &&
+		a.ImageURL == b.ImageURL &&
+		a.Category == b.Category &&
+		a.AddedBy == b.AddedBy &&
+		sameContributions(a.Contributions, b.Contributors())
+}
+
+// sameContributions returns true if both lines were added by the same users in the same quantities
+func sameContributions(a, b []model.ItemContribution) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sameOptions returns true if both option sets are equal, surcharges included
+func sameOptions(a, b []model.ItemOption) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
 }
 
-// Delete removes a cart
+// Delete removes a cart. Its items are removed by the foreign key cascade.
 func (r *PostgreSQLCartRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM carts WHERE id = $1`
 
@@ -0,0 +1,128 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/repository"
+)
+
+// PostgreSQLCartSnapshotRepository implements the CartSnapshotRepository interface using PostgreSQL
+type PostgreSQLCartSnapshotRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLCartSnapshotRepository creates a new PostgreSQL repository for cart snapshots
+func NewPostgreSQLCartSnapshotRepository(db *sql.DB) repository.CartSnapshotRepository {
+	return &PostgreSQLCartSnapshotRepository{
+		db: db,
+	}
+}
+
+// FindByToken retrieves a snapshot by its share token
+func (r *PostgreSQLCartSnapshotRepository) FindByToken(ctx context.Context, token string) (*model.CartSnapshot, error) {
+	query := `
+		SELECT id, cart_id, created_by, name, token, items, created_at
+		FROM cart_snapshots
+		WHERE token = $1
+	`
+
+	snapshot, err := scanCartSnapshot(r.db.QueryRowContext(ctx, query, token))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("snapshot not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// FindByCartID retrieves the snapshots taken of a cart, newest first
+func (r *PostgreSQLCartSnapshotRepository) FindByCartID(ctx context.Context, cartID uuid.UUID) ([]*model.CartSnapshot, error) {
+	query := `
+		SELECT id, cart_id, created_by, name, token, items, created_at
+		FROM cart_snapshots
+		WHERE cart_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, cartID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshots := make([]*model.CartSnapshot, 0)
+
+	for rows.Next() {
+		snapshot, err := scanCartSnapshot(rows)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// Create persists a new snapshot
+func (r *PostgreSQLCartSnapshotRepository) Create(ctx context.Context, snapshot *model.CartSnapshot) error {
+	items, err := json.Marshal(snapshot.Items)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO cart_snapshots (id, cart_id, created_by, name, token, items, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err = r.db.ExecContext(
+		ctx,
+		query,
+		snapshot.ID,
+		snapshot.CartID,
+		snapshot.CreatedBy,
+		snapshot.Name,
+		snapshot.Token,
+		items,
+		snapshot.CreatedAt,
+	)
+
+	return err
+}
+
+// scanCartSnapshot reads a snapshot row
+func scanCartSnapshot(row rowScanner) (*model.CartSnapshot, error) {
+	var (
+		snapshot model.CartSnapshot
+		items    []byte
+	)
+
+	if err := row.Scan(
+		&snapshot.ID,
+		&snapshot.CartID,
+		&snapshot.CreatedBy,
+		&snapshot.Name,
+		&snapshot.Token,
+		&items,
+		&snapshot.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(items, &snapshot.Items); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
@@ -4,18 +4,33 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+	"gorm.io/gorm"
 )
 
 // CartModel is the PostgreSQL representation of a cart
 type CartModel struct {
-	ID        uuid.UUID     `gorm:"type:uuid;primaryKey"`
-	UserID    uuid.UUID     `gorm:"type:uuid;not null"`
-	Items     CartItemsJSON `gorm:"type:jsonb"`
-	CreatedAt time.Time     `gorm:"not null;default:now()"`
-	UpdatedAt time.Time     `gorm:"not null;default:now()"`
+	ID     uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Name   string    `gorm:"type:varchar(100);not null;default:'My cart'"`
+	// Active marks the cart the user is shopping with; MigrateActiveCarts adds the
+	// index that allows a single active cart per user
+	Active bool `gorm:"not null;default:false"`
+	// Kiosk marks the carts of kiosk terminal sessions
+	Kiosk bool `gorm:"not null;default:false"`
+	// LegacyItems is the JSONB column carts used before cart_items existed. It is kept
+	// only so MigrateJSONItems can move old carts; new carts leave it NULL.
+	LegacyItems CartItemsJSON         `gorm:"column:items;type:jsonb"`
+	Items       []CartItemModel       `gorm:"foreignKey:CartID;constraint:OnDelete:CASCADE"`
+	Members     []CartMemberModel     `gorm:"foreignKey:CartID;constraint:OnDelete:CASCADE"`
+	Invitations []CartInvitationModel `gorm:"foreignKey:CartID;constraint:OnDelete:CASCADE"`
+	Activity    []CartActivityModel   `gorm:"foreignKey:CartID;constraint:OnDelete:CASCADE"`
+	CreatedAt   time.Time             `gorm:"not null;default:now()"`
+	UpdatedAt   time.Time             `gorm:"not null;default:now()"`
 }
 
 // TableName overrides the table name for GORM
@@ -23,6 +38,189 @@ func (CartModel) TableName() string {
 	return "carts"
 }
 
+// CartItemModel is the PostgreSQL representation of a cart item
+type CartItemModel struct {
+	ID        uuid.UUID       `gorm:"type:uuid;primaryKey"`
+	CartID    uuid.UUID       `gorm:"type:uuid;not null;index"`
+	ProductID uuid.UUID       `gorm:"type:uuid;not null;index"`
+	VariantID string          `gorm:"type:varchar(100);not null;default:''"`
+	Options   ItemOptionsJSON `gorm:"type:jsonb;not null;default:'[]'"`
+	Name      string          `gorm:"type:varchar(255);not null"`
+	Price     float64         `gorm:"type:decimal(10,2);not null"`
+	Quantity  int             `gorm:"type:integer;not null"`
+	ImageURL  string          `gorm:"type:text;not null;default:''"`
+	Category  string          `gorm:"type:varchar(100);not null;default:''"`
+	Position  int             `gorm:"type:integer;not null;default:0"`
+	// Saved marks the items in the saved-for-later list
+	Saved bool `gorm:"not null;default:false"`
+	// AddedBy is the user who first added the line; MigrateItemContributors fills it for old lines
+	AddedBy *uuid.UUID `gorm:"type:uuid"`
+	// Contributions splits the quantity among the users who added it
+	Contributions ItemContributionsJSON `gorm:"type:jsonb;not null;default:'[]'"`
+}
+
+// TableName overrides the table name for GORM
+func (CartItemModel) TableName() string {
+	return "cart_items"
+}
+
+// CartMemberModel is the PostgreSQL representation of a member of a shared cart
+type CartMemberModel struct {
+	CartID   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID   uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	Role     string    `gorm:"type:varchar(20);not null"`
+	JoinedAt time.Time `gorm:"not null;default:now()"`
+}
+
+// TableName overrides the table name for GORM
+func (CartMemberModel) TableName() string {
+	return "cart_members"
+}
+
+// CartInvitationModel is the PostgreSQL representation of an invitation to a shared cart
+type CartInvitationModel struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	CartID      uuid.UUID `gorm:"type:uuid;not null;index"`
+	InviteeID   uuid.UUID `gorm:"type:uuid;not null;index"`
+	InvitedBy   uuid.UUID `gorm:"type:uuid;not null"`
+	Role        string    `gorm:"type:varchar(20);not null"`
+	Status      string    `gorm:"type:varchar(20);not null"`
+	CreatedAt   time.Time `gorm:"not null;default:now()"`
+	ExpiresAt   time.Time `gorm:"not null"`
+	RespondedAt *time.Time
+}
+
+// TableName overrides the table name for GORM
+func (CartInvitationModel) TableName() string {
+	return "cart_invitations"
+}
+
+// CartActivityModel is the PostgreSQL representation of a cart activity entry. Rows are only inserted.
+type CartActivityModel struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	CartID    uuid.UUID  `gorm:"type:uuid;not null;index:idx_cart_activity_cart,priority:1"`
+	ActorID   *uuid.UUID `gorm:"type:uuid"`
+	Operation string     `gorm:"type:varchar(30);not null"`
+	Changes   string     `gorm:"type:jsonb;not null"`
+	RevertsID *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt time.Time  `gorm:"not null;default:now();index:idx_cart_activity_cart,priority:2"`
+}
+
+// TableName overrides the table name for GORM
+func (CartActivityModel) TableName() string {
+	return "cart_activity"
+}
+
+// ItemOptionsJSON is a custom type for storing the options of a cart item as JSON in PostgreSQL
+type ItemOptionsJSON []ItemOptionJSON
+
+// ItemOptionJSON is the JSON representation of a cart item option
+type ItemOptionJSON struct {
+	Name      string  `json:"name"`
+	Value     string  `json:"value"`
+	Surcharge float64 `json:"surcharge,omitempty"`
+}
+
+// Value implements the driver.Valuer interface for ItemOptionsJSON
+func (o ItemOptionsJSON) Value() (driver.Value, error) {
+	if o == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(o)
+}
+
+// Scan implements the sql.Scanner interface for ItemOptionsJSON
+func (o *ItemOptionsJSON) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, &o)
+}
+
+// optionsToJSON converts the options of a cart item to their JSON representation
+func optionsToJSON(options []model.ItemOption) ItemOptionsJSON {
+	result := make(ItemOptionsJSON, len(options))
+	for i, option := range options {
+		result[i] = ItemOptionJSON{
+			Name:      option.Name,
+			Value:     option.Value,
+			Surcharge: option.Surcharge,
+		}
+	}
+	return result
+}
+
+// optionsFromJSON converts the JSON representation of cart item options to domain options
+func optionsFromJSON(options ItemOptionsJSON) []model.ItemOption {
+	if len(options) == 0 {
+		return nil
+	}
+	result := make([]model.ItemOption, len(options))
+	for i, option := range options {
+		result[i] = model.ItemOption{
+			Name:      option.Name,
+			Value:     option.Value,
+			Surcharge: option.Surcharge,
+		}
+	}
+	return result
+}
+
+// ItemContributionsJSON is a custom type for storing the contributions to a cart item as JSON in PostgreSQL
+type ItemContributionsJSON []ItemContributionJSON
+
+// ItemContributionJSON is the JSON representation of the part of a cart item added by a user
+type ItemContributionJSON struct {
+	UserID   uuid.UUID `json:"userId"`
+	Quantity int       `json:"quantity"`
+}
+
+// Value implements the driver.Valuer interface for ItemContributionsJSON
+func (c ItemContributionsJSON) Value() (driver.Value, error) {
+	if c == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(c)
+}
+
+// Scan implements the sql.Scanner interface for ItemContributionsJSON
+func (c *ItemContributionsJSON) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, &c)
+}
+
+// contributionsToJSON converts the contributions to a cart item to their JSON representation
+func contributionsToJSON(contributions []model.ItemContribution) ItemContributionsJSON {
+	result := make(ItemContributionsJSON, len(contributions))
+	for i, contribution := range contributions {
+		result[i] = ItemContributionJSON{
+			UserID:   contribution.UserID,
+			Quantity: contribution.Quantity,
+		}
+	}
+	return result
+}
+
+// contributionsFromJSON converts the JSON representation of the contributions to a cart item
+// to domain contributions
+func contributionsFromJSON(contributions ItemContributionsJSON) []model.ItemContribution {
+	if len(contributions) == 0 {
+		return nil
+	}
+	result := make([]model.ItemContribution, len(contributions))
+	for i, contribution := range contributions {
+		result[i] = model.ItemContribution{
+			UserID:   contribution.UserID,
+			Quantity: contribution.Quantity,
+		}
+	}
+	return result
+}
+
 // CartItemsJSON is a custom type for storing cart items as JSON in PostgreSQL
 type CartItemsJSON []*CartItemJSON
 
@@ -34,6 +232,7 @@ type CartItemJSON struct {
 	Price     float64   `json:"price"`
 	Quantity  int       `json:"quantity"`
 	ImageURL  string    `json:"imageUrl"`
+	Category  string    `json:"category,omitempty"`
 }
 
 // Value implements the driver.Valuer interface for CartItemsJSON
@@ -49,3 +248,138 @@ func (c *CartItemsJSON) Scan(value interface{}) error {
 	}
 	return json.Unmarshal(b, &c)
 }
+
+// migrateJSONItemsQuery copies the items of the legacy JSONB column into cart_items,
+// keeping their IDs and order. Items already migrated are skipped, so it can run again.
+const migrateJSONItemsQuery = `
+	INSERT INTO cart_items (id, cart_id, product_id, name, price, quantity, image_url, category, position)
+	SELECT
+		(item->>'id')::uuid,
+		c.id,
+		(item->>'productId')::uuid,
+		COALESCE(item->>'name', ''),
+		COALESCE((item->>'price')::numeric, 0),
+		COALESCE((item->>'quantity')::integer, 1),
+		COALESCE(item->>'imageUrl', ''),
+		COALESCE(item->>'category', ''),
+		ordinality - 1
+	FROM carts c
+	CROSS JOIN LATERAL jsonb_array_elements(c.items) WITH ORDINALITY AS t(item, ordinality)
+	WHERE c.items IS NOT NULL AND jsonb_typeof(c.items) = 'array'
+	ON CONFLICT (id) DO NOTHING
+`
+
+// MigrateJSONItems moves the items stored in the legacy carts.items JSONB column to the
+// cart_items table and clears the column, in a single transaction
+func MigrateJSONItems(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Exec(migrateJSONItemsQuery)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("Migrated %d cart items from carts.items", result.RowsAffected)
+		}
+		return tx.Exec(`UPDATE carts SET items = NULL WHERE items IS NOT NULL`).Error
+	})
+}
+
+// MigrateActiveCarts enforces a single active cart per user and, for users without one,
+// activates their most recent cart, which is the cart they were using before carts had
+// names. It is idempotent.
+func MigrateActiveCarts(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE carts SET active = true
+			WHERE id IN (
+				SELECT DISTINCT ON (user_id) id
+				FROM carts
+				ORDER BY user_id, created_at DESC
+			)
+			AND NOT EXISTS (
+				SELECT 1 FROM carts other WHERE other.user_id = carts.user_id AND other.active
+			)
+		`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_carts_active_user ON carts (user_id) WHERE active`).Error
+	})
+}
+
+// MigrateItemContributors records the cart owner as the contributor of the lines added
+// before carts could be shared, and the contributor of the lines added before the quantity
+// of a line was split among its contributors. It is idempotent.
+func MigrateItemContributors(db *gorm.DB) error {
+	result := db.Exec(`
+		UPDATE cart_items SET added_by = carts.user_id
+		FROM carts
+		WHERE cart_items.cart_id = carts.id AND cart_items.added_by IS NULL
+	`)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Recorded the contributor of %d cart items", result.RowsAffected)
+	}
+
+	result = db.Exec(`
+		UPDATE cart_items
+		SET contributions = jsonb_build_array(jsonb_build_object('userId', added_by, 'quantity', quantity))
+		WHERE contributions = '[]'::jsonb AND added_by IS NOT NULL
+	`)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Recorded the contributions to %d cart items", result.RowsAffected)
+	}
+	return nil
+}
+
+// PurchaseRuleModel is the PostgreSQL representation of a product purchase rule
+type PurchaseRuleModel struct {
+	ProductID               uuid.UUID `gorm:"type:uuid;primaryKey"`
+	MaxPerOrder             int       `gorm:"type:integer;not null;default:0"`
+	MaxPerUser              int       `gorm:"type:integer;not null;default:0"`
+	MaxPerUserWindowSeconds int64     `gorm:"type:bigint;not null;default:0"`
+	MinQuantity             int       `gorm:"type:integer;not null;default:0"`
+	PackSize                int       `gorm:"type:integer;not null;default:0"`
+	UpdatedAt               time.Time `gorm:"not null;default:now()"`
+}
+
+// TableName overrides the table name for GORM
+func (PurchaseRuleModel) TableName() string {
+	return "purchase_rules"
+}
+
+// CartSnapshotModel is the PostgreSQL representation of a cart snapshot. Snapshots outlive
+// their cart, so CartID is not a foreign key.
+type CartSnapshotModel struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	CartID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	CreatedBy uuid.UUID `gorm:"type:uuid;not null"`
+	Name      string    `gorm:"type:varchar(100);not null"`
+	Token     string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	Items     string    `gorm:"type:jsonb;not null"`
+	CreatedAt time.Time `gorm:"not null;default:now()"`
+}
+
+// TableName overrides the table name for GORM
+func (CartSnapshotModel) TableName() string {
+	return "cart_snapshots"
+}
+
+// ProductBarcodeModel is the PostgreSQL representation of the product a barcode or SKU identifies
+type ProductBarcodeModel struct {
+	Code      string    `gorm:"type:varchar(32);primaryKey"`
+	Format    string    `gorm:"type:varchar(10);not null"`
+	ProductID uuid.UUID `gorm:"type:uuid;not null;index"`
+	VariantID string    `gorm:"type:varchar(100);not null;default:''"`
+	Category  string    `gorm:"type:varchar(100);not null;default:''"`
+	UpdatedAt time.Time `gorm:"not null;default:now()"`
+}
+
+// TableName overrides the table name for GORM
+func (ProductBarcodeModel) TableName() string {
+	return "product_barcodes"
+}
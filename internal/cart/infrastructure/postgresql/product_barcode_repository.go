@@ -0,0 +1,136 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/repository"
+)
+
+// PostgreSQLProductBarcodeRepository implements the ProductBarcodeRepository interface using PostgreSQL
+type PostgreSQLProductBarcodeRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLProductBarcodeRepository creates a new PostgreSQL repository for product barcodes
+func NewPostgreSQLProductBarcodeRepository(db *sql.DB) repository.ProductBarcodeRepository {
+	return &PostgreSQLProductBarcodeRepository{
+		db: db,
+	}
+}
+
+// FindByCode retrieves the product of a normalized code
+func (r *PostgreSQLProductBarcodeRepository) FindByCode(ctx context.Context, code string) (*model.ProductBarcode, error) {
+	query := `
+		SELECT code, format, product_id, variant_id, category, updated_at
+		FROM product_barcodes
+		WHERE code = $1
+	`
+
+	barcode, err := scanProductBarcode(r.db.QueryRowContext(ctx, query, code))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("unknown barcode")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return barcode, nil
+}
+
+// FindAll retrieves every code
+func (r *PostgreSQLProductBarcodeRepository) FindAll(ctx context.Context) ([]*model.ProductBarcode, error) {
+	query := `
+		SELECT code, format, product_id, variant_id, category, updated_at
+		FROM product_barcodes
+		ORDER BY code
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	barcodes := make([]*model.ProductBarcode, 0)
+
+	for rows.Next() {
+		barcode, err := scanProductBarcode(rows)
+		if err != nil {
+			return nil, err
+		}
+		barcodes = append(barcodes, barcode)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return barcodes, nil
+}
+
+// Save persists a code (creates or updates)
+func (r *PostgreSQLProductBarcodeRepository) Save(ctx context.Context, barcode *model.ProductBarcode) error {
+	query := `
+		INSERT INTO product_barcodes (code, format, product_id, variant_id, category, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (code) DO UPDATE
+		SET format = $2, product_id = $3, variant_id = $4, category = $5, updated_at = $6
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		barcode.Code,
+		string(barcode.Format),
+		barcode.ProductID,
+		barcode.VariantID,
+		barcode.Category,
+		barcode.UpdatedAt,
+	)
+
+	return err
+}
+
+// Delete removes a code
+func (r *PostgreSQLProductBarcodeRepository) Delete(ctx context.Context, code string) error {
+	query := `DELETE FROM product_barcodes WHERE code = $1`
+
+	result, err := r.db.ExecContext(ctx, query, code)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("unknown barcode")
+	}
+
+	return nil
+}
+
+// scanProductBarcode reads a product barcode row
+func scanProductBarcode(row rowScanner) (*model.ProductBarcode, error) {
+	var barcode model.ProductBarcode
+	var format string
+
+	if err := row.Scan(
+		&barcode.Code,
+		&format,
+		&barcode.ProductID,
+		&barcode.VariantID,
+		&barcode.Category,
+		&barcode.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	barcode.Format = model.BarcodeFormat(format)
+
+	return &barcode, nil
+}
@@ -0,0 +1,175 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/repository"
+)
+
+// PostgreSQLPurchaseRuleRepository implements the PurchaseRuleRepository interface using PostgreSQL
+type PostgreSQLPurchaseRuleRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLPurchaseRuleRepository creates a new PostgreSQL repository for purchase rules
+func NewPostgreSQLPurchaseRuleRepository(db *sql.DB) repository.PurchaseRuleRepository {
+	return &PostgreSQLPurchaseRuleRepository{
+		db: db,
+	}
+}
+
+// FindByProductID retrieves the purchase rule of a product
+func (r *PostgreSQLPurchaseRuleRepository) FindByProductID(ctx context.Context, productID uuid.UUID) (*model.ProductPurchaseRule, error) {
+	query := `
+		SELECT product_id, max_per_order, max_per_user, max_per_user_window_seconds, min_quantity, pack_size, updated_at
+		FROM purchase_rules
+		WHERE product_id = $1
+	`
+
+	rule, err := scanPurchaseRule(r.db.QueryRowContext(ctx, query, productID))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("purchase rule not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+// FindAll retrieves every purchase rule
+func (r *PostgreSQLPurchaseRuleRepository) FindAll(ctx context.Context) ([]*model.ProductPurchaseRule, error) {
+	query := `
+		SELECT product_id, max_per_order, max_per_user, max_per_user_window_seconds, min_quantity, pack_size, updated_at
+		FROM purchase_rules
+		ORDER BY updated_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := make([]*model.ProductPurchaseRule, 0)
+
+	for rows.Next() {
+		rule, err := scanPurchaseRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// Save persists a purchase rule (creates or updates)
+func (r *PostgreSQLPurchaseRuleRepository) Save(ctx context.Context, rule *model.ProductPurchaseRule) error {
+	query := `
+		INSERT INTO purchase_rules (product_id, max_per_order, max_per_user, max_per_user_window_seconds, min_quantity, pack_size, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (product_id) DO UPDATE
+		SET max_per_order = $2, max_per_user = $3, max_per_user_window_seconds = $4, min_quantity = $5, pack_size = $6, updated_at = $7
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		rule.ProductID,
+		rule.MaxPerOrder,
+		rule.MaxPerUser,
+		int64(rule.MaxPerUserWindow/time.Second),
+		rule.MinQuantity,
+		rule.PackSize,
+		rule.UpdatedAt,
+	)
+
+	return err
+}
+
+// Delete removes the purchase rule of a product
+func (r *PostgreSQLPurchaseRuleRepository) Delete(ctx context.Context, productID uuid.UUID) error {
+	query := `DELETE FROM purchase_rules WHERE product_id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, productID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("purchase rule not found")
+	}
+
+	return nil
+}
+
+// scanPurchaseRule reads a purchase rule row
+func scanPurchaseRule(row rowScanner) (*model.ProductPurchaseRule, error) {
+	var rule model.ProductPurchaseRule
+	var windowSeconds int64
+
+	err := row.Scan(
+		&rule.ProductID,
+		&rule.MaxPerOrder,
+		&rule.MaxPerUser,
+		&windowSeconds,
+		&rule.MinQuantity,
+		&rule.PackSize,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.MaxPerUserWindow = time.Duration(windowSeconds) * time.Second
+	return &rule, nil
+}
+
+// PostgreSQLPurchaseHistory implements the PurchaseHistory interface over the placed checkouts
+type PostgreSQLPurchaseHistory struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLPurchaseHistory creates a new PostgreSQL purchase history
+func NewPostgreSQLPurchaseHistory(db *sql.DB) repository.PurchaseHistory {
+	return &PostgreSQLPurchaseHistory{
+		db: db,
+	}
+}
+
+// QuantityPurchased returns the quantity of a product the user bought since the given time.
+// Checkouts awaiting payment at the counter count as bought, since their units are reserved,
+// and checkouts are dated by when they were started, since refunds and cash payments update them later.
+func (h *PostgreSQLPurchaseHistory) QuantityPurchased(ctx context.Context, userID, productID uuid.UUID, since time.Time) (int, error) {
+	query := `
+		SELECT COALESCE(SUM((item->>'quantity')::integer), 0)
+		FROM checkouts c
+		CROSS JOIN LATERAL jsonb_array_elements(c.items) item
+		WHERE c.user_id = $1
+		AND c.status IN ('COMPLETED', 'AWAITING_PAYMENT')
+		AND c.created_at >= $3
+		AND item->>'productId' = $2
+	`
+
+	var quantity int
+	if err := h.db.QueryRowContext(ctx, query, userID, productID.String(), since).Scan(&quantity); err != nil {
+		return 0, err
+	}
+
+	return quantity, nil
+}
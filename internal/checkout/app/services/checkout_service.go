@@ -3,17 +3,45 @@ package services
 import (
 	"context"
 	"errors"
+	"log"
+	"time"
 
 	"github.com/google/uuid"
+	cartServices "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services"
+	cartDto "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services/dto"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/app/services/dto"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/repository"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/auth"
+	invoicingServices "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/app/services"
+	invoicingModel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/domain/model"
+	loyaltyServices "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/loyalty/app/services"
+	loyaltyModel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/loyalty/domain/model"
+	notificationServices "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/app/services"
+	notificationModel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/domain/model"
+	segmentServices "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/app/services"
+	segmentModel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/domain/model"
+	webhookServices "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/app/services"
+	webhookModel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/domain/model"
 )
 
+// maxPaymentCodeAttempts limits the payment codes drawn for a checkout whose code is taken
+const maxPaymentCodeAttempts = 5
+
 // CheckoutService handles operations related to the checkout process
 type CheckoutService struct {
-	checkoutRepository repository.CheckoutRepository
-	shippingRepository repository.ShippingRepository
+	checkoutRepository        repository.CheckoutRepository
+	shippingRepository        repository.ShippingRepository
+	installmentPlanRepository repository.InstallmentPlanRepository
+	giftCardRepository        repository.GiftCardRepository
+	cartService               *cartServices.CartService
+	loyaltyService            *loyaltyServices.LoyaltyService
+	segmentService            *segmentServices.SegmentService
+	invoicingService          *invoicingServices.InvoicingService
+	notificationService       *notificationServices.NotificationService
+	webhookService            *webhookServices.WebhookService
+	// cashPaymentWindow is how long a cash on pickup checkout waits to be paid at the counter
+	cashPaymentWindow time.Duration
 	// External service clients would be injected here
 	// productClient, inventoryClient, etc.
 }
@@ -22,10 +50,28 @@ type CheckoutService struct {
 func NewCheckoutService(
 	checkoutRepository repository.CheckoutRepository,
 	shippingRepository repository.ShippingRepository,
+	installmentPlanRepository repository.InstallmentPlanRepository,
+	giftCardRepository repository.GiftCardRepository,
+	cartService *cartServices.CartService,
+	loyaltyService *loyaltyServices.LoyaltyService,
+	segmentService *segmentServices.SegmentService,
+	invoicingService *invoicingServices.InvoicingService,
+	notificationService *notificationServices.NotificationService,
+	webhookService *webhookServices.WebhookService,
+	cashPaymentWindow time.Duration,
 ) *CheckoutService {
 	return &CheckoutService{
-		checkoutRepository: checkoutRepository,
-		shippingRepository: shippingRepository,
+		checkoutRepository:        checkoutRepository,
+		shippingRepository:        shippingRepository,
+		installmentPlanRepository: installmentPlanRepository,
+		giftCardRepository:        giftCardRepository,
+		cartService:               cartService,
+		loyaltyService:            loyaltyService,
+		segmentService:            segmentService,
+		invoicingService:          invoicingService,
+		notificationService:       notificationService,
+		webhookService:            webhookService,
+		cashPaymentWindow:         cashPaymentWindow,
 	}
 }
 
@@ -37,25 +83,41 @@ func (s *CheckoutService) InitiateCheckout(ctx context.Context, req *dto.Checkou
 	}
 
 	// In a real implementation:
-	// 1. Call Cart service to get cart details
-	// 2. Check if cart exists and is not empty
-	// 3. Check if items are in stock (via inventory service)
-	// 4. Reserve inventory
-
-	// For demonstration, we'll create a mock cart items list
-	// In a real implementation, these would come from the cart service
-	userID := uuid.New() // In reality, this would come from the cart or auth context
-	items := []*model.CheckoutItem{
-		{
-			ProductID: uuid.New(),
-			Name:      "Sample Product",
-			Price:     29.99,
-			Quantity:  2,
-			Subtotal:  59.98,
-			ImageURL:  "https://example.com/product.jpg",
-		},
-	}
-	subtotal := 59.98
+	// 1. Check if items are in stock (via inventory service)
+	// 2. Reserve inventory
+
+	// Only the cart owner can check it out, since the purchase is charged to them
+	actor, ok := auth.UserFrom(ctx)
+	if !ok {
+		return nil, errors.New("user ID is required")
+	}
+	cart, err := s.cartService.GetCartForCheckout(cartServices.WithActor(ctx, actor), cartID.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(cart.Items) == 0 {
+		return nil, errors.New("cart is empty")
+	}
+
+	userID, err := uuid.Parse(cart.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := checkoutItemsFromCart(cart)
+	if err != nil {
+		return nil, err
+	}
+
+	// Charge the customer segment prices (student or staff) the user is entitled to
+	if err := s.applySegmentPrices(ctx, userID, items); err != nil {
+		return nil, err
+	}
+
+	subtotal := 0.0
+	for _, item := range items {
+		subtotal += item.Subtotal
+	}
 
 	// Create a new checkout
 	checkout, err := model.NewCheckout(cartID, userID, items, subtotal)
@@ -63,16 +125,26 @@ func (s *CheckoutService) InitiateCheckout(ctx context.Context, req *dto.Checkou
 		return nil, err
 	}
 
+	// The purchase limits were checked against this cart only; check them against what the
+	// user already bought with other carts
+	if err := s.checkPurchaseRules(ctx, checkout); err != nil {
+		return nil, err
+	}
+
 	// Store the checkout
 	if err := s.checkoutRepository.Save(ctx, checkout); err != nil {
 		return nil, err
 	}
 
-	return dto.CheckoutFromDomain(checkout), nil
+	response := dto.CheckoutFromDomain(checkout)
+	s.publishEvent(ctx, webhookModel.EventCheckoutInitiated, response)
+
+	return response, nil
 }
 
-// GetCheckout retrieves a checkout by ID
-func (s *CheckoutService) GetCheckout(ctx context.Context, checkoutID string) (*dto.CheckoutResponseDTO, error) {
+// findCheckout loads a checkout the request may act on: its own user's or, for the back
+// office, anyone's
+func (s *CheckoutService) findCheckout(ctx context.Context, checkoutID string) (*model.Checkout, error) {
 	id, err := uuid.Parse(checkoutID)
 	if err != nil {
 		return nil, errors.New("invalid checkout ID format")
@@ -82,18 +154,25 @@ func (s *CheckoutService) GetCheckout(ctx context.Context, checkoutID string) (*
 	if err != nil {
 		return nil, err
 	}
+	if !auth.CanActFor(ctx, checkout.UserID) {
+		return nil, errors.New("checkout access denied")
+	}
+	return checkout, nil
+}
+
+// GetCheckout retrieves a checkout by ID
+func (s *CheckoutService) GetCheckout(ctx context.Context, checkoutID string) (*dto.CheckoutResponseDTO, error) {
+	checkout, err := s.findCheckout(ctx, checkoutID)
+	if err != nil {
+		return nil, err
+	}
 
 	return dto.CheckoutFromDomain(checkout), nil
 }
 
 // UpdateShipping updates the shipping details for a checkout
 func (s *CheckoutService) UpdateShipping(ctx context.Context, checkoutID string, req *dto.ShippingDetailsRequest) (*dto.CheckoutResponseDTO, error) {
-	id, err := uuid.Parse(checkoutID)
-	if err != nil {
-		return nil, errors.New("invalid checkout ID format")
-	}
-
-	checkout, err := s.checkoutRepository.FindByID(ctx, id)
+	checkout, err := s.findCheckout(ctx, checkoutID)
 	if err != nil {
 		return nil, err
 	}
@@ -144,18 +223,33 @@ func (s *CheckoutService) UpdateShipping(ctx context.Context, checkoutID string,
 
 // SetPaymentMethod sets the payment method for a checkout
 func (s *CheckoutService) SetPaymentMethod(ctx context.Context, checkoutID string, req *dto.PaymentMethodRequest) (*dto.CheckoutResponseDTO, error) {
-	id, err := uuid.Parse(checkoutID)
+	checkout, err := s.findCheckout(ctx, checkoutID)
 	if err != nil {
-		return nil, errors.New("invalid checkout ID format")
+		return nil, err
 	}
 
-	checkout, err := s.checkoutRepository.FindByID(ctx, id)
+	// Build and validate the typed payment method
+	paymentMethod, err := paymentMethodFromRequest(req)
 	if err != nil {
 		return nil, err
 	}
 
+	// Resolve the installment plan, if the payment is financed
+	var plan *model.InstallmentPlan
+	if req.InstallmentPlanID != "" {
+		planID, err := uuid.Parse(req.InstallmentPlanID)
+		if err != nil {
+			return nil, errors.New("invalid installment plan ID format")
+		}
+
+		plan, err = s.installmentPlanRepository.FindByID(ctx, planID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Update checkout with payment method
-	if err := checkout.SetPaymentMethod(req.PaymentType, req.PaymentDetails); err != nil {
+	if err := checkout.SetPaymentMethod(paymentMethod, plan); err != nil {
 		return nil, err
 	}
 
@@ -167,14 +261,187 @@ func (s *CheckoutService) SetPaymentMethod(ctx context.Context, checkoutID strin
 	return dto.CheckoutFromDomain(checkout), nil
 }
 
-// CompleteCheckout finalizes the checkout process
-func (s *CheckoutService) CompleteCheckout(ctx context.Context, checkoutID string) (*dto.CheckoutResponseDTO, error) {
-	id, err := uuid.Parse(checkoutID)
+// QuoteInstallments lists the installment plans available for a checkout's total
+func (s *CheckoutService) QuoteInstallments(ctx context.Context, checkoutID string, cardBrand string, issuer string) ([]*dto.InstallmentQuoteDTO, error) {
+	if cardBrand == "" {
+		return nil, errors.New("card brand is required")
+	}
+
+	checkout, err := s.findCheckout(ctx, checkoutID)
 	if err != nil {
-		return nil, errors.New("invalid checkout ID format")
+		return nil, err
 	}
 
-	checkout, err := s.checkoutRepository.FindByID(ctx, id)
+	plans, err := s.installmentPlanRepository.FindByCard(ctx, cardBrand, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := checkout.FinanceableAmount()
+	result := make([]*dto.InstallmentQuoteDTO, 0, len(plans))
+	for _, plan := range plans {
+		if !plan.AppliesTo(cardBrand, issuer, amount) {
+			continue
+		}
+		result = append(result, dto.InstallmentQuoteFromDomain(plan, amount))
+	}
+
+	return result, nil
+}
+
+// CreateInstallmentPlan adds an installment plan to the catalog
+func (s *CheckoutService) CreateInstallmentPlan(ctx context.Context, req *dto.InstallmentPlanRequest) (*dto.InstallmentPlanDTO, error) {
+	plan, err := model.NewInstallmentPlan(req.CardBrand, req.Issuer, req.Installments, req.InterestRate, req.MinAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.installmentPlanRepository.Save(ctx, plan); err != nil {
+		return nil, err
+	}
+
+	return dto.InstallmentPlanFromDomain(plan), nil
+}
+
+// ListInstallmentPlans retrieves the installment plans of the catalog
+func (s *CheckoutService) ListInstallmentPlans(ctx context.Context) ([]*dto.InstallmentPlanDTO, error) {
+	plans, err := s.installmentPlanRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.InstallmentPlanDTO, len(plans))
+	for i, plan := range plans {
+		result[i] = dto.InstallmentPlanFromDomain(plan)
+	}
+
+	return result, nil
+}
+
+// DeactivateInstallmentPlan stops offering an installment plan
+func (s *CheckoutService) DeactivateInstallmentPlan(ctx context.Context, planID string) error {
+	id, err := uuid.Parse(planID)
+	if err != nil {
+		return errors.New("invalid installment plan ID format")
+	}
+
+	return s.installmentPlanRepository.Deactivate(ctx, id)
+}
+
+// ApplyGiftCard pays part of a checkout with a gift card or store credit.
+// The balance is only reserved on the checkout; it is debited when the checkout completes.
+func (s *CheckoutService) ApplyGiftCard(ctx context.Context, checkoutID string, req *dto.GiftCardApplyRequest) (*dto.CheckoutResponseDTO, error) {
+	checkout, err := s.findCheckout(ctx, checkoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	giftCard, err := s.giftCardRepository.FindByCode(ctx, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !giftCard.CanBeUsedBy(checkout.UserID) {
+		return nil, errors.New("store credit does not belong to the user")
+	}
+
+	// Default to the lesser of the card balance and what is still due
+	amount := req.Amount
+	if amount == 0 {
+		amount = checkout.AmountDue()
+		for _, tender := range checkout.GiftCards {
+			if tender.GiftCardID == giftCard.ID {
+				amount += tender.Amount
+			}
+		}
+		if giftCard.Balance < amount {
+			amount = giftCard.Balance
+		}
+	}
+
+	if err := giftCard.CheckRedeemable(amount, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := checkout.ApplyGiftCard(giftCard.Tender(amount)); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkoutRepository.Save(ctx, checkout); err != nil {
+		return nil, err
+	}
+
+	return dto.CheckoutFromDomain(checkout), nil
+}
+
+// RemoveGiftCard removes a gift card or store credit from a checkout
+func (s *CheckoutService) RemoveGiftCard(ctx context.Context, checkoutID string, giftCardID string) (*dto.CheckoutResponseDTO, error) {
+	cardID, err := uuid.Parse(giftCardID)
+	if err != nil {
+		return nil, errors.New("invalid gift card ID format")
+	}
+
+	checkout, err := s.findCheckout(ctx, checkoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkout.RemoveGiftCard(cardID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkoutRepository.Save(ctx, checkout); err != nil {
+		return nil, err
+	}
+
+	return dto.CheckoutFromDomain(checkout), nil
+}
+
+// RedeemLoyaltyPoints applies a discount line for the loyalty points the user wants to redeem.
+// The points are only reserved on the checkout; they are debited when the checkout completes.
+func (s *CheckoutService) RedeemLoyaltyPoints(ctx context.Context, checkoutID string, req *dto.LoyaltyRedemptionRequest) (*dto.CheckoutResponseDTO, error) {
+	checkout, err := s.findCheckout(ctx, checkoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	discount, err := s.loyaltyService.QuoteRedemption(ctx, checkout.UserID, req.Points)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkout.ApplyDiscount(model.NewLoyaltyDiscount(req.Points, discount)); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkoutRepository.Save(ctx, checkout); err != nil {
+		return nil, err
+	}
+
+	return dto.CheckoutFromDomain(checkout), nil
+}
+
+// RemoveLoyaltyPoints removes the loyalty points discount from a checkout
+func (s *CheckoutService) RemoveLoyaltyPoints(ctx context.Context, checkoutID string) (*dto.CheckoutResponseDTO, error) {
+	checkout, err := s.findCheckout(ctx, checkoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkout.RemoveDiscount(model.DiscountSourceLoyaltyPoints); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkoutRepository.Save(ctx, checkout); err != nil {
+		return nil, err
+	}
+
+	return dto.CheckoutFromDomain(checkout), nil
+}
+
+// CompleteCheckout finalizes the checkout process
+func (s *CheckoutService) CompleteCheckout(ctx context.Context, checkoutID string) (*dto.CheckoutResponseDTO, error) {
+	checkout, err := s.findCheckout(ctx, checkoutID)
 	if err != nil {
 		return nil, err
 	}
@@ -185,15 +452,492 @@ func (s *CheckoutService) CompleteCheckout(ctx context.Context, checkoutID strin
 	// 3. Create order in order management system
 	// 4. Notify fulfillment service
 
-	// Complete the checkout
-	if err := checkout.Complete(); err != nil {
+	// Complete the checkout. Cash on pickup checkouts wait to be paid at the counter; gift
+	// cards and loyalty points are still debited now, so the reservation holds them.
+	previous := *checkout
+	if checkout.PaysCashOnPickup() {
+		err = checkout.AwaitCashPayment(time.Now().Add(s.cashPaymentWindow))
+	} else {
+		err = checkout.Complete()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the purchase limits again, in case other checkouts of the user were placed since
+	if err := s.checkPurchaseRules(ctx, checkout); err != nil {
 		return nil, err
 	}
 
-	// Save the updated checkout
-	if err := s.checkoutRepository.Save(ctx, checkout); err != nil {
+	// Claim the completion before debiting anything, so a concurrent request completing the
+	// same checkout fails instead of debiting the gift cards and points a second time
+	if err := s.claimCompletion(ctx, checkout, previous.Status); err != nil {
+		return nil, err
+	}
+
+	// Debit gift cards and store credit atomically, releasing the checkout if they cannot be debited
+	debits, err := s.giftCardTransactions(ctx, checkout, (*model.GiftCard).Debit)
+	if err == nil {
+		err = s.giftCardRepository.ApplyTransactions(ctx, debits)
+	}
+	if err != nil {
+		s.releaseCompletion(ctx, &previous, checkout.Status)
+		return nil, err
+	}
+
+	// Debit the redeemed loyalty points
+	if points := checkout.LoyaltyPointsRedeemed(); points > 0 {
+		if err := s.loyaltyService.RedeemForCheckout(ctx, checkout.UserID, checkout.ID, points); err != nil {
+			s.refundGiftCards(ctx, checkout)
+			s.releaseCompletion(ctx, &previous, checkout.Status)
+			return nil, err
+		}
+	}
+
+	// Orders paid at the counter are confirmed once the cash is received
+	eventType := webhookModel.EventCheckoutAwaitingPayment
+	if checkout.IsCompleted() {
+		s.earnLoyaltyPoints(ctx, checkout)
+		s.issueInvoice(ctx, checkout)
+		s.notifyOrder(ctx, checkout, s.notificationService.NotifyOrderConfirmed)
+		eventType = webhookModel.EventCheckoutCompleted
+	}
+
+	response := dto.CheckoutFromDomain(checkout)
+	s.publishEvent(ctx, eventType, response)
+
+	return response, nil
+}
+
+// checkPurchaseRules checks the quantities of the checkout against the purchase rules of its
+// products and the user's purchase history
+func (s *CheckoutService) checkPurchaseRules(ctx context.Context, checkout *model.Checkout) error {
+	quantities := make(map[uuid.UUID]int)
+	for _, item := range checkout.Items {
+		quantities[item.ProductID] += item.Quantity
+	}
+	return s.cartService.CheckPurchase(ctx, checkout.UserID, quantities)
+}
+
+// claimCompletion stores the status of a checkout that was just completed, as long as it still
+// has the status it was completed from. Payment codes are only unique among the checkouts
+// awaiting payment, so a checkout whose code is taken by another one gets a new code and is
+// stored again.
+func (s *CheckoutService) claimCompletion(ctx context.Context, checkout *model.Checkout, from model.CheckoutStatus) error {
+	err := s.checkoutRepository.SaveStatusTransition(ctx, checkout, from)
+	for attempt := 1; attempt < maxPaymentCodeAttempts && err != nil && err.Error() == "payment code already in use"; attempt++ {
+		if err = checkout.ReissuePaymentCode(); err != nil {
+			return err
+		}
+		err = s.checkoutRepository.SaveStatusTransition(ctx, checkout, from)
+	}
+	return err
+}
+
+// releaseCompletion puts back the status a checkout had before a completion that could not
+// debit its gift cards or points, so it can be completed again. The request already failed,
+// so a failure is only logged.
+func (s *CheckoutService) releaseCompletion(ctx context.Context, previous *model.Checkout, claimed model.CheckoutStatus) {
+	if err := s.checkoutRepository.SaveStatusTransition(ctx, previous, claimed); err != nil {
+		log.Printf("Failed to release the completion of checkout %s: %v", previous.ID, err)
+	}
+}
+
+// earnLoyaltyPoints credits the points earned with a checkout. The purchase is already
+// completed, so a failure is only logged.
+func (s *CheckoutService) earnLoyaltyPoints(ctx context.Context, checkout *model.Checkout) {
+	if err := s.loyaltyService.EarnForCheckout(ctx, checkout.UserID, checkout.ID, purchaseLines(checkout)); err != nil {
+		log.Printf("Failed to credit loyalty points for checkout %s: %v", checkout.ID, err)
+	}
+}
+
+// issueInvoice issues the invoice of a completed checkout. The purchase is already completed,
+// so a failure is only logged.
+func (s *CheckoutService) issueInvoice(ctx context.Context, checkout *model.Checkout) {
+	if _, err := s.invoicingService.InvoiceSale(ctx, saleFromCheckout(checkout)); err != nil {
+		log.Printf("Failed to issue the invoice of checkout %s: %v", checkout.ID, err)
+	}
+}
+
+// notifyOrder queues an email about the checkout for its user. The checkout is already
+// saved, so a failure is only logged.
+func (s *CheckoutService) notifyOrder(
+	ctx context.Context,
+	checkout *model.Checkout,
+	notify func(context.Context, *notificationModel.OrderSummary) error,
+) {
+	if err := notify(ctx, orderSummary(checkout)); err != nil {
+		log.Printf("Failed to notify checkout %s: %v", checkout.ID, err)
+	}
+}
+
+// publishEvent queues a checkout event for the webhook subscribers, with the checkout as
+// GET /checkout/{checkoutId} returns it. The checkout is already saved, so a failure is only logged.
+func (s *CheckoutService) publishEvent(ctx context.Context, eventType webhookModel.EventType, checkout *dto.CheckoutResponseDTO) {
+	if err := s.webhookService.Publish(ctx, eventType, checkout); err != nil {
+		log.Printf("Failed to publish %s for checkout %s: %v", eventType, checkout.ID, err)
+	}
+}
+
+// GetCashPayment retrieves the checkout awaiting payment at the counter with a payment code
+func (s *CheckoutService) GetCashPayment(ctx context.Context, code string) (*dto.CheckoutResponseDTO, error) {
+	checkout, err := s.checkoutRepository.FindByPaymentCode(ctx, model.NormalizePaymentCode(code))
+	if err != nil {
 		return nil, err
 	}
 
 	return dto.CheckoutFromDomain(checkout), nil
 }
+
+// RecordCashPayment records the cash received at the counter for a checkout, works out the
+// change and completes the checkout
+func (s *CheckoutService) RecordCashPayment(ctx context.Context, code string, req *dto.CashPaymentRequest) (*dto.CheckoutResponseDTO, error) {
+	checkout, err := s.checkoutRepository.FindByPaymentCode(ctx, model.NormalizePaymentCode(code))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkout.RecordCashPayment(req.AmountReceived, req.ReceivedBy, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// Only one request can close the payment, even if the code is entered twice or the
+	// deadline passes meanwhile
+	if err := s.checkoutRepository.SaveCashPaymentOutcome(ctx, checkout); err != nil {
+		return nil, err
+	}
+
+	s.earnLoyaltyPoints(ctx, checkout)
+	s.issueInvoice(ctx, checkout)
+	s.notifyOrder(ctx, checkout, s.notificationService.NotifyOrderConfirmed)
+
+	response := dto.CheckoutFromDomain(checkout)
+	s.publishEvent(ctx, webhookModel.EventCheckoutCompleted, response)
+
+	return response, nil
+}
+
+// CancelOverduePayments cancels the cash on pickup checkouts not paid before their deadline,
+// giving back the gift cards and loyalty points they held. It returns the number of
+// cancelled checkouts.
+func (s *CheckoutService) CancelOverduePayments(ctx context.Context) (int, error) {
+	checkouts, err := s.checkoutRepository.FindOverduePayments(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	cancelled := 0
+	for _, checkout := range checkouts {
+		checkout.Cancel()
+
+		// The cancellation is stored first, so a checkout paid at the counter meanwhile keeps
+		// its gift cards and points
+		if err := s.checkoutRepository.SaveCashPaymentOutcome(ctx, checkout); err != nil {
+			if err.Error() == "checkout is not awaiting payment" {
+				continue
+			}
+			return cancelled, err
+		}
+		cancelled++
+
+		if _, err := s.giftCardRepository.RefundCheckout(ctx, checkout.ID); err != nil {
+			return cancelled, err
+		}
+
+		if checkout.LoyaltyPointsRedeemed() > 0 {
+			if err := s.loyaltyService.ReverseCheckout(ctx, checkout.UserID, checkout.ID); err != nil {
+				return cancelled, err
+			}
+		}
+
+		s.notifyOrder(ctx, checkout, s.notificationService.NotifyOrderCancelled)
+		s.publishEvent(ctx, webhookModel.EventCheckoutCancelled, dto.CheckoutFromDomain(checkout))
+	}
+
+	return cancelled, nil
+}
+
+// RunPaymentExpiry cancels overdue cash payments every interval until the context is cancelled
+func (s *CheckoutService) RunPaymentExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cancelled, err := s.CancelOverduePayments(ctx)
+			if err != nil {
+				log.Printf("Failed to cancel overdue cash payments: %v", err)
+			} else if cancelled > 0 {
+				log.Printf("Cancelled %d checkouts not paid at the counter", cancelled)
+			}
+		}
+	}
+}
+
+// RefundCheckout refunds a completed checkout, crediting back gift cards and store credit.
+// The checkout is only marked as refunded once the gift cards and loyalty points were given
+// back, and both steps are idempotent, so a refund that failed halfway can be retried.
+func (s *CheckoutService) RefundCheckout(ctx context.Context, checkoutID string) (*dto.CheckoutResponseDTO, error) {
+	checkout, err := s.findCheckout(ctx, checkoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkout.Refund(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.giftCardRepository.RefundCheckout(ctx, checkout.ID); err != nil {
+		return nil, err
+	}
+
+	// Take back the points earned and restore the points redeemed
+	if err := s.loyaltyService.ReverseCheckout(ctx, checkout.UserID, checkout.ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkoutRepository.Save(ctx, checkout); err != nil {
+		return nil, err
+	}
+
+	s.notifyOrder(ctx, checkout, s.notificationService.NotifyOrderRefunded)
+
+	response := dto.CheckoutFromDomain(checkout)
+	s.publishEvent(ctx, webhookModel.EventCheckoutRefunded, response)
+
+	return response, nil
+}
+
+// refundGiftCards credits back the gift card debits of a checkout that could not be completed
+func (s *CheckoutService) refundGiftCards(ctx context.Context, checkout *model.Checkout) {
+	_, _ = s.giftCardRepository.RefundCheckout(ctx, checkout.ID)
+}
+
+// giftCardTransactions builds one ledger entry per gift card tender of the checkout
+func (s *CheckoutService) giftCardTransactions(
+	ctx context.Context,
+	checkout *model.Checkout,
+	operation func(*model.GiftCard, uuid.UUID, float64) (*model.GiftCardTransaction, error),
+) ([]*model.GiftCardTransaction, error) {
+	transactions := make([]*model.GiftCardTransaction, 0, len(checkout.GiftCards))
+	for _, tender := range checkout.GiftCards {
+		giftCard, err := s.giftCardRepository.FindByID(ctx, tender.GiftCardID)
+		if err != nil {
+			return nil, err
+		}
+
+		transaction, err := operation(giftCard, checkout.ID, tender.Amount)
+		if err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, transaction)
+	}
+	return transactions, nil
+}
+
+// checkoutItemsFromCart copies the cart lines, variant, options and contributors included, at their list price.
+// Segment prices are applied by the checkout itself.
+func checkoutItemsFromCart(cart *cartDto.CartResponse) ([]*model.CheckoutItem, error) {
+	items := make([]*model.CheckoutItem, len(cart.Items))
+	for i, line := range cart.Items {
+		productID, err := uuid.Parse(line.ProductID)
+		if err != nil {
+			return nil, err
+		}
+
+		addedBy, err := uuid.Parse(line.AddedBy)
+		if err != nil {
+			return nil, err
+		}
+
+		contributions := make([]model.ItemContribution, len(line.Contributions))
+		for j, contribution := range line.Contributions {
+			userID, err := uuid.Parse(contribution.UserID)
+			if err != nil {
+				return nil, err
+			}
+			contributions[j] = model.ItemContribution{
+				UserID:   userID,
+				Quantity: contribution.Quantity,
+			}
+		}
+
+		options := make([]model.ItemOption, len(line.Options))
+		for j, option := range line.Options {
+			options[j] = model.ItemOption{
+				Name:      option.Name,
+				Value:     option.Value,
+				Surcharge: option.Surcharge,
+			}
+		}
+
+		items[i] = &model.CheckoutItem{
+			ProductID:     productID,
+			VariantID:     line.VariantID,
+			Options:       options,
+			Name:          line.Name,
+			Price:         line.OriginalPrice,
+			Quantity:      line.Quantity,
+			Subtotal:      line.OriginalSubtotal,
+			ImageURL:      line.ImageURL,
+			Category:      line.Category,
+			AddedBy:       addedBy,
+			Contributions: contributions,
+		}
+	}
+	return items, nil
+}
+
+// applySegmentPrices replaces the list price of the items with the user's segment prices
+func (s *CheckoutService) applySegmentPrices(ctx context.Context, userID uuid.UUID, items []*model.CheckoutItem) error {
+	lines := make([]segmentModel.PriceLine, len(items))
+	for i, item := range items {
+		lines[i] = segmentModel.PriceLine{
+			ProductID: item.ProductID,
+			Category:  item.Category,
+			Price:     item.ProductPrice(),
+		}
+	}
+
+	prices, err := s.segmentService.PriceLines(ctx, userID, lines)
+	if err != nil {
+		return err
+	}
+
+	for i, price := range prices {
+		if price != nil {
+			items[i].ApplySegmentPrice(string(price.Segment), price.Price)
+		}
+	}
+
+	return nil
+}
+
+// purchaseLines returns the amount spent per product category, after discounts,
+// which is what loyalty points are earned on
+func purchaseLines(checkout *model.Checkout) []loyaltyModel.PurchaseLine {
+	ratio := 0.0
+	if checkout.Subtotal > 0 {
+		ratio = (checkout.Subtotal - checkout.DiscountTotal()) / checkout.Subtotal
+	}
+
+	lines := make([]loyaltyModel.PurchaseLine, len(checkout.Items))
+	for i, item := range checkout.Items {
+		lines[i] = loyaltyModel.PurchaseLine{
+			Category: item.Category,
+			Amount:   item.Subtotal * ratio,
+		}
+	}
+	return lines
+}
+
+// saleFromCheckout returns the lines of a completed checkout as invoicing sees them: items
+// and shipping net of discounts and taxed at the checkout rate, and financing untaxed
+func saleFromCheckout(checkout *model.Checkout) *invoicingModel.Sale {
+	ratio := 0.0
+	if checkout.Subtotal > 0 {
+		ratio = (checkout.Subtotal - checkout.DiscountTotal()) / checkout.Subtotal
+	}
+
+	sale := &invoicingModel.Sale{
+		CheckoutID:  checkout.ID,
+		UserID:      checkout.UserID,
+		CompletedAt: checkout.UpdatedAt,
+		Lines:       make([]invoicingModel.SaleLine, 0, len(checkout.Items)+2),
+	}
+	for _, item := range checkout.Items {
+		sale.Lines = append(sale.Lines, invoicingModel.SaleLine{
+			Description: item.Name,
+			Quantity:    item.Quantity,
+			NetAmount:   item.Subtotal * ratio,
+			IVARate:     checkout.TaxRate,
+		})
+	}
+	if checkout.ShippingCost > 0 {
+		sale.Lines = append(sale.Lines, invoicingModel.SaleLine{
+			Description: "Shipping",
+			Quantity:    1,
+			NetAmount:   checkout.ShippingCost,
+			IVARate:     checkout.TaxRate,
+		})
+	}
+	if checkout.FinancingCost > 0 {
+		sale.Lines = append(sale.Lines, invoicingModel.SaleLine{
+			Description: "Financing",
+			Quantity:    1,
+			NetAmount:   checkout.FinancingCost,
+		})
+	}
+	return sale
+}
+
+// orderSummary returns a checkout as order emails show it. Orders waiting to be paid at the
+// counter carry the payment code and deadline.
+func orderSummary(checkout *model.Checkout) *notificationModel.OrderSummary {
+	order := &notificationModel.OrderSummary{
+		CheckoutID: checkout.ID,
+		UserID:     checkout.UserID,
+		OrderCode:  model.OrderCode(checkout.ID),
+		Lines:      make([]notificationModel.SummaryLine, len(checkout.Items)),
+		Subtotal:   checkout.Subtotal,
+		Discounts:  checkout.DiscountTotal(),
+		Shipping:   checkout.ShippingCost,
+		Tax:        checkout.Tax,
+		Total:      checkout.Total,
+	}
+	for i, item := range checkout.Items {
+		order.Lines[i] = notificationModel.SummaryLine{
+			Name:     item.Name,
+			Quantity: item.Quantity,
+			Amount:   item.Subtotal,
+		}
+	}
+	return order
+}
+
+// paymentMethodFromRequest builds the payment method variant that matches the requested payment type
+func paymentMethodFromRequest(req *dto.PaymentMethodRequest) (*model.PaymentMethod, error) {
+	switch model.PaymentType(req.PaymentType) {
+	case model.PaymentTypeCardToken:
+		if req.Card == nil {
+			return nil, errors.New("card details are required for card payments")
+		}
+		return model.NewCardTokenPayment(
+			req.Card.Token,
+			req.Card.CardBrand,
+			req.Card.Issuer,
+			req.Card.LastFour,
+			req.Card.ExpiryMonth,
+			req.Card.ExpiryYear,
+			req.Card.HolderName,
+		)
+	case model.PaymentTypeBankTransfer:
+		if req.BankTransfer == nil {
+			return nil, errors.New("bank transfer details are required for bank transfer payments")
+		}
+		return model.NewBankTransferPayment(
+			req.BankTransfer.BankName,
+			req.BankTransfer.CBU,
+			req.BankTransfer.Alias,
+			req.BankTransfer.Reference,
+		)
+	case model.PaymentTypeCashOnPickup:
+		pickupPoint := ""
+		if req.CashOnPickup != nil {
+			pickupPoint = req.CashOnPickup.PickupPoint
+		}
+		return model.NewCashOnPickupPayment(pickupPoint)
+	case model.PaymentTypeWallet:
+		if req.Wallet == nil {
+			return nil, errors.New("wallet details are required for wallet payments")
+		}
+		return model.NewWalletPayment(req.Wallet.Provider, req.Wallet.Token)
+	case "":
+		return nil, errors.New("payment type is required")
+	default:
+		return nil, errors.New("unsupported payment type")
+	}
+}
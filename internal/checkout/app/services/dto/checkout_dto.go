@@ -4,14 +4,30 @@ import (
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
 )
 
-// CheckoutItemDTO represents an item in a checkout
+// CheckoutItemDTO represents an item in a checkout. Price is the unit price charged,
+// including the option surcharges.
 type CheckoutItemDTO struct {
-	ProductID string  `json:"productId"`
+	ProductID       string          `json:"productId"`
+	VariantID       string          `json:"variantId,omitempty"`
+	Options         []ItemOptionDTO `json:"options"`
+	Surcharge       float64         `json:"surcharge"`
+	Name            string          `json:"name"`
+	Price           float64         `json:"price"`
+	OriginalPrice   float64         `json:"originalPrice"`
+	DiscountedPrice float64         `json:"discountedPrice"`
+	Segment         string          `json:"segment,omitempty"`
+	Quantity        int             `json:"quantity"`
+	Subtotal        float64         `json:"subtotal"`
+	ImageURL        string          `json:"imageUrl"`
+	Category        string          `json:"category,omitempty"`
+	AddedBy         string          `json:"addedBy"`
+}
+
+// ItemOptionDTO represents a customization option of a checkout item
+type ItemOptionDTO struct {
 	Name      string  `json:"name"`
-	Price     float64 `json:"price"`
-	Quantity  int     `json:"quantity"`
-	Subtotal  float64 `json:"subtotal"`
-	ImageURL  string  `json:"imageUrl"`
+	Value     string  `json:"value"`
+	Surcharge float64 `json:"surcharge"`
 }
 
 // DeliveryOptionDTO represents shipping details for a checkout
@@ -20,26 +36,138 @@ type DeliveryOptionDTO struct {
 	ShippingMethodID  string `json:"shippingMethodId"`
 }
 
-// PaymentMethodDTO represents payment method details
+// PaymentMethodDTO represents payment method details. Only masked data is exposed.
 type PaymentMethodDTO struct {
-	PaymentType    string                 `json:"paymentType"`
-	PaymentDetails map[string]interface{} `json:"paymentDetails"`
+	PaymentType  string                   `json:"paymentType"`
+	Card         *CardDTO                 `json:"card,omitempty"`
+	BankTransfer *BankTransferDTO         `json:"bankTransfer,omitempty"`
+	CashOnPickup *CashOnPickupDTO         `json:"cashOnPickup,omitempty"`
+	Wallet       *WalletDTO               `json:"wallet,omitempty"`
+	Installments *InstallmentSelectionDTO `json:"installments,omitempty"`
+}
+
+// CardDTO represents the masked data of a tokenized card
+type CardDTO struct {
+	CardBrand   string `json:"cardBrand"`
+	Issuer      string `json:"issuer,omitempty"`
+	LastFour    string `json:"lastFour"`
+	ExpiryMonth int    `json:"expiryMonth"`
+	ExpiryYear  int    `json:"expiryYear"`
+	HolderName  string `json:"holderName,omitempty"`
+}
+
+// BankTransferDTO represents the masked data of a bank transfer
+type BankTransferDTO struct {
+	BankName  string `json:"bankName"`
+	MaskedCBU string `json:"maskedCbu,omitempty"`
+	Alias     string `json:"alias,omitempty"`
+	Reference string `json:"reference,omitempty"`
+}
+
+// CashOnPickupDTO represents the data of a cash payment made at pickup
+type CashOnPickupDTO struct {
+	PickupPoint string `json:"pickupPoint,omitempty"`
+}
+
+// WalletDTO represents the data of a digital wallet payment
+type WalletDTO struct {
+	Provider string `json:"provider"`
+}
+
+// InstallmentSelectionDTO represents the installment plan chosen for a payment
+type InstallmentSelectionDTO struct {
+	PlanID       string  `json:"planId"`
+	CardBrand    string  `json:"cardBrand"`
+	Issuer       string  `json:"issuer,omitempty"`
+	Installments int     `json:"installments"`
+	InterestRate float64 `json:"interestRate"`
+	MinAmount    float64 `json:"minAmount"`
+}
+
+// InstallmentPlanDTO represents an installment plan of the catalog
+type InstallmentPlanDTO struct {
+	ID           string  `json:"id"`
+	CardBrand    string  `json:"cardBrand"`
+	Issuer       string  `json:"issuer,omitempty"`
+	Installments int     `json:"installments"`
+	InterestRate float64 `json:"interestRate"`
+	MinAmount    float64 `json:"minAmount"`
+}
+
+// InstallmentQuoteDTO represents an installment plan quoted for a checkout total
+type InstallmentQuoteDTO struct {
+	PlanID            string  `json:"planId"`
+	CardBrand         string  `json:"cardBrand"`
+	Issuer            string  `json:"issuer,omitempty"`
+	Installments      int     `json:"installments"`
+	InterestRate      float64 `json:"interestRate"`
+	FinancingCost     float64 `json:"financingCost"`
+	InstallmentAmount float64 `json:"installmentAmount"`
+	Total             float64 `json:"total"`
+}
+
+// ContributorShareDTO represents the part of the subtotal added by one contributor of a shared cart
+type ContributorShareDTO struct {
+	UserID     string  `json:"userId"`
+	Subtotal   float64 `json:"subtotal"`
+	Percentage float64 `json:"percentage"`
+}
+
+// GiftCardTenderDTO represents the part of a checkout paid with a gift card or store credit
+type GiftCardTenderDTO struct {
+	GiftCardID string  `json:"giftCardId"`
+	MaskedCode string  `json:"maskedCode"`
+	Kind       string  `json:"kind"`
+	Amount     float64 `json:"amount"`
+}
+
+// DiscountLineDTO represents a discount applied to a checkout
+type DiscountLineDTO struct {
+	Source      string  `json:"source"`
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+	Points      int     `json:"points,omitempty"`
 }
 
 // CheckoutResponseDTO represents checkout data for API responses
 type CheckoutResponseDTO struct {
-	ID           string             `json:"id"`
-	CartID       string             `json:"cartId"`
-	Status       string             `json:"status"`
-	Items        []CheckoutItemDTO  `json:"items"`
-	Subtotal     float64            `json:"subtotal"`
-	ShippingCost float64            `json:"shippingCost"`
-	Tax          float64            `json:"tax"`
-	Total        float64            `json:"total"`
-	Delivery     *DeliveryOptionDTO `json:"delivery,omitempty"`
-	Payment      *PaymentMethodDTO  `json:"payment,omitempty"`
-	CreatedAt    string             `json:"createdAt"`
-	UpdatedAt    string             `json:"updatedAt"`
+	ID            string                `json:"id"`
+	CartID        string                `json:"cartId"`
+	Status        string                `json:"status"`
+	Items         []CheckoutItemDTO     `json:"items"`
+	Subtotal      float64               `json:"subtotal"`
+	Contributors  []ContributorShareDTO `json:"contributors"`
+	Discounts     []DiscountLineDTO     `json:"discounts"`
+	DiscountTotal float64               `json:"discountTotal"`
+	ShippingCost  float64               `json:"shippingCost"`
+	Tax           float64               `json:"tax"`
+	FinancingCost float64               `json:"financingCost"`
+	Total         float64               `json:"total"`
+	Delivery      *DeliveryOptionDTO    `json:"delivery,omitempty"`
+	Payment       *PaymentMethodDTO     `json:"payment,omitempty"`
+	GiftCards     []GiftCardTenderDTO   `json:"giftCards"`
+	AmountDue     float64               `json:"amountDue"`
+	CashPayment   *CashPaymentDTO       `json:"cashPayment,omitempty"`
+	CreatedAt     string                `json:"createdAt"`
+	UpdatedAt     string                `json:"updatedAt"`
+}
+
+// CashPaymentDTO represents the payment at the counter of a cash on pickup checkout. The
+// received amount and change are set once staff record the payment.
+type CashPaymentDTO struct {
+	Code       string  `json:"code"`
+	AmountDue  float64 `json:"amountDue"`
+	DueAt      string  `json:"dueAt"`
+	Received   float64 `json:"received,omitempty"`
+	Change     float64 `json:"change,omitempty"`
+	ReceivedBy string  `json:"receivedBy,omitempty"`
+	PaidAt     string  `json:"paidAt,omitempty"`
+}
+
+// CashPaymentRequest represents the cash received at the counter for a checkout
+type CashPaymentRequest struct {
+	AmountReceived float64 `json:"amountReceived" validate:"required,gt=0"`
+	ReceivedBy     string  `json:"receivedBy,omitempty"`
 }
 
 // CheckoutInitRequest represents the request to initialize a checkout
@@ -53,28 +181,81 @@ type ShippingDetailsRequest struct {
 	MethodID  string `json:"shippingMethodId" validate:"required,uuid"`
 }
 
-// PaymentMethodRequest represents the request to set a payment method
+// PaymentMethodRequest represents the request to set a payment method.
+// Exactly one variant matching PaymentType must be provided.
 type PaymentMethodRequest struct {
-	PaymentType    string                 `json:"paymentType" validate:"required"`
-	PaymentDetails map[string]interface{} `json:"paymentDetails" validate:"required"`
+	PaymentType       string               `json:"paymentType" validate:"required,oneof=CARD_TOKEN BANK_TRANSFER CASH_ON_PICKUP WALLET"`
+	Card              *CardTokenRequest    `json:"card,omitempty"`
+	BankTransfer      *BankTransferRequest `json:"bankTransfer,omitempty"`
+	CashOnPickup      *CashOnPickupRequest `json:"cashOnPickup,omitempty"`
+	Wallet            *WalletRequest       `json:"wallet,omitempty"`
+	InstallmentPlanID string               `json:"installmentPlanId,omitempty" validate:"omitempty,uuid"`
+}
+
+// InstallmentPlanRequest represents the request to add an installment plan to the catalog.
+// Plans without an issuer apply to every issuer of the card brand.
+type InstallmentPlanRequest struct {
+	CardBrand    string  `json:"cardBrand" validate:"required"`
+	Issuer       string  `json:"issuer"`
+	Installments int     `json:"installments" validate:"required,gt=0"`
+	InterestRate float64 `json:"interestRate" validate:"gte=0"`
+	MinAmount    float64 `json:"minAmount" validate:"gte=0"`
+}
+
+// LoyaltyRedemptionRequest represents the request to redeem loyalty points on a checkout
+type LoyaltyRedemptionRequest struct {
+	Points int `json:"points" validate:"required,gt=0"`
+}
+
+// CardTokenRequest represents a card tokenized by the payment provider
+type CardTokenRequest struct {
+	Token       string `json:"token" validate:"required"`
+	CardBrand   string `json:"cardBrand" validate:"required"`
+	Issuer      string `json:"issuer"`
+	LastFour    string `json:"lastFour" validate:"required,len=4,numeric"`
+	ExpiryMonth int    `json:"expiryMonth" validate:"required,min=1,max=12"`
+	ExpiryYear  int    `json:"expiryYear" validate:"required"`
+	HolderName  string `json:"holderName"`
+}
+
+// BankTransferRequest represents a bank transfer payment
+type BankTransferRequest struct {
+	BankName  string `json:"bankName" validate:"required"`
+	CBU       string `json:"cbu" validate:"omitempty,len=22,numeric"`
+	Alias     string `json:"alias"`
+	Reference string `json:"reference"`
+}
+
+// CashOnPickupRequest represents a cash payment made when picking up the order
+type CashOnPickupRequest struct {
+	PickupPoint string `json:"pickupPoint"`
+}
+
+// WalletRequest represents a digital wallet payment
+type WalletRequest struct {
+	Provider string `json:"provider" validate:"required"`
+	Token    string `json:"token" validate:"required"`
 }
 
 // ShippingAddressDTO represents a shipping address for API responses
 type ShippingAddressDTO struct {
-	ID            string `json:"id"`
-	UserID        string `json:"userId"`
-	FirstName     string `json:"firstName"`
-	LastName      string `json:"lastName"`
-	StreetAddress string `json:"streetAddress"`
-	Apartment     string `json:"apartment,omitempty"`
-	City          string `json:"city"`
-	State         string `json:"state"`
-	PostalCode    string `json:"postalCode"`
-	Country       string `json:"country"`
-	PhoneNumber   string `json:"phoneNumber"`
-	IsDefault     bool   `json:"isDefault"`
-	CreatedAt     string `json:"createdAt"`
-	UpdatedAt     string `json:"updatedAt"`
+	ID            string   `json:"id"`
+	UserID        string   `json:"userId"`
+	FirstName     string   `json:"firstName"`
+	LastName      string   `json:"lastName"`
+	StreetAddress string   `json:"streetAddress"`
+	Apartment     string   `json:"apartment,omitempty"`
+	City          string   `json:"city"`
+	State         string   `json:"state"`
+	ProvinceName  string   `json:"provinceName"`
+	PostalCode    string   `json:"postalCode"`
+	Country       string   `json:"country"`
+	PhoneNumber   string   `json:"phoneNumber"`
+	Latitude      *float64 `json:"latitude,omitempty"`
+	Longitude     *float64 `json:"longitude,omitempty"`
+	IsDefault     bool     `json:"isDefault"`
+	CreatedAt     string   `json:"createdAt"`
+	UpdatedAt     string   `json:"updatedAt"`
 }
 
 // ShippingAddressRequest represents the request to create or update a shipping address
@@ -92,6 +273,64 @@ type ShippingAddressRequest struct {
 	IsDefault     bool   `json:"isDefault"`
 }
 
+// AddressFieldsDTO represents the fields of a shipping address in a validation result
+type AddressFieldsDTO struct {
+	FirstName     string `json:"firstName"`
+	LastName      string `json:"lastName"`
+	StreetAddress string `json:"streetAddress"`
+	Apartment     string `json:"apartment,omitempty"`
+	City          string `json:"city"`
+	State         string `json:"state"`
+	ProvinceName  string `json:"provinceName,omitempty"`
+	PostalCode    string `json:"postalCode"`
+	Country       string `json:"country"`
+	PhoneNumber   string `json:"phoneNumber"`
+}
+
+// AddressFieldErrorDTO represents an invalid field of a shipping address
+type AddressFieldErrorDTO struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// AddressValidationDTO represents the result of validating a shipping address
+type AddressValidationDTO struct {
+	Valid      bool                   `json:"valid"`
+	Errors     []AddressFieldErrorDTO `json:"errors"`
+	Address    *AddressFieldsDTO      `json:"address"`
+	Suggestion *AddressFieldsDTO      `json:"suggestion,omitempty"`
+}
+
+// AddressCandidateDTO represents an address suggested by the autocomplete for API responses
+type AddressCandidateDTO struct {
+	StreetAddress string   `json:"streetAddress"`
+	City          string   `json:"city"`
+	State         string   `json:"state"`
+	ProvinceName  string   `json:"provinceName"`
+	PostalCode    string   `json:"postalCode,omitempty"`
+	Latitude      *float64 `json:"latitude,omitempty"`
+	Longitude     *float64 `json:"longitude,omitempty"`
+}
+
+// StreetRowError describes a street dataset file row that could not be imported
+type StreetRowError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// StreetImportResult represents the outcome of a street dataset import for API responses
+type StreetImportResult struct {
+	Imported int              `json:"imported"`
+	Errors   []StreetRowError `json:"errors"`
+}
+
+// ProvinceDTO represents a province of Argentina for API responses
+type ProvinceDTO struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
 // ShippingMethodDTO represents a shipping method for API responses
 type ShippingMethodDTO struct {
 	ID                    string  `json:"id"`
@@ -105,27 +344,49 @@ type ShippingMethodDTO struct {
 func CheckoutFromDomain(checkout *model.Checkout) *CheckoutResponseDTO {
 	items := make([]CheckoutItemDTO, len(checkout.Items))
 	for i, item := range checkout.Items {
+		options := make([]ItemOptionDTO, len(item.Options))
+		for j, option := range item.Options {
+			options[j] = ItemOptionDTO{
+				Name:      option.Name,
+				Value:     option.Value,
+				Surcharge: option.Surcharge,
+			}
+		}
 		items[i] = CheckoutItemDTO{
-			ProductID: item.ProductID.String(),
-			Name:      item.Name,
-			Price:     item.Price,
-			Quantity:  item.Quantity,
-			Subtotal:  item.Subtotal,
-			ImageURL:  item.ImageURL,
+			ProductID:       item.ProductID.String(),
+			VariantID:       item.VariantID,
+			Options:         options,
+			Surcharge:       item.Surcharge(),
+			Name:            item.Name,
+			Price:           item.Price,
+			OriginalPrice:   item.ListPrice(),
+			DiscountedPrice: item.Price,
+			Segment:         item.Segment,
+			Quantity:        item.Quantity,
+			Subtotal:        item.Subtotal,
+			ImageURL:        item.ImageURL,
+			Category:        item.Category,
+			AddedBy:         item.AddedBy.String(),
 		}
 	}
 
 	result := &CheckoutResponseDTO{
-		ID:           checkout.ID.String(),
-		CartID:       checkout.CartID.String(),
-		Status:       string(checkout.Status),
-		Items:        items,
-		Subtotal:     checkout.Subtotal,
-		ShippingCost: checkout.ShippingCost,
-		Tax:          checkout.Tax,
-		Total:        checkout.Total,
-		CreatedAt:    checkout.CreatedAt.Format("2006-01-02T15:04:05Z"),
-		UpdatedAt:    checkout.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+		ID:            checkout.ID.String(),
+		CartID:        checkout.CartID.String(),
+		Status:        string(checkout.Status),
+		Items:         items,
+		Subtotal:      checkout.Subtotal,
+		Contributors:  make([]ContributorShareDTO, 0),
+		Discounts:     make([]DiscountLineDTO, len(checkout.Discounts)),
+		DiscountTotal: checkout.DiscountTotal(),
+		ShippingCost:  checkout.ShippingCost,
+		Tax:           checkout.Tax,
+		FinancingCost: checkout.FinancingCost,
+		Total:         checkout.Total,
+		GiftCards:     make([]GiftCardTenderDTO, len(checkout.GiftCards)),
+		AmountDue:     checkout.AmountDue(),
+		CreatedAt:     checkout.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:     checkout.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 	}
 
 	if checkout.DeliveryOption != nil {
@@ -136,18 +397,134 @@ func CheckoutFromDomain(checkout *model.Checkout) *CheckoutResponseDTO {
 	}
 
 	if checkout.PaymentMethod != nil {
-		result.Payment = &PaymentMethodDTO{
-			PaymentType:    checkout.PaymentMethod.PaymentType,
-			PaymentDetails: checkout.PaymentMethod.PaymentDetails,
+		result.Payment = PaymentMethodFromDomain(checkout.PaymentMethod)
+	}
+
+	for i, discount := range checkout.Discounts {
+		result.Discounts[i] = DiscountLineDTO{
+			Source:      string(discount.Source),
+			Description: discount.Description,
+			Amount:      discount.Amount,
+			Points:      discount.Points,
+		}
+	}
+
+	for _, share := range checkout.ContributorShares() {
+		result.Contributors = append(result.Contributors, ContributorShareDTO{
+			UserID:     share.UserID.String(),
+			Subtotal:   share.Subtotal,
+			Percentage: share.Percentage,
+		})
+	}
+
+	for i, tender := range checkout.GiftCards {
+		result.GiftCards[i] = GiftCardTenderDTO{
+			GiftCardID: tender.GiftCardID.String(),
+			MaskedCode: tender.MaskedCode,
+			Kind:       string(tender.Kind),
+			Amount:     tender.Amount,
+		}
+	}
+
+	if payment := checkout.CashPayment; payment != nil {
+		result.CashPayment = &CashPaymentDTO{
+			Code:       payment.Code,
+			AmountDue:  payment.AmountDue,
+			DueAt:      payment.DueAt.Format("2006-01-02T15:04:05Z"),
+			Received:   payment.Received,
+			Change:     payment.Change,
+			ReceivedBy: payment.ReceivedBy,
+		}
+		if payment.PaidAt != nil {
+			result.CashPayment.PaidAt = payment.PaidAt.Format("2006-01-02T15:04:05Z")
 		}
 	}
 
 	return result
 }
 
+// PaymentMethodFromDomain converts a payment method domain model to a DTO
+func PaymentMethodFromDomain(paymentMethod *model.PaymentMethod) *PaymentMethodDTO {
+	result := &PaymentMethodDTO{
+		PaymentType: string(paymentMethod.PaymentType),
+	}
+
+	if card := paymentMethod.Card; card != nil {
+		result.Card = &CardDTO{
+			CardBrand:   card.CardBrand,
+			Issuer:      card.Issuer,
+			LastFour:    card.LastFour,
+			ExpiryMonth: card.ExpiryMonth,
+			ExpiryYear:  card.ExpiryYear,
+			HolderName:  card.HolderName,
+		}
+	}
+
+	if transfer := paymentMethod.BankTransfer; transfer != nil {
+		result.BankTransfer = &BankTransferDTO{
+			BankName:  transfer.BankName,
+			MaskedCBU: transfer.MaskedCBU,
+			Alias:     transfer.Alias,
+			Reference: transfer.Reference,
+		}
+	}
+
+	if cash := paymentMethod.CashOnPickup; cash != nil {
+		result.CashOnPickup = &CashOnPickupDTO{
+			PickupPoint: cash.PickupPoint,
+		}
+	}
+
+	if wallet := paymentMethod.Wallet; wallet != nil {
+		result.Wallet = &WalletDTO{
+			Provider: wallet.Provider,
+		}
+	}
+
+	if installments := paymentMethod.Installments; installments != nil {
+		result.Installments = &InstallmentSelectionDTO{
+			PlanID:       installments.PlanID.String(),
+			CardBrand:    installments.CardBrand,
+			Issuer:       installments.Issuer,
+			Installments: installments.Installments,
+			InterestRate: installments.InterestRate,
+			MinAmount:    installments.MinAmount,
+		}
+	}
+
+	return result
+}
+
+// InstallmentPlanFromDomain converts an installment plan domain model to a DTO
+func InstallmentPlanFromDomain(plan *model.InstallmentPlan) *InstallmentPlanDTO {
+	return &InstallmentPlanDTO{
+		ID:           plan.ID.String(),
+		CardBrand:    plan.CardBrand,
+		Issuer:       plan.Issuer,
+		Installments: plan.Installments,
+		InterestRate: plan.InterestRate,
+		MinAmount:    plan.MinAmount,
+	}
+}
+
+// InstallmentQuoteFromDomain converts an installment plan quoted for an amount to a DTO
+func InstallmentQuoteFromDomain(plan *model.InstallmentPlan, amount float64) *InstallmentQuoteDTO {
+	financingCost := plan.FinancingCost(amount)
+	return &InstallmentQuoteDTO{
+		PlanID:            plan.ID.String(),
+		CardBrand:         plan.CardBrand,
+		Issuer:            plan.Issuer,
+		Installments:      plan.Installments,
+		InterestRate:      plan.InterestRate,
+		FinancingCost:     financingCost,
+		InstallmentAmount: plan.InstallmentAmount(amount),
+		Total:             amount + financingCost,
+	}
+}
+
 // ShippingAddressFromDomain converts a shipping address domain model to a DTO
 func ShippingAddressFromDomain(address *model.ShippingAddress) *ShippingAddressDTO {
-	return &ShippingAddressDTO{
+	result := &ShippingAddressDTO{
 		ID:            address.ID.String(),
 		UserID:        address.UserID.String(),
 		FirstName:     address.FirstName,
@@ -156,6 +533,7 @@ func ShippingAddressFromDomain(address *model.ShippingAddress) *ShippingAddressD
 		Apartment:     address.Apartment,
 		City:          address.City,
 		State:         address.State,
+		ProvinceName:  address.ProvinceName(),
 		PostalCode:    address.PostalCode,
 		Country:       address.Country,
 		PhoneNumber:   address.PhoneNumber,
@@ -163,6 +541,82 @@ func ShippingAddressFromDomain(address *model.ShippingAddress) *ShippingAddressD
 		CreatedAt:     address.CreatedAt.Format("2006-01-02T15:04:05Z"),
 		UpdatedAt:     address.UpdatedAt.Format("2006-01-02T15:04:05Z"),
 	}
+	if address.Location != nil {
+		result.Latitude = &address.Location.Latitude
+		result.Longitude = &address.Location.Longitude
+	}
+	return result
+}
+
+// AddressValidationFromDomain converts the result of validating a shipping address to a DTO
+func AddressValidationFromDomain(validation *model.AddressValidation) *AddressValidationDTO {
+	result := &AddressValidationDTO{
+		Valid:   validation.Valid(),
+		Errors:  AddressFieldErrorsFromDomain(validation),
+		Address: addressFieldsFromDomain(&validation.Address),
+	}
+	if validation.Suggestion != nil {
+		result.Suggestion = addressFieldsFromDomain(validation.Suggestion)
+	}
+	return result
+}
+
+// AddressFieldErrorsFromDomain converts the invalid fields of a shipping address to DTOs
+func AddressFieldErrorsFromDomain(validation *model.AddressValidation) []AddressFieldErrorDTO {
+	fieldErrors := make([]AddressFieldErrorDTO, len(validation.Errors))
+	for i, fieldError := range validation.Errors {
+		fieldErrors[i] = AddressFieldErrorDTO{
+			Field:   fieldError.Field,
+			Code:    string(fieldError.Code),
+			Message: fieldError.Message,
+		}
+	}
+	return fieldErrors
+}
+
+// addressFieldsFromDomain converts the fields of a shipping address to a DTO
+func addressFieldsFromDomain(fields *model.AddressFields) *AddressFieldsDTO {
+	result := &AddressFieldsDTO{
+		FirstName:     fields.FirstName,
+		LastName:      fields.LastName,
+		StreetAddress: fields.StreetAddress,
+		Apartment:     fields.Apartment,
+		City:          fields.City,
+		State:         fields.State,
+		PostalCode:    fields.PostalCode,
+		Country:       fields.Country,
+		PhoneNumber:   fields.PhoneNumber,
+	}
+	if province, ok := model.FindProvince(fields.State); ok {
+		result.ProvinceName = province.Name
+	}
+	return result
+}
+
+// AddressCandidateFromDomain converts an autocompleted address to a DTO
+func AddressCandidateFromDomain(candidate *model.AddressCandidate) *AddressCandidateDTO {
+	result := &AddressCandidateDTO{
+		StreetAddress: candidate.StreetAddress,
+		City:          candidate.City,
+		State:         candidate.State,
+		PostalCode:    candidate.PostalCode,
+	}
+	if province, ok := model.FindProvince(candidate.State); ok {
+		result.ProvinceName = province.Name
+	}
+	if candidate.Location != nil {
+		result.Latitude = &candidate.Location.Latitude
+		result.Longitude = &candidate.Location.Longitude
+	}
+	return result
+}
+
+// ProvinceFromDomain converts a province to a DTO
+func ProvinceFromDomain(province *model.Province) *ProvinceDTO {
+	return &ProvinceDTO{
+		Code: province.Code,
+		Name: province.Name,
+	}
 }
 
 // ShippingMethodFromDomain converts a shipping method domain model to a DTO
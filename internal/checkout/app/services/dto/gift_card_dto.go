@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
+)
+
+// GiftCardIssueRequest represents the request to issue a gift card or store credit
+type GiftCardIssueRequest struct {
+	Kind        string  `json:"kind" validate:"required,oneof=GIFT_CARD STORE_CREDIT"`
+	Amount      float64 `json:"amount" validate:"required,gt=0"`
+	OwnerUserID string  `json:"ownerUserId" validate:"omitempty,uuid"`
+	ExpiresAt   string  `json:"expiresAt" validate:"omitempty"`
+}
+
+// GiftCardApplyRequest represents the request to pay part of a checkout with a gift card.
+// When Amount is zero, the lesser of the card balance and the amount due is used.
+type GiftCardApplyRequest struct {
+	Code   string  `json:"code" validate:"required"`
+	Amount float64 `json:"amount" validate:"gte=0"`
+}
+
+// GiftCardDTO represents a gift card and its ledger for API responses
+type GiftCardDTO struct {
+	ID             string                   `json:"id"`
+	Code           string                   `json:"code"`
+	Kind           string                   `json:"kind"`
+	OwnerUserID    string                   `json:"ownerUserId,omitempty"`
+	InitialBalance float64                  `json:"initialBalance"`
+	Balance        float64                  `json:"balance"`
+	ExpiresAt      string                   `json:"expiresAt,omitempty"`
+	Transactions   []GiftCardTransactionDTO `json:"transactions"`
+	CreatedAt      string                   `json:"createdAt"`
+	UpdatedAt      string                   `json:"updatedAt"`
+}
+
+// GiftCardTransactionDTO represents a gift card ledger entry for API responses
+type GiftCardTransactionDTO struct {
+	ID           string  `json:"id"`
+	CheckoutID   string  `json:"checkoutId,omitempty"`
+	Type         string  `json:"type"`
+	Amount       float64 `json:"amount"`
+	BalanceAfter float64 `json:"balanceAfter"`
+	CreatedAt    string  `json:"createdAt"`
+}
+
+// GiftCardFromDomain converts a gift card domain model and its ledger to a DTO
+func GiftCardFromDomain(giftCard *model.GiftCard, transactions []*model.GiftCardTransaction) *GiftCardDTO {
+	result := &GiftCardDTO{
+		ID:             giftCard.ID.String(),
+		Code:           giftCard.Code,
+		Kind:           string(giftCard.Kind),
+		InitialBalance: giftCard.InitialBalance,
+		Balance:        giftCard.Balance,
+		Transactions:   make([]GiftCardTransactionDTO, len(transactions)),
+		CreatedAt:      giftCard.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:      giftCard.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	if giftCard.Kind == model.GiftCardKindStoreCredit {
+		result.OwnerUserID = giftCard.OwnerUserID.String()
+	}
+	if giftCard.ExpiresAt != nil {
+		result.ExpiresAt = giftCard.ExpiresAt.Format("2006-01-02T15:04:05Z")
+	}
+
+	for i, transaction := range transactions {
+		result.Transactions[i] = GiftCardTransactionDTO{
+			ID:           transaction.ID.String(),
+			Type:         string(transaction.Type),
+			Amount:       transaction.Amount,
+			BalanceAfter: transaction.BalanceAfter,
+			CreatedAt:    transaction.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		}
+		if transaction.CheckoutID != uuid.Nil {
+			result.Transactions[i].CheckoutID = transaction.CheckoutID.String()
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,8 @@
+package dto
+
+// ReceiptDocument represents a rendered receipt, ready to be downloaded or printed
+type ReceiptDocument struct {
+	Content     []byte
+	ContentType string
+	Filename    string
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/repository"
+)
+
+// GiftCardService handles operations related to gift cards and store credit
+type GiftCardService struct {
+	giftCardRepository repository.GiftCardRepository
+}
+
+// NewGiftCardService creates a new gift card service
+func NewGiftCardService(giftCardRepository repository.GiftCardRepository) *GiftCardService {
+	return &GiftCardService{
+		giftCardRepository: giftCardRepository,
+	}
+}
+
+// IssueGiftCard issues a new gift card or store credit
+func (s *GiftCardService) IssueGiftCard(ctx context.Context, req *dto.GiftCardIssueRequest) (*dto.GiftCardDTO, error) {
+	ownerUserID := uuid.Nil
+	if req.OwnerUserID != "" {
+		id, err := uuid.Parse(req.OwnerUserID)
+		if err != nil {
+			return nil, errors.New("invalid user ID format")
+		}
+		ownerUserID = id
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresAt != "" {
+		t, err := time.Parse(time.RFC3339, req.ExpiresAt)
+		if err != nil {
+			return nil, errors.New("invalid expiration date format")
+		}
+		expiresAt = &t
+	}
+
+	giftCard, issue, err := model.NewGiftCard(model.GiftCardKind(req.Kind), ownerUserID, req.Amount, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.giftCardRepository.Create(ctx, giftCard, issue); err != nil {
+		return nil, err
+	}
+
+	return dto.GiftCardFromDomain(giftCard, []*model.GiftCardTransaction{issue}), nil
+}
+
+// GetGiftCard retrieves a gift card balance and history by its code
+func (s *GiftCardService) GetGiftCard(ctx context.Context, code string) (*dto.GiftCardDTO, error) {
+	giftCard, err := s.giftCardRepository.FindByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := s.giftCardRepository.FindTransactions(ctx, giftCard.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.GiftCardFromDomain(giftCard, transactions), nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/repository"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/auth"
+)
+
+// receiptExtensions maps each receipt format to the extension of its file name
+var receiptExtensions = map[model.ReceiptFormat]string{
+	model.ReceiptFormatPDF:    "pdf",
+	model.ReceiptFormatESCPOS: "bin",
+}
+
+// ReceiptService handles operations related to the receipts of completed checkouts
+type ReceiptService struct {
+	checkoutRepository repository.CheckoutRepository
+	shippingRepository repository.ShippingRepository
+	renderers          map[model.ReceiptFormat]repository.ReceiptRenderer
+}
+
+// NewReceiptService creates a new receipt service
+func NewReceiptService(
+	checkoutRepository repository.CheckoutRepository,
+	shippingRepository repository.ShippingRepository,
+	pdfRenderer repository.ReceiptRenderer,
+	escposRenderer repository.ReceiptRenderer,
+) *ReceiptService {
+	return &ReceiptService{
+		checkoutRepository: checkoutRepository,
+		shippingRepository: shippingRepository,
+		renderers: map[model.ReceiptFormat]repository.ReceiptRenderer{
+			model.ReceiptFormatPDF:    pdfRenderer,
+			model.ReceiptFormatESCPOS: escposRenderer,
+		},
+	}
+}
+
+// RenderReceipt renders the receipt of a completed checkout in the given format
+func (s *ReceiptService) RenderReceipt(ctx context.Context, checkoutID string, format string) (*dto.ReceiptDocument, error) {
+	id, err := uuid.Parse(checkoutID)
+	if err != nil {
+		return nil, errors.New("invalid checkout ID format")
+	}
+
+	receiptFormat := model.ReceiptFormat(format)
+	if receiptFormat == "" {
+		receiptFormat = model.ReceiptFormatPDF
+	}
+	renderer, ok := s.renderers[receiptFormat]
+	if !ok {
+		return nil, errors.New("unsupported receipt format")
+	}
+
+	checkout, err := s.checkoutRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !auth.CanActFor(ctx, checkout.UserID) {
+		return nil, errors.New("checkout access denied")
+	}
+
+	// The address and method may have been deleted since the checkout was completed; the
+	// receipt is still issued, without them
+	var address *model.ShippingAddress
+	var method *model.ShippingMethod
+	if checkout.DeliveryOption != nil {
+		if found, err := s.shippingRepository.FindAddressByID(ctx, checkout.DeliveryOption.ShippingAddressID); err == nil {
+			address = found
+		}
+		if found, err := s.shippingRepository.FindMethodByID(ctx, checkout.DeliveryOption.ShippingMethodID); err == nil {
+			method = found
+		}
+	}
+
+	receipt, err := model.NewReceipt(checkout, address, method)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := renderer.Render(receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ReceiptDocument{
+		Content:     content,
+		ContentType: renderer.ContentType(),
+		Filename:    "receipt-" + receipt.OrderCode + "." + receiptExtensions[receiptFormat],
+	}, nil
+}
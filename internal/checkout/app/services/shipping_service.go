@@ -3,6 +3,9 @@ package services
 import (
 	"context"
 	"errors"
+	"io"
+	"log"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/app/services/dto"
@@ -10,15 +13,29 @@ import (
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/repository"
 )
 
+// autocompleteLimit is the largest number of addresses suggested while an address is typed
+const autocompleteLimit = 10
+
+// ErrInvalidStreets is returned when a street dataset file has rows that cannot be imported
+var ErrInvalidStreets = errors.New("street file has invalid rows")
+
 // ShippingService handles operations related to shipping addresses and methods
 type ShippingService struct {
 	shippingRepository repository.ShippingRepository
+	streetRepository   repository.StreetRepository
+	geocoder           repository.Geocoder
 }
 
 // NewShippingService creates a new shipping service
-func NewShippingService(shippingRepository repository.ShippingRepository) *ShippingService {
+func NewShippingService(
+	shippingRepository repository.ShippingRepository,
+	streetRepository repository.StreetRepository,
+	geocoder repository.Geocoder,
+) *ShippingService {
 	return &ShippingService{
 		shippingRepository: shippingRepository,
+		streetRepository:   streetRepository,
+		geocoder:           geocoder,
 	}
 }
 
@@ -54,6 +71,8 @@ func (s *ShippingService) AddShippingAddress(ctx context.Context, req *dto.Shipp
 		}
 	}
 
+	s.locate(ctx, address)
+
 	// Save the address
 	if err := s.shippingRepository.SaveAddress(ctx, address); err != nil {
 		return nil, err
@@ -143,6 +162,8 @@ func (s *ShippingService) UpdateShippingAddress(ctx context.Context, addressID s
 		return nil, err
 	}
 
+	s.locate(ctx, address)
+
 	// Save the updated address
 	if err := s.shippingRepository.SaveAddress(ctx, address); err != nil {
 		return nil, err
@@ -161,6 +182,92 @@ func (s *ShippingService) DeleteShippingAddress(ctx context.Context, addressID s
 	return s.shippingRepository.DeleteAddress(ctx, id)
 }
 
+// ValidateShippingAddress validates and normalizes a shipping address without saving it,
+// suggesting corrections for the invalid fields
+func (s *ShippingService) ValidateShippingAddress(ctx context.Context, req *dto.ShippingAddressRequest) *dto.AddressValidationDTO {
+	validation := model.ValidateAddress(model.AddressFields{
+		FirstName:     req.FirstName,
+		LastName:      req.LastName,
+		StreetAddress: req.StreetAddress,
+		Apartment:     req.Apartment,
+		City:          req.City,
+		State:         req.State,
+		PostalCode:    req.PostalCode,
+		Country:       req.Country,
+		PhoneNumber:   req.PhoneNumber,
+	})
+
+	return dto.AddressValidationFromDomain(validation)
+}
+
+// GetProvinces retrieves the official list of provinces addresses can be in
+func (s *ShippingService) GetProvinces(ctx context.Context) []*dto.ProvinceDTO {
+	provinces := model.Provinces()
+
+	result := make([]*dto.ProvinceDTO, len(provinces))
+	for i, province := range provinces {
+		result[i] = dto.ProvinceFromDomain(province)
+	}
+
+	return result
+}
+
+// AutocompleteAddresses suggests addresses matching the text typed so far, with their
+// coordinates once a door number is typed
+func (s *ShippingService) AutocompleteAddresses(ctx context.Context, text string) ([]*dto.AddressCandidateDTO, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, errors.New("query is required")
+	}
+
+	candidates, err := s.geocoder.Autocomplete(ctx, text, autocompleteLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.AddressCandidateDTO, len(candidates))
+	for i, candidate := range candidates {
+		result[i] = dto.AddressCandidateFromDomain(candidate)
+	}
+
+	return result, nil
+}
+
+// ImportStreets replaces the street dataset addresses are autocompleted and geocoded with by
+// the segments of a CSV file. Nothing is imported if any row is invalid: the result lists the
+// invalid rows and ErrInvalidStreets is returned.
+func (s *ShippingService) ImportStreets(ctx context.Context, file io.Reader) (*dto.StreetImportResult, error) {
+	segments, rowErrors, err := parseStreets(file)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.StreetImportResult{Errors: rowErrors}
+	if len(rowErrors) > 0 {
+		return result, ErrInvalidStreets
+	}
+	if len(segments) == 0 {
+		return nil, errors.New("street file is empty")
+	}
+
+	if err := s.streetRepository.ReplaceAll(ctx, segments); err != nil {
+		return nil, err
+	}
+
+	result.Imported = len(segments)
+	return result, nil
+}
+
+// locate sets where an address is on the map. Addresses the geocoder cannot find are saved
+// without a location, as they still can be shipped to.
+func (s *ShippingService) locate(ctx context.Context, address *model.ShippingAddress) {
+	location, err := s.geocoder.Geocode(ctx, address)
+	if err != nil {
+		log.Printf("Failed to geocode shipping address %s: %v", address.ID, err)
+	}
+	address.Location = location
+}
+
 // GetShippingMethods retrieves all available shipping methods
 func (s *ShippingService) GetShippingMethods(ctx context.Context) ([]*dto.ShippingMethodDTO, error) {
 	methods, err := s.shippingRepository.FindAllMethods(ctx)
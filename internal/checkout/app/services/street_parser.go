@@ -0,0 +1,99 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
+)
+
+// streetHeader is the optional header row of a street dataset file
+var streetHeader = []string{
+	"street", "from_number", "to_number", "city", "province", "postal_code",
+	"from_lat", "from_lon", "to_lat", "to_lon",
+}
+
+// parseStreets reads a street dataset CSV file with one street segment per row: the street
+// name, the first and last door numbers of the segment, the city, the province (name or
+// ISO 3166-2:AR code), an optional postal code and the coordinates of both ends. Every
+// invalid row is reported so the file can be fixed in a single pass.
+func parseStreets(r io.Reader) ([]*model.StreetSegment, []dto.StreetRowError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	segments := make([]*model.StreetSegment, 0)
+	rowErrors := make([]dto.StreetRowError, 0)
+
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, errors.New("street file is not a valid CSV file")
+		}
+
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), streetHeader[0]) {
+			continue
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		segment, err := parseStreetRecord(record)
+		if err != nil {
+			rowErrors = append(rowErrors, dto.StreetRowError{Line: line, Message: err.Error()})
+			continue
+		}
+
+		segments = append(segments, segment)
+	}
+
+	return segments, rowErrors, nil
+}
+
+// parseStreetRecord converts a CSV record into a street segment
+func parseStreetRecord(record []string) (*model.StreetSegment, error) {
+	if len(record) != len(streetHeader) {
+		return nil, fmt.Errorf("expected %d columns", len(streetHeader))
+	}
+
+	fromNumber, err := strconv.Atoi(strings.TrimSpace(record[1]))
+	if err != nil {
+		return nil, errors.New("from_number must be a whole number")
+	}
+	toNumber, err := strconv.Atoi(strings.TrimSpace(record[2]))
+	if err != nil {
+		return nil, errors.New("to_number must be a whole number")
+	}
+
+	from, err := parseCoordinates(record[6], record[7])
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseCoordinates(record[8], record[9])
+	if err != nil {
+		return nil, err
+	}
+
+	return model.NewStreetSegment(record[0], fromNumber, toNumber, record[3], record[4], record[5], from, to)
+}
+
+// parseCoordinates converts the latitude and longitude columns of a record into a point
+func parseCoordinates(latitude, longitude string) (*model.Coordinates, error) {
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latitude), 64)
+	if err != nil {
+		return nil, errors.New("latitude must be a decimal number")
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(longitude), 64)
+	if err != nil {
+		return nil, errors.New("longitude must be a decimal number")
+	}
+	return model.NewCoordinates(lat, lon)
+}
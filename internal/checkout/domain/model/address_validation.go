@@ -0,0 +1,315 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AddressErrorCode identifies why a field of a shipping address is invalid
+type AddressErrorCode string
+
+const (
+	AddressErrorRequired           AddressErrorCode = "REQUIRED"
+	AddressErrorUnsupportedCountry AddressErrorCode = "UNSUPPORTED_COUNTRY"
+	AddressErrorUnknownProvince    AddressErrorCode = "UNKNOWN_PROVINCE"
+	AddressErrorInvalidPostalCode  AddressErrorCode = "INVALID_POSTAL_CODE"
+	AddressErrorProvinceMismatch   AddressErrorCode = "POSTAL_CODE_PROVINCE_MISMATCH"
+	AddressErrorInvalidPhone       AddressErrorCode = "INVALID_PHONE_NUMBER"
+)
+
+// Country is the only country addresses are shipped to
+const Country = "Argentina"
+
+var (
+	// cpaPattern matches a CPA (Código Postal Argentino): the province letter, the four
+	// digits of the old postal code and three letters identifying the side of the block
+	cpaPattern = regexp.MustCompile(`^[A-HJ-NP-Z][0-9]{4}[A-Z]{3}$`)
+	// oldPostalCodePattern matches a postal code in the four-digit format used before the CPA
+	oldPostalCodePattern = regexp.MustCompile(`^[1-9][0-9]{3}$`)
+	// e164Pattern matches a phone number in E.164 form: a plus sign and up to 15 digits
+	e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+)
+
+// countryNames lists the folded ways of writing Argentina
+var countryNames = map[string]bool{
+	"argentina":           true,
+	"republica argentina": true,
+	"ar":                  true,
+	"arg":                 true,
+}
+
+// AddressFields holds the fields of a shipping address a shopper fills in
+type AddressFields struct {
+	FirstName     string `json:"firstName"`
+	LastName      string `json:"lastName"`
+	StreetAddress string `json:"streetAddress"`
+	Apartment     string `json:"apartment"`
+	City          string `json:"city"`
+	State         string `json:"state"`
+	PostalCode    string `json:"postalCode"`
+	Country       string `json:"country"`
+	PhoneNumber   string `json:"phoneNumber"`
+}
+
+// AddressFieldError describes an invalid field of a shipping address
+type AddressFieldError struct {
+	// Field is the name of the field in the API, e.g. postalCode
+	Field   string           `json:"field"`
+	Code    AddressErrorCode `json:"code"`
+	Message string           `json:"message"`
+}
+
+// AddressValidation is the result of validating a shipping address
+type AddressValidation struct {
+	// Address holds the fields normalized: trimmed, the province as its ISO 3166-2:AR code,
+	// the postal code in upper case and the phone number in E.164 form
+	Address AddressFields `json:"address"`
+	// Suggestion is the normalized address with the corrections that could be inferred for
+	// the invalid fields (e.g. the province from a CPA, or the country code of a phone number).
+	// It is nil when the address is valid or nothing could be corrected.
+	Suggestion *AddressFields       `json:"suggestion,omitempty"`
+	Errors     []*AddressFieldError `json:"errors"`
+}
+
+// Valid returns whether the address has no invalid fields
+func (v *AddressValidation) Valid() bool {
+	return len(v.Errors) == 0
+}
+
+// Err returns the validation errors as an AddressValidationError, or nil if the address is valid
+func (v *AddressValidation) Err() error {
+	if v.Valid() {
+		return nil
+	}
+	return &AddressValidationError{Validation: v}
+}
+
+// AddressValidationError is returned when a shipping address has invalid fields
+type AddressValidationError struct {
+	Validation *AddressValidation
+}
+
+// Error implements the error interface
+func (e *AddressValidationError) Error() string {
+	return "invalid shipping address"
+}
+
+// ValidateAddress validates a shipping address in Argentina and normalizes its fields. The
+// province is matched against the official list and stored as its ISO 3166-2:AR code, postal
+// codes are accepted both as CPA and in the old four-digit format and must belong to the
+// province, and phone numbers must be in E.164 form.
+func ValidateAddress(fields AddressFields) *AddressValidation {
+	v := &AddressValidation{
+		Address: AddressFields{
+			FirstName:     strings.TrimSpace(fields.FirstName),
+			LastName:      strings.TrimSpace(fields.LastName),
+			StreetAddress: strings.TrimSpace(fields.StreetAddress),
+			Apartment:     strings.TrimSpace(fields.Apartment),
+			City:          strings.TrimSpace(fields.City),
+			State:         strings.TrimSpace(fields.State),
+			PostalCode:    normalizePostalCode(fields.PostalCode),
+			Country:       strings.TrimSpace(fields.Country),
+			PhoneNumber:   strings.TrimSpace(fields.PhoneNumber),
+		},
+	}
+	suggestion := v.Address
+	corrected := false
+
+	v.require("firstName", "First name", v.Address.FirstName)
+	v.require("lastName", "Last name", v.Address.LastName)
+	v.require("streetAddress", "Street address", v.Address.StreetAddress)
+	v.require("city", "City", v.Address.City)
+
+	// Country
+	if v.require("country", "Country", v.Address.Country) {
+		if countryNames[FoldName(v.Address.Country)] {
+			v.Address.Country = Country
+			suggestion.Country = Country
+		} else {
+			v.fail("country", AddressErrorUnsupportedCountry, "Only addresses in Argentina can be shipped to")
+			suggestion.Country = Country
+			corrected = true
+		}
+	}
+
+	// Postal code
+	postalCode := v.Address.PostalCode
+	isCPA := cpaPattern.MatchString(postalCode)
+	if v.require("postalCode", "Postal code", postalCode) && !isCPA && !oldPostalCodePattern.MatchString(postalCode) {
+		v.fail("postalCode", AddressErrorInvalidPostalCode, "Postal code must be a CPA (e.g. C1425DKF) or have 4 digits")
+		postalCode = ""
+	}
+
+	// Province, checked against the postal code
+	var postalProvince *Province
+	if isCPA {
+		postalProvince, _ = FindProvince("AR-" + postalCode[:1])
+	}
+	if v.require("state", "Province", v.Address.State) {
+		province, ok := FindProvince(v.Address.State)
+		if ok && province.Code != CABA && ambiguousBuenosAires[FoldName(v.Address.State)] && isCABAPostalCode(postalCode) {
+			province, _ = FindProvince(CABA)
+		}
+
+		switch {
+		case !ok:
+			v.fail("state", AddressErrorUnknownProvince, fmt.Sprintf("%q is not a province of Argentina", v.Address.State))
+			if postalProvince != nil {
+				suggestion.State = postalProvince.Code
+				corrected = true
+			}
+		case postalCode != "" && !postalCodeInProvince(postalCode, province):
+			v.Address.State = province.Code
+			v.fail("postalCode", AddressErrorProvinceMismatch, fmt.Sprintf("Postal code %s is not in %s", postalCode, province.Name))
+			if postalProvince != nil {
+				suggestion.State = postalProvince.Code
+				corrected = true
+			} else {
+				suggestion.State = province.Code
+			}
+		default:
+			v.Address.State = province.Code
+			suggestion.State = province.Code
+		}
+	} else if postalProvince != nil {
+		suggestion.State = postalProvince.Code
+		corrected = true
+	}
+
+	// Phone number
+	if v.require("phoneNumber", "Phone number", v.Address.PhoneNumber) {
+		phoneNumber, valid, guessed := normalizePhoneNumber(v.Address.PhoneNumber)
+		if valid {
+			v.Address.PhoneNumber = phoneNumber
+			suggestion.PhoneNumber = phoneNumber
+		} else {
+			v.fail("phoneNumber", AddressErrorInvalidPhone, "Phone number must be in international format, e.g. +5491145678900")
+			if guessed {
+				suggestion.PhoneNumber = phoneNumber
+				corrected = true
+			}
+		}
+	}
+
+	if !v.Valid() && corrected {
+		v.Suggestion = &suggestion
+	}
+	return v
+}
+
+// require records a REQUIRED error if a field is empty. It returns whether the field has a value.
+func (v *AddressValidation) require(field, label, value string) bool {
+	if value == "" {
+		v.fail(field, AddressErrorRequired, label+" is required")
+		return false
+	}
+	return true
+}
+
+// fail records an invalid field
+func (v *AddressValidation) fail(field string, code AddressErrorCode, message string) {
+	v.Errors = append(v.Errors, &AddressFieldError{
+		Field:   field,
+		Code:    code,
+		Message: message,
+	})
+}
+
+// normalizePostalCode upper-cases a postal code and removes spaces and dashes, so "c1425 dkf"
+// becomes "C1425DKF"
+func normalizePostalCode(postalCode string) string {
+	return strings.Map(func(char rune) rune {
+		if char == ' ' || char == '-' {
+			return -1
+		}
+		return char
+	}, strings.ToUpper(strings.TrimSpace(postalCode)))
+}
+
+// isCABAPostalCode returns whether a postal code belongs to the Autonomous City of Buenos Aires,
+// whose four-digit codes go from 1000 to 1499
+func isCABAPostalCode(postalCode string) bool {
+	if cpaPattern.MatchString(postalCode) {
+		return postalCode[0] == 'C'
+	}
+	return oldPostalCodePattern.MatchString(postalCode) && postalCode >= "1000" && postalCode <= "1499"
+}
+
+// postalCodeInProvince returns whether a valid postal code can belong to a province. CPAs
+// start with the province letter. Four-digit codes only identify a town, so they are only
+// checked for the city of Buenos Aires, whose range no province shares.
+func postalCodeInProvince(postalCode string, province *Province) bool {
+	if cpaPattern.MatchString(postalCode) {
+		return postalCode[0] == province.Letter()
+	}
+	if province.Code == CABA {
+		return isCABAPostalCode(postalCode)
+	}
+	return true
+}
+
+// normalizePhoneNumber removes the separators of a phone number and checks it is in E.164
+// form. Argentine numbers must have 10 digits after the country code, plus the 9 of mobile
+// numbers. When the number is not valid but looks like a local Argentine number (e.g.
+// "011 4567-8900" or "11 15 4567 8900"), it returns the E.164 form guessed for it.
+func normalizePhoneNumber(phoneNumber string) (normalized string, valid bool, guessed bool) {
+	number := strings.Map(func(char rune) rune {
+		switch char {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return char
+	}, phoneNumber)
+	if strings.HasPrefix(number, "00") {
+		number = "+" + strings.TrimPrefix(number, "00")
+	}
+
+	if e164Pattern.MatchString(number) {
+		if !strings.HasPrefix(number, "+54") || isArgentineNationalNumber(strings.TrimPrefix(number, "+54")) {
+			return number, true, false
+		}
+		if guess, ok := guessArgentineNumber(strings.TrimPrefix(number, "+54")); ok {
+			return guess, false, true
+		}
+		return number, false, false
+	}
+
+	if strings.HasPrefix(number, "+") || !isDigits(number) {
+		return number, false, false
+	}
+	if strings.HasPrefix(number, "54") && isArgentineNationalNumber(number[2:]) {
+		return "+" + number, false, true
+	}
+	if guess, ok := guessArgentineNumber(number); ok {
+		return guess, false, true
+	}
+	return number, false, false
+}
+
+// isArgentineNationalNumber returns whether the digits after +54 form a valid number: an area
+// code and subscriber number adding up to 10 digits, preceded by 9 for mobile numbers
+func isArgentineNationalNumber(number string) bool {
+	if strings.HasPrefix(number, "0") {
+		return false
+	}
+	return len(number) == 10 || (len(number) == 11 && number[0] == '9')
+}
+
+// guessArgentineNumber converts a number dialed within Argentina to E.164: it drops the 0 trunk
+// prefix, and turns the 15 prefix of mobile numbers (dialed after the area code, which has 2
+// to 4 digits) into the 9 that precedes the area code
+func guessArgentineNumber(number string) (string, bool) {
+	number = strings.TrimPrefix(number, "0")
+	if isArgentineNationalNumber(number) {
+		return "+54" + number, true
+	}
+	if len(number) == 12 {
+		for areaCodeLength := 2; areaCodeLength <= 4; areaCodeLength++ {
+			if number[areaCodeLength:areaCodeLength+2] == "15" {
+				return "+549" + number[:areaCodeLength] + number[areaCodeLength+2:], true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,188 @@
+package model
+
+import "testing"
+
+func TestFindProvince(t *testing.T) {
+	tests := []struct {
+		name     string
+		wantCode string
+		wantOK   bool
+	}{
+		{name: "Córdoba", wantCode: "AR-X", wantOK: true},
+		{name: "cordoba", wantCode: "AR-X", wantOK: true},
+		{name: "Pcia. de Córdoba", wantCode: "AR-X", wantOK: true},
+		{name: "Provincia de Entre Ríos", wantCode: "AR-E", wantOK: true},
+		{name: "ar-b", wantCode: "AR-B", wantOK: true},
+		{name: "Buenos Aires", wantCode: "AR-B", wantOK: true},
+		{name: "Bs. As.", wantCode: "AR-B", wantOK: true},
+		{name: "C.A.B.A.", wantCode: "AR-C", wantOK: true},
+		{name: "Capital Federal", wantCode: "AR-C", wantOK: true},
+		{name: "  TIERRA DEL FUEGO ", wantCode: "AR-V", wantOK: true},
+		{name: "Gotham", wantOK: false},
+		{name: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			province, ok := FindProvince(tt.name)
+			if ok != tt.wantOK {
+				t.Fatalf("FindProvince(%q) ok = %v, want %v", tt.name, ok, tt.wantOK)
+			}
+			if ok && province.Code != tt.wantCode {
+				t.Errorf("FindProvince(%q) = %s, want %s", tt.name, province.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestNormalizePhoneNumber(t *testing.T) {
+	tests := []struct {
+		phoneNumber string
+		want        string
+		wantValid   bool
+		wantGuessed bool
+	}{
+		{phoneNumber: "+54 9 11 4567-8900", want: "+5491145678900", wantValid: true},
+		{phoneNumber: "+54 (11) 4567.8900", want: "+541145678900", wantValid: true},
+		{phoneNumber: "0054 11 4567 8900", want: "+541145678900", wantValid: true},
+		{phoneNumber: "+1 (415) 555-0100", want: "+14155550100", wantValid: true},
+		{phoneNumber: "+54 011 4567 8900", want: "+541145678900", wantGuessed: true},
+		{phoneNumber: "011 4567-8900", want: "+541145678900", wantGuessed: true},
+		{phoneNumber: "11 15 4567 8900", want: "+5491145678900", wantGuessed: true},
+		{phoneNumber: "0351 15 456 7890", want: "+5493514567890", wantGuessed: true},
+		{phoneNumber: "5491145678900", want: "+5491145678900", wantGuessed: true},
+		{phoneNumber: "+54 12345", want: "+5412345"},
+		{phoneNumber: "4567-8900", want: "45678900"},
+		{phoneNumber: "call me", want: "callme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.phoneNumber, func(t *testing.T) {
+			got, valid, guessed := normalizePhoneNumber(tt.phoneNumber)
+			if got != tt.want || valid != tt.wantValid || guessed != tt.wantGuessed {
+				t.Errorf("normalizePhoneNumber(%q) = %q, %v, %v, want %q, %v, %v",
+					tt.phoneNumber, got, valid, guessed, tt.want, tt.wantValid, tt.wantGuessed)
+			}
+		})
+	}
+}
+
+func TestValidateAddress(t *testing.T) {
+	tests := []struct {
+		name           string
+		state          string
+		postalCode     string
+		phoneNumber    string
+		country        string
+		wantState      string
+		wantPostalCode string
+		wantPhone      string
+		wantErrors     []AddressErrorCode
+		// wantSuggestion is the province suggested for the address, empty if none is
+		wantSuggestion string
+	}{
+		{
+			name:  "cpa in the city",
+			state: "Capital Federal", postalCode: "c1063 aco", phoneNumber: "+54 9 11 4567-8900",
+			wantState: "AR-C", wantPostalCode: "C1063ACO", wantPhone: "+5491145678900",
+		},
+		{
+			name:  "buenos aires with a city postal code",
+			state: "Buenos Aires", postalCode: "1425", phoneNumber: "+541145678900",
+			wantState: "AR-C", wantPostalCode: "1425", wantPhone: "+541145678900",
+		},
+		{
+			name:  "buenos aires with a province cpa",
+			state: "Bs. As.", postalCode: "B1900ABC", phoneNumber: "+542214567890",
+			wantState: "AR-B", wantPostalCode: "B1900ABC", wantPhone: "+542214567890",
+		},
+		{
+			name:  "buenos aires with a province postal code",
+			state: "Buenos Aires", postalCode: "1900", phoneNumber: "+542214567890",
+			wantState: "AR-B", wantPostalCode: "1900", wantPhone: "+542214567890",
+		},
+		{
+			name:  "four-digit postal code outside the city range",
+			state: "CABA", postalCode: "1900", phoneNumber: "+541145678900",
+			wantState: "AR-C", wantPostalCode: "1900", wantPhone: "+541145678900",
+			wantErrors: []AddressErrorCode{AddressErrorProvinceMismatch},
+		},
+		{
+			name:  "cpa of another province",
+			state: "Córdoba", postalCode: "C1425DKF", phoneNumber: "+543514567890",
+			wantState: "AR-X", wantPostalCode: "C1425DKF", wantPhone: "+543514567890",
+			wantErrors: []AddressErrorCode{AddressErrorProvinceMismatch}, wantSuggestion: "AR-C",
+		},
+		{
+			name:  "unknown province suggested from the cpa",
+			state: "Gotham", postalCode: "X5000ABC", phoneNumber: "+543514567890",
+			wantState: "Gotham", wantPostalCode: "X5000ABC", wantPhone: "+543514567890",
+			wantErrors: []AddressErrorCode{AddressErrorUnknownProvince}, wantSuggestion: "AR-X",
+		},
+		{
+			name:  "invalid postal code",
+			state: "Córdoba", postalCode: "50000", phoneNumber: "+543514567890",
+			wantState: "AR-X", wantPostalCode: "50000", wantPhone: "+543514567890",
+			wantErrors: []AddressErrorCode{AddressErrorInvalidPostalCode},
+		},
+		{
+			name:  "local phone number",
+			state: "Córdoba", postalCode: "X5000ABC", phoneNumber: "0351 15 456 7890",
+			wantState: "AR-X", wantPostalCode: "X5000ABC", wantPhone: "0351 15 456 7890",
+			wantErrors: []AddressErrorCode{AddressErrorInvalidPhone}, wantSuggestion: "AR-X",
+		},
+		{
+			name:  "another country",
+			state: "Córdoba", postalCode: "X5000ABC", phoneNumber: "+543514567890", country: "Chile",
+			wantState: "AR-X", wantPostalCode: "X5000ABC", wantPhone: "+543514567890",
+			wantErrors: []AddressErrorCode{AddressErrorUnsupportedCountry}, wantSuggestion: "AR-X",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			country := tt.country
+			if country == "" {
+				country = "argentina"
+			}
+			v := ValidateAddress(AddressFields{
+				FirstName:     "Ana",
+				LastName:      "Gómez",
+				StreetAddress: "Av. Paseo Colón 850",
+				City:          "Buenos Aires",
+				State:         tt.state,
+				PostalCode:    tt.postalCode,
+				Country:       country,
+				PhoneNumber:   tt.phoneNumber,
+			})
+
+			if v.Address.State != tt.wantState || v.Address.PostalCode != tt.wantPostalCode || v.Address.PhoneNumber != tt.wantPhone {
+				t.Errorf("address = %s, %s, %s, want %s, %s, %s",
+					v.Address.State, v.Address.PostalCode, v.Address.PhoneNumber, tt.wantState, tt.wantPostalCode, tt.wantPhone)
+			}
+
+			codes := make([]AddressErrorCode, len(v.Errors))
+			for i, fieldErr := range v.Errors {
+				codes[i] = fieldErr.Code
+			}
+			if len(codes) != len(tt.wantErrors) {
+				t.Fatalf("errors = %v, want %v", codes, tt.wantErrors)
+			}
+			for i := range codes {
+				if codes[i] != tt.wantErrors[i] {
+					t.Fatalf("errors = %v, want %v", codes, tt.wantErrors)
+				}
+			}
+
+			if tt.wantSuggestion == "" {
+				if v.Suggestion != nil {
+					t.Errorf("suggestion = %+v, want none", v.Suggestion)
+				}
+				return
+			}
+			if v.Suggestion == nil || v.Suggestion.State != tt.wantSuggestion {
+				t.Errorf("suggestion = %+v, want state %s", v.Suggestion, tt.wantSuggestion)
+			}
+		})
+	}
+}
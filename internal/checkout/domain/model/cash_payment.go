@@ -0,0 +1,140 @@
+package model
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// paymentCodeAlphabet leaves out characters that are easy to misread at the counter (0/O, 1/I/L)
+const paymentCodeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+
+// paymentCodeLength is the amount of characters in a payment code
+const paymentCodeLength = 6
+
+// CashPayment represents a checkout paid in cash at the counter. The shopper gets a short
+// payment code when completing the checkout and shows it at the counter before DueAt;
+// staff then record the cash received and the change given.
+type CashPayment struct {
+	Code       string     `json:"code"`
+	AmountDue  float64    `json:"amountDue"`
+	DueAt      time.Time  `json:"dueAt"`
+	Received   float64    `json:"received,omitempty"`
+	Change     float64    `json:"change,omitempty"`
+	ReceivedBy string     `json:"receivedBy,omitempty"`
+	PaidAt     *time.Time `json:"paidAt,omitempty"`
+}
+
+// NormalizePaymentCode uppercases a payment code and drops the spaces and dashes it may be typed with
+func NormalizePaymentCode(code string) string {
+	code = strings.ToUpper(code)
+	code = strings.ReplaceAll(code, " ", "")
+	return strings.ReplaceAll(code, "-", "")
+}
+
+// newPaymentCode generates a random payment code
+func newPaymentCode() (string, error) {
+	code := make([]byte, paymentCodeLength)
+	max := big.NewInt(int64(len(paymentCodeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = paymentCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// PaysCashOnPickup returns true if the amount left after gift cards is paid in cash at the counter
+func (c *Checkout) PaysCashOnPickup() bool {
+	return c.PaymentMethod != nil && c.PaymentMethod.PaymentType == PaymentTypeCashOnPickup && c.AmountDue() > 0
+}
+
+// AwaitCashPayment completes a cash on pickup checkout into the AWAITING_PAYMENT state and
+// issues its payment code. The reservation is cancelled if it is not paid before the deadline.
+func (c *Checkout) AwaitCashPayment(deadline time.Time) error {
+	if c.Status == CheckoutStatusCancelled {
+		return errors.New("cannot complete a cancelled checkout")
+	}
+	if c.Status != CheckoutStatusPaymentSelected {
+		return errors.New("payment method must be selected before completing checkout")
+	}
+	if !c.PaysCashOnPickup() {
+		return errors.New("payment method is not cash on pickup")
+	}
+	if c.GiftCardTotal() > roundToCents(c.Total) {
+		return errors.New("gift card amounts exceed the checkout total")
+	}
+
+	code, err := newPaymentCode()
+	if err != nil {
+		return err
+	}
+
+	c.CashPayment = &CashPayment{
+		Code:      code,
+		AmountDue: c.AmountDue(),
+		DueAt:     deadline,
+	}
+	c.Status = CheckoutStatusAwaitingPayment
+	c.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// ReissuePaymentCode gives a checkout awaiting payment a new payment code, for when the one
+// issued is already taken by another open payment
+func (c *Checkout) ReissuePaymentCode() error {
+	if !c.IsAwaitingPayment() || c.CashPayment == nil {
+		return errors.New("checkout is not awaiting payment")
+	}
+
+	code, err := newPaymentCode()
+	if err != nil {
+		return err
+	}
+
+	c.CashPayment.Code = code
+	return nil
+}
+
+// RecordCashPayment records the cash received at the counter, works out the change and
+// completes the checkout
+func (c *Checkout) RecordCashPayment(received float64, receivedBy string, now time.Time) error {
+	if c.Status != CheckoutStatusAwaitingPayment || c.CashPayment == nil {
+		return errors.New("checkout is not awaiting payment")
+	}
+	if c.IsPaymentOverdue(now) {
+		return errors.New("payment deadline has passed")
+	}
+	if received <= 0 {
+		return errors.New("amount received must be greater than zero")
+	}
+
+	received = roundToCents(received)
+	if received < c.CashPayment.AmountDue {
+		return errors.New("amount received is less than the amount due")
+	}
+
+	c.CashPayment.Received = received
+	c.CashPayment.Change = roundToCents(received - c.CashPayment.AmountDue)
+	c.CashPayment.ReceivedBy = strings.TrimSpace(receivedBy)
+	c.CashPayment.PaidAt = &now
+	c.Status = CheckoutStatusCompleted
+	c.UpdatedAt = now
+
+	return nil
+}
+
+// IsAwaitingPayment returns true if the checkout waits to be paid at the counter
+func (c *Checkout) IsAwaitingPayment() bool {
+	return c.Status == CheckoutStatusAwaitingPayment
+}
+
+// IsPaymentOverdue returns true if the checkout was not paid at the counter before its deadline
+func (c *Checkout) IsPaymentOverdue(now time.Time) bool {
+	return c.IsAwaitingPayment() && c.CashPayment != nil && now.After(c.CashPayment.DueAt)
+}
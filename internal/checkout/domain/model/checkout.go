@@ -14,41 +14,124 @@ const (
 	CheckoutStatusInitiated        CheckoutStatus = "INITIATED"
 	CheckoutStatusShippingSelected CheckoutStatus = "SHIPPING_SELECTED"
 	CheckoutStatusPaymentSelected  CheckoutStatus = "PAYMENT_SELECTED"
+	CheckoutStatusAwaitingPayment  CheckoutStatus = "AWAITING_PAYMENT"
 	CheckoutStatusCompleted        CheckoutStatus = "COMPLETED"
 	CheckoutStatusCancelled        CheckoutStatus = "CANCELLED"
+	CheckoutStatusRefunded         CheckoutStatus = "REFUNDED"
 )
 
-// CheckoutItem represents an item in the checkout
+// CheckoutItem represents an item in the checkout. Price is the unit price charged, option
+// surcharges included; when a customer segment price applies, OriginalPrice keeps the list price.
 type CheckoutItem struct {
-	ProductID uuid.UUID `json:"productId"`
-	Name      string    `json:"name"`
-	Price     float64   `json:"price"`
-	Quantity  int       `json:"quantity"`
-	Subtotal  float64   `json:"subtotal"`
-	ImageURL  string    `json:"imageUrl"`
+	ProductID     uuid.UUID    `json:"productId"`
+	VariantID     string       `json:"variantId,omitempty"`
+	Options       []ItemOption `json:"options,omitempty"`
+	Name          string       `json:"name"`
+	Price         float64      `json:"price"`
+	OriginalPrice float64      `json:"originalPrice,omitempty"`
+	Segment       string       `json:"segment,omitempty"`
+	Quantity      int          `json:"quantity"`
+	Subtotal      float64      `json:"subtotal"`
+	ImageURL      string       `json:"imageUrl"`
+	Category      string       `json:"category,omitempty"`
+	// AddedBy is the user who first added the line to the cart, which differs from the
+	// checkout user on shared carts
+	AddedBy uuid.UUID `json:"addedBy"`
+	// Contributions splits the quantity among the users who added it to the cart
+	Contributions []ItemContribution `json:"contributions,omitempty"`
 }
 
-// PaymentMethod represents payment method details
-type PaymentMethod struct {
-	PaymentType    string                 `json:"paymentType"`
-	PaymentDetails map[string]interface{} `json:"paymentDetails"`
+// ItemContribution is the part of the quantity of a checkout item added by one user, copied from the cart line
+type ItemContribution struct {
+	UserID   uuid.UUID `json:"userId"`
+	Quantity int       `json:"quantity"`
+}
+
+// ItemOption represents a customization chosen for a checkout item, copied from the cart line
+type ItemOption struct {
+	Name      string  `json:"name"`
+	Value     string  `json:"value"`
+	Surcharge float64 `json:"surcharge,omitempty"`
+}
+
+// Surcharge returns the amount the options add to the unit price
+func (i *CheckoutItem) Surcharge() float64 {
+	total := 0.0
+	for _, option := range i.Options {
+		total += option.Surcharge
+	}
+	return total
+}
+
+// ApplySegmentPrice charges the item at the price granted to a customer segment. The
+// segment price replaces the product price; option surcharges are still added.
+func (i *CheckoutItem) ApplySegmentPrice(segment string, price float64) {
+	if i.OriginalPrice == 0 {
+		i.OriginalPrice = i.Price
+	}
+	i.Segment = segment
+	i.Price = price + i.Surcharge()
+	i.Subtotal = roundToCents(i.Price * float64(i.Quantity))
+}
+
+// contributors returns the contributions to the item, or the whole quantity for the user who
+// added it when the contributions were not recorded
+func (i *CheckoutItem) contributors(checkoutUserID uuid.UUID) []ItemContribution {
+	total := 0
+	for _, contribution := range i.Contributions {
+		total += contribution.Quantity
+	}
+	if total == i.Quantity && total > 0 {
+		return i.Contributions
+	}
+
+	userID := i.AddedBy
+	if userID == uuid.Nil {
+		userID = checkoutUserID
+	}
+	return []ItemContribution{{UserID: userID, Quantity: i.Quantity}}
+}
+
+// ProductPrice returns the list price of the product, without option surcharges
+func (i *CheckoutItem) ProductPrice() float64 {
+	return i.ListPrice() - i.Surcharge()
+}
+
+// ListPrice returns the unit price before any segment discount
+func (i *CheckoutItem) ListPrice() float64 {
+	if i.OriginalPrice != 0 {
+		return i.OriginalPrice
+	}
+	return i.Price
+}
+
+// ContributorShare represents the part of the subtotal added by one contributor of a shared cart
+type ContributorShare struct {
+	UserID     uuid.UUID `json:"userId"`
+	Subtotal   float64   `json:"subtotal"`
+	Percentage float64   `json:"percentage"`
 }
 
 // Checkout represents the Checkout aggregate root in the Checkout Process bounded context
 type Checkout struct {
-	ID             uuid.UUID       `json:"id"`
-	CartID         uuid.UUID       `json:"cartId"`
-	UserID         uuid.UUID       `json:"userId"`
-	Status         CheckoutStatus  `json:"status"`
-	Items          []*CheckoutItem `json:"items"`
-	Subtotal       float64         `json:"subtotal"`
-	ShippingCost   float64         `json:"shippingCost"`
-	Tax            float64         `json:"tax"`
-	Total          float64         `json:"total"`
-	DeliveryOption *DeliveryOption `json:"deliveryOption"`
-	PaymentMethod  *PaymentMethod  `json:"paymentMethod"`
-	CreatedAt      time.Time       `json:"createdAt"`
-	UpdatedAt      time.Time       `json:"updatedAt"`
+	ID             uuid.UUID         `json:"id"`
+	CartID         uuid.UUID         `json:"cartId"`
+	UserID         uuid.UUID         `json:"userId"`
+	Status         CheckoutStatus    `json:"status"`
+	Items          []*CheckoutItem   `json:"items"`
+	Subtotal       float64           `json:"subtotal"`
+	ShippingCost   float64           `json:"shippingCost"`
+	Discounts      []*DiscountLine   `json:"discounts"`
+	Tax            float64           `json:"tax"`
+	TaxRate        float64           `json:"taxRate"`
+	FinancingCost  float64           `json:"financingCost"`
+	Total          float64           `json:"total"`
+	DeliveryOption *DeliveryOption   `json:"deliveryOption"`
+	PaymentMethod  *PaymentMethod    `json:"paymentMethod"`
+	GiftCards      []*GiftCardTender `json:"giftCards"`
+	CashPayment    *CashPayment      `json:"cashPayment,omitempty"`
+	CreatedAt      time.Time         `json:"createdAt"`
+	UpdatedAt      time.Time         `json:"updatedAt"`
 }
 
 // NewCheckout creates a new checkout from a cart
@@ -74,6 +157,8 @@ func NewCheckout(cartID, userID uuid.UUID, items []*CheckoutItem, subtotal float
 		ShippingCost: 0,
 		Tax:          0,
 		Total:        subtotal, // Initially just the subtotal
+		Discounts:    make([]*DiscountLine, 0),
+		GiftCards:    make([]*GiftCardTender, 0),
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}, nil
@@ -84,6 +169,9 @@ func (c *Checkout) SetDeliveryOption(deliveryOption *DeliveryOption, shippingCos
 	if c.Status == CheckoutStatusCancelled {
 		return errors.New("cannot update a cancelled checkout")
 	}
+	if c.isFinalized() {
+		return errors.New("cannot update a completed checkout")
+	}
 
 	if deliveryOption == nil {
 		return errors.New("delivery option cannot be nil")
@@ -101,73 +189,343 @@ func (c *Checkout) SetDeliveryOption(deliveryOption *DeliveryOption, shippingCos
 	c.ShippingCost = shippingCost
 	c.Status = CheckoutStatusShippingSelected
 	c.UpdateTotal()
+	c.dropUnavailableInstallments()
 	c.UpdatedAt = time.Now()
 
 	return nil
 }
 
-// SetPaymentMethod sets the payment method for the checkout
-func (c *Checkout) SetPaymentMethod(paymentType string, paymentDetails map[string]interface{}) error {
+// SetPaymentMethod sets the payment method for the checkout, optionally financed with an installment plan
+func (c *Checkout) SetPaymentMethod(paymentMethod *PaymentMethod, plan *InstallmentPlan) error {
 	if c.Status == CheckoutStatusCancelled {
 		return errors.New("cannot update a cancelled checkout")
 	}
+	if c.isFinalized() {
+		return errors.New("cannot update a completed checkout")
+	}
 
 	if c.Status == CheckoutStatusInitiated {
 		return errors.New("shipping option must be selected before payment")
 	}
 
-	if paymentType == "" {
-		return errors.New("payment type is required")
+	if paymentMethod == nil {
+		return errors.New("payment method cannot be nil")
 	}
 
-	if paymentDetails == nil {
-		return errors.New("payment details cannot be nil")
+	paymentMethod.Installments = nil
+	if plan != nil {
+		if !paymentMethod.IsCard() {
+			return errors.New("installments are only available for card payments")
+		}
+		if !plan.AppliesTo(paymentMethod.Card.CardBrand, paymentMethod.Card.Issuer, c.FinanceableAmount()) {
+			return errors.New("installment plan is not available for this payment")
+		}
+		paymentMethod.Installments = plan.Selection()
 	}
 
-	c.PaymentMethod = &PaymentMethod{
-		PaymentType:    paymentType,
-		PaymentDetails: paymentDetails,
-	}
+	c.PaymentMethod = paymentMethod
 	c.Status = CheckoutStatusPaymentSelected
+	c.UpdateTotal()
+	c.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// ApplyGiftCard uses a gift card or store credit to pay part of the checkout.
+// Applying the same card again replaces the previous amount.
+func (c *Checkout) ApplyGiftCard(tender *GiftCardTender) error {
+	if c.Status == CheckoutStatusCancelled {
+		return errors.New("cannot update a cancelled checkout")
+	}
+	if c.isFinalized() {
+		return errors.New("cannot update a completed checkout")
+	}
+	if tender == nil || tender.GiftCardID == uuid.Nil {
+		return errors.New("gift card is required")
+	}
+	if tender.Amount <= 0 {
+		return errors.New("amount must be greater than zero")
+	}
+
+	c.removeGiftCard(tender.GiftCardID)
+	if tender.Amount > roundToCents(c.amountBeforeFinancing()-c.GiftCardTotal()) {
+		return errors.New("gift card amount exceeds the amount due")
+	}
+
+	c.GiftCards = append(c.GiftCards, tender)
+	c.UpdateTotal()
+
+	if !c.installmentsAvailable() {
+		c.removeGiftCard(tender.GiftCardID)
+		c.UpdateTotal()
+		return ErrBelowInstallmentMinimum
+	}
+
+	c.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// RemoveGiftCard removes a gift card or store credit from the checkout tenders
+func (c *Checkout) RemoveGiftCard(giftCardID uuid.UUID) error {
+	if c.isFinalized() {
+		return errors.New("cannot update a completed checkout")
+	}
+	if !c.removeGiftCard(giftCardID) {
+		return errors.New("gift card not applied to checkout")
+	}
+
+	c.UpdateTotal()
+	c.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// removeGiftCard drops the tender for a gift card, returning false if it was not applied
+func (c *Checkout) removeGiftCard(giftCardID uuid.UUID) bool {
+	for i, tender := range c.GiftCards {
+		if tender.GiftCardID == giftCardID {
+			c.GiftCards = append(c.GiftCards[:i], c.GiftCards[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyDiscount adds a discount line to the checkout. A checkout holds at most one
+// line per source, so applying a discount again replaces the previous one.
+func (c *Checkout) ApplyDiscount(discount *DiscountLine) error {
+	if c.Status == CheckoutStatusCancelled {
+		return errors.New("cannot update a cancelled checkout")
+	}
+	if c.isFinalized() {
+		return errors.New("cannot update a completed checkout")
+	}
+	if discount == nil || discount.Amount <= 0 {
+		return errors.New("discount amount must be greater than zero")
+	}
+
+	previous := c.discount(discount.Source)
+	c.removeDiscount(discount.Source)
+	if discount.Amount > roundToCents(c.Subtotal-c.DiscountTotal()) {
+		if previous != nil {
+			c.Discounts = append(c.Discounts, previous)
+		}
+		return errors.New("discount exceeds the checkout subtotal")
+	}
+
+	c.Discounts = append(c.Discounts, discount)
+	c.refreshTotals()
+
+	if c.GiftCardTotal() > roundToCents(c.amountBeforeFinancing()) {
+		c.removeDiscount(discount.Source)
+		if previous != nil {
+			c.Discounts = append(c.Discounts, previous)
+		}
+		c.refreshTotals()
+		return errors.New("discount leaves gift card amounts above the checkout total")
+	}
+
+	if !c.installmentsAvailable() {
+		c.removeDiscount(discount.Source)
+		if previous != nil {
+			c.Discounts = append(c.Discounts, previous)
+		}
+		c.refreshTotals()
+		return ErrBelowInstallmentMinimum
+	}
+
+	c.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// RemoveDiscount removes the discount line of the given source
+func (c *Checkout) RemoveDiscount(source DiscountSource) error {
+	if c.isFinalized() {
+		return errors.New("cannot update a completed checkout")
+	}
+	if !c.removeDiscount(source) {
+		return errors.New("discount not applied to checkout")
+	}
+
+	c.refreshTotals()
 	c.UpdatedAt = time.Now()
 
 	return nil
 }
 
-// Complete marks the checkout as completed
+// discount returns the discount line of the given source, or nil if there is none
+func (c *Checkout) discount(source DiscountSource) *DiscountLine {
+	for _, line := range c.Discounts {
+		if line.Source == source {
+			return line
+		}
+	}
+	return nil
+}
+
+// removeDiscount drops the discount line of the given source, returning false if there was none
+func (c *Checkout) removeDiscount(source DiscountSource) bool {
+	for i, line := range c.Discounts {
+		if line.Source == source {
+			c.Discounts = append(c.Discounts[:i], c.Discounts[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// DiscountTotal returns the sum of the discount lines
+func (c *Checkout) DiscountTotal() float64 {
+	total := 0.0
+	for _, line := range c.Discounts {
+		total += line.Amount
+	}
+	return roundToCents(total)
+}
+
+// LoyaltyPointsRedeemed returns the loyalty points redeemed on the checkout
+func (c *Checkout) LoyaltyPointsRedeemed() int {
+	if line := c.discount(DiscountSourceLoyaltyPoints); line != nil {
+		return line.Points
+	}
+	return 0
+}
+
+// GiftCardTotal returns the amount paid with gift cards and store credit
+func (c *Checkout) GiftCardTotal() float64 {
+	total := 0.0
+	for _, tender := range c.GiftCards {
+		total += tender.Amount
+	}
+	return roundToCents(total)
+}
+
+// AmountDue returns the amount left to be paid with the payment method after gift cards
+func (c *Checkout) AmountDue() float64 {
+	due := roundToCents(c.Total - c.GiftCardTotal())
+	if due < 0 {
+		return 0
+	}
+	return due
+}
+
+// Complete marks the checkout as completed. A checkout fully paid with gift cards
+// or store credit does not need a payment method. Cash on pickup checkouts go through
+// AwaitCashPayment instead.
 func (c *Checkout) Complete() error {
 	if c.Status == CheckoutStatusCancelled {
 		return errors.New("cannot complete a cancelled checkout")
 	}
 
-	if c.Status != CheckoutStatusPaymentSelected {
+	if c.Status == CheckoutStatusAwaitingPayment {
+		return errors.New("checkout is awaiting payment at the counter")
+	}
+	if c.PaysCashOnPickup() {
+		return errors.New("cash on pickup checkouts are paid at the counter")
+	}
+
+	paidWithGiftCards := c.Status == CheckoutStatusShippingSelected && len(c.GiftCards) > 0 && c.AmountDue() == 0
+	if c.Status != CheckoutStatusPaymentSelected && !paidWithGiftCards {
 		return errors.New("payment method must be selected before completing checkout")
 	}
 
+	if c.GiftCardTotal() > roundToCents(c.Total) {
+		return errors.New("gift card amounts exceed the checkout total")
+	}
+	if c.Status == CheckoutStatusPaymentSelected && !c.installmentsAvailable() {
+		return ErrBelowInstallmentMinimum
+	}
+
 	c.Status = CheckoutStatusCompleted
 	c.UpdatedAt = time.Now()
 
 	return nil
 }
 
+// Refund marks a completed checkout as refunded
+func (c *Checkout) Refund() error {
+	if c.Status != CheckoutStatusCompleted {
+		return errors.New("only completed checkouts can be refunded")
+	}
+
+	c.Status = CheckoutStatusRefunded
+	c.UpdatedAt = time.Now()
+
+	return nil
+}
+
 // Cancel marks the checkout as cancelled
 func (c *Checkout) Cancel() {
-	if c.Status != CheckoutStatusCompleted {
+	if c.Status != CheckoutStatusCompleted && c.Status != CheckoutStatusRefunded {
 		c.Status = CheckoutStatusCancelled
 		c.UpdatedAt = time.Now()
 	}
 }
 
-// CalculateTax calculates the tax amount based on the subtotal and shipping cost
+// CalculateTax calculates the tax amount based on the discounted subtotal and shipping cost
 func (c *Checkout) CalculateTax(taxRate float64) {
-	c.Tax = (c.Subtotal + c.ShippingCost) * taxRate
-	c.UpdateTotal()
+	c.TaxRate = taxRate
+	c.refreshTotals()
+	c.dropUnavailableInstallments()
 	c.UpdatedAt = time.Now()
 }
 
-// UpdateTotal updates the total amount
+// refreshTotals recomputes the tax with the stored rate and then the total
+func (c *Checkout) refreshTotals() {
+	c.Tax = (c.Subtotal - c.DiscountTotal() + c.ShippingCost) * c.TaxRate
+	c.UpdateTotal()
+}
+
+// amountBeforeFinancing returns the total before the installment plan surcharge
+func (c *Checkout) amountBeforeFinancing() float64 {
+	return c.Subtotal - c.DiscountTotal() + c.ShippingCost + c.Tax
+}
+
+// FinanceableAmount returns the amount that can be financed with an installment plan,
+// which excludes the part paid with gift cards and store credit
+func (c *Checkout) FinanceableAmount() float64 {
+	amount := c.amountBeforeFinancing() - c.GiftCardTotal()
+	if amount < 0 {
+		return 0
+	}
+	return amount
+}
+
+// installmentsAvailable returns whether the financed amount still reaches the minimum of the
+// selected installment plan. Gift cards and discounts lower it after the plan was chosen.
+func (c *Checkout) installmentsAvailable() bool {
+	if c.PaymentMethod == nil || c.PaymentMethod.Installments == nil {
+		return true
+	}
+	return c.FinanceableAmount() >= c.PaymentMethod.Installments.MinAmount
+}
+
+// dropUnavailableInstallments clears the installment plan once the financed amount falls below its
+// minimum. Shipping changes send the checkout back to payment selection, so the plan is dropped
+// instead of rejecting the change and the buyer picks one again.
+func (c *Checkout) dropUnavailableInstallments() {
+	if c.installmentsAvailable() {
+		return
+	}
+	c.PaymentMethod.Installments = nil
+	c.UpdateTotal()
+}
+
+// UpdateTotal updates the total amount, including the financing surcharge of the selected installment plan
 func (c *Checkout) UpdateTotal() {
-	c.Total = c.Subtotal + c.ShippingCost + c.Tax
+	c.FinancingCost = 0
+	if c.PaymentMethod != nil && c.PaymentMethod.Installments != nil {
+		c.FinancingCost = c.PaymentMethod.Installments.FinancingCost(c.FinanceableAmount())
+	}
+	c.Total = c.amountBeforeFinancing() + c.FinancingCost
+}
+
+// isFinalized returns true once the checkout was completed, even if it still waits to be
+// paid at the counter, so its amounts can no longer change
+func (c *Checkout) isFinalized() bool {
+	return c.Status == CheckoutStatusAwaitingPayment || c.Status == CheckoutStatusCompleted || c.Status == CheckoutStatusRefunded
 }
 
 // IsCompleted returns true if the checkout is completed
@@ -175,7 +533,42 @@ func (c *Checkout) IsCompleted() bool {
 	return c.Status == CheckoutStatusCompleted
 }
 
+// IsRefunded returns true if the checkout was refunded
+func (c *Checkout) IsRefunded() bool {
+	return c.Status == CheckoutStatusRefunded
+}
+
 // IsCancelled returns true if the checkout is cancelled
 func (c *Checkout) IsCancelled() bool {
 	return c.Status == CheckoutStatusCancelled
 }
+
+// ContributorShares splits the subtotal among the users who added the items, in proportion
+// to the units each one added and in the order they first appear. Items without
+// contributions, from checkouts created before lines recorded them, belong to the user who
+// added them, or to the checkout user if the checkout predates shared carts.
+func (c *Checkout) ContributorShares() []*ContributorShare {
+	shares := make([]*ContributorShare, 0)
+	byUser := make(map[uuid.UUID]*ContributorShare)
+
+	for _, item := range c.Items {
+		for _, contribution := range item.contributors(c.UserID) {
+			share, ok := byUser[contribution.UserID]
+			if !ok {
+				share = &ContributorShare{UserID: contribution.UserID}
+				byUser[contribution.UserID] = share
+				shares = append(shares, share)
+			}
+			share.Subtotal += item.Subtotal * float64(contribution.Quantity) / float64(item.Quantity)
+		}
+	}
+
+	for _, share := range shares {
+		share.Subtotal = roundToCents(share.Subtotal)
+		if c.Subtotal > 0 {
+			share.Percentage = roundToCents(share.Subtotal / c.Subtotal * 100)
+		}
+	}
+
+	return shares
+}
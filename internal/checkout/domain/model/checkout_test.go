@@ -0,0 +1,135 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// newFinancedCheckout returns a checkout of 10000 with 1000 of shipping, paid by card in
+// installments with a plan whose minimum financed amount is minAmount
+func newFinancedCheckout(t *testing.T, minAmount float64) *Checkout {
+	t.Helper()
+
+	items := []*CheckoutItem{{ProductID: uuid.New(), Name: "Mate", Price: 5000, Quantity: 2, Subtotal: 10000}}
+	checkout, err := NewCheckout(uuid.New(), uuid.New(), items, 10000)
+	if err != nil {
+		t.Fatalf("NewCheckout() error = %v", err)
+	}
+	if err := checkout.SetDeliveryOption(NewDeliveryOption(uuid.New(), uuid.New()), 1000); err != nil {
+		t.Fatalf("SetDeliveryOption() error = %v", err)
+	}
+
+	card, err := NewCardTokenPayment("tok_visa", "visa", "galicia", "4242", 12, 2030, "Ana Gómez")
+	if err != nil {
+		t.Fatalf("NewCardTokenPayment() error = %v", err)
+	}
+	plan, err := NewInstallmentPlan("visa", "", 3, 0.1, minAmount)
+	if err != nil {
+		t.Fatalf("NewInstallmentPlan() error = %v", err)
+	}
+	if err := checkout.SetPaymentMethod(card, plan); err != nil {
+		t.Fatalf("SetPaymentMethod() error = %v", err)
+	}
+	return checkout
+}
+
+func TestCheckoutInstallmentMinimum(t *testing.T) {
+	tests := []struct {
+		name      string
+		minAmount float64
+		change    func(c *Checkout) error
+		wantErr   error
+		wantPlan  bool
+		wantTotal float64
+	}{
+		{
+			name:      "gift card keeps the financed amount above the minimum",
+			minAmount: 8000,
+			change: func(c *Checkout) error {
+				return c.ApplyGiftCard(&GiftCardTender{GiftCardID: uuid.New(), Amount: 2000})
+			},
+			wantPlan:  true,
+			wantTotal: 11900,
+		},
+		{
+			name:      "gift card below the minimum is rejected",
+			minAmount: 10000,
+			change: func(c *Checkout) error {
+				return c.ApplyGiftCard(&GiftCardTender{GiftCardID: uuid.New(), Amount: 2000})
+			},
+			wantErr:   ErrBelowInstallmentMinimum,
+			wantPlan:  true,
+			wantTotal: 12100,
+		},
+		{
+			name:      "discount below the minimum is rejected",
+			minAmount: 10000,
+			change: func(c *Checkout) error {
+				return c.ApplyDiscount(NewLoyaltyDiscount(2000, 2000))
+			},
+			wantErr:   ErrBelowInstallmentMinimum,
+			wantPlan:  true,
+			wantTotal: 12100,
+		},
+		{
+			name:      "cheaper shipping below the minimum drops the plan",
+			minAmount: 10500,
+			change: func(c *Checkout) error {
+				return c.SetDeliveryOption(NewDeliveryOption(uuid.New(), uuid.New()), 0)
+			},
+			wantPlan:  false,
+			wantTotal: 10000,
+		},
+		{
+			name:      "shipping above the minimum keeps the plan",
+			minAmount: 10500,
+			change: func(c *Checkout) error {
+				return c.SetDeliveryOption(NewDeliveryOption(uuid.New(), uuid.New()), 800)
+			},
+			wantPlan:  true,
+			wantTotal: 11880,
+		},
+		{
+			name:      "tax above the minimum keeps the plan",
+			minAmount: 11000,
+			change: func(c *Checkout) error {
+				c.CalculateTax(0.1)
+				return nil
+			},
+			wantPlan:  true,
+			wantTotal: 13310,
+		},
+		{
+			name:      "lower tax below the minimum drops the plan",
+			minAmount: 11000,
+			change: func(c *Checkout) error {
+				c.CalculateTax(0.1)
+				if err := c.ApplyGiftCard(&GiftCardTender{GiftCardID: uuid.New(), Amount: 1000}); err != nil {
+					return err
+				}
+				c.CalculateTax(0)
+				return nil
+			},
+			wantPlan:  false,
+			wantTotal: 11000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkout := newFinancedCheckout(t, tt.minAmount)
+
+			err := tt.change(checkout)
+			if err != tt.wantErr {
+				t.Fatalf("change error = %v, want %v", err, tt.wantErr)
+			}
+			if gotPlan := checkout.PaymentMethod.Installments != nil; gotPlan != tt.wantPlan {
+				t.Errorf("installment plan kept = %v, want %v", gotPlan, tt.wantPlan)
+			}
+			if got := roundToCents(checkout.Total); got != tt.wantTotal {
+				t.Errorf("Total = %v, want %v", got, tt.wantTotal)
+			}
+		})
+	}
+}
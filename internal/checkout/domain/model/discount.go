@@ -0,0 +1,26 @@
+package model
+
+// DiscountSource identifies where a checkout discount line comes from
+type DiscountSource string
+
+const (
+	DiscountSourceLoyaltyPoints DiscountSource = "LOYALTY_POINTS"
+)
+
+// DiscountLine represents a discount applied to the checkout subtotal
+type DiscountLine struct {
+	Source      DiscountSource `json:"source"`
+	Description string         `json:"description"`
+	Amount      float64        `json:"amount"`
+	Points      int            `json:"points,omitempty"`
+}
+
+// NewLoyaltyDiscount creates the discount line granted for redeeming loyalty points
+func NewLoyaltyDiscount(points int, amount float64) *DiscountLine {
+	return &DiscountLine{
+		Source:      DiscountSourceLoyaltyPoints,
+		Description: "Loyalty points redeemed",
+		Amount:      roundToCents(amount),
+		Points:      points,
+	}
+}
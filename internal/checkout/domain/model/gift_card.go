@@ -0,0 +1,223 @@
+package model
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GiftCardKind represents the kind of stored-value tender
+type GiftCardKind string
+
+const (
+	GiftCardKindGiftCard    GiftCardKind = "GIFT_CARD"
+	GiftCardKindStoreCredit GiftCardKind = "STORE_CREDIT"
+)
+
+// GiftCardTransactionType represents the kind of movement recorded in a gift card ledger
+type GiftCardTransactionType string
+
+const (
+	GiftCardTransactionIssue  GiftCardTransactionType = "ISSUE"
+	GiftCardTransactionDebit  GiftCardTransactionType = "DEBIT"
+	GiftCardTransactionRefund GiftCardTransactionType = "REFUND"
+)
+
+// giftCardCodeAlphabet excludes characters that are easy to confuse when typed (0/O, 1/I)
+const giftCardCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// GiftCard represents a gift card or store credit balance that can be used as tender
+type GiftCard struct {
+	ID             uuid.UUID    `json:"id"`
+	Code           string       `json:"code"`
+	Kind           GiftCardKind `json:"kind"`
+	OwnerUserID    uuid.UUID    `json:"ownerUserId"`
+	InitialBalance float64      `json:"initialBalance"`
+	Balance        float64      `json:"balance"`
+	ExpiresAt      *time.Time   `json:"expiresAt"`
+	CreatedAt      time.Time    `json:"createdAt"`
+	UpdatedAt      time.Time    `json:"updatedAt"`
+}
+
+// GiftCardTransaction represents an entry of the gift card ledger. Amount is signed:
+// debits are negative and issues/refunds are positive.
+type GiftCardTransaction struct {
+	ID           uuid.UUID               `json:"id"`
+	GiftCardID   uuid.UUID               `json:"giftCardId"`
+	CheckoutID   uuid.UUID               `json:"checkoutId"`
+	Type         GiftCardTransactionType `json:"type"`
+	Amount       float64                 `json:"amount"`
+	BalanceAfter float64                 `json:"balanceAfter"`
+	CreatedAt    time.Time               `json:"createdAt"`
+}
+
+// GiftCardTender represents the portion of a checkout paid with a gift card or store credit
+type GiftCardTender struct {
+	GiftCardID uuid.UUID    `json:"giftCardId"`
+	MaskedCode string       `json:"maskedCode"`
+	Kind       GiftCardKind `json:"kind"`
+	Amount     float64      `json:"amount"`
+}
+
+// NewGiftCard issues a new gift card or store credit with a random code.
+// Store credit must be owned by a user. It returns the card and its ISSUE ledger entry.
+func NewGiftCard(kind GiftCardKind, ownerUserID uuid.UUID, amount float64, expiresAt *time.Time) (*GiftCard, *GiftCardTransaction, error) {
+	if kind != GiftCardKindGiftCard && kind != GiftCardKindStoreCredit {
+		return nil, nil, errors.New("invalid gift card kind")
+	}
+	if kind == GiftCardKindStoreCredit && ownerUserID == uuid.Nil {
+		return nil, nil, errors.New("store credit must belong to a user")
+	}
+	if amount <= 0 {
+		return nil, nil, errors.New("amount must be greater than zero")
+	}
+
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, nil, errors.New("expiration date must be in the future")
+	}
+
+	code, err := generateGiftCardCode()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	card := &GiftCard{
+		ID:             uuid.New(),
+		Code:           code,
+		Kind:           kind,
+		OwnerUserID:    ownerUserID,
+		InitialBalance: roundToCents(amount),
+		Balance:        roundToCents(amount),
+		ExpiresAt:      expiresAt,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	issue := &GiftCardTransaction{
+		ID:           uuid.New(),
+		GiftCardID:   card.ID,
+		Type:         GiftCardTransactionIssue,
+		Amount:       card.Balance,
+		BalanceAfter: card.Balance,
+		CreatedAt:    now,
+	}
+
+	return card, issue, nil
+}
+
+// IsExpired returns true if the gift card cannot be used anymore at the given time
+func (g *GiftCard) IsExpired(now time.Time) bool {
+	return g.ExpiresAt != nil && !now.Before(*g.ExpiresAt)
+}
+
+// CanBeUsedBy returns true if the user can use this balance. Gift cards are bearer
+// instruments, while store credit can only be used by its owner.
+func (g *GiftCard) CanBeUsedBy(userID uuid.UUID) bool {
+	return g.Kind != GiftCardKindStoreCredit || g.OwnerUserID == userID
+}
+
+// CheckRedeemable validates that the amount can be redeemed from the card at the given time
+func (g *GiftCard) CheckRedeemable(amount float64, now time.Time) error {
+	if g.IsExpired(now) {
+		return errors.New("gift card has expired")
+	}
+	if amount <= 0 {
+		return errors.New("amount must be greater than zero")
+	}
+	if roundToCents(amount) > g.Balance {
+		return errors.New("insufficient gift card balance")
+	}
+	return nil
+}
+
+// Debit subtracts the amount, rounded to cents, from the balance and returns the ledger entry
+// for the checkout
+func (g *GiftCard) Debit(checkoutID uuid.UUID, amount float64) (*GiftCardTransaction, error) {
+	now := time.Now()
+	amount = roundToCents(amount)
+	if err := g.CheckRedeemable(amount, now); err != nil {
+		return nil, err
+	}
+
+	g.Balance = roundToCents(g.Balance - amount)
+	g.UpdatedAt = now
+
+	return &GiftCardTransaction{
+		ID:           uuid.New(),
+		GiftCardID:   g.ID,
+		CheckoutID:   checkoutID,
+		Type:         GiftCardTransactionDebit,
+		Amount:       -amount,
+		BalanceAfter: g.Balance,
+		CreatedAt:    now,
+	}, nil
+}
+
+// Refund adds back an amount, rounded to cents, previously debited for the checkout and
+// returns the ledger entry
+func (g *GiftCard) Refund(checkoutID uuid.UUID, amount float64) (*GiftCardTransaction, error) {
+	amount = roundToCents(amount)
+	if amount <= 0 {
+		return nil, errors.New("amount must be greater than zero")
+	}
+
+	now := time.Now()
+	g.Balance = roundToCents(g.Balance + amount)
+	g.UpdatedAt = now
+
+	return &GiftCardTransaction{
+		ID:           uuid.New(),
+		GiftCardID:   g.ID,
+		CheckoutID:   checkoutID,
+		Type:         GiftCardTransactionRefund,
+		Amount:       amount,
+		BalanceAfter: g.Balance,
+		CreatedAt:    now,
+	}, nil
+}
+
+// MaskedCode returns the code with every group but the last one hidden
+func (g *GiftCard) MaskedCode() string {
+	groups := strings.Split(g.Code, "-")
+	for i := 0; i < len(groups)-1; i++ {
+		groups[i] = strings.Repeat("*", len(groups[i]))
+	}
+	return strings.Join(groups, "-")
+}
+
+// Tender returns the checkout tender that uses the given amount of this card
+func (g *GiftCard) Tender(amount float64) *GiftCardTender {
+	return &GiftCardTender{
+		GiftCardID: g.ID,
+		MaskedCode: g.MaskedCode(),
+		Kind:       g.Kind,
+		Amount:     roundToCents(amount),
+	}
+}
+
+// NormalizeGiftCardCode normalizes a code typed by a user (case and surrounding spaces)
+func NormalizeGiftCardCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// generateGiftCardCode generates a random code formatted as XXXX-XXXX-XXXX-XXXX
+func generateGiftCardCode() (string, error) {
+	var sb strings.Builder
+	alphabetSize := big.NewInt(int64(len(giftCardCodeAlphabet)))
+	for i := 0; i < 16; i++ {
+		if i > 0 && i%4 == 0 {
+			sb.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(giftCardCodeAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
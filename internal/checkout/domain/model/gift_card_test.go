@@ -0,0 +1,172 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestGiftCardDebit(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name        string
+		balance     float64
+		expiresAt   *time.Time
+		amount      float64
+		wantErr     string
+		wantBalance float64
+		wantAmount  float64
+	}{
+		{name: "partial debit", balance: 1000, amount: 250.5, wantBalance: 749.5, wantAmount: -250.5},
+		{name: "debit of the whole balance", balance: 1000, amount: 1000, wantBalance: 0, wantAmount: -1000},
+		{name: "amount rounded to cents", balance: 100, amount: 33.333, wantBalance: 66.67, wantAmount: -33.33},
+		{name: "rounding up within the balance", balance: 10.01, amount: 10.005, wantBalance: 0, wantAmount: -10.01},
+		{name: "floating point remainder", balance: 0.3, amount: 0.1, wantBalance: 0.2, wantAmount: -0.1},
+		{name: "above the balance", balance: 100, amount: 100.01, wantErr: "insufficient gift card balance", wantBalance: 100},
+		{name: "rounding up above the balance", balance: 10, amount: 10.005, wantErr: "insufficient gift card balance", wantBalance: 10},
+		{name: "zero amount", balance: 100, amount: 0, wantErr: "amount must be greater than zero", wantBalance: 100},
+		{name: "amount rounding to zero", balance: 100, amount: 0.004, wantErr: "amount must be greater than zero", wantBalance: 100},
+		{name: "negative amount", balance: 100, amount: -5, wantErr: "amount must be greater than zero", wantBalance: 100},
+		{name: "expired card", balance: 100, expiresAt: &past, amount: 10, wantErr: "gift card has expired", wantBalance: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := &GiftCard{ID: uuid.New(), Kind: GiftCardKindGiftCard, Balance: tt.balance, ExpiresAt: tt.expiresAt}
+			checkoutID := uuid.New()
+
+			transaction, err := card.Debit(checkoutID, tt.amount)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Debit() error = %v, want %q", err, tt.wantErr)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Debit() error = %v", err)
+				}
+				if transaction.Type != GiftCardTransactionDebit || transaction.CheckoutID != checkoutID {
+					t.Errorf("Debit() transaction = %+v, want a debit for the checkout", transaction)
+				}
+				if transaction.Amount != tt.wantAmount {
+					t.Errorf("transaction Amount = %v, want %v", transaction.Amount, tt.wantAmount)
+				}
+				if transaction.BalanceAfter != tt.wantBalance {
+					t.Errorf("transaction BalanceAfter = %v, want %v", transaction.BalanceAfter, tt.wantBalance)
+				}
+			}
+			if card.Balance != tt.wantBalance {
+				t.Errorf("Balance = %v, want %v", card.Balance, tt.wantBalance)
+			}
+		})
+	}
+}
+
+func TestGiftCardRefund(t *testing.T) {
+	tests := []struct {
+		name        string
+		balance     float64
+		amount      float64
+		wantErr     string
+		wantBalance float64
+		wantAmount  float64
+	}{
+		{name: "refund to an empty card", balance: 0, amount: 500, wantBalance: 500, wantAmount: 500},
+		{name: "amount rounded to cents", balance: 66.67, amount: 33.333, wantBalance: 100, wantAmount: 33.33},
+		{name: "floating point remainder", balance: 0.1, amount: 0.2, wantBalance: 0.3, wantAmount: 0.2},
+		{name: "zero amount", balance: 10, amount: 0, wantErr: "amount must be greater than zero", wantBalance: 10},
+		{name: "amount rounding to zero", balance: 10, amount: 0.001, wantErr: "amount must be greater than zero", wantBalance: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card := &GiftCard{ID: uuid.New(), Kind: GiftCardKindStoreCredit, OwnerUserID: uuid.New(), Balance: tt.balance}
+
+			transaction, err := card.Refund(uuid.New(), tt.amount)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Refund() error = %v, want %q", err, tt.wantErr)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Refund() error = %v", err)
+				}
+				if transaction.Type != GiftCardTransactionRefund || transaction.Amount != tt.wantAmount {
+					t.Errorf("Refund() transaction = %+v, want a refund of %v", transaction, tt.wantAmount)
+				}
+			}
+			if card.Balance != tt.wantBalance {
+				t.Errorf("Balance = %v, want %v", card.Balance, tt.wantBalance)
+			}
+		})
+	}
+}
+
+func TestGiftCardDebitThenRefundRestoresBalance(t *testing.T) {
+	card := &GiftCard{ID: uuid.New(), Kind: GiftCardKindGiftCard, Balance: 1234.56}
+	checkoutID := uuid.New()
+
+	amounts := []float64{0.1, 0.2, 99.99, 333.33}
+	for _, amount := range amounts {
+		if _, err := card.Debit(checkoutID, amount); err != nil {
+			t.Fatalf("Debit(%v) error = %v", amount, err)
+		}
+	}
+	for _, amount := range amounts {
+		if _, err := card.Refund(checkoutID, amount); err != nil {
+			t.Fatalf("Refund(%v) error = %v", amount, err)
+		}
+	}
+
+	if card.Balance != 1234.56 {
+		t.Errorf("Balance = %v, want 1234.56", card.Balance)
+	}
+}
+
+func TestNewGiftCard(t *testing.T) {
+	future := time.Now().Add(24 * time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name        string
+		kind        GiftCardKind
+		owner       uuid.UUID
+		amount      float64
+		expiresAt   *time.Time
+		wantErr     string
+		wantBalance float64
+	}{
+		{name: "gift card", kind: GiftCardKindGiftCard, amount: 5000, expiresAt: &future, wantBalance: 5000},
+		{name: "amount rounded to cents", kind: GiftCardKindGiftCard, amount: 99.999, wantBalance: 100},
+		{name: "store credit with owner", kind: GiftCardKindStoreCredit, owner: uuid.New(), amount: 150, wantBalance: 150},
+		{name: "store credit without owner", kind: GiftCardKindStoreCredit, amount: 150, wantErr: "store credit must belong to a user"},
+		{name: "invalid kind", kind: "VOUCHER", amount: 150, wantErr: "invalid gift card kind"},
+		{name: "zero amount", kind: GiftCardKindGiftCard, amount: 0, wantErr: "amount must be greater than zero"},
+		{name: "expired", kind: GiftCardKindGiftCard, amount: 150, expiresAt: &past, wantErr: "expiration date must be in the future"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card, issue, err := NewGiftCard(tt.kind, tt.owner, tt.amount, tt.expiresAt)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("NewGiftCard() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewGiftCard() error = %v", err)
+			}
+			if card.Balance != tt.wantBalance || card.InitialBalance != tt.wantBalance {
+				t.Errorf("Balance, InitialBalance = %v, %v, want %v", card.Balance, card.InitialBalance, tt.wantBalance)
+			}
+			if issue.Type != GiftCardTransactionIssue || issue.Amount != tt.wantBalance || issue.BalanceAfter != tt.wantBalance {
+				t.Errorf("issue transaction = %+v, want an issue of %v", issue, tt.wantBalance)
+			}
+			if len(card.Code) != 19 || card.MaskedCode()[:15] != "****-****-****-" {
+				t.Errorf("Code = %q, MaskedCode = %q, want a XXXX-XXXX-XXXX-XXXX code", card.Code, card.MaskedCode())
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrBelowInstallmentMinimum is returned when a change would leave the financed amount below the
+// minimum of the selected installment plan
+var ErrBelowInstallmentMinimum = errors.New("amount is below the minimum of the selected installment plan")
+
+// InstallmentPlan represents a financing plan (cuotas) offered for a card brand and issuer
+type InstallmentPlan struct {
+	ID           uuid.UUID `json:"id"`
+	CardBrand    string    `json:"cardBrand"`
+	Issuer       string    `json:"issuer"`
+	Installments int       `json:"installments"`
+	InterestRate float64   `json:"interestRate"`
+	MinAmount    float64   `json:"minAmount"`
+}
+
+// InstallmentSelection represents the installment plan chosen for a payment method
+type InstallmentSelection struct {
+	PlanID       uuid.UUID `json:"planId"`
+	CardBrand    string    `json:"cardBrand"`
+	Issuer       string    `json:"issuer"`
+	Installments int       `json:"installments"`
+	InterestRate float64   `json:"interestRate"`
+	// MinAmount is the minimum financed amount of the plan, checked again whenever the total changes
+	MinAmount float64 `json:"minAmount"`
+}
+
+// NewInstallmentPlan creates a new installment plan
+func NewInstallmentPlan(cardBrand, issuer string, installments int, interestRate, minAmount float64) (*InstallmentPlan, error) {
+	if cardBrand == "" {
+		return nil, errors.New("card brand is required")
+	}
+	if installments <= 0 {
+		return nil, errors.New("installments must be positive")
+	}
+	if interestRate < 0 {
+		return nil, errors.New("interest rate cannot be negative")
+	}
+	if minAmount < 0 {
+		return nil, errors.New("minimum amount cannot be negative")
+	}
+
+	return &InstallmentPlan{
+		ID:           uuid.New(),
+		CardBrand:    strings.ToLower(cardBrand),
+		Issuer:       strings.ToLower(issuer),
+		Installments: installments,
+		InterestRate: interestRate,
+		MinAmount:    minAmount,
+	}, nil
+}
+
+// AppliesTo returns true if the plan can be used for the given card brand, issuer and amount.
+// Plans without an issuer apply to every issuer of the card brand.
+func (p *InstallmentPlan) AppliesTo(cardBrand, issuer string, amount float64) bool {
+	if !strings.EqualFold(p.CardBrand, cardBrand) {
+		return false
+	}
+	if p.Issuer != "" && !strings.EqualFold(p.Issuer, issuer) {
+		return false
+	}
+	return amount >= p.MinAmount
+}
+
+// FinancingCost returns the surcharge added when financing the given amount with this plan
+func (p *InstallmentPlan) FinancingCost(amount float64) float64 {
+	return roundToCents(amount * p.InterestRate)
+}
+
+// InstallmentAmount returns the amount of each installment for the given amount
+func (p *InstallmentPlan) InstallmentAmount(amount float64) float64 {
+	return roundToCents((amount + p.FinancingCost(amount)) / float64(p.Installments))
+}
+
+// Selection returns the value object stored in a payment method when this plan is chosen
+func (p *InstallmentPlan) Selection() *InstallmentSelection {
+	return &InstallmentSelection{
+		PlanID:       p.ID,
+		CardBrand:    p.CardBrand,
+		Issuer:       p.Issuer,
+		Installments: p.Installments,
+		InterestRate: p.InterestRate,
+		MinAmount:    p.MinAmount,
+	}
+}
+
+// FinancingCost returns the surcharge for the given amount using the selected plan
+func (s *InstallmentSelection) FinancingCost(amount float64) float64 {
+	return roundToCents(amount * s.InterestRate)
+}
+
+// roundToCents rounds an amount to two decimal places
+func roundToCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestNewInstallmentPlan(t *testing.T) {
+	tests := []struct {
+		name         string
+		cardBrand    string
+		installments int
+		interestRate float64
+		minAmount    float64
+		wantErr      string
+	}{
+		{name: "valid plan", cardBrand: "Visa", installments: 3, interestRate: 0.1, minAmount: 1000},
+		{name: "interest free plan", cardBrand: "visa", installments: 6, interestRate: 0, minAmount: 0},
+		{name: "missing card brand", installments: 3, wantErr: "card brand is required"},
+		{name: "zero installments", cardBrand: "visa", installments: 0, wantErr: "installments must be positive"},
+		{name: "negative interest", cardBrand: "visa", installments: 3, interestRate: -0.1, wantErr: "interest rate cannot be negative"},
+		{name: "negative minimum", cardBrand: "visa", installments: 3, minAmount: -1, wantErr: "minimum amount cannot be negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := NewInstallmentPlan(tt.cardBrand, "Galicia", tt.installments, tt.interestRate, tt.minAmount)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("NewInstallmentPlan() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewInstallmentPlan() error = %v", err)
+			}
+			if plan.CardBrand != "visa" || plan.Issuer != "galicia" {
+				t.Errorf("NewInstallmentPlan() brand, issuer = %q, %q, want lowercase", plan.CardBrand, plan.Issuer)
+			}
+		})
+	}
+}
+
+func TestInstallmentPlanAppliesTo(t *testing.T) {
+	plan := &InstallmentPlan{CardBrand: "visa", Issuer: "galicia", Installments: 3, MinAmount: 1000}
+	anyIssuer := &InstallmentPlan{CardBrand: "visa", Installments: 3, MinAmount: 1000}
+
+	tests := []struct {
+		name      string
+		plan      *InstallmentPlan
+		cardBrand string
+		issuer    string
+		amount    float64
+		want      bool
+	}{
+		{name: "matching card above minimum", plan: plan, cardBrand: "visa", issuer: "galicia", amount: 1500, want: true},
+		{name: "amount equal to minimum", plan: plan, cardBrand: "VISA", issuer: "Galicia", amount: 1000, want: true},
+		{name: "amount below minimum", plan: plan, cardBrand: "visa", issuer: "galicia", amount: 999.99, want: false},
+		{name: "other card brand", plan: plan, cardBrand: "mastercard", issuer: "galicia", amount: 1500, want: false},
+		{name: "other issuer", plan: plan, cardBrand: "visa", issuer: "santander", amount: 1500, want: false},
+		{name: "plan for every issuer", plan: anyIssuer, cardBrand: "visa", issuer: "santander", amount: 1500, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.plan.AppliesTo(tt.cardBrand, tt.issuer, tt.amount); got != tt.want {
+				t.Errorf("AppliesTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInstallmentPlanQuote(t *testing.T) {
+	tests := []struct {
+		name              string
+		installments      int
+		interestRate      float64
+		amount            float64
+		wantFinancingCost float64
+		wantInstallment   float64
+	}{
+		{name: "interest free", installments: 3, interestRate: 0, amount: 3000, wantFinancingCost: 0, wantInstallment: 1000},
+		{name: "with interest", installments: 6, interestRate: 0.2, amount: 12000, wantFinancingCost: 2400, wantInstallment: 2400},
+		{name: "rounds the surcharge to cents", installments: 3, interestRate: 0.155, amount: 1000.33, wantFinancingCost: 155.05, wantInstallment: 385.13},
+		{name: "single installment", installments: 1, interestRate: 0.1, amount: 999.99, wantFinancingCost: 100, wantInstallment: 1099.99},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := &InstallmentPlan{CardBrand: "visa", Installments: tt.installments, InterestRate: tt.interestRate}
+			if got := plan.FinancingCost(tt.amount); got != tt.wantFinancingCost {
+				t.Errorf("FinancingCost() = %v, want %v", got, tt.wantFinancingCost)
+			}
+			if got := plan.InstallmentAmount(tt.amount); got != tt.wantInstallment {
+				t.Errorf("InstallmentAmount() = %v, want %v", got, tt.wantInstallment)
+			}
+			if got := plan.Selection().FinancingCost(tt.amount); got != tt.wantFinancingCost {
+				t.Errorf("Selection().FinancingCost() = %v, want %v", got, tt.wantFinancingCost)
+			}
+		})
+	}
+}
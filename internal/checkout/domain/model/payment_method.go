@@ -0,0 +1,247 @@
+package model
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// PaymentType represents the kind of payment method selected for a checkout
+type PaymentType string
+
+const (
+	PaymentTypeCardToken    PaymentType = "CARD_TOKEN"
+	PaymentTypeBankTransfer PaymentType = "BANK_TRANSFER"
+	PaymentTypeCashOnPickup PaymentType = "CASH_ON_PICKUP"
+	PaymentTypeWallet       PaymentType = "WALLET"
+)
+
+// ErrSensitivePaymentData is returned when payment details contain card numbers or security codes
+var ErrSensitivePaymentData = errors.New("payment details must not contain card numbers or security codes")
+
+// CardTokenDetails holds a tokenized card. The card number itself never reaches this service.
+type CardTokenDetails struct {
+	Token       string `json:"token"`
+	CardBrand   string `json:"cardBrand"`
+	Issuer      string `json:"issuer,omitempty"`
+	LastFour    string `json:"lastFour"`
+	ExpiryMonth int    `json:"expiryMonth"`
+	ExpiryYear  int    `json:"expiryYear"`
+	HolderName  string `json:"holderName,omitempty"`
+}
+
+// BankTransferDetails holds the data of a bank transfer. Only the masked CBU is kept.
+type BankTransferDetails struct {
+	BankName  string `json:"bankName"`
+	MaskedCBU string `json:"maskedCbu,omitempty"`
+	Alias     string `json:"alias,omitempty"`
+	Reference string `json:"reference,omitempty"`
+}
+
+// CashOnPickupDetails holds the data of a payment made in cash when picking up the order
+type CashOnPickupDetails struct {
+	PickupPoint string `json:"pickupPoint,omitempty"`
+}
+
+// WalletDetails holds a payment token issued by a digital wallet (Mercado Pago, MODO, ...)
+type WalletDetails struct {
+	Provider string `json:"provider"`
+	Token    string `json:"token"`
+}
+
+// PaymentMethod represents payment method details. Exactly one of the variants is set,
+// according to PaymentType.
+type PaymentMethod struct {
+	PaymentType  PaymentType           `json:"paymentType"`
+	Card         *CardTokenDetails     `json:"card,omitempty"`
+	BankTransfer *BankTransferDetails  `json:"bankTransfer,omitempty"`
+	CashOnPickup *CashOnPickupDetails  `json:"cashOnPickup,omitempty"`
+	Wallet       *WalletDetails        `json:"wallet,omitempty"`
+	Installments *InstallmentSelection `json:"installments,omitempty"`
+}
+
+// NewCardTokenPayment creates a card payment method from a token issued by the payment provider
+func NewCardTokenPayment(token, cardBrand, issuer, lastFour string, expiryMonth, expiryYear int, holderName string) (*PaymentMethod, error) {
+	if token == "" {
+		return nil, errors.New("card token is required")
+	}
+	if cardBrand == "" {
+		return nil, errors.New("card brand is required")
+	}
+	if len(lastFour) != 4 || !isDigits(lastFour) {
+		return nil, errors.New("card last four digits must be exactly 4 digits")
+	}
+	if expiryMonth < 1 || expiryMonth > 12 {
+		return nil, errors.New("card expiry month must be between 1 and 12")
+	}
+	if expiryYear < 2000 {
+		return nil, errors.New("card expiry year is invalid")
+	}
+	if isDigits(token) || containsSensitiveData(token, issuer, holderName) {
+		return nil, ErrSensitivePaymentData
+	}
+
+	return &PaymentMethod{
+		PaymentType: PaymentTypeCardToken,
+		Card: &CardTokenDetails{
+			Token:       token,
+			CardBrand:   strings.ToLower(cardBrand),
+			Issuer:      strings.ToLower(issuer),
+			LastFour:    lastFour,
+			ExpiryMonth: expiryMonth,
+			ExpiryYear:  expiryYear,
+			HolderName:  holderName,
+		},
+	}, nil
+}
+
+// NewBankTransferPayment creates a bank transfer payment method. The CBU is masked before being stored.
+func NewBankTransferPayment(bankName, cbu, alias, reference string) (*PaymentMethod, error) {
+	if bankName == "" {
+		return nil, errors.New("bank name is required")
+	}
+	if cbu == "" && alias == "" {
+		return nil, errors.New("either CBU or alias is required")
+	}
+	if cbu != "" && (len(cbu) != 22 || !isDigits(cbu)) {
+		return nil, errors.New("CBU must be exactly 22 digits")
+	}
+	if containsSensitiveData(bankName, alias, reference) {
+		return nil, ErrSensitivePaymentData
+	}
+
+	details := &BankTransferDetails{
+		BankName:  bankName,
+		Alias:     alias,
+		Reference: reference,
+	}
+	if cbu != "" {
+		details.MaskedCBU = maskDigits(cbu, 4)
+	}
+
+	return &PaymentMethod{
+		PaymentType:  PaymentTypeBankTransfer,
+		BankTransfer: details,
+	}, nil
+}
+
+// NewCashOnPickupPayment creates a cash payment method settled when picking up the order
+func NewCashOnPickupPayment(pickupPoint string) (*PaymentMethod, error) {
+	if containsSensitiveData(pickupPoint) {
+		return nil, ErrSensitivePaymentData
+	}
+
+	return &PaymentMethod{
+		PaymentType:  PaymentTypeCashOnPickup,
+		CashOnPickup: &CashOnPickupDetails{PickupPoint: pickupPoint},
+	}, nil
+}
+
+// NewWalletPayment creates a digital wallet payment method
+func NewWalletPayment(provider, token string) (*PaymentMethod, error) {
+	if provider == "" {
+		return nil, errors.New("wallet provider is required")
+	}
+	if token == "" {
+		return nil, errors.New("wallet token is required")
+	}
+	if containsSensitiveData(provider, token) {
+		return nil, ErrSensitivePaymentData
+	}
+
+	return &PaymentMethod{
+		PaymentType: PaymentTypeWallet,
+		Wallet: &WalletDetails{
+			Provider: strings.ToLower(provider),
+			Token:    token,
+		},
+	}, nil
+}
+
+// IsCard returns true if the payment method is a tokenized card
+func (p *PaymentMethod) IsCard() bool {
+	return p.PaymentType == PaymentTypeCardToken && p.Card != nil
+}
+
+// securityCodePattern matches a card security code written next to its label, e.g. "CVV 123",
+// "cvc: 1234" or "código de seguridad 123". Bare 3 or 4 digit numbers are not rejected, since
+// references and pickup points often contain them.
+var securityCodePattern = regexp.MustCompile(
+	`(?i)\b(cvv2?|cvc2?|cvn|cid|csc|security code|c(o|ó)digo de seguridad|cod\.? ?seg\.?)["']?\s*[:=#-]?\s*["']?[0-9]{3,4}\b`,
+)
+
+// containsSensitiveData returns true if any of the values looks like a card number (PAN) or
+// holds a card security code
+func containsSensitiveData(values ...string) bool {
+	for _, value := range values {
+		if containsPAN(value) || securityCodePattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsPAN looks for a run of 13 to 19 digits (spaces and dashes allowed in between)
+// that passes the Luhn checksum
+func containsPAN(value string) bool {
+	digits := make([]byte, 0, len(value))
+	flush := func() bool {
+		found := len(digits) >= 13 && len(digits) <= 19 && luhnValid(digits)
+		digits = digits[:0]
+		return found
+	}
+
+	for _, r := range value {
+		switch {
+		case unicode.IsDigit(r):
+			digits = append(digits, byte(r))
+		case r == ' ' || r == '-':
+			continue
+		default:
+			if flush() {
+				return true
+			}
+		}
+	}
+	return flush()
+}
+
+// luhnValid checks a sequence of ASCII digits against the Luhn checksum
+func luhnValid(digits []byte) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// isDigits returns true if the value is a non-empty string of ASCII digits
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// maskDigits replaces every character but the last visible ones with asterisks
+func maskDigits(value string, visible int) string {
+	if len(value) <= visible {
+		return value
+	}
+	return strings.Repeat("*", len(value)-visible) + value[len(value)-visible:]
+}
@@ -0,0 +1,138 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestContainsSensitiveData(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  bool
+	}{
+		{name: "empty value", value: "", want: false},
+		{name: "plain reference", value: "Order 1234 for Ana", want: false},
+		{name: "visa number", value: "4111111111111111", want: true},
+		{name: "visa number with spaces", value: "4111 1111 1111 1111", want: true},
+		{name: "mastercard number with dashes", value: "5555-5555-5555-4444", want: true},
+		{name: "amex number inside text", value: "pay with 378282246310005 please", want: true},
+		{name: "thirteen digit number", value: "4222222222222", want: true},
+		{name: "card-like number failing luhn", value: "4111111111111112", want: false},
+		{name: "twelve digits passing luhn", value: "000000000000", want: false},
+		{name: "twenty digits passing luhn", value: "00000000000000000000", want: false},
+		{name: "cbu length number", value: "2850590940090418135201", want: false},
+		{name: "labeled cvv", value: "CVV 123", want: true},
+		{name: "labeled cvc with colon", value: "cvc: 1234", want: true},
+		{name: "labeled security code in spanish", value: "código de seguridad 321", want: true},
+		{name: "abbreviated security code", value: "cod. seg. 456", want: true},
+		{name: "quoted cvv2", value: `cvv2="789"`, want: true},
+		{name: "bare three digits", value: "Local 123", want: false},
+		{name: "label without code", value: "no CVV needed", want: false},
+		{name: "label with too many digits", value: "cvv 12345", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := containsSensitiveData(tt.value); got != tt.want {
+				t.Errorf("containsSensitiveData(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLuhnValid(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   bool
+	}{
+		{digits: "79927398713", want: true},
+		{digits: "79927398710", want: false},
+		{digits: "4111111111111111", want: true},
+		{digits: "4012888888881881", want: true},
+		{digits: "6011111111111117", want: true},
+		{digits: "6011111111111118", want: false},
+		{digits: "0", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.digits, func(t *testing.T) {
+			if got := luhnValid([]byte(tt.digits)); got != tt.want {
+				t.Errorf("luhnValid(%q) = %v, want %v", tt.digits, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCardTokenPayment(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		lastFour   string
+		issuer     string
+		holderName string
+		wantErr    string
+	}{
+		{name: "valid token", token: "tok_1N3x9", lastFour: "4242", issuer: "Galicia", holderName: "Ana Gómez"},
+		{name: "missing token", lastFour: "4242", wantErr: "card token is required"},
+		{name: "short last four", token: "tok_1N3x9", lastFour: "424", wantErr: "card last four digits must be exactly 4 digits"},
+		{name: "non numeric last four", token: "tok_1N3x9", lastFour: "42a2", wantErr: "card last four digits must be exactly 4 digits"},
+		{name: "card number as token", token: "4111111111111111", lastFour: "1111", wantErr: ErrSensitivePaymentData.Error()},
+		{name: "numeric token", token: "123456", lastFour: "4242", wantErr: ErrSensitivePaymentData.Error()},
+		{name: "card number in holder name", token: "tok_1N3x9", lastFour: "1111", holderName: "Ana 4111 1111 1111 1111", wantErr: ErrSensitivePaymentData.Error()},
+		{name: "security code in issuer", token: "tok_1N3x9", lastFour: "4242", issuer: "Galicia CVV 123", wantErr: ErrSensitivePaymentData.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payment, err := NewCardTokenPayment(tt.token, "Visa", tt.issuer, tt.lastFour, 12, 2030, tt.holderName)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("NewCardTokenPayment() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewCardTokenPayment() error = %v", err)
+			}
+			if !payment.IsCard() || payment.Card.CardBrand != "visa" {
+				t.Errorf("NewCardTokenPayment() = %+v, want a visa card", payment.Card)
+			}
+		})
+	}
+}
+
+func TestNewBankTransferPayment(t *testing.T) {
+	tests := []struct {
+		name          string
+		cbu           string
+		alias         string
+		reference     string
+		wantErr       string
+		wantMaskedCBU string
+	}{
+		{name: "cbu is masked", cbu: "2850590940090418135201", wantMaskedCBU: "******************5201"},
+		{name: "alias only", alias: "ana.gomez.mp"},
+		{name: "neither cbu nor alias", wantErr: "either CBU or alias is required"},
+		{name: "short cbu", cbu: "285059094009", wantErr: "CBU must be exactly 22 digits"},
+		{name: "card number in reference", alias: "ana.gomez.mp", reference: "5555555555554444", wantErr: ErrSensitivePaymentData.Error()},
+		{name: "security code in reference", alias: "ana.gomez.mp", reference: "cvc 999", wantErr: ErrSensitivePaymentData.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payment, err := NewBankTransferPayment("Banco Nación", tt.cbu, tt.alias, tt.reference)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("NewBankTransferPayment() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewBankTransferPayment() error = %v", err)
+			}
+			if payment.BankTransfer.MaskedCBU != tt.wantMaskedCBU {
+				t.Errorf("MaskedCBU = %q, want %q", payment.BankTransfer.MaskedCBU, tt.wantMaskedCBU)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package model
+
+import (
+	"strings"
+)
+
+// Province is one of the 24 jurisdictions of Argentina: the 23 provinces and the
+// Autonomous City of Buenos Aires
+type Province struct {
+	// Code is the ISO 3166-2:AR code of the province, e.g. AR-B
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// Letter returns the letter identifying the province, which is also the first character
+// of its CPA postal codes
+func (p *Province) Letter() byte {
+	return p.Code[len(p.Code)-1]
+}
+
+// CABA is the ISO 3166-2:AR code of the Autonomous City of Buenos Aires
+const CABA = "AR-C"
+
+// provinces is the official list of provinces with their ISO 3166-2:AR codes
+var provinces = []*Province{
+	{Code: "AR-C", Name: "Ciudad Autónoma de Buenos Aires"},
+	{Code: "AR-B", Name: "Buenos Aires"},
+	{Code: "AR-K", Name: "Catamarca"},
+	{Code: "AR-H", Name: "Chaco"},
+	{Code: "AR-U", Name: "Chubut"},
+	{Code: "AR-X", Name: "Córdoba"},
+	{Code: "AR-W", Name: "Corrientes"},
+	{Code: "AR-E", Name: "Entre Ríos"},
+	{Code: "AR-P", Name: "Formosa"},
+	{Code: "AR-Y", Name: "Jujuy"},
+	{Code: "AR-L", Name: "La Pampa"},
+	{Code: "AR-F", Name: "La Rioja"},
+	{Code: "AR-M", Name: "Mendoza"},
+	{Code: "AR-N", Name: "Misiones"},
+	{Code: "AR-Q", Name: "Neuquén"},
+	{Code: "AR-R", Name: "Río Negro"},
+	{Code: "AR-A", Name: "Salta"},
+	{Code: "AR-J", Name: "San Juan"},
+	{Code: "AR-D", Name: "San Luis"},
+	{Code: "AR-Z", Name: "Santa Cruz"},
+	{Code: "AR-S", Name: "Santa Fe"},
+	{Code: "AR-G", Name: "Santiago del Estero"},
+	{Code: "AR-V", Name: "Tierra del Fuego, Antártida e Islas del Atlántico Sur"},
+	{Code: "AR-T", Name: "Tucumán"},
+}
+
+// provinceAliases maps the usual ways of writing a province, folded, to its code. The
+// folded names and codes of every province are matched too.
+var provinceAliases = map[string]string{
+	"caba":                          "AR-C",
+	"capital":                       "AR-C",
+	"capital federal":               "AR-C",
+	"cap fed":                       "AR-C",
+	"cf":                            "AR-C",
+	"ciudad de buenos aires":        "AR-C",
+	"ciudad autonoma":               "AR-C",
+	"cdad de buenos aires":          "AR-C",
+	"cdad autonoma de buenos aires": "AR-C",
+	"bs as":                         "AR-B",
+	"bsas":                          "AR-B",
+	"bs aires":                      "AR-B",
+	"pba":                           "AR-B",
+	"gba":                           "AR-B",
+	"cba":                           "AR-X",
+	"sta fe":                        "AR-S",
+	"mza":                           "AR-M",
+	"tuc":                           "AR-T",
+	"sgo del estero":                "AR-G",
+	"stgo del estero":               "AR-G",
+	"tierra del fuego":              "AR-V",
+	"tdf":                           "AR-V",
+	"sta cruz":                      "AR-Z",
+	"nqn":                           "AR-Q",
+}
+
+// ambiguousBuenosAires lists the folded names that people use both for the province and the
+// city of Buenos Aires. The postal code tells them apart.
+var ambiguousBuenosAires = map[string]bool{
+	"buenos aires": true,
+	"bs as":        true,
+	"bsas":         true,
+	"bs aires":     true,
+}
+
+// provincesByKey indexes the provinces by folded code, name and alias
+var provincesByKey = indexProvinces()
+
+// indexProvinces builds the province lookup table
+func indexProvinces() map[string]*Province {
+	index := make(map[string]*Province)
+	byCode := make(map[string]*Province)
+	for _, province := range provinces {
+		byCode[province.Code] = province
+		index[FoldName(province.Code)] = province
+		index[FoldName(province.Name)] = province
+	}
+	for alias, code := range provinceAliases {
+		index[alias] = byCode[code]
+	}
+	return index
+}
+
+// Provinces returns the official list of provinces
+func Provinces() []*Province {
+	return provinces
+}
+
+// FindProvince returns the province written as name, accepting its ISO 3166-2:AR code, its
+// official name with or without accents and the usual abbreviations (e.g. "CABA", "Capital",
+// "bs as", "Pcia. de Córdoba")
+func FindProvince(name string) (*Province, bool) {
+	province, ok := provincesByKey[provinceKey(name)]
+	return province, ok
+}
+
+// provinceKey folds a province name and strips the "provincia de" prefix
+func provinceKey(name string) string {
+	key := FoldName(name)
+	for _, prefix := range []string{"provincia de ", "provincia del ", "pcia de ", "prov de "} {
+		if strings.HasPrefix(key, prefix) {
+			return strings.TrimPrefix(key, prefix)
+		}
+	}
+	return key
+}
+
+// accentFolder replaces the accented letters used in Spanish with their plain versions
+var accentFolder = strings.NewReplacer(
+	"á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n",
+	"Á", "a", "É", "e", "Í", "i", "Ó", "o", "Ú", "u", "Ü", "u", "Ñ", "n",
+)
+
+// FoldName lowercases a name to match it however people type it, removing accents and dots
+// and collapsing any other punctuation into single spaces, so "Bs. As." and "bs as", or
+// "C.A.B.A." and "caba", match
+func FoldName(name string) string {
+	name = strings.ToLower(accentFolder.Replace(name))
+
+	var folded strings.Builder
+	for _, char := range name {
+		switch {
+		case char >= 'a' && char <= 'z', char >= '0' && char <= '9', char == '-':
+			folded.WriteRune(char)
+		case char != '.':
+			folded.WriteRune(' ')
+		}
+	}
+
+	return strings.Join(strings.Fields(folded.String()), " ")
+}
@@ -0,0 +1,169 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReceiptFormat represents the format a receipt is rendered in
+type ReceiptFormat string
+
+const (
+	// ReceiptFormatPDF receipts are printed or kept by the shopper
+	ReceiptFormatPDF ReceiptFormat = "pdf"
+	// ReceiptFormatESCPOS receipts are sent to the thermal printer of the kiosk
+	ReceiptFormatESCPOS ReceiptFormat = "escpos"
+)
+
+// Receipt represents the receipt of a completed checkout, ready to be rendered
+type Receipt struct {
+	OrderCode      string
+	CheckoutID     uuid.UUID
+	Refunded       bool
+	IssuedAt       time.Time
+	Lines          []*ReceiptLine
+	Subtotal       float64
+	Discounts      []*DiscountLine
+	ShippingMethod string
+	ShippingCost   float64
+	TaxBase        float64
+	TaxRate        float64
+	Tax            float64
+	FinancingCost  float64
+	Total          float64
+	Payments       []*ReceiptPayment
+	ShipTo         []string
+}
+
+// ReceiptLine represents an item line of a receipt. Details hold the variant and options.
+type ReceiptLine struct {
+	Name      string
+	Details   []string
+	Quantity  int
+	UnitPrice float64
+	Subtotal  float64
+}
+
+// ReceiptPayment represents one of the tenders that paid a checkout
+type ReceiptPayment struct {
+	Label  string
+	Amount float64
+}
+
+// NewReceipt builds the receipt of a completed checkout. The address and shipping method
+// are optional; they are left out of the receipt when nil.
+func NewReceipt(checkout *Checkout, address *ShippingAddress, method *ShippingMethod) (*Receipt, error) {
+	if !checkout.IsCompleted() && !checkout.IsRefunded() {
+		return nil, errors.New("receipts are only available for completed checkouts")
+	}
+
+	receipt := &Receipt{
+		OrderCode:     OrderCode(checkout.ID),
+		CheckoutID:    checkout.ID,
+		Refunded:      checkout.IsRefunded(),
+		IssuedAt:      checkout.UpdatedAt,
+		Lines:         make([]*ReceiptLine, len(checkout.Items)),
+		Subtotal:      roundToCents(checkout.Subtotal),
+		Discounts:     checkout.Discounts,
+		ShippingCost:  roundToCents(checkout.ShippingCost),
+		TaxBase:       roundToCents(checkout.Subtotal - checkout.DiscountTotal() + checkout.ShippingCost),
+		TaxRate:       checkout.TaxRate,
+		Tax:           roundToCents(checkout.Tax),
+		FinancingCost: roundToCents(checkout.FinancingCost),
+		Total:         roundToCents(checkout.Total),
+		Payments:      receiptPayments(checkout),
+	}
+
+	for i, item := range checkout.Items {
+		line := &ReceiptLine{
+			Name:      item.Name,
+			Quantity:  item.Quantity,
+			UnitPrice: item.Price,
+			Subtotal:  item.Subtotal,
+		}
+		if item.VariantID != "" {
+			line.Details = append(line.Details, "Variant: "+item.VariantID)
+		}
+		for _, option := range item.Options {
+			line.Details = append(line.Details, option.Name+": "+option.Value)
+		}
+		receipt.Lines[i] = line
+	}
+
+	if method != nil {
+		receipt.ShippingMethod = method.Name
+	}
+	if address != nil {
+		receipt.ShipTo = addressLines(address)
+	}
+
+	return receipt, nil
+}
+
+// OrderCode returns the short code a checkout is known by at the counter
+func OrderCode(checkoutID uuid.UUID) string {
+	return strings.ToUpper(strings.ReplaceAll(checkoutID.String(), "-", "")[:8])
+}
+
+// receiptPayments lists the gift cards followed by the payment method that paid the rest
+func receiptPayments(checkout *Checkout) []*ReceiptPayment {
+	payments := make([]*ReceiptPayment, 0, len(checkout.GiftCards)+3)
+	for _, tender := range checkout.GiftCards {
+		label := "Gift card " + tender.MaskedCode
+		if tender.Kind == GiftCardKindStoreCredit {
+			label = "Store credit " + tender.MaskedCode
+		}
+		payments = append(payments, &ReceiptPayment{Label: label, Amount: tender.Amount})
+	}
+
+	method := checkout.PaymentMethod
+	if method == nil || checkout.AmountDue() == 0 {
+		return payments
+	}
+
+	payment := &ReceiptPayment{Amount: checkout.AmountDue()}
+	switch {
+	case method.Card != nil:
+		payment.Label = fmt.Sprintf("Card %s **** %s", strings.ToUpper(method.Card.CardBrand), method.Card.LastFour)
+		if method.Installments != nil {
+			payment.Label += fmt.Sprintf(" (%d installments)", method.Installments.Installments)
+		}
+	case method.BankTransfer != nil:
+		payment.Label = "Bank transfer " + method.BankTransfer.BankName
+	case method.Wallet != nil:
+		payment.Label = "Wallet " + method.Wallet.Provider
+	case method.PaymentType == PaymentTypeCashOnPickup:
+		payment.Label = "Cash"
+	default:
+		payment.Label = string(method.PaymentType)
+	}
+	payments = append(payments, payment)
+
+	if cash := checkout.CashPayment; cash != nil && cash.PaidAt != nil {
+		payments = append(payments,
+			&ReceiptPayment{Label: "Cash received", Amount: cash.Received},
+			&ReceiptPayment{Label: "Change", Amount: cash.Change},
+		)
+	}
+
+	return payments
+}
+
+// addressLines formats a shipping address as it is printed on a receipt
+func addressLines(address *ShippingAddress) []string {
+	lines := []string{strings.TrimSpace(address.FirstName + " " + address.LastName)}
+
+	street := address.StreetAddress
+	if address.Apartment != "" {
+		street += ", " + address.Apartment
+	}
+	lines = append(lines, street)
+	lines = append(lines, strings.TrimSpace(address.PostalCode+" "+address.City))
+	lines = append(lines, strings.TrimSpace(address.ProvinceName()+", "+address.Country))
+
+	return lines
+}
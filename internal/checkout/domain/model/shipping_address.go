@@ -9,61 +9,35 @@ import (
 
 // ShippingAddress represents a shipping address entity in the Checkout Process bounded context
 type ShippingAddress struct {
-	ID            uuid.UUID `json:"id"`
-	UserID        uuid.UUID `json:"userId"`
-	FirstName     string    `json:"firstName"`
-	LastName      string    `json:"lastName"`
-	StreetAddress string    `json:"streetAddress"`
-	Apartment     string    `json:"apartment"`
-	City          string    `json:"city"`
-	State         string    `json:"state"`
-	PostalCode    string    `json:"postalCode"`
-	Country       string    `json:"country"`
-	PhoneNumber   string    `json:"phoneNumber"`
-	IsDefault     bool      `json:"isDefault"`
-	CreatedAt     time.Time `json:"createdAt"`
-	UpdatedAt     time.Time `json:"updatedAt"`
+	ID            uuid.UUID    `json:"id"`
+	UserID        uuid.UUID    `json:"userId"`
+	FirstName     string       `json:"firstName"`
+	LastName      string       `json:"lastName"`
+	StreetAddress string       `json:"streetAddress"`
+	Apartment     string       `json:"apartment"`
+	City          string       `json:"city"`
+	State         string       `json:"state"`
+	PostalCode    string       `json:"postalCode"`
+	Country       string       `json:"country"`
+	PhoneNumber   string       `json:"phoneNumber"`
+	Location      *Coordinates `json:"location,omitempty"`
+	IsDefault     bool         `json:"isDefault"`
+	CreatedAt     time.Time    `json:"createdAt"`
+	UpdatedAt     time.Time    `json:"updatedAt"`
 }
 
-// NewShippingAddress creates a new shipping address
+// NewShippingAddress creates a new shipping address, validating and normalizing its fields.
+// An address with invalid fields returns an *AddressValidationError.
 func NewShippingAddress(
 	userID uuid.UUID,
 	firstName, lastName, streetAddress, apartment, city, state, postalCode, country, phoneNumber string,
 	isDefault bool,
 ) (*ShippingAddress, error) {
-	// Validate required fields
 	if userID == uuid.Nil {
 		return nil, errors.New("user ID is required")
 	}
-	if firstName == "" {
-		return nil, errors.New("first name is required")
-	}
-	if lastName == "" {
-		return nil, errors.New("last name is required")
-	}
-	if streetAddress == "" {
-		return nil, errors.New("street address is required")
-	}
-	if city == "" {
-		return nil, errors.New("city is required")
-	}
-	if state == "" {
-		return nil, errors.New("state is required")
-	}
-	if postalCode == "" {
-		return nil, errors.New("postal code is required")
-	}
-	if country == "" {
-		return nil, errors.New("country is required")
-	}
-	if phoneNumber == "" {
-		return nil, errors.New("phone number is required")
-	}
 
-	now := time.Now()
-	return &ShippingAddress{
-		ID:            uuid.New(),
-		UserID:        userID,
+	validation := ValidateAddress(AddressFields{
 		FirstName:     firstName,
 		LastName:      lastName,
 		StreetAddress: streetAddress,
@@ -73,52 +47,62 @@ func NewShippingAddress(
 		PostalCode:    postalCode,
 		Country:       country,
 		PhoneNumber:   phoneNumber,
+	})
+	if err := validation.Err(); err != nil {
+		return nil, err
+	}
+	fields := validation.Address
+
+	now := time.Now()
+	return &ShippingAddress{
+		ID:            uuid.New(),
+		UserID:        userID,
+		FirstName:     fields.FirstName,
+		LastName:      fields.LastName,
+		StreetAddress: fields.StreetAddress,
+		Apartment:     fields.Apartment,
+		City:          fields.City,
+		State:         fields.State,
+		PostalCode:    fields.PostalCode,
+		Country:       fields.Country,
+		PhoneNumber:   fields.PhoneNumber,
 		IsDefault:     isDefault,
 		CreatedAt:     now,
 		UpdatedAt:     now,
 	}, nil
 }
 
-// Update updates the shipping address details
+// Update updates the shipping address details, validating and normalizing them as
+// NewShippingAddress does
 func (a *ShippingAddress) Update(
 	firstName, lastName, streetAddress, apartment, city, state, postalCode, country, phoneNumber string,
 	isDefault bool,
 ) error {
-	// Validate required fields
-	if firstName == "" {
-		return errors.New("first name is required")
-	}
-	if lastName == "" {
-		return errors.New("last name is required")
-	}
-	if streetAddress == "" {
-		return errors.New("street address is required")
-	}
-	if city == "" {
-		return errors.New("city is required")
-	}
-	if state == "" {
-		return errors.New("state is required")
-	}
-	if postalCode == "" {
-		return errors.New("postal code is required")
-	}
-	if country == "" {
-		return errors.New("country is required")
-	}
-	if phoneNumber == "" {
-		return errors.New("phone number is required")
+	validation := ValidateAddress(AddressFields{
+		FirstName:     firstName,
+		LastName:      lastName,
+		StreetAddress: streetAddress,
+		Apartment:     apartment,
+		City:          city,
+		State:         state,
+		PostalCode:    postalCode,
+		Country:       country,
+		PhoneNumber:   phoneNumber,
+	})
+	if err := validation.Err(); err != nil {
+		return err
 	}
+	fields := validation.Address
 
-	a.FirstName = firstName
-	a.LastName = lastName
-	a.StreetAddress = streetAddress
-	a.Apartment = apartment
-	a.City = city
-	a.State = state
-	a.PostalCode = postalCode
-	a.Country = country
-	a.PhoneNumber = phoneNumber
+	a.FirstName = fields.FirstName
+	a.LastName = fields.LastName
+	a.StreetAddress = fields.StreetAddress
+	a.Apartment = fields.Apartment
+	a.City = fields.City
+	a.State = fields.State
+	a.PostalCode = fields.PostalCode
+	a.Country = fields.Country
+	a.PhoneNumber = fields.PhoneNumber
 	a.IsDefault = isDefault
 	a.UpdatedAt = time.Now()
 
@@ -136,5 +120,14 @@ func (a *ShippingAddress) FormattedAddress() string {
 	if a.Apartment != "" {
 		apartment = ", " + a.Apartment
 	}
-	return a.StreetAddress + apartment + ", " + a.City + ", " + a.State + " " + a.PostalCode + ", " + a.Country
+	return a.StreetAddress + apartment + ", " + a.City + ", " + a.ProvinceName() + " " + a.PostalCode + ", " + a.Country
+}
+
+// ProvinceName returns the name of the province of the address. Addresses saved before
+// provinces were normalized keep the state as it was written.
+func (a *ShippingAddress) ProvinceName() string {
+	if province, ok := FindProvince(a.State); ok {
+		return province.Name
+	}
+	return a.State
 }
@@ -0,0 +1,169 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// earthRadiusKm is the mean radius of the Earth, used to measure distances between coordinates
+const earthRadiusKm = 6371.0
+
+// Coordinates is a point on the map in decimal degrees (WGS 84)
+type Coordinates struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// NewCoordinates creates a point, checking the latitude and longitude are in range
+func NewCoordinates(latitude, longitude float64) (*Coordinates, error) {
+	if latitude < -90 || latitude > 90 {
+		return nil, errors.New("latitude must be between -90 and 90")
+	}
+	if longitude < -180 || longitude > 180 {
+		return nil, errors.New("longitude must be between -180 and 180")
+	}
+	return &Coordinates{Latitude: latitude, Longitude: longitude}, nil
+}
+
+// DistanceTo returns the great-circle distance to another point, in kilometers
+func (c *Coordinates) DistanceTo(other *Coordinates) float64 {
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+
+	deltaLatitude := toRadians(other.Latitude - c.Latitude)
+	deltaLongitude := toRadians(other.Longitude - c.Longitude)
+	a := math.Sin(deltaLatitude/2)*math.Sin(deltaLatitude/2) +
+		math.Cos(toRadians(c.Latitude))*math.Cos(toRadians(other.Latitude))*
+			math.Sin(deltaLongitude/2)*math.Sin(deltaLongitude/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// StreetSegment is a stretch of a street between two door numbers, as listed in the street
+// dataset addresses are geocoded with. The coordinates of a door number are interpolated
+// between the ends of its segment.
+type StreetSegment struct {
+	ID         uuid.UUID    `json:"id"`
+	Street     string       `json:"street"`
+	FromNumber int          `json:"fromNumber"`
+	ToNumber   int          `json:"toNumber"`
+	City       string       `json:"city"`
+	State      string       `json:"state"`
+	PostalCode string       `json:"postalCode"`
+	From       *Coordinates `json:"from"`
+	To         *Coordinates `json:"to"`
+}
+
+// NewStreetSegment creates a street segment. The province is normalized to its ISO 3166-2:AR code.
+func NewStreetSegment(street string, fromNumber, toNumber int, city, state, postalCode string, from, to *Coordinates) (*StreetSegment, error) {
+	street = strings.TrimSpace(street)
+	if StreetSearchName(street) == "" {
+		return nil, errors.New("street is required")
+	}
+	if fromNumber < 0 || toNumber < fromNumber {
+		return nil, errors.New("door numbers must be a non-negative range")
+	}
+	city = strings.TrimSpace(city)
+	if city == "" {
+		return nil, errors.New("city is required")
+	}
+	province, ok := FindProvince(state)
+	if !ok {
+		return nil, errors.New("unknown province")
+	}
+	if from == nil || to == nil {
+		return nil, errors.New("coordinates are required")
+	}
+
+	return &StreetSegment{
+		ID:         uuid.New(),
+		Street:     street,
+		FromNumber: fromNumber,
+		ToNumber:   toNumber,
+		City:       city,
+		State:      province.Code,
+		PostalCode: normalizePostalCode(postalCode),
+		From:       from,
+		To:         to,
+	}, nil
+}
+
+// Contains returns whether a door number is in the segment
+func (s *StreetSegment) Contains(number int) bool {
+	return number >= s.FromNumber && number <= s.ToNumber
+}
+
+// Locate returns the coordinates of a door number of the segment, interpolated between its ends
+func (s *StreetSegment) Locate(number int) *Coordinates {
+	fraction := 0.0
+	if s.ToNumber > s.FromNumber {
+		fraction = float64(number-s.FromNumber) / float64(s.ToNumber-s.FromNumber)
+	}
+	fraction = math.Max(0, math.Min(1, fraction))
+
+	return &Coordinates{
+		Latitude:  s.From.Latitude + fraction*(s.To.Latitude-s.From.Latitude),
+		Longitude: s.From.Longitude + fraction*(s.To.Longitude-s.From.Longitude),
+	}
+}
+
+// AddressCandidate is an address suggested while a shopper types one
+type AddressCandidate struct {
+	// StreetAddress is the street and, when it was typed, the door number
+	StreetAddress string `json:"streetAddress"`
+	City          string `json:"city"`
+	State         string `json:"state"`
+	PostalCode    string `json:"postalCode"`
+	// Location is only set when the door number is known
+	Location *Coordinates `json:"location,omitempty"`
+}
+
+// streetTypes lists the folded words that name a kind of street rather than a street, so
+// "Av. Corrientes", "Avenida Corrientes" and "Corrientes" match
+var streetTypes = map[string]bool{
+	"av":        true,
+	"avda":      true,
+	"avenida":   true,
+	"calle":     true,
+	"pasaje":    true,
+	"pje":       true,
+	"boulevard": true,
+	"bulevar":   true,
+	"bv":        true,
+	"bvd":       true,
+	"diagonal":  true,
+	"diag":      true,
+	"autopista": true,
+}
+
+// StreetSearchName folds a street name for searching, removing accents, punctuation and the
+// words naming the kind of street
+func StreetSearchName(street string) string {
+	words := strings.Fields(FoldName(street))
+
+	name := make([]string, 0, len(words))
+	for _, word := range words {
+		if !streetTypes[word] {
+			name = append(name, word)
+		}
+	}
+	return strings.Join(name, " ")
+}
+
+// ParseStreetAddress splits a street address into the street and its door number, e.g.
+// "Av. Paseo Colón 850" into "Av. Paseo Colón" and 850. The number is zero when there is none.
+func ParseStreetAddress(streetAddress string) (string, int) {
+	words := strings.Fields(streetAddress)
+	if len(words) < 2 {
+		return strings.TrimSpace(streetAddress), 0
+	}
+
+	number, err := strconv.Atoi(words[len(words)-1])
+	if err != nil || number <= 0 {
+		return strings.TrimSpace(streetAddress), 0
+	}
+	return strings.Join(words[:len(words)-1], " "), number
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
@@ -18,6 +19,23 @@ type CheckoutRepository interface {
 	// FindByUserID retrieves the latest checkout for a user
 	FindByUserID(ctx context.Context, userID uuid.UUID) ([]*model.Checkout, error)
 
-	// Save persists a checkout (creates or updates)
+	// FindByPaymentCode retrieves the checkout awaiting payment at the counter with a payment code
+	FindByPaymentCode(ctx context.Context, code string) (*model.Checkout, error)
+
+	// FindOverduePayments retrieves the checkouts awaiting payment whose deadline passed before the given time
+	FindOverduePayments(ctx context.Context, before time.Time) ([]*model.Checkout, error)
+
+	// Save persists a checkout (creates or updates). It fails with "payment code already in
+	// use" if another checkout awaiting payment has the same payment code.
 	Save(ctx context.Context, checkout *model.Checkout) error
+
+	// SaveStatusTransition stores the status and cash payment of a checkout, as long as the
+	// stored checkout still has the from status. It fails with "checkout was updated by another
+	// request" otherwise, and with "payment code already in use" like Save.
+	SaveStatusTransition(ctx context.Context, checkout *model.Checkout, from model.CheckoutStatus) error
+
+	// SaveCashPaymentOutcome stores the status and cash payment of a checkout that was paid at
+	// the counter or cancelled for not being paid, as long as it is still awaiting payment.
+	// It fails with "checkout is not awaiting payment" otherwise.
+	SaveCashPaymentOutcome(ctx context.Context, checkout *model.Checkout) error
 }
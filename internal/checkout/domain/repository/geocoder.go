@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
+)
+
+// Geocoder defines the interface to complete addresses as they are typed and to find where
+// they are on the map
+type Geocoder interface {
+	// Autocomplete returns up to limit addresses matching the text typed so far, e.g.
+	// "corrientes 12" or "paseo colon 850, caba", best matches first
+	Autocomplete(ctx context.Context, text string, limit int) ([]*model.AddressCandidate, error)
+
+	// Geocode returns the coordinates of a shipping address, or nil if it cannot be located
+	Geocode(ctx context.Context, address *model.ShippingAddress) (*model.Coordinates, error)
+}
+
+// StreetQuery filters the street dataset
+type StreetQuery struct {
+	// Name is the street name folded with model.StreetSearchName. Streets match when a word of
+	// their name starts with it.
+	Name string
+	// Number, when not zero, only matches the segments that include the door number. When it
+	// is zero, only the first segment of every street is returned.
+	Number int
+	// State, when set, only matches the streets of the province with that ISO 3166-2:AR code
+	State string
+	// City, when set, only matches the streets of the cities whose folded name starts with it
+	City string
+	// Limit is the largest number of segments returned
+	Limit int
+}
+
+// StreetRepository defines the interface for the street dataset addresses are geocoded with
+type StreetRepository interface {
+	// Search retrieves the street segments matching a query, the streets whose name starts
+	// with the query first and then the shortest names
+	Search(ctx context.Context, query *StreetQuery) ([]*model.StreetSegment, error)
+
+	// ReplaceAll replaces the whole dataset with the given segments in a single transaction
+	ReplaceAll(ctx context.Context, segments []*model.StreetSegment) error
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
+)
+
+// GiftCardRepository defines the interface for the gift card and store credit ledger
+type GiftCardRepository interface {
+	// FindByID retrieves a gift card by its ID
+	FindByID(ctx context.Context, id uuid.UUID) (*model.GiftCard, error)
+
+	// FindByCode retrieves a gift card by its code
+	FindByCode(ctx context.Context, code string) (*model.GiftCard, error)
+
+	// FindTransactions retrieves the ledger entries of a gift card, oldest first
+	FindTransactions(ctx context.Context, giftCardID uuid.UUID) ([]*model.GiftCardTransaction, error)
+
+	// Create persists a newly issued gift card together with its ISSUE ledger entry
+	Create(ctx context.Context, giftCard *model.GiftCard, issue *model.GiftCardTransaction) error
+
+	// ApplyTransactions atomically applies the ledger entries to the gift card balances.
+	// Either every entry is applied or none is, and no balance is allowed to go negative.
+	ApplyTransactions(ctx context.Context, transactions []*model.GiftCardTransaction) error
+
+	// RefundCheckout credits back to every gift card what the checkout still owes it: its debits
+	// minus the refunds already made. Refunding a checkout again credits nothing more, so it is
+	// safe to retry.
+	RefundCheckout(ctx context.Context, checkoutID uuid.UUID) ([]*model.GiftCardTransaction, error)
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
+)
+
+// InstallmentPlanRepository defines the interface for installment plan catalog operations
+type InstallmentPlanRepository interface {
+	// FindByID retrieves an installment plan by its ID
+	FindByID(ctx context.Context, id uuid.UUID) (*model.InstallmentPlan, error)
+
+	// FindByCard retrieves the installment plans offered for a card brand and issuer
+	FindByCard(ctx context.Context, cardBrand, issuer string) ([]*model.InstallmentPlan, error)
+
+	// FindAll retrieves every active installment plan of the catalog
+	FindAll(ctx context.Context) ([]*model.InstallmentPlan, error)
+
+	// Save persists an installment plan (creates or updates)
+	Save(ctx context.Context, plan *model.InstallmentPlan) error
+
+	// Deactivate removes an installment plan from the catalog. Checkouts that already selected
+	// it keep their selection.
+	Deactivate(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
+)
+
+// ReceiptRenderer defines the interface to render a receipt in one format
+type ReceiptRenderer interface {
+	// Render returns the receipt document
+	Render(receipt *model.Receipt) ([]byte, error)
+
+	// ContentType returns the media type of the rendered documents
+	ContentType() string
+}
@@ -0,0 +1,112 @@
+package geocoding
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/repository"
+)
+
+const (
+	// minQueryLength is the shortest street name autocompleted, so a couple of letters do not
+	// match half the dataset
+	minQueryLength = 3
+	// geocodeCandidates is how many segments with the street name and door number are looked
+	// at to find the one in the city of the address
+	geocodeCandidates = 20
+)
+
+// StreetGeocoder implements the Geocoder interface with the local street dataset. Door numbers
+// are located by interpolating between the ends of the segment of the street they are in.
+type StreetGeocoder struct {
+	streetRepository repository.StreetRepository
+}
+
+// NewStreetGeocoder creates a new geocoder backed by the street dataset
+func NewStreetGeocoder(streetRepository repository.StreetRepository) repository.Geocoder {
+	return &StreetGeocoder{
+		streetRepository: streetRepository,
+	}
+}
+
+// Autocomplete returns the addresses matching the text typed so far. The text is a street
+// name, optionally followed by a door number and, after commas, the city or province.
+func (g *StreetGeocoder) Autocomplete(ctx context.Context, text string, limit int) ([]*model.AddressCandidate, error) {
+	parts := strings.Split(text, ",")
+	street, number := model.ParseStreetAddress(parts[0])
+
+	query := &repository.StreetQuery{
+		Name:   model.StreetSearchName(street),
+		Number: number,
+		Limit:  limit,
+	}
+	if len(query.Name) < minQueryLength {
+		return []*model.AddressCandidate{}, nil
+	}
+	for _, locality := range parts[1:] {
+		if strings.TrimSpace(locality) == "" {
+			continue
+		}
+		if province, ok := model.FindProvince(locality); ok {
+			query.State = province.Code
+		} else {
+			query.City = locality
+		}
+	}
+
+	segments, err := g.streetRepository.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]*model.AddressCandidate, len(segments))
+	for i, segment := range segments {
+		candidates[i] = &model.AddressCandidate{
+			StreetAddress: segment.Street,
+			City:          segment.City,
+			State:         segment.State,
+			PostalCode:    segment.PostalCode,
+		}
+		if number > 0 {
+			candidates[i].StreetAddress += " " + strconv.Itoa(number)
+			candidates[i].Location = segment.Locate(number)
+		}
+	}
+
+	return candidates, nil
+}
+
+// Geocode returns the coordinates of a shipping address. The street must be in the dataset
+// with the door number of the address, in its province and city; any city is accepted in the
+// city of Buenos Aires, which people name in many ways.
+func (g *StreetGeocoder) Geocode(ctx context.Context, address *model.ShippingAddress) (*model.Coordinates, error) {
+	street, number := model.ParseStreetAddress(address.StreetAddress)
+	name := model.StreetSearchName(street)
+	if number == 0 || name == "" {
+		return nil, nil
+	}
+
+	segments, err := g.streetRepository.Search(ctx, &repository.StreetQuery{
+		Name:   name,
+		Number: number,
+		State:  address.State,
+		Limit:  geocodeCandidates,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	city := model.FoldName(address.City)
+	for _, segment := range segments {
+		if model.StreetSearchName(segment.Street) != name {
+			continue
+		}
+		if address.State == model.CABA || model.FoldName(segment.City) == city {
+			return segment.Locate(number), nil
+		}
+	}
+
+	return nil, nil
+}
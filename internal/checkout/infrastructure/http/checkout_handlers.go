@@ -5,20 +5,64 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+	cartDto "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services/dto"
+	cartModel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/app/services"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/app/services/dto"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
 )
 
+// paymentMethodBadRequestErrors lists the payment method errors caused by invalid client input
+var paymentMethodBadRequestErrors = map[string]bool{
+	"cannot update a cancelled checkout":                              true,
+	"cannot update a completed checkout":                              true,
+	"shipping option must be selected before payment":                 true,
+	"installment plan is not available for this payment":              true,
+	"installments are only available for card payments":               true,
+	"invalid installment plan ID format":                              true,
+	"payment type is required":                                        true,
+	"unsupported payment type":                                        true,
+	"card details are required for card payments":                     true,
+	"bank transfer details are required for bank transfer payments":   true,
+	"wallet details are required for wallet payments":                 true,
+	"card token is required":                                          true,
+	"card brand is required":                                          true,
+	"card last four digits must be exactly 4 digits":                  true,
+	"card expiry month must be between 1 and 12":                      true,
+	"card expiry year is invalid":                                     true,
+	"bank name is required":                                           true,
+	"either CBU or alias is required":                                 true,
+	"CBU must be exactly 22 digits":                                   true,
+	"wallet provider is required":                                     true,
+	"wallet token is required":                                        true,
+	"payment details must not contain card numbers or security codes": true,
+}
+
+// cashPaymentBadRequestErrors lists the errors recording a cash payment caused by invalid client input
+var cashPaymentBadRequestErrors = map[string]bool{
+	"amount received must be greater than zero":   true,
+	"amount received is less than the amount due": true,
+}
+
 // CheckoutHandler handles HTTP requests for checkout operations
 type CheckoutHandler struct {
 	checkoutService *services.CheckoutService
+	requireUser     func(http.Handler) http.Handler
+	requireAdmin    func(http.Handler) http.Handler
 }
 
-// NewCheckoutHandler creates a new checkout handler
-func NewCheckoutHandler(checkoutService *services.CheckoutService) *CheckoutHandler {
+// NewCheckoutHandler creates a new checkout handler. Checkouts go through requireUser, so
+// they are only used by their own user or the back office, and refunds and cash payments
+// through requireAdmin, since they are handled from the back office and the counter.
+func NewCheckoutHandler(
+	checkoutService *services.CheckoutService,
+	requireUser func(http.Handler) http.Handler,
+	requireAdmin func(http.Handler) http.Handler,
+) *CheckoutHandler {
 	return &CheckoutHandler{
 		checkoutService: checkoutService,
+		requireUser:     requireUser,
+		requireAdmin:    requireAdmin,
 	}
 }
 
@@ -27,12 +71,28 @@ func (h *CheckoutHandler) RegisterRoutes(router *mux.Router) {
 	// Create a subrouter for checkout routes
 	checkoutRouter := router.PathPrefix("/checkout").Subrouter()
 
-	// Register routes
-	checkoutRouter.HandleFunc("/init", h.InitiateCheckout).Methods("POST")
-	checkoutRouter.HandleFunc("/{checkoutId}", h.GetCheckout).Methods("GET")
-	checkoutRouter.HandleFunc("/{checkoutId}/shipping", h.UpdateShipping).Methods("PUT")
-	checkoutRouter.HandleFunc("/{checkoutId}/payment-method", h.SetPaymentMethod).Methods("PUT")
-	checkoutRouter.HandleFunc("/{checkoutId}/complete", h.CompleteCheckout).Methods("POST")
+	// Refunds and cash payments are only available to the back office and the counter
+	adminRouter := checkoutRouter.NewRoute().Subrouter()
+	adminRouter.Use(h.requireAdmin)
+
+	adminRouter.HandleFunc("/cash-payments/{code}", h.GetCashPayment).Methods("GET")
+	adminRouter.HandleFunc("/cash-payments/{code}", h.RecordCashPayment).Methods("POST")
+	adminRouter.HandleFunc("/{checkoutId}/refund", h.RefundCheckout).Methods("POST")
+
+	// Requests act on behalf of the user named in the X-User-ID header
+	userRouter := checkoutRouter.NewRoute().Subrouter()
+	userRouter.Use(h.requireUser)
+
+	userRouter.HandleFunc("/init", h.InitiateCheckout).Methods("POST")
+	userRouter.HandleFunc("/{checkoutId}", h.GetCheckout).Methods("GET")
+	userRouter.HandleFunc("/{checkoutId}/shipping", h.UpdateShipping).Methods("PUT")
+	userRouter.HandleFunc("/{checkoutId}/installments", h.QuoteInstallments).Methods("GET")
+	userRouter.HandleFunc("/{checkoutId}/payment-method", h.SetPaymentMethod).Methods("PUT")
+	userRouter.HandleFunc("/{checkoutId}/gift-cards", h.ApplyGiftCard).Methods("POST")
+	userRouter.HandleFunc("/{checkoutId}/gift-cards/{giftCardId}", h.RemoveGiftCard).Methods("DELETE")
+	userRouter.HandleFunc("/{checkoutId}/loyalty", h.RedeemLoyaltyPoints).Methods("POST")
+	userRouter.HandleFunc("/{checkoutId}/loyalty", h.RemoveLoyaltyPoints).Methods("DELETE")
+	userRouter.HandleFunc("/{checkoutId}/complete", h.CompleteCheckout).Methods("POST")
 }
 
 // InitiateCheckout handles the request to initialize a checkout
@@ -45,7 +105,19 @@ func (h *CheckoutHandler) InitiateCheckout(w http.ResponseWriter, r *http.Reques
 
 	checkout, err := h.checkoutService.InitiateCheckout(r.Context(), &req)
 	if err != nil {
-		errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		if violations, ok := err.(*cartModel.RuleViolationError); ok {
+			errors.WriteValidationErrorResponse(w, http.StatusUnprocessableEntity, "Purchase rules violated", cartDto.RuleViolationsFromDomain(violations))
+		} else if err.Error() == "cart not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, "Cart not found")
+		} else if err.Error() == "user ID is required" {
+			errors.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+		} else if err.Error() == "cart access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else if err.Error() == "invalid cart ID format" || err.Error() == "cart is empty" {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
 
@@ -63,6 +135,8 @@ func (h *CheckoutHandler) GetCheckout(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		if err.Error() == "checkout not found" {
 			errors.WriteErrorResponse(w, http.StatusNotFound, "Checkout not found")
+		} else if err.Error() == "checkout access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
 		} else {
 			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
 		}
@@ -88,9 +162,11 @@ func (h *CheckoutHandler) UpdateShipping(w http.ResponseWriter, r *http.Request)
 	if err != nil {
 		if err.Error() == "checkout not found" || err.Error() == "shipping address not found" || err.Error() == "shipping method not found" {
 			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if err.Error() == "checkout access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
 		} else if err.Error() == "shipping address does not belong to the user" {
 			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
-		} else if err.Error() == "cannot update a cancelled checkout" {
+		} else if err.Error() == "cannot update a cancelled checkout" || err.Error() == "cannot update a completed checkout" {
 			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
 		} else {
 			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
@@ -108,16 +184,21 @@ func (h *CheckoutHandler) SetPaymentMethod(w http.ResponseWriter, r *http.Reques
 	checkoutID := vars["checkoutId"]
 
 	var req dto.PaymentMethodRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	decoder := json.NewDecoder(r.Body)
+	// Reject unknown fields so raw card data (cardNumber, cvv, ...) never gets silently accepted
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
 		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
 	checkout, err := h.checkoutService.SetPaymentMethod(r.Context(), checkoutID, &req)
 	if err != nil {
-		if err.Error() == "checkout not found" {
+		if err.Error() == "checkout not found" || err.Error() == "installment plan not found" {
 			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
-		} else if err.Error() == "cannot update a cancelled checkout" || err.Error() == "shipping option must be selected before payment" {
+		} else if err.Error() == "checkout access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else if paymentMethodBadRequestErrors[err.Error()] {
 			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
 		} else {
 			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
@@ -129,17 +210,257 @@ func (h *CheckoutHandler) SetPaymentMethod(w http.ResponseWriter, r *http.Reques
 	json.NewEncoder(w).Encode(checkout)
 }
 
+// QuoteInstallments handles the request to quote installment plans for a checkout
+func (h *CheckoutHandler) QuoteInstallments(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	checkoutID := vars["checkoutId"]
+	cardBrand := r.URL.Query().Get("cardBrand")
+	issuer := r.URL.Query().Get("issuer")
+
+	quotes, err := h.checkoutService.QuoteInstallments(r.Context(), checkoutID, cardBrand, issuer)
+	if err != nil {
+		if err.Error() == "checkout not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if err.Error() == "checkout access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else if err.Error() == "card brand is required" || err.Error() == "invalid checkout ID format" {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(quotes)
+}
+
 // CompleteCheckout handles the request to complete a checkout
 func (h *CheckoutHandler) CompleteCheckout(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	checkoutID := vars["checkoutId"]
 
 	checkout, err := h.checkoutService.CompleteCheckout(r.Context(), checkoutID)
+	if err != nil {
+		if violations, ok := err.(*cartModel.RuleViolationError); ok {
+			errors.WriteValidationErrorResponse(w, http.StatusUnprocessableEntity, "Purchase rules violated", cartDto.RuleViolationsFromDomain(violations))
+		} else if err.Error() == "checkout not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if err.Error() == "checkout access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else if err.Error() == "checkout was updated by another request" {
+			errors.WriteErrorResponse(w, http.StatusConflict, err.Error())
+		} else if err.Error() == "cannot complete a cancelled checkout" || err.Error() == "payment method must be selected before completing checkout" ||
+			err.Error() == "checkout is awaiting payment at the counter" ||
+			err.Error() == "gift card amounts exceed the checkout total" || err.Error() == "gift card has expired" ||
+			err.Error() == "insufficient gift card balance" || err.Error() == "insufficient loyalty points" ||
+			err.Error() == "amount is below the minimum of the selected installment plan" {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(checkout)
+}
+
+// giftCardBadRequestErrors lists the gift card tender errors caused by invalid client input
+var giftCardBadRequestErrors = map[string]bool{
+	"invalid checkout ID format":                                   true,
+	"invalid gift card ID format":                                  true,
+	"cannot update a cancelled checkout":                           true,
+	"cannot update a completed checkout":                           true,
+	"amount must be greater than zero":                             true,
+	"gift card has expired":                                        true,
+	"insufficient gift card balance":                               true,
+	"gift card amount exceeds the amount due":                      true,
+	"gift card not applied to checkout":                            true,
+	"store credit does not belong to the user":                     true,
+	"amount is below the minimum of the selected installment plan": true,
+}
+
+// ApplyGiftCard handles the request to pay part of a checkout with a gift card or store credit
+func (h *CheckoutHandler) ApplyGiftCard(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	checkoutID := vars["checkoutId"]
+
+	var req dto.GiftCardApplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	checkout, err := h.checkoutService.ApplyGiftCard(r.Context(), checkoutID, &req)
+	if err != nil {
+		if err.Error() == "checkout not found" || err.Error() == "gift card not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if err.Error() == "checkout access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else if giftCardBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(checkout)
+}
+
+// RemoveGiftCard handles the request to remove a gift card or store credit from a checkout
+func (h *CheckoutHandler) RemoveGiftCard(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	checkoutID := vars["checkoutId"]
+	giftCardID := vars["giftCardId"]
+
+	checkout, err := h.checkoutService.RemoveGiftCard(r.Context(), checkoutID, giftCardID)
+	if err != nil {
+		if err.Error() == "checkout not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if err.Error() == "checkout access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else if giftCardBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(checkout)
+}
+
+// RefundCheckout handles the request to refund a completed checkout
+func (h *CheckoutHandler) RefundCheckout(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	checkoutID := vars["checkoutId"]
+
+	checkout, err := h.checkoutService.RefundCheckout(r.Context(), checkoutID)
 	if err != nil {
 		if err.Error() == "checkout not found" {
 			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
-		} else if err.Error() == "cannot complete a cancelled checkout" || err.Error() == "payment method must be selected before completing checkout" {
+		} else if err.Error() == "only completed checkouts can be refunded" {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "loyalty points earned on the checkout were already spent" {
+			errors.WriteErrorResponse(w, http.StatusConflict, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(checkout)
+}
+
+// loyaltyBadRequestErrors lists the loyalty redemption errors caused by invalid client input
+var loyaltyBadRequestErrors = map[string]bool{
+	"invalid checkout ID format":                                   true,
+	"cannot update a cancelled checkout":                           true,
+	"cannot update a completed checkout":                           true,
+	"points must be greater than zero":                             true,
+	"insufficient loyalty points":                                  true,
+	"discount amount must be greater than zero":                    true,
+	"discount exceeds the checkout subtotal":                       true,
+	"discount leaves gift card amounts above the checkout total":   true,
+	"discount not applied to checkout":                             true,
+	"amount is below the minimum of the selected installment plan": true,
+}
+
+// RedeemLoyaltyPoints handles the request to redeem loyalty points as a checkout discount
+func (h *CheckoutHandler) RedeemLoyaltyPoints(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	checkoutID := vars["checkoutId"]
+
+	var req dto.LoyaltyRedemptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	checkout, err := h.checkoutService.RedeemLoyaltyPoints(r.Context(), checkoutID, &req)
+	if err != nil {
+		if err.Error() == "checkout not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if err.Error() == "checkout access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else if loyaltyBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(checkout)
+}
+
+// RemoveLoyaltyPoints handles the request to remove the loyalty points discount from a checkout
+func (h *CheckoutHandler) RemoveLoyaltyPoints(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	checkoutID := vars["checkoutId"]
+
+	checkout, err := h.checkoutService.RemoveLoyaltyPoints(r.Context(), checkoutID)
+	if err != nil {
+		if err.Error() == "checkout not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if err.Error() == "checkout access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else if loyaltyBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(checkout)
+}
+
+// GetCashPayment handles the request of the counter staff to look up a checkout by its payment code
+func (h *CheckoutHandler) GetCashPayment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	code := vars["code"]
+
+	checkout, err := h.checkoutService.GetCashPayment(r.Context(), code)
+	if err != nil {
+		if err.Error() == "payment code not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(checkout)
+}
+
+// RecordCashPayment handles the request of the counter staff to record the cash received for a checkout
+func (h *CheckoutHandler) RecordCashPayment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	code := vars["code"]
+
+	var req dto.CashPaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	checkout, err := h.checkoutService.RecordCashPayment(r.Context(), code, &req)
+	if err != nil {
+		if err.Error() == "payment code not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if cashPaymentBadRequestErrors[err.Error()] {
 			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "payment deadline has passed" || err.Error() == "checkout is not awaiting payment" {
+			errors.WriteErrorResponse(w, http.StatusConflict, err.Error())
 		} else {
 			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
 		}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/app/services"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
+)
+
+// GiftCardHandler handles HTTP requests for gift card operations
+type GiftCardHandler struct {
+	giftCardService *services.GiftCardService
+	requireAdmin    func(http.Handler) http.Handler
+}
+
+// NewGiftCardHandler creates a new gift card handler. Issuing goes through requireAdmin, since
+// gift cards and store credit are issued from the back office.
+func NewGiftCardHandler(giftCardService *services.GiftCardService, requireAdmin func(http.Handler) http.Handler) *GiftCardHandler {
+	return &GiftCardHandler{
+		giftCardService: giftCardService,
+		requireAdmin:    requireAdmin,
+	}
+}
+
+// RegisterRoutes registers the gift card routes on the given router
+func (h *GiftCardHandler) RegisterRoutes(router *mux.Router) {
+	// Create a subrouter for gift card routes
+	giftCardRouter := router.PathPrefix("/gift-cards").Subrouter()
+
+	// Register routes
+	giftCardRouter.HandleFunc("/{code}", h.GetGiftCard).Methods("GET")
+
+	// Issuing is only available to the back office
+	adminRouter := giftCardRouter.NewRoute().Subrouter()
+	adminRouter.Use(h.requireAdmin)
+
+	adminRouter.HandleFunc("", h.IssueGiftCard).Methods("POST")
+}
+
+// IssueGiftCard handles the request to issue a gift card or store credit
+// @Summary Issue gift card
+// @Description Issue a gift card, or store credit owned by a user, with a random code
+// @Tags gift-cards
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param request body dto.GiftCardIssueRequest true "Gift card"
+// @Success 201 {object} dto.GiftCardDTO "Gift card issued successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/gift-cards [post]
+func (h *GiftCardHandler) IssueGiftCard(w http.ResponseWriter, r *http.Request) {
+	var req dto.GiftCardIssueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	giftCard, err := h.giftCardService.IssueGiftCard(r.Context(), &req)
+	if err != nil {
+		switch err.Error() {
+		case "invalid gift card kind", "store credit must belong to a user", "amount must be greater than zero",
+			"expiration date must be in the future", "invalid expiration date format", "invalid user ID format":
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		default:
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(giftCard)
+}
+
+// GetGiftCard handles the request to get a gift card balance and history
+// @Summary Get gift card
+// @Description Get the balance and ledger of a gift card or store credit by its code
+// @Tags gift-cards
+// @Produce json
+// @Param code path string true "Gift card code"
+// @Success 200 {object} dto.GiftCardDTO "Gift card"
+// @Failure 404 {object} errors.ErrorResponse "Gift card not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/gift-cards/{code} [get]
+func (h *GiftCardHandler) GetGiftCard(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	code := vars["code"]
+
+	giftCard, err := h.giftCardService.GetGiftCard(r.Context(), code)
+	if err != nil {
+		if err.Error() == "gift card not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, "Gift card not found")
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(giftCard)
+}
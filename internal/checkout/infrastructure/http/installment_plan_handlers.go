@@ -0,0 +1,101 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/app/services"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
+)
+
+// installmentPlanBadRequestErrors lists the installment plan errors caused by invalid client input
+var installmentPlanBadRequestErrors = map[string]bool{
+	"invalid installment plan ID format": true,
+	"card brand is required":             true,
+	"installments must be positive":      true,
+	"interest rate cannot be negative":   true,
+	"minimum amount cannot be negative":  true,
+}
+
+// InstallmentPlanHandler handles HTTP requests to manage the installment plan catalog
+type InstallmentPlanHandler struct {
+	checkoutService *services.CheckoutService
+	requireAdmin    func(http.Handler) http.Handler
+}
+
+// NewInstallmentPlanHandler creates a new installment plan handler. Every route goes through
+// requireAdmin, since the catalog is managed from the back office.
+func NewInstallmentPlanHandler(checkoutService *services.CheckoutService, requireAdmin func(http.Handler) http.Handler) *InstallmentPlanHandler {
+	return &InstallmentPlanHandler{
+		checkoutService: checkoutService,
+		requireAdmin:    requireAdmin,
+	}
+}
+
+// RegisterRoutes registers the installment plan routes on the given router
+func (h *InstallmentPlanHandler) RegisterRoutes(router *mux.Router) {
+	// Create a subrouter for installment plan routes
+	planRouter := router.PathPrefix("/installment-plans").Subrouter()
+	planRouter.Use(h.requireAdmin)
+
+	// Register routes
+	planRouter.HandleFunc("", h.ListInstallmentPlans).Methods("GET")
+	planRouter.HandleFunc("", h.CreateInstallmentPlan).Methods("POST")
+	planRouter.HandleFunc("/{planId}", h.DeactivateInstallmentPlan).Methods("DELETE")
+}
+
+// ListInstallmentPlans handles the request to list the installment plan catalog
+func (h *InstallmentPlanHandler) ListInstallmentPlans(w http.ResponseWriter, r *http.Request) {
+	plans, err := h.checkoutService.ListInstallmentPlans(r.Context())
+	if err != nil {
+		errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(plans)
+}
+
+// CreateInstallmentPlan handles the request to add an installment plan to the catalog
+func (h *InstallmentPlanHandler) CreateInstallmentPlan(w http.ResponseWriter, r *http.Request) {
+	var req dto.InstallmentPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	plan, err := h.checkoutService.CreateInstallmentPlan(r.Context(), &req)
+	if err != nil {
+		if installmentPlanBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(plan)
+}
+
+// DeactivateInstallmentPlan handles the request to stop offering an installment plan
+func (h *InstallmentPlanHandler) DeactivateInstallmentPlan(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	planID := vars["planId"]
+
+	if err := h.checkoutService.DeactivateInstallmentPlan(r.Context(), planID); err != nil {
+		if err.Error() == "installment plan not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if installmentPlanBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/app/services"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
+)
+
+// ReceiptHandler handles HTTP requests for checkout receipts
+type ReceiptHandler struct {
+	receiptService *services.ReceiptService
+	requireUser    func(http.Handler) http.Handler
+}
+
+// NewReceiptHandler creates a new receipt handler. Receipts go through requireUser, so they
+// are only downloaded by the user of the checkout or the back office.
+func NewReceiptHandler(receiptService *services.ReceiptService, requireUser func(http.Handler) http.Handler) *ReceiptHandler {
+	return &ReceiptHandler{
+		receiptService: receiptService,
+		requireUser:    requireUser,
+	}
+}
+
+// RegisterRoutes registers the receipt routes on the given router
+func (h *ReceiptHandler) RegisterRoutes(router *mux.Router) {
+	// Create a subrouter for checkout routes
+	checkoutRouter := router.PathPrefix("/checkout").Subrouter()
+
+	// Requests act on behalf of the user named in the X-User-ID header
+	checkoutRouter.Use(h.requireUser)
+
+	// Register routes
+	checkoutRouter.HandleFunc("/{checkoutId}/receipt", h.GetReceipt).Methods("GET")
+}
+
+// GetReceipt handles the request to download the receipt of a completed checkout
+// @Summary Download receipt
+// @Description Download the receipt of a completed or refunded checkout, as a PDF or as an ESC/POS byte stream for the kiosk's thermal printer
+// @Tags checkout
+// @Produce application/pdf
+// @Produce application/octet-stream
+// @Param X-User-ID header string false "Acting user; must be the checkout user unless the admin key is given" format(uuid)
+// @Param X-Admin-Key header string false "Admin API key, to download the receipt of any checkout"
+// @Param checkoutId path string true "Checkout ID" format(uuid)
+// @Param format query string false "Receipt format" Enums(pdf, escpos) default(pdf)
+// @Success 200 {file} file "Receipt"
+// @Failure 400 {object} errors.ErrorResponse "Invalid checkout ID or unsupported format"
+// @Failure 401 {object} errors.ErrorResponse "Missing user ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the checkout user, or invalid admin key"
+// @Failure 404 {object} errors.ErrorResponse "Checkout not found"
+// @Failure 409 {object} errors.ErrorResponse "Checkout not completed"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/checkout/{checkoutId}/receipt [get]
+func (h *ReceiptHandler) GetReceipt(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	checkoutID := vars["checkoutId"]
+
+	receipt, err := h.receiptService.RenderReceipt(r.Context(), checkoutID, r.URL.Query().Get("format"))
+	if err != nil {
+		switch err.Error() {
+		case "checkout not found":
+			errors.WriteErrorResponse(w, http.StatusNotFound, "Checkout not found")
+		case "checkout access denied":
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		case "invalid checkout ID format", "unsupported receipt format":
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		case "receipts are only available for completed checkouts":
+			errors.WriteErrorResponse(w, http.StatusConflict, err.Error())
+		default:
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", receipt.ContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+receipt.Filename+`"`)
+	w.Write(receipt.Content)
+}
@@ -2,23 +2,32 @@ package http
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/app/services"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
 )
 
+// maxStreetFileSize limits the size of an uploaded street dataset file
+const maxStreetFileSize = 100 << 20
+
 // ShippingHandler handles HTTP requests for shipping operations
 type ShippingHandler struct {
 	shippingService *services.ShippingService
+	requireAdmin    func(http.Handler) http.Handler
 }
 
-// NewShippingHandler creates a new shipping handler
-func NewShippingHandler(shippingService *services.ShippingService) *ShippingHandler {
+// NewShippingHandler creates a new shipping handler. Importing the street dataset goes through
+// requireAdmin, since it replaces the dataset every shopper's addresses are geocoded with.
+func NewShippingHandler(shippingService *services.ShippingService, requireAdmin func(http.Handler) http.Handler) *ShippingHandler {
 	return &ShippingHandler{
 		shippingService: shippingService,
+		requireAdmin:    requireAdmin,
 	}
 }
 
@@ -30,10 +39,18 @@ func (h *ShippingHandler) RegisterRoutes(router *mux.Router) {
 	// Register routes
 	shippingRouter.HandleFunc("/addresses", h.AddShippingAddress).Methods("POST")
 	shippingRouter.HandleFunc("/addresses", h.GetUserShippingAddresses).Methods("GET")
+	shippingRouter.HandleFunc("/addresses/validate", h.ValidateShippingAddress).Methods("POST")
+	shippingRouter.HandleFunc("/addresses/autocomplete", h.AutocompleteAddresses).Methods("GET")
 	shippingRouter.HandleFunc("/addresses/{addressId}", h.GetShippingAddress).Methods("GET")
 	shippingRouter.HandleFunc("/addresses/{addressId}", h.UpdateShippingAddress).Methods("PUT")
 	shippingRouter.HandleFunc("/addresses/{addressId}", h.DeleteShippingAddress).Methods("DELETE")
 	shippingRouter.HandleFunc("/methods", h.GetShippingMethods).Methods("GET")
+	shippingRouter.HandleFunc("/provinces", h.GetProvinces).Methods("GET")
+
+	// The street dataset is replaced from the back office
+	streetRouter := shippingRouter.PathPrefix("/streets").Subrouter()
+	streetRouter.Use(h.requireAdmin)
+	streetRouter.HandleFunc("", h.ImportStreets).Methods("POST")
 }
 
 // AddShippingAddress handles the request to add a shipping address
@@ -46,7 +63,11 @@ func (h *ShippingHandler) AddShippingAddress(w http.ResponseWriter, r *http.Requ
 
 	address, err := h.shippingService.AddShippingAddress(r.Context(), &req)
 	if err != nil {
-		errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		if invalid, ok := err.(*model.AddressValidationError); ok {
+			errors.WriteValidationErrorResponse(w, http.StatusUnprocessableEntity, "Invalid shipping address", dto.AddressFieldErrorsFromDomain(invalid.Validation))
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
 
@@ -105,7 +126,9 @@ func (h *ShippingHandler) UpdateShippingAddress(w http.ResponseWriter, r *http.R
 
 	address, err := h.shippingService.UpdateShippingAddress(r.Context(), addressID, &req)
 	if err != nil {
-		if err.Error() == "shipping address not found" {
+		if invalid, ok := err.(*model.AddressValidationError); ok {
+			errors.WriteValidationErrorResponse(w, http.StatusUnprocessableEntity, "Invalid shipping address", dto.AddressFieldErrorsFromDomain(invalid.Validation))
+		} else if err.Error() == "shipping address not found" {
 			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
 		} else if err.Error() == "shipping address does not belong to the user" {
 			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
@@ -119,6 +142,36 @@ func (h *ShippingHandler) UpdateShippingAddress(w http.ResponseWriter, r *http.R
 	json.NewEncoder(w).Encode(address)
 }
 
+// ValidateShippingAddress handles the request to validate a shipping address without saving it
+func (h *ShippingHandler) ValidateShippingAddress(w http.ResponseWriter, r *http.Request) {
+	var req dto.ShippingAddressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	validation := h.shippingService.ValidateShippingAddress(r.Context(), &req)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(validation)
+}
+
+// AutocompleteAddresses handles the request to suggest addresses matching the text typed so far
+func (h *ShippingHandler) AutocompleteAddresses(w http.ResponseWriter, r *http.Request) {
+	candidates, err := h.shippingService.AutocompleteAddresses(r.Context(), r.URL.Query().Get("q"))
+	if err != nil {
+		if err.Error() == "query is required" {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(candidates)
+}
+
 // DeleteShippingAddress handles the request to delete a shipping address
 func (h *ShippingHandler) DeleteShippingAddress(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -148,3 +201,45 @@ func (h *ShippingHandler) GetShippingMethods(w http.ResponseWriter, r *http.Requ
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(methods)
 }
+
+// GetProvinces handles the request to get the provinces addresses can be in
+func (h *ShippingHandler) GetProvinces(w http.ResponseWriter, r *http.Request) {
+	provinces := h.shippingService.GetProvinces(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(provinces)
+}
+
+// ImportStreets handles the upload of a street dataset CSV file, either as a multipart "file"
+// field or as the raw request body
+func (h *ShippingHandler) ImportStreets(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxStreetFileSize)
+
+	var file io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		upload, _, err := r.FormFile("file")
+		if err != nil {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid street file upload")
+			return
+		}
+		defer upload.Close()
+		file = upload
+	}
+
+	result, err := h.shippingService.ImportStreets(r.Context(), file)
+	if err != nil {
+		if err == services.ErrInvalidStreets {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			json.NewEncoder(w).Encode(result)
+		} else if err.Error() == "street file is not a valid CSV file" || err.Error() == "street file is empty" {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
@@ -5,12 +5,24 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
 	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/repository"
+	"github.com/lib/pq"
 )
 
+// uniqueViolation is the PostgreSQL error code of a unique constraint violation
+const uniqueViolation = "23505"
+
+// checkoutColumns lists the columns read by every checkout query, in scanCheckout order
+const checkoutColumns = `
+	id, cart_id, user_id, status, items, subtotal, shipping_cost, tax, financing_cost, total,
+	delivery_option, payment_method, gift_cards, created_at, updated_at, discounts, tax_rate,
+	cash_payment
+`
+
 // PostgreSQLCheckoutRepository implements the CheckoutRepository interface using PostgreSQL
 type PostgreSQLCheckoutRepository struct {
 	db *sql.DB
@@ -23,15 +35,13 @@ func NewPostgreSQLCheckoutRepository(db *sql.DB) repository.CheckoutRepository {
 	}
 }
 
-// FindByID retrieves a checkout by its ID
-func (r *PostgreSQLCheckoutRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Checkout, error) {
-	query := `
-		SELECT id, cart_id, user_id, status, items, subtotal, shipping_cost, tax, total, 
-		       delivery_option, payment_method, created_at, updated_at
-		FROM checkouts
-		WHERE id = $1
-	`
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
+// scanCheckout reads a checkout selected with checkoutColumns
+func scanCheckout(row rowScanner) (*model.Checkout, error) {
 	var (
 		checkoutID         uuid.UUID
 		cartID             uuid.UUID
@@ -41,14 +51,19 @@ func (r *PostgreSQLCheckoutRepository) FindByID(ctx context.Context, id uuid.UUI
 		subtotal           float64
 		shippingCost       float64
 		tax                float64
+		financingCost      float64
 		total              float64
 		deliveryOptionJSON sql.NullString
 		paymentMethodJSON  sql.NullString
+		giftCardsJSON      sql.NullString
 		createdAt          sql.NullTime
 		updatedAt          sql.NullTime
+		discountsJSON      sql.NullString
+		taxRate            sql.NullFloat64
+		cashPaymentJSON    sql.NullString
 	)
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	if err := row.Scan(
 		&checkoutID,
 		&cartID,
 		&userID,
@@ -57,17 +72,17 @@ func (r *PostgreSQLCheckoutRepository) FindByID(ctx context.Context, id uuid.UUI
 		&subtotal,
 		&shippingCost,
 		&tax,
+		&financingCost,
 		&total,
 		&deliveryOptionJSON,
 		&paymentMethodJSON,
+		&giftCardsJSON,
 		&createdAt,
 		&updatedAt,
-	)
-
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("checkout not found")
-		}
+		&discountsJSON,
+		&taxRate,
+		&cashPaymentJSON,
+	); err != nil {
 		return nil, err
 	}
 
@@ -79,17 +94,21 @@ func (r *PostgreSQLCheckoutRepository) FindByID(ctx context.Context, id uuid.UUI
 
 	// Create checkout object
 	checkout := &model.Checkout{
-		ID:           checkoutID,
-		CartID:       cartID,
-		UserID:       userID,
-		Status:       model.CheckoutStatus(status),
-		Items:        items,
-		Subtotal:     subtotal,
-		ShippingCost: shippingCost,
-		Tax:          tax,
-		Total:        total,
-		CreatedAt:    createdAt.Time,
-		UpdatedAt:    updatedAt.Time,
+		ID:            checkoutID,
+		CartID:        cartID,
+		UserID:        userID,
+		Status:        model.CheckoutStatus(status),
+		Items:         items,
+		Subtotal:      subtotal,
+		ShippingCost:  shippingCost,
+		Discounts:     make([]*model.DiscountLine, 0),
+		Tax:           tax,
+		TaxRate:       taxRate.Float64,
+		FinancingCost: financingCost,
+		Total:         total,
+		GiftCards:     make([]*model.GiftCardTender, 0),
+		CreatedAt:     createdAt.Time,
+		UpdatedAt:     updatedAt.Time,
 	}
 
 	// Deserialize delivery option if present
@@ -110,52 +129,41 @@ func (r *PostgreSQLCheckoutRepository) FindByID(ctx context.Context, id uuid.UUI
 		checkout.PaymentMethod = &paymentMethod
 	}
 
+	// Deserialize gift card tenders if present
+	if giftCardsJSON.Valid {
+		if err := json.Unmarshal([]byte(giftCardsJSON.String), &checkout.GiftCards); err != nil {
+			return nil, err
+		}
+	}
+
+	// Deserialize discount lines if present
+	if discountsJSON.Valid {
+		if err := json.Unmarshal([]byte(discountsJSON.String), &checkout.Discounts); err != nil {
+			return nil, err
+		}
+	}
+
+	// Deserialize the cash payment if present
+	if cashPaymentJSON.Valid {
+		var cashPayment model.CashPayment
+		if err := json.Unmarshal([]byte(cashPaymentJSON.String), &cashPayment); err != nil {
+			return nil, err
+		}
+		checkout.CashPayment = &cashPayment
+	}
+
 	return checkout, nil
 }
 
-// FindByCartID retrieves a checkout by cart ID
-func (r *PostgreSQLCheckoutRepository) FindByCartID(ctx context.Context, cartID uuid.UUID) (*model.Checkout, error) {
+// FindByID retrieves a checkout by its ID
+func (r *PostgreSQLCheckoutRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Checkout, error) {
 	query := `
-		SELECT id, cart_id, user_id, status, items, subtotal, shipping_cost, tax, total, 
-		       delivery_option, payment_method, created_at, updated_at
+		SELECT ` + checkoutColumns + `
 		FROM checkouts
-		WHERE cart_id = $1
-		ORDER BY created_at DESC
-		LIMIT 1
+		WHERE id = $1
 	`
 
-	var (
-		checkoutID         uuid.UUID
-		dbCartID           uuid.UUID
-		userID             uuid.UUID
-		status             string
-		itemsJSON          []byte
-		subtotal           float64
-		shippingCost       float64
-		tax                float64
-		total              float64
-		deliveryOptionJSON sql.NullString
-		paymentMethodJSON  sql.NullString
-		createdAt          sql.NullTime
-		updatedAt          sql.NullTime
-	)
-
-	err := r.db.QueryRowContext(ctx, query, cartID).Scan(
-		&checkoutID,
-		&dbCartID,
-		&userID,
-		&status,
-		&itemsJSON,
-		&subtotal,
-		&shippingCost,
-		&tax,
-		&total,
-		&deliveryOptionJSON,
-		&paymentMethodJSON,
-		&createdAt,
-		&updatedAt,
-	)
-
+	checkout, err := scanCheckout(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors.New("checkout not found")
@@ -163,43 +171,25 @@ func (r *PostgreSQLCheckoutRepository) FindByCartID(ctx context.Context, cartID
 		return nil, err
 	}
 
-	// Deserialize items from JSON
-	var items []*model.CheckoutItem
-	if err := json.Unmarshal(itemsJSON, &items); err != nil {
-		return nil, err
-	}
-
-	// Create checkout object
-	checkout := &model.Checkout{
-		ID:           checkoutID,
-		CartID:       dbCartID,
-		UserID:       userID,
-		Status:       model.CheckoutStatus(status),
-		Items:        items,
-		Subtotal:     subtotal,
-		ShippingCost: shippingCost,
-		Tax:          tax,
-		Total:        total,
-		CreatedAt:    createdAt.Time,
-		UpdatedAt:    updatedAt.Time,
-	}
+	return checkout, nil
+}
 
-	// Deserialize delivery option if present
-	if deliveryOptionJSON.Valid {
-		var deliveryOption model.DeliveryOption
-		if err := json.Unmarshal([]byte(deliveryOptionJSON.String), &deliveryOption); err != nil {
-			return nil, err
-		}
-		checkout.DeliveryOption = &deliveryOption
-	}
+// FindByCartID retrieves a checkout by cart ID
+func (r *PostgreSQLCheckoutRepository) FindByCartID(ctx context.Context, cartID uuid.UUID) (*model.Checkout, error) {
+	query := `
+		SELECT ` + checkoutColumns + `
+		FROM checkouts
+		WHERE cart_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
 
-	// Deserialize payment method if present
-	if paymentMethodJSON.Valid {
-		var paymentMethod model.PaymentMethod
-		if err := json.Unmarshal([]byte(paymentMethodJSON.String), &paymentMethod); err != nil {
-			return nil, err
+	checkout, err := scanCheckout(r.db.QueryRowContext(ctx, query, cartID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("checkout not found")
 		}
-		checkout.PaymentMethod = &paymentMethod
+		return nil, err
 	}
 
 	return checkout, nil
@@ -208,8 +198,7 @@ func (r *PostgreSQLCheckoutRepository) FindByCartID(ctx context.Context, cartID
 // FindByUserID retrieves the latest checkouts for a user
 func (r *PostgreSQLCheckoutRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]*model.Checkout, error) {
 	query := `
-		SELECT id, cart_id, user_id, status, items, subtotal, shipping_cost, tax, total, 
-		       delivery_option, payment_method, created_at, updated_at
+		SELECT ` + checkoutColumns + `
 		FROM checkouts
 		WHERE user_id = $1
 		ORDER BY created_at DESC
@@ -225,77 +214,61 @@ func (r *PostgreSQLCheckoutRepository) FindByUserID(ctx context.Context, userID
 	var checkouts []*model.Checkout
 
 	for rows.Next() {
-		var (
-			checkoutID         uuid.UUID
-			cartID             uuid.UUID
-			dbUserID           uuid.UUID
-			status             string
-			itemsJSON          []byte
-			subtotal           float64
-			shippingCost       float64
-			tax                float64
-			total              float64
-			deliveryOptionJSON sql.NullString
-			paymentMethodJSON  sql.NullString
-			createdAt          sql.NullTime
-			updatedAt          sql.NullTime
-		)
-
-		if err := rows.Scan(
-			&checkoutID,
-			&cartID,
-			&dbUserID,
-			&status,
-			&itemsJSON,
-			&subtotal,
-			&shippingCost,
-			&tax,
-			&total,
-			&deliveryOptionJSON,
-			&paymentMethodJSON,
-			&createdAt,
-			&updatedAt,
-		); err != nil {
+		checkout, err := scanCheckout(rows)
+		if err != nil {
 			return nil, err
 		}
 
-		// Deserialize items from JSON
-		var items []*model.CheckoutItem
-		if err := json.Unmarshal(itemsJSON, &items); err != nil {
-			return nil, err
-		}
+		checkouts = append(checkouts, checkout)
+	}
 
-		// Create checkout object
-		checkout := &model.Checkout{
-			ID:           checkoutID,
-			CartID:       cartID,
-			UserID:       dbUserID,
-			Status:       model.CheckoutStatus(status),
-			Items:        items,
-			Subtotal:     subtotal,
-			ShippingCost: shippingCost,
-			Tax:          tax,
-			Total:        total,
-			CreatedAt:    createdAt.Time,
-			UpdatedAt:    updatedAt.Time,
-		}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return checkouts, nil
+}
+
+// FindByPaymentCode retrieves the checkout awaiting payment at the counter with a payment code
+func (r *PostgreSQLCheckoutRepository) FindByPaymentCode(ctx context.Context, code string) (*model.Checkout, error) {
+	query := `
+		SELECT ` + checkoutColumns + `
+		FROM checkouts
+		WHERE payment_code = $1 AND status = $2
+	`
 
-		// Deserialize delivery option if present
-		if deliveryOptionJSON.Valid {
-			var deliveryOption model.DeliveryOption
-			if err := json.Unmarshal([]byte(deliveryOptionJSON.String), &deliveryOption); err != nil {
-				return nil, err
-			}
-			checkout.DeliveryOption = &deliveryOption
+	checkout, err := scanCheckout(r.db.QueryRowContext(ctx, query, code, string(model.CheckoutStatusAwaitingPayment)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("payment code not found")
 		}
+		return nil, err
+	}
+
+	return checkout, nil
+}
+
+// FindOverduePayments retrieves the checkouts awaiting payment whose deadline passed before the given time
+func (r *PostgreSQLCheckoutRepository) FindOverduePayments(ctx context.Context, before time.Time) ([]*model.Checkout, error) {
+	query := `
+		SELECT ` + checkoutColumns + `
+		FROM checkouts
+		WHERE status = $1 AND payment_due_at < $2
+		ORDER BY payment_due_at
+	`
 
-		// Deserialize payment method if present
-		if paymentMethodJSON.Valid {
-			var paymentMethod model.PaymentMethod
-			if err := json.Unmarshal([]byte(paymentMethodJSON.String), &paymentMethod); err != nil {
-				return nil, err
-			}
-			checkout.PaymentMethod = &paymentMethod
+	rows, err := r.db.QueryContext(ctx, query, string(model.CheckoutStatusAwaitingPayment), before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checkouts := make([]*model.Checkout, 0)
+
+	for rows.Next() {
+		checkout, err := scanCheckout(rows)
+		if err != nil {
+			return nil, err
 		}
 
 		checkouts = append(checkouts, checkout)
@@ -342,15 +315,47 @@ func (r *PostgreSQLCheckoutRepository) Save(ctx context.Context, checkout *model
 		}
 	}
 
+	// Serialize gift card tenders to JSON
+	giftCardsJSON, err := json.Marshal(checkout.GiftCards)
+	if err != nil {
+		return err
+	}
+
+	// Serialize discount lines to JSON
+	discountsJSON, err := json.Marshal(checkout.Discounts)
+	if err != nil {
+		return err
+	}
+
+	// Serialize the cash payment to JSON if present; its code and deadline get their own
+	// columns so the counter and the expiry job can look them up
+	var (
+		cashPaymentJSON sql.NullString
+		paymentCode     sql.NullString
+		paymentDueAt    sql.NullTime
+	)
+	if checkout.CashPayment != nil {
+		cashPaymentBytes, err := json.Marshal(checkout.CashPayment)
+		if err != nil {
+			return err
+		}
+		cashPaymentJSON = sql.NullString{String: string(cashPaymentBytes), Valid: true}
+		paymentCode = sql.NullString{String: checkout.CashPayment.Code, Valid: true}
+		paymentDueAt = sql.NullTime{Time: checkout.CashPayment.DueAt, Valid: true}
+	}
+
 	query := `
 		INSERT INTO checkouts (
 			id, cart_id, user_id, status, items, subtotal, shipping_cost, tax, total,
-			delivery_option, payment_method, created_at, updated_at
+			delivery_option, payment_method, created_at, updated_at, financing_cost, gift_cards,
+			discounts, tax_rate, cash_payment, payment_code, payment_due_at
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
 		ON CONFLICT (id) DO UPDATE
 		SET status = $4, items = $5, subtotal = $6, shipping_cost = $7, tax = $8, total = $9,
-			delivery_option = $10, payment_method = $11, updated_at = $13
+			delivery_option = $10, payment_method = $11, updated_at = $13, financing_cost = $14,
+			gift_cards = $15, discounts = $16, tax_rate = $17, cash_payment = $18,
+			payment_code = $19, payment_due_at = $20
 	`
 
 	_, err = r.db.ExecContext(
@@ -369,7 +374,113 @@ func (r *PostgreSQLCheckoutRepository) Save(ctx context.Context, checkout *model
 		paymentMethodJSON,
 		checkout.CreatedAt,
 		checkout.UpdatedAt,
+		checkout.FinancingCost,
+		giftCardsJSON,
+		discountsJSON,
+		checkout.TaxRate,
+		cashPaymentJSON,
+		paymentCode,
+		paymentDueAt,
 	)
 
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation && pqErr.Constraint == openPaymentCodeIndex {
+		return errors.New("payment code already in use")
+	}
+
 	return err
 }
+
+// SaveStatusTransition stores the status and cash payment of a checkout. The update only
+// applies while the stored checkout still has the from status, so concurrent requests cannot
+// both complete it and debit its gift cards and loyalty points twice.
+func (r *PostgreSQLCheckoutRepository) SaveStatusTransition(ctx context.Context, checkout *model.Checkout, from model.CheckoutStatus) error {
+	var (
+		cashPaymentJSON sql.NullString
+		paymentCode     sql.NullString
+		paymentDueAt    sql.NullTime
+	)
+	if checkout.CashPayment != nil {
+		cashPaymentBytes, err := json.Marshal(checkout.CashPayment)
+		if err != nil {
+			return err
+		}
+		cashPaymentJSON = sql.NullString{String: string(cashPaymentBytes), Valid: true}
+		paymentCode = sql.NullString{String: checkout.CashPayment.Code, Valid: true}
+		paymentDueAt = sql.NullTime{Time: checkout.CashPayment.DueAt, Valid: true}
+	}
+
+	query := `
+		UPDATE checkouts
+		SET status = $2, cash_payment = $3, payment_code = $4, payment_due_at = $5, updated_at = $6
+		WHERE id = $1 AND status = $7
+	`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		checkout.ID,
+		checkout.Status,
+		cashPaymentJSON,
+		paymentCode,
+		paymentDueAt,
+		checkout.UpdatedAt,
+		string(from),
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation && pqErr.Constraint == openPaymentCodeIndex {
+			return errors.New("payment code already in use")
+		}
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("checkout was updated by another request")
+	}
+
+	return nil
+}
+
+// SaveCashPaymentOutcome stores the status and cash payment of a checkout that was awaiting
+// payment at the counter. The update only applies while the stored checkout is still
+// awaiting payment, so the counter and the expiry job cannot both close it.
+func (r *PostgreSQLCheckoutRepository) SaveCashPaymentOutcome(ctx context.Context, checkout *model.Checkout) error {
+	cashPaymentJSON, err := json.Marshal(checkout.CashPayment)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE checkouts
+		SET status = $2, cash_payment = $3, updated_at = $4
+		WHERE id = $1 AND status = $5
+	`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		checkout.ID,
+		checkout.Status,
+		string(cashPaymentJSON),
+		checkout.UpdatedAt,
+		string(model.CheckoutStatusAwaitingPayment),
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("checkout is not awaiting payment")
+	}
+
+	return nil
+}
@@ -0,0 +1,311 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/repository"
+)
+
+// PostgreSQLGiftCardRepository implements the GiftCardRepository interface using PostgreSQL
+type PostgreSQLGiftCardRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLGiftCardRepository creates a new PostgreSQL repository for gift cards
+func NewPostgreSQLGiftCardRepository(db *sql.DB) repository.GiftCardRepository {
+	return &PostgreSQLGiftCardRepository{
+		db: db,
+	}
+}
+
+// FindByID retrieves a gift card by its ID
+func (r *PostgreSQLGiftCardRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.GiftCard, error) {
+	query := `
+		SELECT id, code, kind, owner_user_id, initial_balance, balance, expires_at, created_at, updated_at
+		FROM gift_cards
+		WHERE id = $1
+	`
+
+	return r.findOne(ctx, query, id)
+}
+
+// FindByCode retrieves a gift card by its code
+func (r *PostgreSQLGiftCardRepository) FindByCode(ctx context.Context, code string) (*model.GiftCard, error) {
+	query := `
+		SELECT id, code, kind, owner_user_id, initial_balance, balance, expires_at, created_at, updated_at
+		FROM gift_cards
+		WHERE code = $1
+	`
+
+	return r.findOne(ctx, query, model.NormalizeGiftCardCode(code))
+}
+
+// findOne runs a single-row gift card query
+func (r *PostgreSQLGiftCardRepository) findOne(ctx context.Context, query string, arg interface{}) (*model.GiftCard, error) {
+	var (
+		giftCard    model.GiftCard
+		kind        string
+		ownerUserID uuid.NullUUID
+		expiresAt   sql.NullTime
+	)
+
+	err := r.db.QueryRowContext(ctx, query, arg).Scan(
+		&giftCard.ID,
+		&giftCard.Code,
+		&kind,
+		&ownerUserID,
+		&giftCard.InitialBalance,
+		&giftCard.Balance,
+		&expiresAt,
+		&giftCard.CreatedAt,
+		&giftCard.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("gift card not found")
+		}
+		return nil, err
+	}
+
+	giftCard.Kind = model.GiftCardKind(kind)
+	if ownerUserID.Valid {
+		giftCard.OwnerUserID = ownerUserID.UUID
+	}
+	if expiresAt.Valid {
+		giftCard.ExpiresAt = &expiresAt.Time
+	}
+
+	return &giftCard, nil
+}
+
+// FindTransactions retrieves the ledger entries of a gift card, oldest first
+func (r *PostgreSQLGiftCardRepository) FindTransactions(ctx context.Context, giftCardID uuid.UUID) ([]*model.GiftCardTransaction, error) {
+	query := `
+		SELECT id, gift_card_id, checkout_id, type, amount, balance_after, created_at
+		FROM gift_card_transactions
+		WHERE gift_card_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, giftCardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []*model.GiftCardTransaction
+
+	for rows.Next() {
+		var (
+			transaction model.GiftCardTransaction
+			checkoutID  uuid.NullUUID
+			txType      string
+		)
+
+		if err := rows.Scan(
+			&transaction.ID,
+			&transaction.GiftCardID,
+			&checkoutID,
+			&txType,
+			&transaction.Amount,
+			&transaction.BalanceAfter,
+			&transaction.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		transaction.Type = model.GiftCardTransactionType(txType)
+		if checkoutID.Valid {
+			transaction.CheckoutID = checkoutID.UUID
+		}
+
+		transactions = append(transactions, &transaction)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// Create persists a newly issued gift card together with its ISSUE ledger entry
+func (r *PostgreSQLGiftCardRepository) Create(ctx context.Context, giftCard *model.GiftCard, issue *model.GiftCardTransaction) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO gift_cards (id, code, kind, owner_user_id, initial_balance, balance, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	var expiresAt sql.NullTime
+	if giftCard.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: *giftCard.ExpiresAt, Valid: true}
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		query,
+		giftCard.ID,
+		giftCard.Code,
+		giftCard.Kind,
+		nullableUUID(giftCard.OwnerUserID),
+		giftCard.InitialBalance,
+		giftCard.Balance,
+		expiresAt,
+		giftCard.CreatedAt,
+		giftCard.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	if err := insertGiftCardTransaction(ctx, tx, issue); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ApplyTransactions atomically applies the ledger entries to the gift card balances
+func (r *PostgreSQLGiftCardRepository) ApplyTransactions(ctx context.Context, transactions []*model.GiftCardTransaction) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The balance guard makes concurrent debits of the same card fail instead of overdrawing it
+	query := `
+		UPDATE gift_cards
+		SET balance = balance + $2, updated_at = now()
+		WHERE id = $1 AND balance + $2 >= 0
+		RETURNING balance
+	`
+
+	for _, transaction := range transactions {
+		var balance float64
+		err := tx.QueryRowContext(ctx, query, transaction.GiftCardID, transaction.Amount).Scan(&balance)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errors.New("insufficient gift card balance")
+			}
+			return err
+		}
+
+		transaction.BalanceAfter = balance
+		if err := insertGiftCardTransaction(ctx, tx, transaction); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// RefundCheckout credits back the outstanding debits of a checkout. The cards are locked while
+// the amounts are worked out, so concurrent refunds of the same checkout cannot both credit them.
+func (r *PostgreSQLGiftCardRepository) RefundCheckout(ctx context.Context, checkoutID uuid.UUID) ([]*model.GiftCardTransaction, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		SELECT id FROM gift_cards
+		WHERE id IN (SELECT gift_card_id FROM gift_card_transactions WHERE checkout_id = $1)
+		ORDER BY id
+		FOR UPDATE
+	`, checkoutID); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT gift_card_id, -SUM(amount)
+		FROM gift_card_transactions
+		WHERE checkout_id = $1
+		GROUP BY gift_card_id
+		HAVING SUM(amount) < 0
+		ORDER BY gift_card_id
+	`, checkoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	refunds := make([]*model.GiftCardTransaction, 0)
+	for rows.Next() {
+		refund := &model.GiftCardTransaction{
+			ID:         uuid.New(),
+			CheckoutID: checkoutID,
+			Type:       model.GiftCardTransactionRefund,
+			CreatedAt:  now,
+		}
+		if err := rows.Scan(&refund.GiftCardID, &refund.Amount); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		refunds = append(refunds, refund)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, refund := range refunds {
+		if err := tx.QueryRowContext(ctx, `
+			UPDATE gift_cards
+			SET balance = balance + $2, updated_at = now()
+			WHERE id = $1
+			RETURNING balance
+		`, refund.GiftCardID, refund.Amount).Scan(&refund.BalanceAfter); err != nil {
+			return nil, err
+		}
+
+		if err := insertGiftCardTransaction(ctx, tx, refund); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return refunds, nil
+}
+
+// insertGiftCardTransaction appends an entry to the gift card ledger
+func insertGiftCardTransaction(ctx context.Context, tx *sql.Tx, transaction *model.GiftCardTransaction) error {
+	query := `
+		INSERT INTO gift_card_transactions (id, gift_card_id, checkout_id, type, amount, balance_after, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := tx.ExecContext(
+		ctx,
+		query,
+		transaction.ID,
+		transaction.GiftCardID,
+		nullableUUID(transaction.CheckoutID),
+		transaction.Type,
+		transaction.Amount,
+		transaction.BalanceAfter,
+		transaction.CreatedAt,
+	)
+
+	return err
+}
+
+// nullableUUID maps uuid.Nil to a SQL NULL
+func nullableUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
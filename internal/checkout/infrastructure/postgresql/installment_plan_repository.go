@@ -0,0 +1,158 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/repository"
+)
+
+// PostgreSQLInstallmentPlanRepository implements the InstallmentPlanRepository interface using PostgreSQL
+type PostgreSQLInstallmentPlanRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLInstallmentPlanRepository creates a new PostgreSQL repository for installment plans
+func NewPostgreSQLInstallmentPlanRepository(db *sql.DB) repository.InstallmentPlanRepository {
+	return &PostgreSQLInstallmentPlanRepository{
+		db: db,
+	}
+}
+
+// FindByID retrieves an installment plan by its ID
+func (r *PostgreSQLInstallmentPlanRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.InstallmentPlan, error) {
+	query := `
+		SELECT id, card_brand, issuer, installments, interest_rate, min_amount
+		FROM installment_plans
+		WHERE id = $1 AND active = true
+	`
+
+	plan := &model.InstallmentPlan{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&plan.ID,
+		&plan.CardBrand,
+		&plan.Issuer,
+		&plan.Installments,
+		&plan.InterestRate,
+		&plan.MinAmount,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("installment plan not found")
+		}
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// FindByCard retrieves the installment plans offered for a card brand and issuer.
+// Plans registered without an issuer apply to every issuer of the card brand.
+func (r *PostgreSQLInstallmentPlanRepository) FindByCard(ctx context.Context, cardBrand, issuer string) ([]*model.InstallmentPlan, error) {
+	query := `
+		SELECT id, card_brand, issuer, installments, interest_rate, min_amount
+		FROM installment_plans
+		WHERE card_brand = $1 AND (issuer = $2 OR issuer = '') AND active = true
+		ORDER BY installments ASC, interest_rate ASC
+	`
+
+	return r.findMany(ctx, query, strings.ToLower(cardBrand), strings.ToLower(issuer))
+}
+
+// FindAll retrieves every active installment plan of the catalog
+func (r *PostgreSQLInstallmentPlanRepository) FindAll(ctx context.Context) ([]*model.InstallmentPlan, error) {
+	query := `
+		SELECT id, card_brand, issuer, installments, interest_rate, min_amount
+		FROM installment_plans
+		WHERE active = true
+		ORDER BY card_brand ASC, issuer ASC, installments ASC, interest_rate ASC
+	`
+
+	return r.findMany(ctx, query)
+}
+
+// findMany runs an installment plan query
+func (r *PostgreSQLInstallmentPlanRepository) findMany(ctx context.Context, query string, args ...interface{}) ([]*model.InstallmentPlan, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plans := make([]*model.InstallmentPlan, 0)
+
+	for rows.Next() {
+		plan := &model.InstallmentPlan{}
+		if err := rows.Scan(
+			&plan.ID,
+			&plan.CardBrand,
+			&plan.Issuer,
+			&plan.Installments,
+			&plan.InterestRate,
+			&plan.MinAmount,
+		); err != nil {
+			return nil, err
+		}
+
+		plans = append(plans, plan)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return plans, nil
+}
+
+// Save persists an installment plan (creates or updates)
+func (r *PostgreSQLInstallmentPlanRepository) Save(ctx context.Context, plan *model.InstallmentPlan) error {
+	query := `
+		INSERT INTO installment_plans (id, card_brand, issuer, installments, interest_rate, min_amount, active)
+		VALUES ($1, $2, $3, $4, $5, $6, true)
+		ON CONFLICT (id) DO UPDATE
+		SET card_brand = $2, issuer = $3, installments = $4, interest_rate = $5, min_amount = $6,
+			updated_at = now()
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		plan.ID,
+		plan.CardBrand,
+		plan.Issuer,
+		plan.Installments,
+		plan.InterestRate,
+		plan.MinAmount,
+	)
+
+	return err
+}
+
+// Deactivate removes an installment plan from the catalog
+func (r *PostgreSQLInstallmentPlanRepository) Deactivate(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE installment_plans
+		SET active = false, updated_at = now()
+		WHERE id = $1 AND active = true
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("installment plan not found")
+	}
+
+	return nil
+}
@@ -4,9 +4,11 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // ShippingAddressModel is the PostgreSQL representation of a shipping address
@@ -20,6 +22,8 @@ type ShippingAddressModel struct {
 	PostalCode    string    `gorm:"type:varchar(20);not null"`
 	Country       string    `gorm:"type:varchar(100);not null"`
 	Phone         string    `gorm:"type:varchar(20)"`
+	Latitude      *float64  `gorm:"type:double precision"`
+	Longitude     *float64  `gorm:"type:double precision"`
 	IsDefault     bool      `gorm:"default:false"`
 	CreatedAt     time.Time `gorm:"not null;default:now()"`
 	UpdatedAt     time.Time `gorm:"not null;default:now()"`
@@ -30,6 +34,29 @@ func (ShippingAddressModel) TableName() string {
 	return "shipping_addresses"
 }
 
+// StreetSegmentModel is the PostgreSQL representation of a segment of the street dataset.
+// MigrateStreetSearch adds the trigram index autocomplete searches search_name with.
+type StreetSegmentModel struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Street        string    `gorm:"type:varchar(150);not null"`
+	SearchName    string    `gorm:"type:varchar(150);not null;index:idx_street_segments_search"`
+	FromNumber    int       `gorm:"type:integer;not null"`
+	ToNumber      int       `gorm:"type:integer;not null"`
+	City          string    `gorm:"type:varchar(100);not null"`
+	SearchCity    string    `gorm:"type:varchar(100);not null"`
+	State         string    `gorm:"type:varchar(10);not null;index:idx_street_segments_state"`
+	PostalCode    string    `gorm:"type:varchar(20)"`
+	FromLatitude  float64   `gorm:"type:double precision;not null"`
+	FromLongitude float64   `gorm:"type:double precision;not null"`
+	ToLatitude    float64   `gorm:"type:double precision;not null"`
+	ToLongitude   float64   `gorm:"type:double precision;not null"`
+}
+
+// TableName overrides the table name for GORM
+func (StreetSegmentModel) TableName() string {
+	return "street_segments"
+}
+
 // ShippingMethodModel is the PostgreSQL representation of a shipping method
 type ShippingMethodModel struct {
 	ID            uuid.UUID `gorm:"type:uuid;primaryKey"`
@@ -47,23 +74,84 @@ func (ShippingMethodModel) TableName() string {
 	return "shipping_methods"
 }
 
+// InstallmentPlanModel is the PostgreSQL representation of an installment plan
+type InstallmentPlanModel struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey"`
+	CardBrand    string    `gorm:"type:varchar(50);not null;index:idx_installment_plans_card"`
+	Issuer       string    `gorm:"type:varchar(100);not null;default:'';index:idx_installment_plans_card"`
+	Installments int       `gorm:"type:integer;not null"`
+	InterestRate float64   `gorm:"type:decimal(6,4);not null;default:0"`
+	MinAmount    float64   `gorm:"type:decimal(10,2);not null;default:0"`
+	Active       bool      `gorm:"default:true"`
+	CreatedAt    time.Time `gorm:"not null;default:now()"`
+	UpdatedAt    time.Time `gorm:"not null;default:now()"`
+}
+
+// TableName overrides the table name for GORM
+func (InstallmentPlanModel) TableName() string {
+	return "installment_plans"
+}
+
+// GiftCardModel is the PostgreSQL representation of a gift card or store credit
+type GiftCardModel struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	Code           string     `gorm:"type:varchar(19);not null;uniqueIndex"`
+	Kind           string     `gorm:"type:varchar(20);not null"`
+	OwnerUserID    *uuid.UUID `gorm:"type:uuid;index"`
+	InitialBalance float64    `gorm:"type:decimal(10,2);not null"`
+	Balance        float64    `gorm:"type:decimal(10,2);not null;check:balance >= 0"`
+	ExpiresAt      *time.Time `gorm:"type:timestamp with time zone"`
+	CreatedAt      time.Time  `gorm:"not null;default:now()"`
+	UpdatedAt      time.Time  `gorm:"not null;default:now()"`
+}
+
+// TableName overrides the table name for GORM
+func (GiftCardModel) TableName() string {
+	return "gift_cards"
+}
+
+// GiftCardTransactionModel is the PostgreSQL representation of a gift card ledger entry
+type GiftCardTransactionModel struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	GiftCardID   uuid.UUID  `gorm:"type:uuid;not null;index;references:gift_cards(id)"`
+	CheckoutID   *uuid.UUID `gorm:"type:uuid;index"`
+	Type         string     `gorm:"type:varchar(20);not null"`
+	Amount       float64    `gorm:"type:decimal(10,2);not null"`
+	BalanceAfter float64    `gorm:"type:decimal(10,2);not null"`
+	CreatedAt    time.Time  `gorm:"not null;default:now()"`
+}
+
+// TableName overrides the table name for GORM
+func (GiftCardTransactionModel) TableName() string {
+	return "gift_card_transactions"
+}
+
 // CheckoutModel is the PostgreSQL representation of a checkout
 type CheckoutModel struct {
-	ID                uuid.UUID         `gorm:"type:uuid;primaryKey"`
-	UserID            uuid.UUID         `gorm:"type:uuid;not null"`
-	CartID            uuid.UUID         `gorm:"type:uuid;not null"`
-	ShippingAddressID *uuid.UUID        `gorm:"type:uuid;references:shipping_addresses(id)"`
-	ShippingMethodID  *uuid.UUID        `gorm:"type:uuid;references:shipping_methods(id)"`
-	PaymentMethod     string            `gorm:"type:varchar(50)"`
-	Subtotal          float64           `gorm:"type:decimal(10,2);not null"`
-	ShippingCost      float64           `gorm:"type:decimal(10,2);default:0"`
-	Tax               float64           `gorm:"type:decimal(10,2);default:0"`
-	Total             float64           `gorm:"type:decimal(10,2);not null"`
-	Status            string            `gorm:"type:varchar(20);not null;default:'PENDING'"`
-	Items             CheckoutItemsJSON `gorm:"type:jsonb"`
-	CreatedAt         time.Time         `gorm:"not null;default:now()"`
-	UpdatedAt         time.Time         `gorm:"not null;default:now()"`
-	CompletedAt       *time.Time        `gorm:"type:timestamp with time zone"`
+	ID                uuid.UUID           `gorm:"type:uuid;primaryKey"`
+	UserID            uuid.UUID           `gorm:"type:uuid;not null"`
+	CartID            uuid.UUID           `gorm:"type:uuid;not null"`
+	ShippingAddressID *uuid.UUID          `gorm:"type:uuid;references:shipping_addresses(id)"`
+	ShippingMethodID  *uuid.UUID          `gorm:"type:uuid;references:shipping_methods(id)"`
+	PaymentMethod     string              `gorm:"type:varchar(50)"`
+	Subtotal          float64             `gorm:"type:decimal(10,2);not null"`
+	ShippingCost      float64             `gorm:"type:decimal(10,2);default:0"`
+	Tax               float64             `gorm:"type:decimal(10,2);default:0"`
+	TaxRate           float64             `gorm:"type:decimal(5,4);default:0"`
+	FinancingCost     float64             `gorm:"type:decimal(10,2);default:0"`
+	Total             float64             `gorm:"type:decimal(10,2);not null"`
+	Status            string              `gorm:"type:varchar(20);not null;default:'PENDING'"`
+	Items             CheckoutItemsJSON   `gorm:"type:jsonb"`
+	GiftCards         GiftCardTendersJSON `gorm:"type:jsonb"`
+	Discounts         DiscountLinesJSON   `gorm:"type:jsonb"`
+	CashPayment       *string             `gorm:"type:jsonb"`
+	// PaymentCode is only unique among the checkouts awaiting payment; MigrateOpenPaymentCodes
+	// adds the index
+	PaymentCode  *string    `gorm:"type:varchar(12)"`
+	PaymentDueAt *time.Time `gorm:"type:timestamp with time zone;index"`
+	CreatedAt    time.Time  `gorm:"not null;default:now()"`
+	UpdatedAt    time.Time  `gorm:"not null;default:now()"`
+	CompletedAt  *time.Time `gorm:"type:timestamp with time zone"`
 }
 
 // TableName overrides the table name for GORM
@@ -76,12 +164,32 @@ type CheckoutItemsJSON []*CheckoutItemJSON
 
 // CheckoutItemJSON is the JSON representation of a checkout item
 type CheckoutItemJSON struct {
-	ProductID uuid.UUID `json:"productId"`
-	Name      string    `json:"name"`
-	Price     float64   `json:"price"`
-	Quantity  int       `json:"quantity"`
-	Subtotal  float64   `json:"subtotal"`
-	ImageURL  string    `json:"imageUrl"`
+	ProductID     uuid.UUID              `json:"productId"`
+	VariantID     string                 `json:"variantId,omitempty"`
+	Options       []ItemOptionJSON       `json:"options,omitempty"`
+	Name          string                 `json:"name"`
+	Price         float64                `json:"price"`
+	OriginalPrice float64                `json:"originalPrice,omitempty"`
+	Segment       string                 `json:"segment,omitempty"`
+	Quantity      int                    `json:"quantity"`
+	Subtotal      float64                `json:"subtotal"`
+	ImageURL      string                 `json:"imageUrl"`
+	Category      string                 `json:"category,omitempty"`
+	AddedBy       uuid.UUID              `json:"addedBy"`
+	Contributions []ItemContributionJSON `json:"contributions,omitempty"`
+}
+
+// ItemContributionJSON is the JSON representation of the part of a checkout item added by a user
+type ItemContributionJSON struct {
+	UserID   uuid.UUID `json:"userId"`
+	Quantity int       `json:"quantity"`
+}
+
+// ItemOptionJSON is the JSON representation of a checkout item option
+type ItemOptionJSON struct {
+	Name      string  `json:"name"`
+	Value     string  `json:"value"`
+	Surcharge float64 `json:"surcharge,omitempty"`
 }
 
 // Value implements the driver.Valuer interface for CheckoutItemsJSON
@@ -97,3 +205,150 @@ func (c *CheckoutItemsJSON) Scan(value interface{}) error {
 	}
 	return json.Unmarshal(b, &c)
 }
+
+// GiftCardTendersJSON is a custom type for storing the gift card tenders of a checkout as JSON in PostgreSQL
+type GiftCardTendersJSON []*GiftCardTenderJSON
+
+// GiftCardTenderJSON is the JSON representation of a gift card tender
+type GiftCardTenderJSON struct {
+	GiftCardID uuid.UUID `json:"giftCardId"`
+	MaskedCode string    `json:"maskedCode"`
+	Kind       string    `json:"kind"`
+	Amount     float64   `json:"amount"`
+}
+
+// Value implements the driver.Valuer interface for GiftCardTendersJSON
+func (g GiftCardTendersJSON) Value() (driver.Value, error) {
+	return json.Marshal(g)
+}
+
+// Scan implements the sql.Scanner interface for GiftCardTendersJSON
+func (g *GiftCardTendersJSON) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, &g)
+}
+
+// DiscountLinesJSON is a custom type for storing the discount lines of a checkout as JSON in PostgreSQL
+type DiscountLinesJSON []*DiscountLineJSON
+
+// DiscountLineJSON is the JSON representation of a discount line
+type DiscountLineJSON struct {
+	Source      string  `json:"source"`
+	Description string  `json:"description"`
+	Amount      float64 `json:"amount"`
+	Points      int     `json:"points,omitempty"`
+}
+
+// Value implements the driver.Valuer interface for DiscountLinesJSON
+func (d DiscountLinesJSON) Value() (driver.Value, error) {
+	return json.Marshal(d)
+}
+
+// Scan implements the sql.Scanner interface for DiscountLinesJSON
+func (d *DiscountLinesJSON) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, &d)
+}
+
+// MigrateStreetSearch adds a trigram index on the folded street names, so autocomplete can
+// match from the start of any word of a name without scanning the whole dataset. It needs
+// the pg_trgm extension, which it creates if missing. It is idempotent.
+func MigrateStreetSearch(db *gorm.DB) error {
+	if err := db.Exec(`CREATE EXTENSION IF NOT EXISTS pg_trgm`).Error; err != nil {
+		return err
+	}
+	return db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_street_segments_search_trgm
+		ON street_segments USING gin (search_name gin_trgm_ops)
+	`).Error
+}
+
+// openPaymentCodeIndex is the index keeping payment codes unique among the checkouts awaiting
+// payment. Codes of paid or cancelled checkouts can be issued again.
+const openPaymentCodeIndex = "idx_checkouts_open_payment_code"
+
+// MigrateOpenPaymentCodes replaces the index that kept payment codes unique over every
+// checkout with one that only covers the checkouts awaiting payment. It is idempotent.
+func MigrateOpenPaymentCodes(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`DROP INDEX IF EXISTS idx_checkouts_payment_code`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`
+			CREATE UNIQUE INDEX IF NOT EXISTS ` + openPaymentCodeIndex + `
+			ON checkouts (payment_code) WHERE status = 'AWAITING_PAYMENT'
+		`).Error
+	})
+}
+
+// MigrateLegacyPaymentDetails scrubs the free-form paymentDetails that checkouts stored before
+// payment methods had validated variants, since they may hold raw card numbers or security
+// codes. Checkouts that were not completed yet lose their payment method and go back to
+// SHIPPING_SELECTED, so the shopper selects one again; the others keep the payment type and
+// installments. It is idempotent.
+func MigrateLegacyPaymentDetails(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		reset := tx.Exec(`
+			UPDATE checkouts SET payment_method = NULL, status = 'SHIPPING_SELECTED', updated_at = now()
+			WHERE payment_method::text LIKE '%"paymentDetails"%' AND status IN ('INITIATED', 'SHIPPING_SELECTED', 'PAYMENT_SELECTED')
+		`)
+		if reset.Error != nil {
+			return reset.Error
+		}
+
+		rows, err := tx.Raw(`
+			SELECT id, payment_method::text FROM checkouts
+			WHERE payment_method::text LIKE '%"paymentDetails"%'
+		`).Rows()
+		if err != nil {
+			return err
+		}
+
+		redacted := make(map[uuid.UUID]string)
+		for rows.Next() {
+			var (
+				id            uuid.UUID
+				paymentMethod string
+			)
+			if err := rows.Scan(&id, &paymentMethod); err != nil {
+				rows.Close()
+				return err
+			}
+
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(paymentMethod), &fields); err != nil {
+				rows.Close()
+				return err
+			}
+			delete(fields, "paymentDetails")
+
+			scrubbed, err := json.Marshal(fields)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			redacted[id] = string(scrubbed)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for id, paymentMethod := range redacted {
+			if err := tx.Exec(`UPDATE checkouts SET payment_method = ? WHERE id = ?`, paymentMethod, id).Error; err != nil {
+				return err
+			}
+		}
+
+		if reset.RowsAffected+int64(len(redacted)) > 0 {
+			log.Printf("Removed the legacy payment details of %d checkouts", reset.RowsAffected+int64(len(redacted)))
+		}
+		return nil
+	})
+}
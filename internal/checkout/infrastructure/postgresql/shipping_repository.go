@@ -26,7 +26,7 @@ func NewPostgreSQLShippingRepository(db *sql.DB) repository.ShippingRepository {
 func (r *PostgreSQLShippingRepository) FindAddressByID(ctx context.Context, id uuid.UUID) (*model.ShippingAddress, error) {
 	query := `
 		SELECT id, user_id, first_name, last_name, street_address, apartment, city, state, 
-		       postal_code, country, phone_number, is_default, created_at, updated_at
+		       postal_code, country, phone_number, latitude, longitude, is_default, created_at, updated_at
 		FROM shipping_addresses
 		WHERE id = $1
 	`
@@ -43,6 +43,8 @@ func (r *PostgreSQLShippingRepository) FindAddressByID(ctx context.Context, id u
 		postalCode    string
 		country       string
 		phoneNumber   string
+		latitude      sql.NullFloat64
+		longitude     sql.NullFloat64
 		isDefault     bool
 		createdAt     sql.NullTime
 		updatedAt     sql.NullTime
@@ -60,6 +62,8 @@ func (r *PostgreSQLShippingRepository) FindAddressByID(ctx context.Context, id u
 		&postalCode,
 		&country,
 		&phoneNumber,
+		&latitude,
+		&longitude,
 		&isDefault,
 		&createdAt,
 		&updatedAt,
@@ -89,6 +93,7 @@ func (r *PostgreSQLShippingRepository) FindAddressByID(ctx context.Context, id u
 		PostalCode:    postalCode,
 		Country:       country,
 		PhoneNumber:   phoneNumber,
+		Location:      scanLocation(latitude, longitude),
 		IsDefault:     isDefault,
 		CreatedAt:     createdAt.Time,
 		UpdatedAt:     updatedAt.Time,
@@ -101,7 +106,7 @@ func (r *PostgreSQLShippingRepository) FindAddressByID(ctx context.Context, id u
 func (r *PostgreSQLShippingRepository) FindAddressesByUserID(ctx context.Context, userID uuid.UUID) ([]*model.ShippingAddress, error) {
 	query := `
 		SELECT id, user_id, first_name, last_name, street_address, apartment, city, state, 
-		       postal_code, country, phone_number, is_default, created_at, updated_at
+		       postal_code, country, phone_number, latitude, longitude, is_default, created_at, updated_at
 		FROM shipping_addresses
 		WHERE user_id = $1
 		ORDER BY is_default DESC, created_at DESC
@@ -128,6 +133,8 @@ func (r *PostgreSQLShippingRepository) FindAddressesByUserID(ctx context.Context
 			postalCode    string
 			country       string
 			phoneNumber   string
+			latitude      sql.NullFloat64
+			longitude     sql.NullFloat64
 			isDefault     bool
 			createdAt     sql.NullTime
 			updatedAt     sql.NullTime
@@ -145,6 +152,8 @@ func (r *PostgreSQLShippingRepository) FindAddressesByUserID(ctx context.Context
 			&postalCode,
 			&country,
 			&phoneNumber,
+			&latitude,
+			&longitude,
 			&isDefault,
 			&createdAt,
 			&updatedAt,
@@ -169,6 +178,7 @@ func (r *PostgreSQLShippingRepository) FindAddressesByUserID(ctx context.Context
 			PostalCode:    postalCode,
 			Country:       country,
 			PhoneNumber:   phoneNumber,
+			Location:      scanLocation(latitude, longitude),
 			IsDefault:     isDefault,
 			CreatedAt:     createdAt.Time,
 			UpdatedAt:     updatedAt.Time,
@@ -189,13 +199,13 @@ func (r *PostgreSQLShippingRepository) SaveAddress(ctx context.Context, address
 	query := `
 		INSERT INTO shipping_addresses (
 			id, user_id, first_name, last_name, street_address, apartment, city, state,
-			postal_code, country, phone_number, is_default, created_at, updated_at
+			postal_code, country, phone_number, latitude, longitude, is_default, created_at, updated_at
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		ON CONFLICT (id) DO UPDATE
 		SET first_name = $3, last_name = $4, street_address = $5, apartment = $6,
 			city = $7, state = $8, postal_code = $9, country = $10,
-			phone_number = $11, is_default = $12, updated_at = $14
+			phone_number = $11, latitude = $12, longitude = $13, is_default = $14, updated_at = $16
 	`
 
 	apartment := sql.NullString{
@@ -203,6 +213,12 @@ func (r *PostgreSQLShippingRepository) SaveAddress(ctx context.Context, address
 		Valid:  address.Apartment != "",
 	}
 
+	var latitude, longitude sql.NullFloat64
+	if address.Location != nil {
+		latitude = sql.NullFloat64{Float64: address.Location.Latitude, Valid: true}
+		longitude = sql.NullFloat64{Float64: address.Location.Longitude, Valid: true}
+	}
+
 	_, err := r.db.ExecContext(
 		ctx,
 		query,
@@ -217,6 +233,8 @@ func (r *PostgreSQLShippingRepository) SaveAddress(ctx context.Context, address
 		address.PostalCode,
 		address.Country,
 		address.PhoneNumber,
+		latitude,
+		longitude,
 		address.IsDefault,
 		address.CreatedAt,
 		address.UpdatedAt,
@@ -225,6 +243,14 @@ func (r *PostgreSQLShippingRepository) SaveAddress(ctx context.Context, address
 	return err
 }
 
+// scanLocation converts the nullable latitude and longitude columns of an address to its location
+func scanLocation(latitude, longitude sql.NullFloat64) *model.Coordinates {
+	if !latitude.Valid || !longitude.Valid {
+		return nil
+	}
+	return &model.Coordinates{Latitude: latitude.Float64, Longitude: longitude.Float64}
+}
+
 // DeleteAddress removes a shipping address
 func (r *PostgreSQLShippingRepository) DeleteAddress(ctx context.Context, id uuid.UUID) error {
 	query := `DELETE FROM shipping_addresses WHERE id = $1`
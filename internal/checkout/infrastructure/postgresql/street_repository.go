@@ -0,0 +1,161 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/repository"
+)
+
+// streetSegmentColumns lists the columns read into a street segment, in scan order
+const streetSegmentColumns = `
+	id, street, from_number, to_number, city, state, postal_code,
+	from_latitude, from_longitude, to_latitude, to_longitude
+`
+
+// streetSegmentFilter matches the segments of a StreetQuery: $1 is the name, $2 the door
+// number (0 for any), $3 the province (empty for any) and $4 the city prefix (empty for any).
+// The name patterns are served by the trigram index MigrateStreetSearch adds; names have at
+// least 3 letters, so every pattern has a trigram to look up.
+const streetSegmentFilter = `
+	WHERE (search_name LIKE $1::text || '%' OR search_name LIKE '% ' || $1::text || '%')
+	  AND ($2::integer = 0 OR (from_number <= $2 AND to_number >= $2))
+	  AND ($3::text = '' OR state = $3)
+	  AND ($4::text = '' OR search_city LIKE $4::text || '%')
+`
+
+// streetSegmentOrder ranks the streets whose name starts with the query first, then the shortest names
+const streetSegmentOrder = `
+	ORDER BY (search_name LIKE $1::text || '%') DESC, length(search_name), search_name, city, from_number
+	LIMIT $5
+`
+
+// PostgreSQLStreetRepository implements the StreetRepository interface using PostgreSQL
+type PostgreSQLStreetRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLStreetRepository creates a new PostgreSQL repository for the street dataset
+func NewPostgreSQLStreetRepository(db *sql.DB) repository.StreetRepository {
+	return &PostgreSQLStreetRepository{
+		db: db,
+	}
+}
+
+// Search retrieves the street segments matching a query
+func (r *PostgreSQLStreetRepository) Search(ctx context.Context, query *repository.StreetQuery) ([]*model.StreetSegment, error) {
+	sqlQuery := `SELECT ` + streetSegmentColumns + ` FROM street_segments ` + streetSegmentFilter + streetSegmentOrder
+	if query.Number == 0 {
+		// Only the first segment of every street
+		sqlQuery = `
+			SELECT ` + streetSegmentColumns + ` FROM (
+				SELECT DISTINCT ON (search_name, search_city, state) *
+				FROM street_segments
+				` + streetSegmentFilter + `
+				ORDER BY search_name, search_city, state, from_number
+			) streets
+		` + streetSegmentOrder
+	}
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, query.Name, query.Number, query.State, model.FoldName(query.City), query.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	segments := make([]*model.StreetSegment, 0)
+	for rows.Next() {
+		segment, err := scanStreetSegment(rows)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, segment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return segments, nil
+}
+
+// ReplaceAll replaces the whole dataset with the given segments in a single transaction
+func (r *PostgreSQLStreetRepository) ReplaceAll(ctx context.Context, segments []*model.StreetSegment) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM street_segments`); err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO street_segments (
+			id, street, search_name, from_number, to_number, city, search_city, state, postal_code,
+			from_latitude, from_longitude, to_latitude, to_longitude
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, segment := range segments {
+		if _, err := stmt.ExecContext(
+			ctx,
+			segment.ID,
+			segment.Street,
+			model.StreetSearchName(segment.Street),
+			segment.FromNumber,
+			segment.ToNumber,
+			segment.City,
+			model.FoldName(segment.City),
+			segment.State,
+			segment.PostalCode,
+			segment.From.Latitude,
+			segment.From.Longitude,
+			segment.To.Latitude,
+			segment.To.Longitude,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// scanStreetSegment scans a row selected with streetSegmentColumns into a street segment
+func scanStreetSegment(row rowScanner) (*model.StreetSegment, error) {
+	var (
+		segment    model.StreetSegment
+		postalCode sql.NullString
+		from       model.Coordinates
+		to         model.Coordinates
+	)
+
+	if err := row.Scan(
+		&segment.ID,
+		&segment.Street,
+		&segment.FromNumber,
+		&segment.ToNumber,
+		&segment.City,
+		&segment.State,
+		&postalCode,
+		&from.Latitude,
+		&from.Longitude,
+		&to.Latitude,
+		&to.Longitude,
+	); err != nil {
+		return nil, err
+	}
+
+	segment.PostalCode = postalCode.String
+	segment.From = &from
+	segment.To = &to
+
+	return &segment, nil
+}
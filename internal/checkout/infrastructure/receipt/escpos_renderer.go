@@ -0,0 +1,63 @@
+package receipt
+
+import (
+	"bytes"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/repository"
+)
+
+// escposColumns is the amount of characters per line of font A on 80 mm paper
+const escposColumns = 48
+
+// ESC/POS commands used by the renderer
+var (
+	escposInitialize   = []byte{0x1b, 0x40}       // ESC @
+	escposCodePage1252 = []byte{0x1b, 0x74, 0x10} // ESC t 16: WPC1252
+	escposBoldOn       = []byte{0x1b, 0x45, 0x01} // ESC E 1
+	escposBoldOff      = []byte{0x1b, 0x45, 0x00} // ESC E 0
+	escposFeed         = []byte{0x1b, 0x64, 0x04} // ESC d 4: feed 4 lines
+	escposPartialCut   = []byte{0x1d, 0x56, 0x01} // GS V 1
+)
+
+// ESCPOSRenderer renders receipts as a byte stream for the thermal printer of the kiosk
+type ESCPOSRenderer struct{}
+
+// NewESCPOSRenderer creates a new ESC/POS receipt renderer
+func NewESCPOSRenderer() repository.ReceiptRenderer {
+	return &ESCPOSRenderer{}
+}
+
+// ContentType returns the media type of ESC/POS byte streams, which have no registered type
+func (r *ESCPOSRenderer) ContentType() string {
+	return "application/octet-stream"
+}
+
+// Render returns the printer commands that print the receipt and cut the paper
+func (r *ESCPOSRenderer) Render(receipt *model.Receipt) ([]byte, error) {
+	var stream bytes.Buffer
+	stream.Write(escposInitialize)
+	stream.Write(escposCodePage1252)
+
+	bold := false
+	for _, line := range layout(receipt, escposColumns) {
+		if line.Bold != bold {
+			bold = line.Bold
+			if bold {
+				stream.Write(escposBoldOn)
+			} else {
+				stream.Write(escposBoldOff)
+			}
+		}
+		stream.Write(latin1(line.Text))
+		stream.WriteByte('\n')
+	}
+	if bold {
+		stream.Write(escposBoldOff)
+	}
+
+	stream.Write(escposFeed)
+	stream.Write(escposPartialCut)
+
+	return stream.Bytes(), nil
+}
@@ -0,0 +1,194 @@
+package receipt
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
+)
+
+// storeName is printed at the top of every receipt
+const storeName = "KIOSKO FIUBA"
+
+// line is a line of text of a receipt, already padded to the receipt width
+type line struct {
+	Text string
+	Bold bool
+}
+
+// layout lays out a receipt as lines of monospaced text of the given width. Both the PDF
+// and the thermal printer render the same layout, so they always show the same content.
+func layout(receipt *model.Receipt, width int) []line {
+	l := &layouter{width: width}
+
+	l.center(storeName, true)
+	if receipt.Refunded {
+		l.center("REFUNDED", true)
+	}
+	l.center("Order "+receipt.OrderCode, true)
+	l.center(receipt.IssuedAt.Format("2006-01-02 15:04"), false)
+	l.rule()
+
+	for _, item := range receipt.Lines {
+		l.wrap(item.Name, "")
+		for _, detail := range item.Details {
+			l.wrap(detail, "  ")
+		}
+		l.columns(fmt.Sprintf("  %d x %s", item.Quantity, money(item.UnitPrice)), money(item.Subtotal), false)
+	}
+	l.rule()
+
+	l.columns("Subtotal", money(receipt.Subtotal), false)
+	for _, discount := range receipt.Discounts {
+		l.columns(discount.Description, money(-discount.Amount), false)
+	}
+	if receipt.ShippingMethod != "" || receipt.ShippingCost != 0 {
+		label := "Shipping"
+		if receipt.ShippingMethod != "" {
+			label += " (" + receipt.ShippingMethod + ")"
+		}
+		l.columns(label, money(receipt.ShippingCost), false)
+	}
+
+	l.rule()
+	l.left("Tax breakdown", true)
+	l.columns(fmt.Sprintf("IVA %s%% on %s", percentage(receipt.TaxRate), money(receipt.TaxBase)), money(receipt.Tax), false)
+	if receipt.FinancingCost != 0 {
+		l.columns("Financing", money(receipt.FinancingCost), false)
+	}
+	l.rule()
+	l.columns("TOTAL", money(receipt.Total), true)
+
+	if len(receipt.Payments) > 0 {
+		l.rule()
+		l.left("Payment", true)
+		for _, payment := range receipt.Payments {
+			l.columns(payment.Label, money(payment.Amount), false)
+		}
+	}
+
+	if len(receipt.ShipTo) > 0 {
+		l.rule()
+		l.left("Ship to", true)
+		for _, text := range receipt.ShipTo {
+			l.wrap(text, "")
+		}
+	}
+
+	l.rule()
+	l.center("Show this code at pickup", false)
+	l.center(receipt.OrderCode, true)
+	l.center("Thank you for your purchase", false)
+
+	return l.lines
+}
+
+// layouter accumulates the lines of a receipt
+type layouter struct {
+	width int
+	lines []line
+}
+
+// left adds a left-aligned line, cut to the width
+func (l *layouter) left(text string, bold bool) {
+	l.lines = append(l.lines, line{Text: pad(truncate(text, l.width), l.width), Bold: bold})
+}
+
+// center adds a centered line, cut to the width
+func (l *layouter) center(text string, bold bool) {
+	text = truncate(text, l.width)
+	margin := (l.width - utf8.RuneCountInString(text)) / 2
+	l.lines = append(l.lines, line{Text: pad(strings.Repeat(" ", margin)+text, l.width), Bold: bold})
+}
+
+// columns adds a line with a label on the left and an amount on the right. Labels too
+// long to fit are cut.
+func (l *layouter) columns(label, amount string, bold bool) {
+	room := l.width - utf8.RuneCountInString(amount) - 1
+	label = pad(truncate(label, room), room)
+	l.lines = append(l.lines, line{Text: label + " " + amount, Bold: bold})
+}
+
+// wrap adds a left-aligned text over as many lines as needed, indenting the lines
+func (l *layouter) wrap(text, indent string) {
+	room := l.width - utf8.RuneCountInString(indent)
+	current := ""
+	for _, word := range strings.Fields(text) {
+		for utf8.RuneCountInString(word) > room {
+			if current != "" {
+				l.left(indent+current, false)
+				current = ""
+			}
+			runes := []rune(word)
+			l.left(indent+string(runes[:room]), false)
+			word = string(runes[room:])
+		}
+		switch {
+		case current == "":
+			current = word
+		case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) <= room:
+			current += " " + word
+		default:
+			l.left(indent+current, false)
+			current = word
+		}
+	}
+	if current != "" {
+		l.left(indent+current, false)
+	}
+}
+
+// rule adds a separator line
+func (l *layouter) rule() {
+	l.lines = append(l.lines, line{Text: strings.Repeat("-", l.width)})
+}
+
+// money formats an amount of pesos
+func money(amount float64) string {
+	if amount < 0 {
+		return fmt.Sprintf("-$%.2f", -amount)
+	}
+	return fmt.Sprintf("$%.2f", amount)
+}
+
+// percentage formats a rate as a percentage without trailing zeros (0.21 -> "21", 0.105 -> "10.5")
+func percentage(rate float64) string {
+	text := fmt.Sprintf("%.2f", rate*100)
+	text = strings.TrimRight(text, "0")
+	return strings.TrimSuffix(text, ".")
+}
+
+// truncate cuts a text to at most width characters
+func truncate(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width])
+}
+
+// pad fills a text with spaces up to width characters
+func pad(text string, width int) string {
+	if n := utf8.RuneCountInString(text); n < width {
+		return text + strings.Repeat(" ", width-n)
+	}
+	return text
+}
+
+// latin1 encodes a text in the single-byte code page shared by the PDF standard fonts and
+// the printer (Windows-1252 matches Latin-1 for the Spanish letters). Characters outside it
+// are printed as "?".
+func latin1(text string) []byte {
+	encoded := make([]byte, 0, len(text))
+	for _, r := range text {
+		if r < 0x20 || (r >= 0x7f && r < 0xa0) || r > 0xff {
+			r = '?'
+		}
+		encoded = append(encoded, byte(r))
+	}
+	return encoded
+}
@@ -0,0 +1,103 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/repository"
+)
+
+const (
+	// pdfColumns is the width of the PDF receipt in characters
+	pdfColumns = 48
+	// pdfFontSize is the size of the Courier font, in points
+	pdfFontSize = 9
+	// pdfLeading is the distance between lines, in points
+	pdfLeading = 11
+	// pdfMargin is the margin around the text, in points
+	pdfMargin = 18
+)
+
+// PDFRenderer renders receipts as a single-page PDF shaped like a till roll. It only uses
+// the standard Courier fonts, so the documents need no embedded fonts and are byte-for-byte
+// the same for the same receipt.
+type PDFRenderer struct{}
+
+// NewPDFRenderer creates a new PDF receipt renderer
+func NewPDFRenderer() repository.ReceiptRenderer {
+	return &PDFRenderer{}
+}
+
+// ContentType returns the media type of PDF documents
+func (r *PDFRenderer) ContentType() string {
+	return "application/pdf"
+}
+
+// Render returns the receipt as a PDF document
+func (r *PDFRenderer) Render(receipt *model.Receipt) ([]byte, error) {
+	lines := layout(receipt, pdfColumns)
+
+	// Courier characters are 0.6 em wide
+	width := pdfColumns*pdfFontSize*0.6 + 2*pdfMargin
+	height := float64(len(lines)*pdfLeading + 2*pdfMargin)
+
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "BT\n%d TL\n%d %.2f Td\n", pdfLeading, pdfMargin, height-pdfMargin-pdfFontSize)
+	bold := false
+	fmt.Fprintf(&content, "/F1 %d Tf\n", pdfFontSize)
+	for _, line := range lines {
+		if line.Bold != bold {
+			bold = line.Bold
+			font := "F1"
+			if bold {
+				font = "F2"
+			}
+			fmt.Fprintf(&content, "/%s %d Tf\n", font, pdfFontSize)
+		}
+		content.WriteByte('(')
+		content.Write(escapePDFString(latin1(line.Text)))
+		content.WriteString(") Tj T*\n")
+	}
+	content.WriteString("ET\n")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>", width, height),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var document bytes.Buffer
+	document.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = document.Len()
+		fmt.Fprintf(&document, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := document.Len()
+	fmt.Fprintf(&document, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&document, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&document, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return document.Bytes(), nil
+}
+
+// escapePDFString escapes the characters with a special meaning in PDF literal strings
+func escapePDFString(text []byte) []byte {
+	escaped := make([]byte, 0, len(text))
+	for _, b := range text {
+		if b == '(' || b == ')' || b == '\\' {
+			escaped = append(escaped, '\\')
+		}
+		escaped = append(escaped, b)
+	}
+	return escaped
+}
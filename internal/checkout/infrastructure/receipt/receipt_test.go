@@ -0,0 +1,117 @@
+package receipt
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/checkout/domain/repository"
+)
+
+// update rewrites the golden files with the current output: go test ./internal/checkout/infrastructure/receipt -update
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// goldenReceipts are the receipts rendered against the golden files, by file name
+var goldenReceipts = map[string]*model.Receipt{
+	"completed": {
+		OrderCode:  "7QK2M9XD",
+		CheckoutID: uuid.MustParse("6f1c2a9e-3b7d-4e52-9a41-0c8d5e7f2b13"),
+		IssuedAt:   time.Date(2026, time.March, 14, 18, 5, 0, 0, time.UTC),
+		Lines: []*model.ReceiptLine{
+			{
+				Name:      "Café con leche",
+				Details:   []string{"Size: Grande", "Extra shot (+$ 300,00)"},
+				Quantity:  2,
+				UnitPrice: 2150,
+				Subtotal:  4300,
+			},
+			{
+				Name:      "Medialunas de manteca (docena) con dulce de leche y crema pastelera",
+				Quantity:  1,
+				UnitPrice: 5400.5,
+				Subtotal:  5400.5,
+			},
+		},
+		Subtotal: 9700.5,
+		Discounts: []*model.DiscountLine{
+			{Source: model.DiscountSourceLoyaltyPoints, Description: "Loyalty points redeemed", Amount: 500, Points: 500},
+		},
+		ShippingMethod: "Envío a domicilio",
+		ShippingCost:   1200,
+		TaxBase:        10400.5,
+		TaxRate:        0.21,
+		Tax:            2184.11,
+		FinancingCost:  350,
+		Total:          12934.61,
+		Payments: []*model.ReceiptPayment{
+			{Label: "Gift card ****4821", Amount: 2000},
+			{Label: "Visa ****1234 (3 installments)", Amount: 10934.61},
+		},
+		ShipTo: []string{"María Pérez", "Av. Paseo Colón 850, Piso 2 (oficina)", "C1063ACV Ciudad Autónoma de Buenos Aires"},
+	},
+	"refunded": {
+		OrderCode:  "B4T8N2PW",
+		CheckoutID: uuid.MustParse("0a7e4c1d-9f26-4b38-8d15-3e6b2f9c4a70"),
+		Refunded:   true,
+		IssuedAt:   time.Date(2026, time.April, 2, 9, 30, 0, 0, time.UTC),
+		Lines: []*model.ReceiptLine{
+			{Name: "Alfajor triple", Quantity: 3, UnitPrice: 1100, Subtotal: 3300},
+		},
+		Subtotal: 3300,
+		TaxBase:  3300,
+		TaxRate:  0.21,
+		Tax:      693,
+		Total:    3993,
+		Payments: []*model.ReceiptPayment{
+			{Label: "Cash", Amount: 3993},
+		},
+	},
+}
+
+func TestPDFRendererGolden(t *testing.T) {
+	testGolden(t, NewPDFRenderer(), ".pdf")
+}
+
+func TestESCPOSRendererGolden(t *testing.T) {
+	testGolden(t, NewESCPOSRenderer(), ".escpos")
+}
+
+// testGolden renders every golden receipt and compares it with its golden file
+func testGolden(t *testing.T, renderer repository.ReceiptRenderer, extension string) {
+	for name, receipt := range goldenReceipts {
+		t.Run(name, func(t *testing.T) {
+			got, err := renderer.Render(receipt)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+
+			path := filepath.Join("testdata", name+extension)
+			if *update {
+				if err := os.WriteFile(path, got, 0o644); err != nil {
+					t.Fatalf("writing %s: %v", path, err)
+				}
+			}
+
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("reading %s: %v (run with -update to create it)", path, err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("Render() does not match %s (run with -update if the change is intended)\ngot:\n%q\nwant:\n%q", path, got, want)
+			}
+
+			again, err := renderer.Render(receipt)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if !bytes.Equal(got, again) {
+				t.Errorf("Render() is not deterministic")
+			}
+		})
+	}
+}
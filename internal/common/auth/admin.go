@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
+)
+
+// AdminKeyHeader carries the API key of back-office requests, such as managing catalogs
+// or kiosk terminals
+const AdminKeyHeader = "X-Admin-Key"
+
+// adminKey is the context key marking requests made by the back office
+type adminKey struct{}
+
+// IsAdmin reports whether a request was made by the back office
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey{}).(bool)
+	return admin
+}
+
+// RequireAdmin returns a middleware that only lets through the requests carrying the admin
+// API key. When no key is configured every request is rejected, so admin routes are closed
+// by default.
+func RequireAdmin(apiKey string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(AdminKeyHeader)
+			if key == "" {
+				errors.WriteErrorResponse(w, http.StatusUnauthorized, "admin key is required")
+				return
+			}
+			if !validAdminKey(key, apiKey) {
+				errors.WriteErrorResponse(w, http.StatusForbidden, "invalid admin key")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), adminKey{}, true)))
+		})
+	}
+}
+
+// validAdminKey compares a request key with the configured one in constant time
+func validAdminKey(key string, apiKey string) bool {
+	return apiKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
+)
+
+// UserHeader names the user on whose behalf a request is made
+const UserHeader = "X-User-ID"
+
+// userKey is the context key of the user making a request
+type userKey struct{}
+
+// WithUser returns a context carrying the user on whose behalf a request is made
+func WithUser(ctx context.Context, userID uuid.UUID) context.Context {
+	return context.WithValue(ctx, userKey{}, userID)
+}
+
+// UserFrom returns the user making a request, and false if the context carries none
+func UserFrom(ctx context.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Value(userKey{}).(uuid.UUID)
+	return userID, ok
+}
+
+// RequireUser is a middleware that puts the user named in the user header in the request
+// context. Requests without it are rejected, so services can check who owns what they touch.
+func RequireUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get(UserHeader)
+		if header == "" {
+			errors.WriteErrorResponse(w, http.StatusUnauthorized, "user ID is required")
+			return
+		}
+
+		userID, err := uuid.Parse(header)
+		if err != nil {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, "invalid user ID format")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), userID)))
+	})
+}
+
+// RequireUserOrAdmin returns a middleware for routes open to both users and the back office.
+// Requests carrying the admin key are let through as admin when the key is valid; any other
+// request must name its user, as with RequireUser.
+func RequireUserOrAdmin(apiKey string) func(http.Handler) http.Handler {
+	requireAdmin := RequireAdmin(apiKey)
+	return func(next http.Handler) http.Handler {
+		asAdmin := requireAdmin(next)
+		asUser := RequireUser(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get(AdminKeyHeader) != "" {
+				asAdmin.ServeHTTP(w, r)
+				return
+			}
+			asUser.ServeHTTP(w, r)
+		})
+	}
+}
+
+// CanActFor reports whether a request may act on what belongs to the given user: the back
+// office can act for anyone, and users only for themselves
+func CanActFor(ctx context.Context, userID uuid.UUID) bool {
+	if IsAdmin(ctx) {
+		return true
+	}
+	actor, ok := UserFrom(ctx)
+	return ok && actor == userID
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -29,6 +31,51 @@ type Config struct {
 
 	// External services
 	ProductCatalogServiceURL string
+
+	// Admin configuration
+	AdminAPIKey string
+
+	// Loyalty program configuration
+	LoyaltyDefaultEarnRate   float64
+	LoyaltyCategoryEarnRates map[string]float64
+	LoyaltyPointValue        float64
+	LoyaltyPointsLifetime    time.Duration
+
+	// Cart configuration
+	CartMaxDistinctLines int
+
+	// Checkout configuration
+	CheckoutCashPaymentWindow        time.Duration
+	CheckoutCashPaymentSweepInterval time.Duration
+
+	// Kiosk configuration
+	KioskSessionIdleTimeout   time.Duration
+	KioskSessionSweepInterval time.Duration
+
+	// Invoicing configuration
+	InvoicingSellerTaxCondition         string
+	InvoicingPointOfSale                int
+	InvoicingAuthorizationRetryInterval time.Duration
+
+	// Notification configuration
+	NotificationSender                     string
+	NotificationFrom                       string
+	NotificationOutboxDir                  string
+	NotificationDeliveryInterval           time.Duration
+	NotificationMaxAttempts                int
+	NotificationRetryBaseDelay             time.Duration
+	NotificationAbandonedCartAfter         time.Duration
+	NotificationAbandonedCartSweepInterval time.Duration
+	SMTPHost                               string
+	SMTPPort                               int
+	SMTPUsername                           string
+	SMTPPassword                           string
+
+	// Webhook configuration
+	WebhookDeliveryInterval time.Duration
+	WebhookMaxAttempts      int
+	WebhookRetryBaseDelay   time.Duration
+	WebhookRequestTimeout   time.Duration
 }
 
 // LoadConfig loads the configuration from environment variables with appropriate prefixes
@@ -53,6 +100,35 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("SHOPPING_EXPERIENCE_DB_NAME", "shopping_experience")
 	viper.SetDefault("SHOPPING_EXPERIENCE_DB_SSLMODE", "disable")
 	viper.SetDefault("PRODUCT_CATALOG_SERVICE_URL", "http://localhost:8000")
+	viper.SetDefault("SHOPPING_EXPERIENCE_ADMIN_API_KEY", "")
+	viper.SetDefault("LOYALTY_DEFAULT_EARN_RATE", 0.01)
+	viper.SetDefault("LOYALTY_CATEGORY_EARN_RATES", "")
+	viper.SetDefault("LOYALTY_POINT_VALUE", 1.0)
+	viper.SetDefault("LOYALTY_POINTS_LIFETIME", "8760h")
+	viper.SetDefault("CART_MAX_DISTINCT_LINES", 50)
+	viper.SetDefault("CHECKOUT_CASH_PAYMENT_WINDOW", "24h")
+	viper.SetDefault("CHECKOUT_CASH_PAYMENT_SWEEP_INTERVAL", "5m")
+	viper.SetDefault("KIOSK_SESSION_IDLE_TIMEOUT", "5m")
+	viper.SetDefault("KIOSK_SESSION_SWEEP_INTERVAL", "1m")
+	viper.SetDefault("INVOICING_SELLER_TAX_CONDITION", "RESPONSABLE_INSCRIPTO")
+	viper.SetDefault("INVOICING_POINT_OF_SALE", 1)
+	viper.SetDefault("INVOICING_AUTHORIZATION_RETRY_INTERVAL", "5m")
+	viper.SetDefault("NOTIFICATION_SENDER", "outbox")
+	viper.SetDefault("NOTIFICATION_FROM", "Kiosko FIUBA <no-reply@kiosko.fi.uba.ar>")
+	viper.SetDefault("NOTIFICATION_OUTBOX_DIR", "./outbox")
+	viper.SetDefault("NOTIFICATION_DELIVERY_INTERVAL", "30s")
+	viper.SetDefault("NOTIFICATION_MAX_ATTEMPTS", 6)
+	viper.SetDefault("NOTIFICATION_RETRY_BASE_DELAY", "1m")
+	viper.SetDefault("NOTIFICATION_ABANDONED_CART_AFTER", "24h")
+	viper.SetDefault("NOTIFICATION_ABANDONED_CART_SWEEP_INTERVAL", "15m")
+	viper.SetDefault("SMTP_HOST", "localhost")
+	viper.SetDefault("SMTP_PORT", 587)
+	viper.SetDefault("SMTP_USERNAME", "")
+	viper.SetDefault("SMTP_PASSWORD", "")
+	viper.SetDefault("WEBHOOK_DELIVERY_INTERVAL", "10s")
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+	viper.SetDefault("WEBHOOK_RETRY_BASE_DELAY", "30s")
+	viper.SetDefault("WEBHOOK_REQUEST_TIMEOUT", "10s")
 
 	// 3. Get configuration values from environment variables
 	viper.AutomaticEnv()
@@ -78,20 +154,119 @@ func LoadConfig() (*Config, error) {
 		shutdownTimeout = 15 * time.Second
 	}
 
+	loyaltyPointsLifetime, err := time.ParseDuration(viper.GetString("LOYALTY_POINTS_LIFETIME"))
+	if err != nil {
+		loyaltyPointsLifetime = 365 * 24 * time.Hour
+	}
+
+	checkoutCashPaymentWindow, err := time.ParseDuration(viper.GetString("CHECKOUT_CASH_PAYMENT_WINDOW"))
+	if err != nil {
+		checkoutCashPaymentWindow = 24 * time.Hour
+	}
+
+	checkoutCashPaymentSweepInterval, err := time.ParseDuration(viper.GetString("CHECKOUT_CASH_PAYMENT_SWEEP_INTERVAL"))
+	if err != nil || checkoutCashPaymentSweepInterval <= 0 {
+		checkoutCashPaymentSweepInterval = 5 * time.Minute
+	}
+
+	kioskSessionIdleTimeout, err := time.ParseDuration(viper.GetString("KIOSK_SESSION_IDLE_TIMEOUT"))
+	if err != nil {
+		kioskSessionIdleTimeout = 5 * time.Minute
+	}
+
+	kioskSessionSweepInterval, err := time.ParseDuration(viper.GetString("KIOSK_SESSION_SWEEP_INTERVAL"))
+	if err != nil || kioskSessionSweepInterval <= 0 {
+		kioskSessionSweepInterval = time.Minute
+	}
+
+	invoicingAuthorizationRetryInterval, err := time.ParseDuration(viper.GetString("INVOICING_AUTHORIZATION_RETRY_INTERVAL"))
+	if err != nil || invoicingAuthorizationRetryInterval <= 0 {
+		invoicingAuthorizationRetryInterval = 5 * time.Minute
+	}
+
+	notificationDeliveryInterval, err := time.ParseDuration(viper.GetString("NOTIFICATION_DELIVERY_INTERVAL"))
+	if err != nil || notificationDeliveryInterval <= 0 {
+		notificationDeliveryInterval = 30 * time.Second
+	}
+
+	notificationRetryBaseDelay, err := time.ParseDuration(viper.GetString("NOTIFICATION_RETRY_BASE_DELAY"))
+	if err != nil || notificationRetryBaseDelay <= 0 {
+		notificationRetryBaseDelay = time.Minute
+	}
+
+	notificationAbandonedCartAfter, err := time.ParseDuration(viper.GetString("NOTIFICATION_ABANDONED_CART_AFTER"))
+	if err != nil || notificationAbandonedCartAfter <= 0 {
+		notificationAbandonedCartAfter = 24 * time.Hour
+	}
+
+	notificationAbandonedCartSweepInterval, err := time.ParseDuration(viper.GetString("NOTIFICATION_ABANDONED_CART_SWEEP_INTERVAL"))
+	if err != nil || notificationAbandonedCartSweepInterval <= 0 {
+		notificationAbandonedCartSweepInterval = 15 * time.Minute
+	}
+
+	webhookDeliveryInterval, err := time.ParseDuration(viper.GetString("WEBHOOK_DELIVERY_INTERVAL"))
+	if err != nil || webhookDeliveryInterval <= 0 {
+		webhookDeliveryInterval = 10 * time.Second
+	}
+
+	webhookRetryBaseDelay, err := time.ParseDuration(viper.GetString("WEBHOOK_RETRY_BASE_DELAY"))
+	if err != nil || webhookRetryBaseDelay <= 0 {
+		webhookRetryBaseDelay = 30 * time.Second
+	}
+
+	webhookRequestTimeout, err := time.ParseDuration(viper.GetString("WEBHOOK_REQUEST_TIMEOUT"))
+	if err != nil || webhookRequestTimeout <= 0 {
+		webhookRequestTimeout = 10 * time.Second
+	}
+
+	loyaltyCategoryEarnRates, err := parseRates(viper.GetString("LOYALTY_CATEGORY_EARN_RATES"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOYALTY_CATEGORY_EARN_RATES: %w", err)
+	}
+
 	config := &Config{
-		Host:                     viper.GetString("SHOPPING_EXPERIENCE_HOST"),
-		Port:                     viper.GetInt("SHOPPING_EXPERIENCE_PORT"),
-		ReadTimeout:              readTimeout,
-		WriteTimeout:             writeTimeout,
-		IdleTimeout:              idleTimeout,
-		ShutdownTimeout:          shutdownTimeout,
-		DbHost:                   viper.GetString("SHOPPING_EXPERIENCE_DB_HOST"),
-		DbPort:                   viper.GetInt("SHOPPING_EXPERIENCE_DB_PORT"),
-		DbUser:                   viper.GetString("SHOPPING_EXPERIENCE_DB_USER"),
-		DbPassword:               viper.GetString("SHOPPING_EXPERIENCE_DB_PASS"),
-		DbName:                   viper.GetString("SHOPPING_EXPERIENCE_DB_NAME"),
-		DbSslMode:                viper.GetString("SHOPPING_EXPERIENCE_DB_SSLMODE"),
-		ProductCatalogServiceURL: viper.GetString("PRODUCT_CATALOG_SERVICE_URL"),
+		Host:                                   viper.GetString("SHOPPING_EXPERIENCE_HOST"),
+		Port:                                   viper.GetInt("SHOPPING_EXPERIENCE_PORT"),
+		ReadTimeout:                            readTimeout,
+		WriteTimeout:                           writeTimeout,
+		IdleTimeout:                            idleTimeout,
+		ShutdownTimeout:                        shutdownTimeout,
+		DbHost:                                 viper.GetString("SHOPPING_EXPERIENCE_DB_HOST"),
+		DbPort:                                 viper.GetInt("SHOPPING_EXPERIENCE_DB_PORT"),
+		DbUser:                                 viper.GetString("SHOPPING_EXPERIENCE_DB_USER"),
+		DbPassword:                             viper.GetString("SHOPPING_EXPERIENCE_DB_PASS"),
+		DbName:                                 viper.GetString("SHOPPING_EXPERIENCE_DB_NAME"),
+		DbSslMode:                              viper.GetString("SHOPPING_EXPERIENCE_DB_SSLMODE"),
+		ProductCatalogServiceURL:               viper.GetString("PRODUCT_CATALOG_SERVICE_URL"),
+		AdminAPIKey:                            viper.GetString("SHOPPING_EXPERIENCE_ADMIN_API_KEY"),
+		LoyaltyDefaultEarnRate:                 viper.GetFloat64("LOYALTY_DEFAULT_EARN_RATE"),
+		LoyaltyCategoryEarnRates:               loyaltyCategoryEarnRates,
+		LoyaltyPointValue:                      viper.GetFloat64("LOYALTY_POINT_VALUE"),
+		LoyaltyPointsLifetime:                  loyaltyPointsLifetime,
+		CartMaxDistinctLines:                   viper.GetInt("CART_MAX_DISTINCT_LINES"),
+		CheckoutCashPaymentWindow:              checkoutCashPaymentWindow,
+		CheckoutCashPaymentSweepInterval:       checkoutCashPaymentSweepInterval,
+		KioskSessionIdleTimeout:                kioskSessionIdleTimeout,
+		KioskSessionSweepInterval:              kioskSessionSweepInterval,
+		InvoicingSellerTaxCondition:            viper.GetString("INVOICING_SELLER_TAX_CONDITION"),
+		InvoicingPointOfSale:                   viper.GetInt("INVOICING_POINT_OF_SALE"),
+		InvoicingAuthorizationRetryInterval:    invoicingAuthorizationRetryInterval,
+		NotificationSender:                     viper.GetString("NOTIFICATION_SENDER"),
+		NotificationFrom:                       viper.GetString("NOTIFICATION_FROM"),
+		NotificationOutboxDir:                  viper.GetString("NOTIFICATION_OUTBOX_DIR"),
+		NotificationDeliveryInterval:           notificationDeliveryInterval,
+		NotificationMaxAttempts:                viper.GetInt("NOTIFICATION_MAX_ATTEMPTS"),
+		NotificationRetryBaseDelay:             notificationRetryBaseDelay,
+		NotificationAbandonedCartAfter:         notificationAbandonedCartAfter,
+		NotificationAbandonedCartSweepInterval: notificationAbandonedCartSweepInterval,
+		SMTPHost:                               viper.GetString("SMTP_HOST"),
+		SMTPPort:                               viper.GetInt("SMTP_PORT"),
+		SMTPUsername:                           viper.GetString("SMTP_USERNAME"),
+		SMTPPassword:                           viper.GetString("SMTP_PASSWORD"),
+		WebhookDeliveryInterval:                webhookDeliveryInterval,
+		WebhookMaxAttempts:                     viper.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+		WebhookRetryBaseDelay:                  webhookRetryBaseDelay,
+		WebhookRequestTimeout:                  webhookRequestTimeout,
 	}
 
 	return config, nil
@@ -102,3 +277,26 @@ func (c *Config) GetDBConnectionString() string {
 	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		c.DbHost, c.DbPort, c.DbUser, c.DbPassword, c.DbName, c.DbSslMode)
 }
+
+// parseRates parses a comma separated list of key=rate pairs (e.g. "books=0.02,snacks=0.005")
+func parseRates(value string) (map[string]float64, error) {
+	rates := make(map[string]float64)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, rawRate, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("expected key=rate, got %q", pair)
+		}
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rawRate), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate for %q: %w", key, err)
+		}
+		rates[strings.ToLower(strings.TrimSpace(key))] = rate
+	}
+	return rates, nil
+}
@@ -22,3 +22,23 @@ func WriteErrorResponse(w http.ResponseWriter, status int, message string) {
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(response)
 }
+
+// ValidationErrorResponse represents an API error response that lists the validation errors found
+type ValidationErrorResponse struct {
+	Status  int         `json:"status"`
+	Message string      `json:"message"`
+	Errors  interface{} `json:"errors"`
+}
+
+// WriteValidationErrorResponse writes a validation error response to the response writer
+func WriteValidationErrorResponse(w http.ResponseWriter, status int, message string, validationErrors interface{}) {
+	response := ValidationErrorResponse{
+		Status:  status,
+		Message: message,
+		Errors:  validationErrors,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
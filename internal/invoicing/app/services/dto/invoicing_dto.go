@@ -0,0 +1,201 @@
+package dto
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/domain/model"
+)
+
+// TaxProfileRequest represents the request to set the tax data a user invoices with
+type TaxProfileRequest struct {
+	TaxCondition   string `json:"taxCondition" validate:"required,oneof=RESPONSABLE_INSCRIPTO MONOTRIBUTO EXENTO CONSUMIDOR_FINAL"`
+	DocumentType   string `json:"documentType" validate:"omitempty,oneof=CUIT DNI"`
+	DocumentNumber string `json:"documentNumber"`
+	LegalName      string `json:"legalName"`
+}
+
+// TaxProfileDTO represents a tax profile for API responses
+type TaxProfileDTO struct {
+	UserID         string `json:"userId"`
+	TaxCondition   string `json:"taxCondition"`
+	DocumentType   string `json:"documentType,omitempty"`
+	DocumentNumber string `json:"documentNumber,omitempty"`
+	LegalName      string `json:"legalName,omitempty"`
+	UpdatedAt      string `json:"updatedAt"`
+}
+
+// TaxProfileFromDomain converts a domain tax profile to a DTO
+func TaxProfileFromDomain(profile *model.TaxProfile) *TaxProfileDTO {
+	return &TaxProfileDTO{
+		UserID:         profile.UserID.String(),
+		TaxCondition:   string(profile.TaxCondition),
+		DocumentType:   string(profile.DocumentType),
+		DocumentNumber: profile.DocumentNumber,
+		LegalName:      profile.LegalName,
+		UpdatedAt:      profile.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// InvoiceCustomerDTO represents the customer data printed on an invoice
+type InvoiceCustomerDTO struct {
+	TaxCondition   string `json:"taxCondition"`
+	DocumentType   string `json:"documentType,omitempty"`
+	DocumentNumber string `json:"documentNumber,omitempty"`
+	Name           string `json:"name,omitempty"`
+}
+
+// InvoiceLineDTO represents an invoice line for API responses
+type InvoiceLineDTO struct {
+	Description string  `json:"description"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unitPrice"`
+	NetAmount   float64 `json:"netAmount"`
+	IVARate     float64 `json:"ivaRate"`
+	IVAAmount   float64 `json:"ivaAmount"`
+	Total       float64 `json:"total"`
+}
+
+// IVASubtotalDTO represents the net amount and IVA of an invoice for one rate
+type IVASubtotalDTO struct {
+	Rate      float64 `json:"rate"`
+	NetAmount float64 `json:"netAmount"`
+	IVAAmount float64 `json:"ivaAmount"`
+}
+
+// InvoiceDTO represents an invoice for API responses
+type InvoiceDTO struct {
+	ID                     string             `json:"id"`
+	CheckoutID             string             `json:"checkoutId"`
+	UserID                 string             `json:"userId"`
+	Type                   string             `json:"type"`
+	PointOfSale            int                `json:"pointOfSale"`
+	Number                 int64              `json:"number"`
+	FormattedNumber        string             `json:"formattedNumber"`
+	Status                 string             `json:"status"`
+	Customer               InvoiceCustomerDTO `json:"customer"`
+	Lines                  []InvoiceLineDTO   `json:"lines"`
+	IVASubtotals           []IVASubtotalDTO   `json:"ivaSubtotals"`
+	NetTotal               float64            `json:"netTotal"`
+	IVATotal               float64            `json:"ivaTotal"`
+	Total                  float64            `json:"total"`
+	AuthorizationCode      string             `json:"authorizationCode,omitempty"`
+	AuthorizationExpiresAt string             `json:"authorizationExpiresAt,omitempty"`
+	IssuedAt               string             `json:"issuedAt"`
+	AuthorizedAt           string             `json:"authorizedAt,omitempty"`
+}
+
+// InvoiceFromDomain converts a domain invoice to a DTO
+func InvoiceFromDomain(invoice *model.Invoice) *InvoiceDTO {
+	lines := make([]InvoiceLineDTO, len(invoice.Lines))
+	for i, line := range invoice.Lines {
+		lines[i] = InvoiceLineDTO{
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice,
+			NetAmount:   line.NetAmount,
+			IVARate:     line.IVARate,
+			IVAAmount:   line.IVAAmount,
+			Total:       line.Total,
+		}
+	}
+
+	subtotals := invoice.IVASubtotals()
+	ivaSubtotals := make([]IVASubtotalDTO, len(subtotals))
+	for i, subtotal := range subtotals {
+		ivaSubtotals[i] = IVASubtotalDTO{
+			Rate:      subtotal.Rate,
+			NetAmount: subtotal.NetAmount,
+			IVAAmount: subtotal.IVAAmount,
+		}
+	}
+
+	invoiceDTO := &InvoiceDTO{
+		ID:              invoice.ID.String(),
+		CheckoutID:      invoice.CheckoutID.String(),
+		UserID:          invoice.UserID.String(),
+		Type:            string(invoice.Type),
+		PointOfSale:     invoice.PointOfSale,
+		Number:          invoice.Number,
+		FormattedNumber: invoice.FormattedNumber(),
+		Status:          string(invoice.Status),
+		Customer: InvoiceCustomerDTO{
+			TaxCondition:   string(invoice.CustomerTaxCondition),
+			DocumentType:   string(invoice.CustomerDocumentType),
+			DocumentNumber: invoice.CustomerDocumentNumber,
+			Name:           invoice.CustomerName,
+		},
+		Lines:             lines,
+		IVASubtotals:      ivaSubtotals,
+		NetTotal:          invoice.NetTotal,
+		IVATotal:          invoice.IVATotal,
+		Total:             invoice.Total,
+		AuthorizationCode: invoice.AuthorizationCode,
+		IssuedAt:          invoice.IssuedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if invoice.AuthorizationExpiresAt != nil {
+		invoiceDTO.AuthorizationExpiresAt = invoice.AuthorizationExpiresAt.Format("2006-01-02T15:04:05Z")
+	}
+	if invoice.AuthorizedAt != nil {
+		invoiceDTO.AuthorizedAt = invoice.AuthorizedAt.Format("2006-01-02T15:04:05Z")
+	}
+
+	return invoiceDTO
+}
+
+// InvoiceExportDTO represents the invoices issued in a period, as exported for the accountant
+type InvoiceExportDTO struct {
+	From     string       `json:"from"`
+	To       string       `json:"to"`
+	Count    int          `json:"count"`
+	NetTotal float64      `json:"netTotal"`
+	IVATotal float64      `json:"ivaTotal"`
+	Total    float64      `json:"total"`
+	Invoices []InvoiceDTO `json:"invoices"`
+}
+
+// invoiceExportHeader lists the CSV columns of the export, one row per invoice and IVA rate
+var invoiceExportHeader = []string{
+	"issued_date", "type", "point_of_sale", "number", "status", "customer_tax_condition",
+	"customer_document_type", "customer_document_number", "customer_name", "iva_rate",
+	"net_amount", "iva_amount", "total", "authorization_code", "checkout_id",
+}
+
+// WriteCSV writes the export as CSV, with one row per invoice and IVA rate as the sales
+// ledger (libro IVA ventas) expects
+func (e *InvoiceExportDTO) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(invoiceExportHeader); err != nil {
+		return err
+	}
+
+	for _, invoice := range e.Invoices {
+		for _, subtotal := range invoice.IVASubtotals {
+			record := []string{
+				invoice.IssuedAt[:10],
+				invoice.Type,
+				fmt.Sprintf("%05d", invoice.PointOfSale),
+				fmt.Sprintf("%08d", invoice.Number),
+				invoice.Status,
+				invoice.Customer.TaxCondition,
+				invoice.Customer.DocumentType,
+				invoice.Customer.DocumentNumber,
+				invoice.Customer.Name,
+				strconv.FormatFloat(subtotal.Rate*100, 'f', -1, 64),
+				fmt.Sprintf("%.2f", subtotal.NetAmount),
+				fmt.Sprintf("%.2f", subtotal.IVAAmount),
+				fmt.Sprintf("%.2f", subtotal.NetAmount+subtotal.IVAAmount),
+				invoice.AuthorizationCode,
+				invoice.CheckoutID,
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/auth"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/domain/repository"
+)
+
+// InvoicingService handles operations related to the invoices of completed checkouts
+type InvoicingService struct {
+	invoiceRepository    repository.InvoiceRepository
+	taxProfileRepository repository.TaxProfileRepository
+	fiscalAuthority      repository.FiscalAuthority
+	// sellerTaxCondition is the IVA condition of the store, which decides the invoice types
+	sellerTaxCondition model.TaxCondition
+	// pointOfSale is the point of sale number the invoices are issued from
+	pointOfSale int
+}
+
+// NewInvoicingService creates a new invoicing service
+func NewInvoicingService(
+	invoiceRepository repository.InvoiceRepository,
+	taxProfileRepository repository.TaxProfileRepository,
+	fiscalAuthority repository.FiscalAuthority,
+	sellerTaxCondition model.TaxCondition,
+	pointOfSale int,
+) *InvoicingService {
+	return &InvoicingService{
+		invoiceRepository:    invoiceRepository,
+		taxProfileRepository: taxProfileRepository,
+		fiscalAuthority:      fiscalAuthority,
+		sellerTaxCondition:   sellerTaxCondition,
+		pointOfSale:          pointOfSale,
+	}
+}
+
+// GetTaxProfile retrieves the tax profile of a user. Only the user and the back office can read it.
+func (s *InvoicingService) GetTaxProfile(ctx context.Context, userID string) (*dto.TaxProfileDTO, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+	if !auth.CanActFor(ctx, id) {
+		return nil, errors.New("tax profile access denied")
+	}
+
+	profile, err := s.taxProfileRepository.FindByUserID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.TaxProfileFromDomain(profile), nil
+}
+
+// UpdateTaxProfile sets the tax data a user is invoiced with from now on. Invoices already
+// issued keep the data they were issued with. Only the user and the back office can set it.
+func (s *InvoicingService) UpdateTaxProfile(ctx context.Context, userID string, req *dto.TaxProfileRequest) (*dto.TaxProfileDTO, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+	if !auth.CanActFor(ctx, id) {
+		return nil, errors.New("tax profile access denied")
+	}
+
+	profile, err := model.NewTaxProfile(
+		id,
+		model.TaxCondition(req.TaxCondition),
+		model.DocumentType(req.DocumentType),
+		req.DocumentNumber,
+		req.LegalName,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.taxProfileRepository.Save(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	return dto.TaxProfileFromDomain(profile), nil
+}
+
+// InvoiceSale issues the invoice of a completed checkout and requests its authorization.
+// A failed authorization leaves the invoice pending, to be retried later.
+func (s *InvoicingService) InvoiceSale(ctx context.Context, sale *model.Sale) (*model.Invoice, error) {
+	profile, err := s.taxProfileRepository.FindByUserID(ctx, sale.UserID)
+	if err != nil {
+		if err.Error() != "tax profile not found" {
+			return nil, err
+		}
+		profile = model.FinalConsumerProfile(sale.UserID)
+	}
+
+	invoice, err := model.NewInvoice(sale, s.sellerTaxCondition, profile, s.pointOfSale)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.invoiceRepository.Issue(ctx, invoice); err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, invoice); err != nil {
+		log.Printf("Failed to authorize invoice %s: %v", invoice.FormattedNumber(), err)
+	}
+
+	return invoice, nil
+}
+
+// GetInvoice retrieves an invoice by ID. Only the invoiced user and the back office can read it.
+func (s *InvoicingService) GetInvoice(ctx context.Context, invoiceID string) (*dto.InvoiceDTO, error) {
+	id, err := uuid.Parse(invoiceID)
+	if err != nil {
+		return nil, errors.New("invalid invoice ID format")
+	}
+
+	invoice, err := s.invoiceRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !auth.CanActFor(ctx, invoice.UserID) {
+		return nil, errors.New("invoice access denied")
+	}
+
+	return dto.InvoiceFromDomain(invoice), nil
+}
+
+// GetCheckoutInvoice retrieves the invoice of a checkout. Only the invoiced user and the back
+// office can read it.
+func (s *InvoicingService) GetCheckoutInvoice(ctx context.Context, checkoutID string) (*dto.InvoiceDTO, error) {
+	id, err := uuid.Parse(checkoutID)
+	if err != nil {
+		return nil, errors.New("invalid checkout ID format")
+	}
+
+	invoice, err := s.invoiceRepository.FindByCheckoutID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !auth.CanActFor(ctx, invoice.UserID) {
+		return nil, errors.New("invoice access denied")
+	}
+
+	return dto.InvoiceFromDomain(invoice), nil
+}
+
+// AuthorizeInvoice retries the authorization of a pending invoice
+func (s *InvoicingService) AuthorizeInvoice(ctx context.Context, invoiceID string) (*dto.InvoiceDTO, error) {
+	id, err := uuid.Parse(invoiceID)
+	if err != nil {
+		return nil, errors.New("invalid invoice ID format")
+	}
+
+	invoice, err := s.invoiceRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if invoice.IsAuthorized() {
+		return nil, errors.New("invoice is already authorized")
+	}
+
+	if err := s.authorize(ctx, invoice); err != nil {
+		return nil, err
+	}
+
+	return dto.InvoiceFromDomain(invoice), nil
+}
+
+// AuthorizePending retries the authorization of every pending invoice. It returns the number
+// of invoices authorized.
+func (s *InvoicingService) AuthorizePending(ctx context.Context) (int, error) {
+	invoices, err := s.invoiceRepository.FindPending(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	authorized := 0
+	for _, invoice := range invoices {
+		if err := s.authorize(ctx, invoice); err != nil {
+			// The authority is likely down; the rest would fail too
+			return authorized, err
+		}
+		authorized++
+	}
+
+	return authorized, nil
+}
+
+// RunAuthorization retries pending authorizations every interval until the context is cancelled
+func (s *InvoicingService) RunAuthorization(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			authorized, err := s.AuthorizePending(ctx)
+			if err != nil {
+				log.Printf("Failed to authorize pending invoices: %v", err)
+			}
+			if authorized > 0 {
+				log.Printf("Authorized %d pending invoices", authorized)
+			}
+		}
+	}
+}
+
+// authorize requests the authorization of an invoice and stores it
+func (s *InvoicingService) authorize(ctx context.Context, invoice *model.Invoice) error {
+	authorization, err := s.fiscalAuthority.Authorize(ctx, invoice)
+	if err != nil {
+		return err
+	}
+
+	if err := invoice.Authorize(authorization, time.Now()); err != nil {
+		return err
+	}
+
+	return s.invoiceRepository.Save(ctx, invoice)
+}
+
+// ExportInvoices returns the invoices issued between two dates, both included, for the accountant
+func (s *InvoicingService) ExportInvoices(ctx context.Context, fromDate, toDate string) (*dto.InvoiceExportDTO, error) {
+	from, err := time.Parse("2006-01-02", fromDate)
+	if err != nil {
+		return nil, errors.New("invalid from date format")
+	}
+	to, err := time.Parse("2006-01-02", toDate)
+	if err != nil {
+		return nil, errors.New("invalid to date format")
+	}
+	if to.Before(from) {
+		return nil, errors.New("from date must not be after to date")
+	}
+
+	invoices, err := s.invoiceRepository.FindIssuedBetween(ctx, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	export := &dto.InvoiceExportDTO{
+		From:     fromDate,
+		To:       toDate,
+		Count:    len(invoices),
+		Invoices: make([]dto.InvoiceDTO, len(invoices)),
+	}
+	for i, invoice := range invoices {
+		export.Invoices[i] = *dto.InvoiceFromDomain(invoice)
+		export.NetTotal += invoice.NetTotal
+		export.IVATotal += invoice.IVATotal
+		export.Total += invoice.Total
+	}
+	export.NetTotal = math.Round(export.NetTotal*100) / 100
+	export.IVATotal = math.Round(export.IVATotal*100) / 100
+	export.Total = math.Round(export.Total*100) / 100
+
+	return export, nil
+}
@@ -0,0 +1,226 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvoiceType represents the letter of an invoice, which depends on the tax conditions of
+// the seller and the customer
+type InvoiceType string
+
+const (
+	// InvoiceTypeA is issued by registered sellers to registered and monotributo customers
+	InvoiceTypeA InvoiceType = "A"
+	// InvoiceTypeB is issued by registered sellers to final consumers and exempt customers
+	InvoiceTypeB InvoiceType = "B"
+	// InvoiceTypeC is issued by monotributo and exempt sellers, without IVA
+	InvoiceTypeC InvoiceType = "C"
+)
+
+// InvoiceTypeFor returns the invoice type a seller issues to a customer
+func InvoiceTypeFor(seller, customer TaxCondition) InvoiceType {
+	if seller != TaxConditionRegistered {
+		return InvoiceTypeC
+	}
+	if customer == TaxConditionRegistered || customer == TaxConditionMonotributo {
+		return InvoiceTypeA
+	}
+	return InvoiceTypeB
+}
+
+// InvoiceStatus represents the status of an invoice before the tax authority
+type InvoiceStatus string
+
+const (
+	// InvoiceStatusPending invoices are numbered but not yet authorized
+	InvoiceStatusPending InvoiceStatus = "PENDING_AUTHORIZATION"
+	// InvoiceStatusAuthorized invoices carry the authorization code (CAE) of the tax authority
+	InvoiceStatusAuthorized InvoiceStatus = "AUTHORIZED"
+)
+
+// Sale represents a completed checkout as seen by invoicing. Line amounts are net of IVA
+// and already have the checkout discounts applied.
+type Sale struct {
+	CheckoutID  uuid.UUID
+	UserID      uuid.UUID
+	CompletedAt time.Time
+	Lines       []SaleLine
+}
+
+// SaleLine represents a line of a sale with the IVA rate it is taxed at
+type SaleLine struct {
+	Description string
+	Quantity    int
+	NetAmount   float64
+	IVARate     float64
+}
+
+// InvoiceLine represents a line of an invoice. On type C invoices IVA is not itemized, so
+// the net amount already includes it and the rate is zero.
+type InvoiceLine struct {
+	Description string  `json:"description"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unitPrice"`
+	NetAmount   float64 `json:"netAmount"`
+	IVARate     float64 `json:"ivaRate"`
+	IVAAmount   float64 `json:"ivaAmount"`
+	Total       float64 `json:"total"`
+}
+
+// IVASubtotal represents the net amount and IVA of an invoice for one rate
+type IVASubtotal struct {
+	Rate      float64 `json:"rate"`
+	NetAmount float64 `json:"netAmount"`
+	IVAAmount float64 `json:"ivaAmount"`
+}
+
+// Authorization represents the approval of an invoice by the tax authority
+type Authorization struct {
+	Code      string
+	ExpiresAt time.Time
+}
+
+// Invoice represents the Invoice aggregate root: the fiscal record of a completed checkout.
+// The customer data is a copy of the tax profile at the time the invoice was issued.
+type Invoice struct {
+	ID                     uuid.UUID      `json:"id"`
+	CheckoutID             uuid.UUID      `json:"checkoutId"`
+	UserID                 uuid.UUID      `json:"userId"`
+	Type                   InvoiceType    `json:"type"`
+	PointOfSale            int            `json:"pointOfSale"`
+	Number                 int64          `json:"number"`
+	Status                 InvoiceStatus  `json:"status"`
+	CustomerTaxCondition   TaxCondition   `json:"customerTaxCondition"`
+	CustomerDocumentType   DocumentType   `json:"customerDocumentType"`
+	CustomerDocumentNumber string         `json:"customerDocumentNumber"`
+	CustomerName           string         `json:"customerName"`
+	Lines                  []*InvoiceLine `json:"lines"`
+	NetTotal               float64        `json:"netTotal"`
+	IVATotal               float64        `json:"ivaTotal"`
+	Total                  float64        `json:"total"`
+	AuthorizationCode      string         `json:"authorizationCode"`
+	AuthorizationExpiresAt *time.Time     `json:"authorizationExpiresAt"`
+	IssuedAt               time.Time      `json:"issuedAt"`
+	AuthorizedAt           *time.Time     `json:"authorizedAt"`
+}
+
+// NewInvoice creates the invoice of a sale for a customer. The number is assigned when
+// the invoice is stored, since it must follow the last one of its point of sale.
+func NewInvoice(sale *Sale, seller TaxCondition, customer *TaxProfile, pointOfSale int) (*Invoice, error) {
+	if len(sale.Lines) == 0 {
+		return nil, errors.New("sale has no lines")
+	}
+	if pointOfSale < 1 || pointOfSale > 99999 {
+		return nil, errors.New("point of sale must be between 1 and 99999")
+	}
+
+	invoice := &Invoice{
+		ID:                     uuid.New(),
+		CheckoutID:             sale.CheckoutID,
+		UserID:                 sale.UserID,
+		Type:                   InvoiceTypeFor(seller, customer.TaxCondition),
+		PointOfSale:            pointOfSale,
+		Status:                 InvoiceStatusPending,
+		CustomerTaxCondition:   customer.TaxCondition,
+		CustomerDocumentType:   customer.DocumentType,
+		CustomerDocumentNumber: customer.DocumentNumber,
+		CustomerName:           customer.LegalName,
+		Lines:                  make([]*InvoiceLine, 0, len(sale.Lines)),
+		IssuedAt:               sale.CompletedAt,
+	}
+
+	for _, saleLine := range sale.Lines {
+		line := &InvoiceLine{
+			Description: saleLine.Description,
+			Quantity:    saleLine.Quantity,
+			NetAmount:   roundToCents(saleLine.NetAmount),
+			IVARate:     saleLine.IVARate,
+		}
+		line.IVAAmount = roundToCents(line.NetAmount * line.IVARate)
+
+		// Type C invoices show the final price only
+		if invoice.Type == InvoiceTypeC {
+			line.NetAmount += line.IVAAmount
+			line.IVARate = 0
+			line.IVAAmount = 0
+		}
+
+		line.Total = roundToCents(line.NetAmount + line.IVAAmount)
+		if line.Quantity > 0 {
+			line.UnitPrice = roundToCents(line.NetAmount / float64(line.Quantity))
+		}
+
+		invoice.NetTotal += line.NetAmount
+		invoice.IVATotal += line.IVAAmount
+		invoice.Lines = append(invoice.Lines, line)
+	}
+
+	invoice.NetTotal = roundToCents(invoice.NetTotal)
+	invoice.IVATotal = roundToCents(invoice.IVATotal)
+	invoice.Total = roundToCents(invoice.NetTotal + invoice.IVATotal)
+
+	return invoice, nil
+}
+
+// FormattedNumber returns the number as printed on the invoice (e.g. "A 00001-00000042")
+func (i *Invoice) FormattedNumber() string {
+	return fmt.Sprintf("%s %05d-%08d", i.Type, i.PointOfSale, i.Number)
+}
+
+// IVASubtotals returns the net amount and IVA of the invoice grouped by rate, from the
+// highest rate to the lowest
+func (i *Invoice) IVASubtotals() []*IVASubtotal {
+	byRate := make(map[float64]*IVASubtotal)
+	subtotals := make([]*IVASubtotal, 0)
+	for _, line := range i.Lines {
+		subtotal, ok := byRate[line.IVARate]
+		if !ok {
+			subtotal = &IVASubtotal{Rate: line.IVARate}
+			byRate[line.IVARate] = subtotal
+			subtotals = append(subtotals, subtotal)
+		}
+		subtotal.NetAmount = roundToCents(subtotal.NetAmount + line.NetAmount)
+		subtotal.IVAAmount = roundToCents(subtotal.IVAAmount + line.IVAAmount)
+	}
+
+	sort.Slice(subtotals, func(a, b int) bool {
+		return subtotals[a].Rate > subtotals[b].Rate
+	})
+	return subtotals
+}
+
+// IsAuthorized returns true if the tax authority approved the invoice
+func (i *Invoice) IsAuthorized() bool {
+	return i.Status == InvoiceStatusAuthorized
+}
+
+// Authorize records the authorization code (CAE) granted by the tax authority
+func (i *Invoice) Authorize(authorization *Authorization, now time.Time) error {
+	if i.IsAuthorized() {
+		return errors.New("invoice is already authorized")
+	}
+	if i.Number == 0 {
+		return errors.New("invoice has not been numbered")
+	}
+	if authorization.Code == "" {
+		return errors.New("authorization code is required")
+	}
+
+	expiresAt := authorization.ExpiresAt
+	i.Status = InvoiceStatusAuthorized
+	i.AuthorizationCode = authorization.Code
+	i.AuthorizationExpiresAt = &expiresAt
+	i.AuthorizedAt = &now
+	return nil
+}
+
+// roundToCents rounds an amount to two decimals
+func roundToCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,145 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaxCondition represents the IVA condition of a taxpayer before the tax authority
+type TaxCondition string
+
+const (
+	TaxConditionRegistered    TaxCondition = "RESPONSABLE_INSCRIPTO"
+	TaxConditionMonotributo   TaxCondition = "MONOTRIBUTO"
+	TaxConditionExempt        TaxCondition = "EXENTO"
+	TaxConditionFinalConsumer TaxCondition = "CONSUMIDOR_FINAL"
+)
+
+// IsValidTaxCondition returns true if the condition is one of the known IVA conditions
+func IsValidTaxCondition(condition TaxCondition) bool {
+	switch condition {
+	case TaxConditionRegistered, TaxConditionMonotributo, TaxConditionExempt, TaxConditionFinalConsumer:
+		return true
+	default:
+		return false
+	}
+}
+
+// DocumentType represents the kind of identity document printed on an invoice
+type DocumentType string
+
+const (
+	DocumentTypeCUIT DocumentType = "CUIT"
+	DocumentTypeDNI  DocumentType = "DNI"
+)
+
+// TaxProfile represents the tax data a user invoices with. Users without a profile are
+// invoiced as anonymous final consumers.
+type TaxProfile struct {
+	UserID         uuid.UUID    `json:"userId"`
+	TaxCondition   TaxCondition `json:"taxCondition"`
+	DocumentType   DocumentType `json:"documentType"`
+	DocumentNumber string       `json:"documentNumber"`
+	LegalName      string       `json:"legalName"`
+	UpdatedAt      time.Time    `json:"updatedAt"`
+}
+
+// NewTaxProfile creates a tax profile, normalizing and validating the document. Final
+// consumers may leave the document empty; every other condition needs a CUIT.
+func NewTaxProfile(userID uuid.UUID, condition TaxCondition, documentType DocumentType, documentNumber, legalName string) (*TaxProfile, error) {
+	if userID == uuid.Nil {
+		return nil, errors.New("user ID is required")
+	}
+	if !IsValidTaxCondition(condition) {
+		return nil, errors.New("invalid tax condition")
+	}
+
+	documentNumber = NormalizeDocumentNumber(documentNumber)
+	legalName = strings.TrimSpace(legalName)
+
+	switch documentType {
+	case "":
+		if documentNumber != "" {
+			return nil, errors.New("document type is required")
+		}
+	case DocumentTypeCUIT:
+		if !IsValidCUIT(documentNumber) {
+			return nil, errors.New("invalid CUIT")
+		}
+	case DocumentTypeDNI:
+		if len(documentNumber) < 7 || len(documentNumber) > 8 || !isDigits(documentNumber) {
+			return nil, errors.New("DNI must have 7 or 8 digits")
+		}
+	default:
+		return nil, errors.New("invalid document type")
+	}
+
+	if condition != TaxConditionFinalConsumer {
+		if documentType != DocumentTypeCUIT {
+			return nil, errors.New("CUIT is required for this tax condition")
+		}
+		if legalName == "" {
+			return nil, errors.New("legal name is required for this tax condition")
+		}
+	}
+	if len(legalName) > 255 {
+		return nil, errors.New("legal name is too long")
+	}
+
+	return &TaxProfile{
+		UserID:         userID,
+		TaxCondition:   condition,
+		DocumentType:   documentType,
+		DocumentNumber: documentNumber,
+		LegalName:      legalName,
+		UpdatedAt:      time.Now(),
+	}, nil
+}
+
+// FinalConsumerProfile returns the profile of a user who did not give any tax data
+func FinalConsumerProfile(userID uuid.UUID) *TaxProfile {
+	return &TaxProfile{
+		UserID:       userID,
+		TaxCondition: TaxConditionFinalConsumer,
+	}
+}
+
+// NormalizeDocumentNumber removes the separators usually typed in CUIT and DNI numbers
+func NormalizeDocumentNumber(number string) string {
+	return strings.NewReplacer(".", "", " ", "", "-", "").Replace(strings.TrimSpace(number))
+}
+
+// IsValidCUIT returns true if the number has 11 digits and a valid check digit
+func IsValidCUIT(cuit string) bool {
+	if len(cuit) != 11 || !isDigits(cuit) {
+		return false
+	}
+
+	weights := []int{5, 4, 3, 2, 7, 6, 5, 4, 3, 2}
+	sum := 0
+	for i, weight := range weights {
+		sum += int(cuit[i]-'0') * weight
+	}
+
+	check := 11 - sum%11
+	switch check {
+	case 11:
+		check = 0
+	case 10:
+		return false
+	}
+	return int(cuit[10]-'0') == check
+}
+
+// isDigits returns true if the text only has decimal digits
+func isDigits(text string) bool {
+	for _, r := range text {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/domain/model"
+)
+
+// FiscalAuthority defines the interface to the web service of the tax authority that
+// authorizes electronic invoices
+type FiscalAuthority interface {
+	// Authorize requests the authorization code (CAE) of a numbered invoice
+	Authorize(ctx context.Context, invoice *model.Invoice) (*model.Authorization, error)
+}
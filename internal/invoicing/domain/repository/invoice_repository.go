@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/domain/model"
+)
+
+// InvoiceRepository defines the interface for invoice persistence operations
+type InvoiceRepository interface {
+	// FindByID retrieves an invoice by its ID
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Invoice, error)
+
+	// FindByCheckoutID retrieves the invoice of a checkout
+	FindByCheckoutID(ctx context.Context, checkoutID uuid.UUID) (*model.Invoice, error)
+
+	// FindIssuedBetween retrieves the invoices issued in [from, to), ordered by point of sale, type and number
+	FindIssuedBetween(ctx context.Context, from, to time.Time) ([]*model.Invoice, error)
+
+	// FindPending retrieves the invoices not yet authorized, oldest first
+	FindPending(ctx context.Context) ([]*model.Invoice, error)
+
+	// Issue assigns the invoice the next number of its point of sale and type and stores it,
+	// in a single transaction so that numbers have no gaps
+	Issue(ctx context.Context, invoice *model.Invoice) error
+
+	// Save persists the status and authorization of an issued invoice
+	Save(ctx context.Context, invoice *model.Invoice) error
+}
+
+// TaxProfileRepository defines the interface for tax profile persistence operations
+type TaxProfileRepository interface {
+	// FindByUserID retrieves the tax profile of a user
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*model.TaxProfile, error)
+
+	// Save persists a tax profile (creates or updates)
+	Save(ctx context.Context, profile *model.TaxProfile) error
+}
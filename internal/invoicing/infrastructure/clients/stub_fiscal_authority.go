@@ -0,0 +1,41 @@
+package clients
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/domain/repository"
+)
+
+// stubAuthorizationLifetime is how long the authorization codes granted by the stub are valid,
+// which matches the usual CAE lifetime
+const stubAuthorizationLifetime = 10 * 24 * time.Hour
+
+// StubFiscalAuthority implements the FiscalAuthority interface without calling the tax
+// authority. It grants every invoice a fake 14-digit code derived from its number, so the
+// invoicing flow can run until the real web service is plugged in.
+type StubFiscalAuthority struct{}
+
+// NewStubFiscalAuthority creates a new stub fiscal authority client
+func NewStubFiscalAuthority() repository.FiscalAuthority {
+	return &StubFiscalAuthority{}
+}
+
+// Authorize grants the invoice a fake authorization code
+func (c *StubFiscalAuthority) Authorize(ctx context.Context, invoice *model.Invoice) (*model.Authorization, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256([]byte(invoice.FormattedNumber()))
+	code := binary.BigEndian.Uint64(sum[:8]) % 1e14
+
+	return &model.Authorization{
+		Code:      fmt.Sprintf("%014d", code),
+		ExpiresAt: invoice.IssuedAt.Add(stubAuthorizationLifetime),
+	}, nil
+}
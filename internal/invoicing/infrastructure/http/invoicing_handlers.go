@@ -0,0 +1,297 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/app/services"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/app/services/dto"
+)
+
+// taxProfileBadRequestErrors lists the tax profile errors caused by invalid client input
+var taxProfileBadRequestErrors = map[string]bool{
+	"invalid user ID format":                        true,
+	"invalid tax condition":                         true,
+	"invalid document type":                         true,
+	"document type is required":                     true,
+	"invalid CUIT":                                  true,
+	"DNI must have 7 or 8 digits":                   true,
+	"CUIT is required for this tax condition":       true,
+	"legal name is required for this tax condition": true,
+	"legal name is too long":                        true,
+}
+
+// InvoicingHandler handles HTTP requests for invoicing operations
+type InvoicingHandler struct {
+	invoicingService *services.InvoicingService
+	requireUser      func(http.Handler) http.Handler
+	requireAdmin     func(http.Handler) http.Handler
+}
+
+// NewInvoicingHandler creates a new invoicing handler. Tax profiles and invoices go through
+// requireUser, so they are only used by their own user or the back office, and exporting and
+// authorizing invoices through requireAdmin, since they are done from the back office.
+func NewInvoicingHandler(
+	invoicingService *services.InvoicingService,
+	requireUser func(http.Handler) http.Handler,
+	requireAdmin func(http.Handler) http.Handler,
+) *InvoicingHandler {
+	return &InvoicingHandler{
+		invoicingService: invoicingService,
+		requireUser:      requireUser,
+		requireAdmin:     requireAdmin,
+	}
+}
+
+// RegisterRoutes registers the invoicing routes on the given router
+func (h *InvoicingHandler) RegisterRoutes(router *mux.Router) {
+	// Create a subrouter for invoicing routes
+	invoicingRouter := router.PathPrefix("/invoicing").Subrouter()
+
+	// Exports and authorization retries are only available to the back office
+	adminRouter := invoicingRouter.NewRoute().Subrouter()
+	adminRouter.Use(h.requireAdmin)
+
+	adminRouter.HandleFunc("/invoices/export", h.ExportInvoices).Methods("GET")
+	adminRouter.HandleFunc("/invoices/{invoiceId}/authorize", h.AuthorizeInvoice).Methods("POST")
+
+	// Requests act on behalf of the user named in the X-User-ID header
+	userRouter := invoicingRouter.NewRoute().Subrouter()
+	userRouter.Use(h.requireUser)
+
+	userRouter.HandleFunc("/tax-profiles/{userId}", h.GetTaxProfile).Methods("GET")
+	userRouter.HandleFunc("/tax-profiles/{userId}", h.UpdateTaxProfile).Methods("PUT")
+	userRouter.HandleFunc("/invoices/{invoiceId}", h.GetInvoice).Methods("GET")
+	userRouter.HandleFunc("/checkouts/{checkoutId}/invoice", h.GetCheckoutInvoice).Methods("GET")
+}
+
+// GetTaxProfile handles the request to get the tax data a user invoices with
+// @Summary Get tax profile
+// @Description Get the tax condition and document a user is invoiced with
+// @Tags invoicing
+// @Produce json
+// @Param X-User-ID header string false "Acting user; must be the profile user unless the admin key is given" format(uuid)
+// @Param X-Admin-Key header string false "Admin API key, to read the profile of any user"
+// @Param userId path string true "User ID" format(uuid)
+// @Success 200 {object} dto.TaxProfileDTO "Tax profile"
+// @Failure 400 {object} errors.ErrorResponse "Invalid user ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing user ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the profile user, or invalid admin key"
+// @Failure 404 {object} errors.ErrorResponse "Tax profile not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/invoicing/tax-profiles/{userId} [get]
+func (h *InvoicingHandler) GetTaxProfile(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := vars["userId"]
+
+	profile, err := h.invoicingService.GetTaxProfile(r.Context(), userID)
+	if err != nil {
+		if err.Error() == "tax profile not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, "Tax profile not found")
+		} else if err.Error() == "tax profile access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else if err.Error() == "invalid user ID format" {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(profile)
+}
+
+// UpdateTaxProfile handles the request to set the tax data a user invoices with
+// @Summary Set tax profile
+// @Description Set the tax condition and document a user is invoiced with from now on. Invoices already issued keep the data they were issued with.
+// @Tags invoicing
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string false "Acting user; must be the profile user unless the admin key is given" format(uuid)
+// @Param X-Admin-Key header string false "Admin API key, to set the profile of any user"
+// @Param userId path string true "User ID" format(uuid)
+// @Param request body dto.TaxProfileRequest true "Tax profile"
+// @Success 200 {object} dto.TaxProfileDTO "Tax profile saved successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Missing user ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the profile user, or invalid admin key"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/invoicing/tax-profiles/{userId} [put]
+func (h *InvoicingHandler) UpdateTaxProfile(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := vars["userId"]
+
+	var req dto.TaxProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	profile, err := h.invoicingService.UpdateTaxProfile(r.Context(), userID, &req)
+	if err != nil {
+		if taxProfileBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "tax profile access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(profile)
+}
+
+// GetInvoice handles the request to get an invoice by ID
+// @Summary Get invoice
+// @Description Get an invoice with its lines, IVA breakdown and authorization status
+// @Tags invoicing
+// @Produce json
+// @Param X-User-ID header string false "Acting user; must be the invoiced user unless the admin key is given" format(uuid)
+// @Param X-Admin-Key header string false "Admin API key, to read any invoice"
+// @Param invoiceId path string true "Invoice ID" format(uuid)
+// @Success 200 {object} dto.InvoiceDTO "Invoice"
+// @Failure 400 {object} errors.ErrorResponse "Invalid invoice ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing user ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the invoiced user, or invalid admin key"
+// @Failure 404 {object} errors.ErrorResponse "Invoice not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/invoicing/invoices/{invoiceId} [get]
+func (h *InvoicingHandler) GetInvoice(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	invoiceID := vars["invoiceId"]
+
+	invoice, err := h.invoicingService.GetInvoice(r.Context(), invoiceID)
+	if err != nil {
+		writeInvoiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(invoice)
+}
+
+// GetCheckoutInvoice handles the request to get the invoice of a checkout
+// @Summary Get checkout invoice
+// @Description Get the invoice issued for a completed checkout
+// @Tags invoicing
+// @Produce json
+// @Param X-User-ID header string false "Acting user; must be the invoiced user unless the admin key is given" format(uuid)
+// @Param X-Admin-Key header string false "Admin API key, to read any invoice"
+// @Param checkoutId path string true "Checkout ID" format(uuid)
+// @Success 200 {object} dto.InvoiceDTO "Invoice"
+// @Failure 400 {object} errors.ErrorResponse "Invalid checkout ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing user ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the invoiced user, or invalid admin key"
+// @Failure 404 {object} errors.ErrorResponse "Invoice not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/invoicing/checkouts/{checkoutId}/invoice [get]
+func (h *InvoicingHandler) GetCheckoutInvoice(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	checkoutID := vars["checkoutId"]
+
+	invoice, err := h.invoicingService.GetCheckoutInvoice(r.Context(), checkoutID)
+	if err != nil {
+		writeInvoiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(invoice)
+}
+
+// AuthorizeInvoice handles the request to retry the authorization of a pending invoice
+// @Summary Authorize invoice
+// @Description Retry the authorization of a pending invoice with the tax authority
+// @Tags invoicing
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param invoiceId path string true "Invoice ID" format(uuid)
+// @Success 200 {object} dto.InvoiceDTO "Invoice authorized"
+// @Failure 400 {object} errors.ErrorResponse "Invalid invoice ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 404 {object} errors.ErrorResponse "Invoice not found"
+// @Failure 409 {object} errors.ErrorResponse "Invoice already authorized"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/invoicing/invoices/{invoiceId}/authorize [post]
+func (h *InvoicingHandler) AuthorizeInvoice(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	invoiceID := vars["invoiceId"]
+
+	invoice, err := h.invoicingService.AuthorizeInvoice(r.Context(), invoiceID)
+	if err != nil {
+		writeInvoiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(invoice)
+}
+
+// ExportInvoices handles the request to export the invoices issued between two dates, as
+// JSON (default) or CSV
+// @Summary Export invoices
+// @Description Export the invoices issued between two dates, as JSON or as CSV
+// @Tags invoicing
+// @Produce json
+// @Produce text/csv
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param from query string true "First day, as YYYY-MM-DD"
+// @Param to query string true "Last day, as YYYY-MM-DD"
+// @Param format query string false "Export format" Enums(json, csv) default(json)
+// @Success 200 {object} dto.InvoiceExportDTO "Invoices"
+// @Failure 400 {object} errors.ErrorResponse "Invalid dates or unsupported format"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/invoicing/invoices/export [get]
+func (h *InvoicingHandler) ExportInvoices(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "unsupported export format")
+		return
+	}
+
+	export, err := h.invoicingService.ExportInvoices(r.Context(), query.Get("from"), query.Get("to"))
+	if err != nil {
+		switch err.Error() {
+		case "invalid from date format", "invalid to date format", "from date must not be after to date":
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		default:
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="invoices-`+export.From+`-`+export.To+`.csv"`)
+		export.WriteCSV(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(export)
+}
+
+// writeInvoiceError maps invoice lookup and authorization errors to HTTP responses
+func writeInvoiceError(w http.ResponseWriter, err error) {
+	switch err.Error() {
+	case "invoice not found":
+		errors.WriteErrorResponse(w, http.StatusNotFound, "Invoice not found")
+	case "invalid invoice ID format", "invalid checkout ID format":
+		errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+	case "invoice access denied":
+		errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+	case "invoice is already authorized":
+		errors.WriteErrorResponse(w, http.StatusConflict, err.Error())
+	default:
+		errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,301 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/domain/repository"
+)
+
+// invoiceColumns lists the columns read by every invoice query, in scanInvoice order
+const invoiceColumns = `
+	id, checkout_id, user_id, type, point_of_sale, number, status, customer_tax_condition,
+	customer_document_type, customer_document_number, customer_name, lines, net_total, iva_total,
+	total, authorization_code, authorization_expires_at, issued_at, authorized_at
+`
+
+// PostgreSQLInvoiceRepository implements the InvoiceRepository interface using PostgreSQL
+type PostgreSQLInvoiceRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLInvoiceRepository creates a new PostgreSQL repository for invoices
+func NewPostgreSQLInvoiceRepository(db *sql.DB) repository.InvoiceRepository {
+	return &PostgreSQLInvoiceRepository{
+		db: db,
+	}
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanInvoice reads an invoice selected with invoiceColumns
+func scanInvoice(row rowScanner) (*model.Invoice, error) {
+	var (
+		invoice                model.Invoice
+		invoiceType            string
+		status                 string
+		taxCondition           string
+		documentType           sql.NullString
+		documentNumber         sql.NullString
+		customerName           sql.NullString
+		linesJSON              []byte
+		authorizationCode      sql.NullString
+		authorizationExpiresAt sql.NullTime
+		authorizedAt           sql.NullTime
+	)
+
+	if err := row.Scan(
+		&invoice.ID,
+		&invoice.CheckoutID,
+		&invoice.UserID,
+		&invoiceType,
+		&invoice.PointOfSale,
+		&invoice.Number,
+		&status,
+		&taxCondition,
+		&documentType,
+		&documentNumber,
+		&customerName,
+		&linesJSON,
+		&invoice.NetTotal,
+		&invoice.IVATotal,
+		&invoice.Total,
+		&authorizationCode,
+		&authorizationExpiresAt,
+		&invoice.IssuedAt,
+		&authorizedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(linesJSON, &invoice.Lines); err != nil {
+		return nil, err
+	}
+
+	invoice.Type = model.InvoiceType(invoiceType)
+	invoice.Status = model.InvoiceStatus(status)
+	invoice.CustomerTaxCondition = model.TaxCondition(taxCondition)
+	invoice.CustomerDocumentType = model.DocumentType(documentType.String)
+	invoice.CustomerDocumentNumber = documentNumber.String
+	invoice.CustomerName = customerName.String
+	invoice.AuthorizationCode = authorizationCode.String
+	if authorizationExpiresAt.Valid {
+		invoice.AuthorizationExpiresAt = &authorizationExpiresAt.Time
+	}
+	if authorizedAt.Valid {
+		invoice.AuthorizedAt = &authorizedAt.Time
+	}
+
+	return &invoice, nil
+}
+
+// FindByID retrieves an invoice by its ID
+func (r *PostgreSQLInvoiceRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Invoice, error) {
+	query := `
+		SELECT ` + invoiceColumns + `
+		FROM invoices
+		WHERE id = $1
+	`
+
+	invoice, err := scanInvoice(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("invoice not found")
+		}
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// FindByCheckoutID retrieves the invoice of a checkout
+func (r *PostgreSQLInvoiceRepository) FindByCheckoutID(ctx context.Context, checkoutID uuid.UUID) (*model.Invoice, error) {
+	query := `
+		SELECT ` + invoiceColumns + `
+		FROM invoices
+		WHERE checkout_id = $1
+	`
+
+	invoice, err := scanInvoice(r.db.QueryRowContext(ctx, query, checkoutID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("invoice not found")
+		}
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+// FindIssuedBetween retrieves the invoices issued in [from, to)
+func (r *PostgreSQLInvoiceRepository) FindIssuedBetween(ctx context.Context, from, to time.Time) ([]*model.Invoice, error) {
+	query := `
+		SELECT ` + invoiceColumns + `
+		FROM invoices
+		WHERE issued_at >= $1 AND issued_at < $2
+		ORDER BY point_of_sale, type, number
+	`
+
+	return r.findAll(ctx, query, from, to)
+}
+
+// FindPending retrieves the invoices not yet authorized
+func (r *PostgreSQLInvoiceRepository) FindPending(ctx context.Context) ([]*model.Invoice, error) {
+	query := `
+		SELECT ` + invoiceColumns + `
+		FROM invoices
+		WHERE status = $1
+		ORDER BY issued_at
+	`
+
+	return r.findAll(ctx, query, string(model.InvoiceStatusPending))
+}
+
+// findAll runs a query that selects invoiceColumns and reads every row
+func (r *PostgreSQLInvoiceRepository) findAll(ctx context.Context, query string, args ...interface{}) ([]*model.Invoice, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invoices := make([]*model.Invoice, 0)
+
+	for rows.Next() {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		invoices = append(invoices, invoice)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return invoices, nil
+}
+
+// Issue numbers and stores a new invoice. The sequence row of the point of sale and type is
+// locked until the transaction ends, so concurrent invoices get consecutive numbers, and a
+// failed insert gives its number back.
+func (r *PostgreSQLInvoiceRepository) Issue(ctx context.Context, invoice *model.Invoice) error {
+	linesJSON, err := json.Marshal(invoice.Lines)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRowContext(
+		ctx,
+		`SELECT EXISTS (SELECT 1 FROM invoices WHERE checkout_id = $1)`,
+		invoice.CheckoutID,
+	).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("checkout is already invoiced")
+	}
+
+	sequenceQuery := `
+		INSERT INTO invoice_sequences (point_of_sale, invoice_type, last_number)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (point_of_sale, invoice_type) DO UPDATE
+		SET last_number = invoice_sequences.last_number + 1
+		RETURNING last_number
+	`
+
+	var number int64
+	if err := tx.QueryRowContext(ctx, sequenceQuery, invoice.PointOfSale, string(invoice.Type)).Scan(&number); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO invoices (
+			id, checkout_id, user_id, type, point_of_sale, number, status, customer_tax_condition,
+			customer_document_type, customer_document_number, customer_name, lines, net_total,
+			iva_total, total, authorization_code, authorization_expires_at, issued_at, authorized_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+	`
+
+	if _, err := tx.ExecContext(
+		ctx,
+		query,
+		invoice.ID,
+		invoice.CheckoutID,
+		invoice.UserID,
+		string(invoice.Type),
+		invoice.PointOfSale,
+		number,
+		string(invoice.Status),
+		string(invoice.CustomerTaxCondition),
+		string(invoice.CustomerDocumentType),
+		invoice.CustomerDocumentNumber,
+		invoice.CustomerName,
+		linesJSON,
+		invoice.NetTotal,
+		invoice.IVATotal,
+		invoice.Total,
+		invoice.AuthorizationCode,
+		invoice.AuthorizationExpiresAt,
+		invoice.IssuedAt,
+		invoice.AuthorizedAt,
+	); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	invoice.Number = number
+	return nil
+}
+
+// Save persists the status and authorization of an issued invoice
+func (r *PostgreSQLInvoiceRepository) Save(ctx context.Context, invoice *model.Invoice) error {
+	query := `
+		UPDATE invoices
+		SET status = $2, authorization_code = $3, authorization_expires_at = $4, authorized_at = $5
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		invoice.ID,
+		string(invoice.Status),
+		invoice.AuthorizationCode,
+		invoice.AuthorizationExpiresAt,
+		invoice.AuthorizedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("invoice not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package postgresql
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvoiceModel is the PostgreSQL representation of an invoice
+type InvoiceModel struct {
+	ID                     uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	CheckoutID             uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex"`
+	UserID                 uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Type                   string     `gorm:"type:varchar(1);not null;uniqueIndex:idx_invoices_number"`
+	PointOfSale            int        `gorm:"type:integer;not null;uniqueIndex:idx_invoices_number"`
+	Number                 int64      `gorm:"type:bigint;not null;uniqueIndex:idx_invoices_number"`
+	Status                 string     `gorm:"type:varchar(30);not null;index"`
+	CustomerTaxCondition   string     `gorm:"type:varchar(30);not null"`
+	CustomerDocumentType   string     `gorm:"type:varchar(10)"`
+	CustomerDocumentNumber string     `gorm:"type:varchar(20)"`
+	CustomerName           string     `gorm:"type:varchar(255)"`
+	Lines                  string     `gorm:"type:jsonb;not null"`
+	NetTotal               float64    `gorm:"type:decimal(12,2);not null"`
+	IVATotal               float64    `gorm:"column:iva_total;type:decimal(12,2);not null"`
+	Total                  float64    `gorm:"type:decimal(12,2);not null"`
+	AuthorizationCode      string     `gorm:"type:varchar(20)"`
+	AuthorizationExpiresAt *time.Time `gorm:"type:timestamp with time zone"`
+	IssuedAt               time.Time  `gorm:"not null;index"`
+	AuthorizedAt           *time.Time `gorm:"type:timestamp with time zone"`
+}
+
+// TableName overrides the table name for GORM
+func (InvoiceModel) TableName() string {
+	return "invoices"
+}
+
+// InvoiceSequenceModel is the PostgreSQL representation of the last number issued by a
+// point of sale for an invoice type
+type InvoiceSequenceModel struct {
+	PointOfSale int    `gorm:"type:integer;primaryKey"`
+	InvoiceType string `gorm:"type:varchar(1);primaryKey"`
+	LastNumber  int64  `gorm:"type:bigint;not null;default:0"`
+}
+
+// TableName overrides the table name for GORM
+func (InvoiceSequenceModel) TableName() string {
+	return "invoice_sequences"
+}
+
+// TaxProfileModel is the PostgreSQL representation of a user's tax profile
+type TaxProfileModel struct {
+	UserID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	TaxCondition   string    `gorm:"type:varchar(30);not null"`
+	DocumentType   string    `gorm:"type:varchar(10)"`
+	DocumentNumber string    `gorm:"type:varchar(20)"`
+	LegalName      string    `gorm:"type:varchar(255)"`
+	UpdatedAt      time.Time `gorm:"not null;default:now()"`
+}
+
+// TableName overrides the table name for GORM
+func (TaxProfileModel) TableName() string {
+	return "tax_profiles"
+}
@@ -0,0 +1,85 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/invoicing/domain/repository"
+)
+
+// PostgreSQLTaxProfileRepository implements the TaxProfileRepository interface using PostgreSQL
+type PostgreSQLTaxProfileRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLTaxProfileRepository creates a new PostgreSQL repository for tax profiles
+func NewPostgreSQLTaxProfileRepository(db *sql.DB) repository.TaxProfileRepository {
+	return &PostgreSQLTaxProfileRepository{
+		db: db,
+	}
+}
+
+// FindByUserID retrieves the tax profile of a user
+func (r *PostgreSQLTaxProfileRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*model.TaxProfile, error) {
+	query := `
+		SELECT user_id, tax_condition, document_type, document_number, legal_name, updated_at
+		FROM tax_profiles
+		WHERE user_id = $1
+	`
+
+	var (
+		profile        model.TaxProfile
+		taxCondition   string
+		documentType   sql.NullString
+		documentNumber sql.NullString
+		legalName      sql.NullString
+	)
+
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&profile.UserID,
+		&taxCondition,
+		&documentType,
+		&documentNumber,
+		&legalName,
+		&profile.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("tax profile not found")
+		}
+		return nil, err
+	}
+
+	profile.TaxCondition = model.TaxCondition(taxCondition)
+	profile.DocumentType = model.DocumentType(documentType.String)
+	profile.DocumentNumber = documentNumber.String
+	profile.LegalName = legalName.String
+
+	return &profile, nil
+}
+
+// Save persists a tax profile (creates or updates)
+func (r *PostgreSQLTaxProfileRepository) Save(ctx context.Context, profile *model.TaxProfile) error {
+	query := `
+		INSERT INTO tax_profiles (user_id, tax_condition, document_type, document_number, legal_name, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE
+		SET tax_condition = $2, document_type = $3, document_number = $4, legal_name = $5, updated_at = $6
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		profile.UserID,
+		string(profile.TaxCondition),
+		string(profile.DocumentType),
+		profile.DocumentNumber,
+		profile.LegalName,
+		profile.UpdatedAt,
+	)
+
+	return err
+}
@@ -0,0 +1,74 @@
+package dto
+
+import (
+	cartDto "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/kiosk/domain/model"
+)
+
+// TerminalDTO represents a kiosk terminal for API responses
+type TerminalDTO struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Location   string `json:"location"`
+	Active     bool   `json:"active"`
+	CreatedAt  string `json:"createdAt"`
+	LastSeenAt string `json:"lastSeenAt,omitempty"`
+}
+
+// TerminalKeyResponse represents a terminal along with its API key. The key is only
+// returned when it is issued.
+type TerminalKeyResponse struct {
+	Terminal *TerminalDTO `json:"terminal"`
+	APIKey   string       `json:"apiKey"`
+}
+
+// TerminalRequest represents the request to register a terminal
+type TerminalRequest struct {
+	Name     string `json:"name" validate:"required"`
+	Location string `json:"location,omitempty"`
+}
+
+// SessionResponse represents a terminal session and its cart for API responses. The
+// cart is left out once the session has ended.
+type SessionResponse struct {
+	ID             string                `json:"id"`
+	TerminalID     string                `json:"terminalId"`
+	CartID         string                `json:"cartId"`
+	Status         string                `json:"status"`
+	StartedAt      string                `json:"startedAt"`
+	LastActivityAt string                `json:"lastActivityAt"`
+	ExpiresAt      string                `json:"expiresAt,omitempty"`
+	EndedAt        string                `json:"endedAt,omitempty"`
+	Cart           *cartDto.CartResponse `json:"cart,omitempty"`
+}
+
+// TerminalFromDomain converts a domain terminal to a DTO
+func TerminalFromDomain(terminal *model.Terminal) *TerminalDTO {
+	result := &TerminalDTO{
+		ID:        terminal.ID.String(),
+		Name:      terminal.Name,
+		Location:  terminal.Location,
+		Active:    terminal.Active,
+		CreatedAt: terminal.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if terminal.LastSeenAt != nil {
+		result.LastSeenAt = terminal.LastSeenAt.Format("2006-01-02T15:04:05Z")
+	}
+	return result
+}
+
+// SessionFromDomain converts a domain session to a DTO
+func SessionFromDomain(session *model.TerminalSession) *SessionResponse {
+	result := &SessionResponse{
+		ID:             session.ID.String(),
+		TerminalID:     session.TerminalID.String(),
+		CartID:         session.CartID.String(),
+		Status:         string(session.Status),
+		StartedAt:      session.StartedAt.Format("2006-01-02T15:04:05Z"),
+		LastActivityAt: session.LastActivityAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if session.EndedAt != nil {
+		result.EndedAt = session.EndedAt.Format("2006-01-02T15:04:05Z")
+	}
+	return result
+}
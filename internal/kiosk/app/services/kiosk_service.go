@@ -0,0 +1,398 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	cartServices "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services"
+	cartDto "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/kiosk/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/kiosk/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/kiosk/domain/repository"
+)
+
+// lastSeenResolution limits how often the last time a terminal was seen is written
+const lastSeenResolution = time.Minute
+
+// KioskService handles kiosk terminals and the short-lived shopping sessions run on them
+type KioskService struct {
+	terminalRepository repository.TerminalRepository
+	sessionRepository  repository.TerminalSessionRepository
+	cartService        *cartServices.CartService
+	idleTimeout        time.Duration
+}
+
+// NewKioskService creates a new kiosk service. Sessions expire after idleTimeout without activity.
+func NewKioskService(
+	terminalRepository repository.TerminalRepository,
+	sessionRepository repository.TerminalSessionRepository,
+	cartService *cartServices.CartService,
+	idleTimeout time.Duration,
+) *KioskService {
+	return &KioskService{
+		terminalRepository: terminalRepository,
+		sessionRepository:  sessionRepository,
+		cartService:        cartService,
+		idleTimeout:        idleTimeout,
+	}
+}
+
+// RegisterTerminal registers a terminal and issues its API key
+func (s *KioskService) RegisterTerminal(ctx context.Context, req *dto.TerminalRequest) (*dto.TerminalKeyResponse, error) {
+	terminal, key, err := model.NewTerminal(req.Name, req.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.terminalRepository.Save(ctx, terminal); err != nil {
+		return nil, err
+	}
+
+	return &dto.TerminalKeyResponse{
+		Terminal: dto.TerminalFromDomain(terminal),
+		APIKey:   key,
+	}, nil
+}
+
+// ListTerminals retrieves every terminal
+func (s *KioskService) ListTerminals(ctx context.Context) ([]*dto.TerminalDTO, error) {
+	terminals, err := s.terminalRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.TerminalDTO, len(terminals))
+	for i, terminal := range terminals {
+		result[i] = dto.TerminalFromDomain(terminal)
+	}
+	return result, nil
+}
+
+// RotateTerminalKey issues a new API key for a terminal, revoking the previous one
+func (s *KioskService) RotateTerminalKey(ctx context.Context, terminalID string) (*dto.TerminalKeyResponse, error) {
+	terminal, err := s.findTerminal(ctx, terminalID)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := terminal.RotateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.terminalRepository.Save(ctx, terminal); err != nil {
+		return nil, err
+	}
+
+	return &dto.TerminalKeyResponse{
+		Terminal: dto.TerminalFromDomain(terminal),
+		APIKey:   key,
+	}, nil
+}
+
+// DisableTerminal stops a terminal from authenticating and ends its open sessions
+func (s *KioskService) DisableTerminal(ctx context.Context, terminalID string) (*dto.TerminalDTO, error) {
+	terminal, err := s.findTerminal(ctx, terminalID)
+	if err != nil {
+		return nil, err
+	}
+
+	terminal.Disable()
+	if err := s.terminalRepository.Save(ctx, terminal); err != nil {
+		return nil, err
+	}
+
+	if err := s.endActiveSessions(ctx, terminal.ID); err != nil {
+		return nil, err
+	}
+
+	return dto.TerminalFromDomain(terminal), nil
+}
+
+// AuthenticateTerminal returns the active terminal that holds an API key
+func (s *KioskService) AuthenticateTerminal(ctx context.Context, key string) (*model.Terminal, error) {
+	if key == "" {
+		return nil, errors.New("terminal key is required")
+	}
+
+	terminal, err := s.terminalRepository.FindByKeyHash(ctx, model.HashTerminalKey(key))
+	if err != nil {
+		if err.Error() == "terminal not found" {
+			return nil, errors.New("invalid terminal key")
+		}
+		return nil, err
+	}
+
+	if !terminal.Active {
+		return nil, errors.New("terminal is disabled")
+	}
+
+	if terminal.LastSeenAt == nil || time.Since(*terminal.LastSeenAt) >= lastSeenResolution {
+		terminal.Seen()
+		if err := s.terminalRepository.Save(ctx, terminal); err != nil {
+			return nil, err
+		}
+	}
+
+	return terminal, nil
+}
+
+// StartSession starts a session with an empty cart at the requesting terminal. Sessions
+// left open at the terminal are ended first, so a student never sees the previous cart.
+func (s *KioskService) StartSession(ctx context.Context) (*dto.SessionResponse, error) {
+	terminal, err := requestTerminal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.endActiveSessions(ctx, terminal.ID); err != nil {
+		return nil, err
+	}
+
+	// The cart belongs to the session rather than to a user
+	session := model.NewTerminalSession(terminal.ID)
+	cart, err := s.cartService.CreateCart(sessionContext(ctx, session), &cartDto.CartCreateRequest{UserID: session.ID.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	session.CartID, err = uuid.Parse(cart.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionRepository.Save(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return s.sessionResponse(session, cart), nil
+}
+
+// GetSession retrieves a session of the requesting terminal and its cart
+func (s *KioskService) GetSession(ctx context.Context, sessionID string) (*dto.SessionResponse, error) {
+	session, err := s.activeSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := s.cartService.GetCart(sessionContext(ctx, session), session.CartID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return s.sessionResponse(session, cart), nil
+}
+
+// ScanItem adds one unit of a scanned product to the cart of a session
+func (s *KioskService) ScanItem(ctx context.Context, sessionID string, req *cartDto.CartScanRequest) (*cartDto.CartScanResponse, error) {
+	session, err := s.activeSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.cartService.ScanItem(sessionContext(ctx, session), session.CartID.String(), req)
+}
+
+// AddItem adds an item to the cart of a session
+func (s *KioskService) AddItem(ctx context.Context, sessionID string, req *cartDto.CartItemRequest) (*cartDto.CartResponse, error) {
+	session, err := s.activeSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.cartService.AddCartItem(sessionContext(ctx, session), session.CartID.String(), req)
+}
+
+// UpdateItem changes the quantity of an item in the cart of a session
+func (s *KioskService) UpdateItem(ctx context.Context, sessionID string, itemID string, req *cartDto.CartItemUpdateRequest) (*cartDto.CartResponse, error) {
+	session, err := s.activeSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.cartService.UpdateCartItem(sessionContext(ctx, session), session.CartID.String(), itemID, req)
+}
+
+// RemoveItem removes an item from the cart of a session
+func (s *KioskService) RemoveItem(ctx context.Context, sessionID string, itemID string) error {
+	session, err := s.activeSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	return s.cartService.RemoveCartItem(sessionContext(ctx, session), session.CartID.String(), itemID)
+}
+
+// EndSession ends a session of the requesting terminal and deletes its cart
+func (s *KioskService) EndSession(ctx context.Context, sessionID string) (*dto.SessionResponse, error) {
+	session, err := s.terminalSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.endSession(ctx, session, model.SessionStatusEnded); err != nil {
+		return nil, err
+	}
+
+	return s.sessionResponse(session, nil), nil
+}
+
+// ExpireIdleSessions ends the sessions that had no activity for the idle timeout and
+// deletes their carts. It returns the number of expired sessions.
+func (s *KioskService) ExpireIdleSessions(ctx context.Context) (int, error) {
+	sessions, err := s.sessionRepository.FindIdle(ctx, time.Now().Add(-s.idleTimeout))
+	if err != nil {
+		return 0, err
+	}
+
+	for i, session := range sessions {
+		if err := s.endSession(ctx, session, model.SessionStatusExpired); err != nil {
+			return i, err
+		}
+	}
+
+	return len(sessions), nil
+}
+
+// RunSessionExpiry expires idle sessions every interval until the context is cancelled
+func (s *KioskService) RunSessionExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := s.ExpireIdleSessions(ctx)
+			if err != nil {
+				log.Printf("Failed to expire kiosk sessions: %v", err)
+			} else if expired > 0 {
+				log.Printf("Expired %d idle kiosk sessions", expired)
+			}
+		}
+	}
+}
+
+// findTerminal loads a terminal by ID
+func (s *KioskService) findTerminal(ctx context.Context, terminalID string) (*model.Terminal, error) {
+	id, err := uuid.Parse(terminalID)
+	if err != nil {
+		return nil, errors.New("invalid terminal ID format")
+	}
+
+	return s.terminalRepository.FindByID(ctx, id)
+}
+
+// terminalSession loads a session and checks that it belongs to the requesting terminal
+func (s *KioskService) terminalSession(ctx context.Context, sessionID string) (*model.TerminalSession, error) {
+	terminal, err := requestTerminal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, errors.New("invalid session ID format")
+	}
+
+	session, err := s.sessionRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sessions of other terminals are not disclosed
+	if session.TerminalID != terminal.ID {
+		return nil, errors.New("session not found")
+	}
+
+	return session, nil
+}
+
+// activeSession loads a session of the requesting terminal and records activity in it.
+// A session found idle is expired on the spot, even if the sweep has not run yet.
+func (s *KioskService) activeSession(ctx context.Context, sessionID string) (*model.TerminalSession, error) {
+	session, err := s.terminalSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.IsIdle(time.Now(), s.idleTimeout) {
+		if err := s.endSession(ctx, session, model.SessionStatusExpired); err != nil {
+			return nil, err
+		}
+	}
+
+	switch session.Status {
+	case model.SessionStatusExpired:
+		return nil, errors.New("session expired")
+	case model.SessionStatusEnded:
+		return nil, errors.New("session has ended")
+	}
+
+	if err := session.Touch(); err != nil {
+		return nil, err
+	}
+	if err := s.sessionRepository.Save(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// endSession deletes the cart of a session and ends it with the given status
+func (s *KioskService) endSession(ctx context.Context, session *model.TerminalSession, status model.SessionStatus) error {
+	if err := session.End(status); err != nil {
+		return err
+	}
+
+	if err := s.cartService.DeleteCart(sessionContext(ctx, session), session.CartID.String()); err != nil && err.Error() != "cart not found" {
+		return err
+	}
+
+	return s.sessionRepository.Save(ctx, session)
+}
+
+// endActiveSessions ends every open session of a terminal
+func (s *KioskService) endActiveSessions(ctx context.Context, terminalID uuid.UUID) error {
+	sessions, err := s.sessionRepository.FindActiveByTerminalID(ctx, terminalID)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if err := s.endSession(ctx, session, model.SessionStatusEnded); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sessionResponse converts a session and its cart to a DTO
+func (s *KioskService) sessionResponse(session *model.TerminalSession, cart *cartDto.CartResponse) *dto.SessionResponse {
+	result := dto.SessionFromDomain(session)
+	if session.IsActive() {
+		result.ExpiresAt = session.LastActivityAt.Add(s.idleTimeout).Format("2006-01-02T15:04:05Z")
+		result.Cart = cart
+	}
+	return result
+}
+
+// requestTerminal returns the terminal a request was authenticated as
+func requestTerminal(ctx context.Context) (*model.Terminal, error) {
+	terminal, ok := TerminalFrom(ctx)
+	if !ok {
+		return nil, errors.New("terminal key is required")
+	}
+	return terminal, nil
+}
+
+// sessionContext returns a context whose cart operations act as the session, which owns its
+// cart, on behalf of a kiosk terminal, the only client allowed to use kiosk carts
+func sessionContext(ctx context.Context, session *model.TerminalSession) context.Context {
+	return cartServices.AsKioskTerminal(cartServices.WithActor(ctx, session.ID))
+}
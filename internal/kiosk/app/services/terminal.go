@@ -0,0 +1,21 @@
+package services
+
+import (
+	"context"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/kiosk/domain/model"
+)
+
+// terminalKey is the context key of the terminal a request comes from
+type terminalKey struct{}
+
+// WithTerminal returns a context carrying the authenticated terminal of a request
+func WithTerminal(ctx context.Context, terminal *model.Terminal) context.Context {
+	return context.WithValue(ctx, terminalKey{}, terminal)
+}
+
+// TerminalFrom returns the authenticated terminal, and false if the context carries none
+func TerminalFrom(ctx context.Context) (*model.Terminal, bool) {
+	terminal, ok := ctx.Value(terminalKey{}).(*model.Terminal)
+	return terminal, ok
+}
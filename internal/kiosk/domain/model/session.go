@@ -0,0 +1,77 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionStatus represents the state of a terminal session
+type SessionStatus string
+
+const (
+	// SessionStatusActive sessions are in use at the terminal
+	SessionStatusActive SessionStatus = "ACTIVE"
+	// SessionStatusEnded sessions were ended by the student or replaced by a new session
+	SessionStatusEnded SessionStatus = "ENDED"
+	// SessionStatusExpired sessions were ended after a period of inactivity
+	SessionStatusExpired SessionStatus = "EXPIRED"
+)
+
+// TerminalSession represents a student shopping at a terminal. The session owns a cart
+// that only lives as long as the session: the cart belongs to the session ID rather than
+// to a user, and it is deleted when the session ends or expires.
+type TerminalSession struct {
+	ID             uuid.UUID     `json:"id"`
+	TerminalID     uuid.UUID     `json:"terminalId"`
+	CartID         uuid.UUID     `json:"cartId"`
+	Status         SessionStatus `json:"status"`
+	StartedAt      time.Time     `json:"startedAt"`
+	LastActivityAt time.Time     `json:"lastActivityAt"`
+	EndedAt        *time.Time    `json:"endedAt,omitempty"`
+}
+
+// NewTerminalSession starts a session at a terminal. The cart is attached once it is created.
+func NewTerminalSession(terminalID uuid.UUID) *TerminalSession {
+	now := time.Now()
+	return &TerminalSession{
+		ID:             uuid.New(),
+		TerminalID:     terminalID,
+		Status:         SessionStatusActive,
+		StartedAt:      now,
+		LastActivityAt: now,
+	}
+}
+
+// IsActive returns true if the session was not ended
+func (s *TerminalSession) IsActive() bool {
+	return s.Status == SessionStatusActive
+}
+
+// IsIdle returns true if the session had no activity for the given timeout
+func (s *TerminalSession) IsIdle(now time.Time, timeout time.Duration) bool {
+	return s.IsActive() && now.Sub(s.LastActivityAt) >= timeout
+}
+
+// Touch records activity in the session, postponing its expiry
+func (s *TerminalSession) Touch() error {
+	if !s.IsActive() {
+		return errors.New("session has ended")
+	}
+
+	s.LastActivityAt = time.Now()
+	return nil
+}
+
+// End closes the session with the given status
+func (s *TerminalSession) End(status SessionStatus) error {
+	if !s.IsActive() {
+		return errors.New("session has ended")
+	}
+
+	now := time.Now()
+	s.Status = status
+	s.EndedAt = &now
+	return nil
+}
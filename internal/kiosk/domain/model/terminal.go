@@ -0,0 +1,90 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// terminalKeyPrefix marks terminal API keys, so they are not mistaken for other credentials
+const terminalKeyPrefix = "kt_"
+
+// terminalKeyBytes is the amount of random bytes in a terminal API key
+const terminalKeyBytes = 32
+
+// maxTerminalNameLength limits the length of a terminal name and location
+const maxTerminalNameLength = 100
+
+// Terminal represents a physical kiosk registered to run shopping sessions. Only the hash
+// of its API key is stored; the key itself is shown once, when it is issued.
+type Terminal struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Location   string     `json:"location"`
+	KeyHash    string     `json:"-"`
+	Active     bool       `json:"active"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastSeenAt *time.Time `json:"lastSeenAt,omitempty"`
+}
+
+// NewTerminal registers a terminal and returns it along with its API key
+func NewTerminal(name, location string) (*Terminal, string, error) {
+	name = strings.TrimSpace(name)
+	location = strings.TrimSpace(location)
+	if name == "" {
+		return nil, "", errors.New("terminal name is required")
+	}
+	if len(name) > maxTerminalNameLength || len(location) > maxTerminalNameLength {
+		return nil, "", errors.New("terminal name is too long")
+	}
+
+	terminal := &Terminal{
+		ID:        uuid.New(),
+		Name:      name,
+		Location:  location,
+		Active:    true,
+		CreatedAt: time.Now(),
+	}
+
+	key, err := terminal.RotateKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return terminal, key, nil
+}
+
+// RotateKey issues a new API key for the terminal, revoking the previous one
+func (t *Terminal) RotateKey() (string, error) {
+	secret := make([]byte, terminalKeyBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	key := terminalKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	t.KeyHash = HashTerminalKey(key)
+	return key, nil
+}
+
+// Disable stops the terminal from authenticating
+func (t *Terminal) Disable() {
+	t.Active = false
+}
+
+// Seen records that the terminal authenticated
+func (t *Terminal) Seen() {
+	now := time.Now()
+	t.LastSeenAt = &now
+}
+
+// HashTerminalKey returns the hash under which a terminal API key is stored
+func HashTerminalKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
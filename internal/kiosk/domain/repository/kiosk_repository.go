@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/kiosk/domain/model"
+)
+
+// TerminalRepository defines the interface for kiosk terminal persistence operations
+type TerminalRepository interface {
+	// FindByID retrieves a terminal by its ID
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Terminal, error)
+
+	// FindByKeyHash retrieves the terminal that holds an API key
+	FindByKeyHash(ctx context.Context, keyHash string) (*model.Terminal, error)
+
+	// FindAll retrieves every terminal
+	FindAll(ctx context.Context) ([]*model.Terminal, error)
+
+	// Save persists a terminal (creates or updates)
+	Save(ctx context.Context, terminal *model.Terminal) error
+}
+
+// TerminalSessionRepository defines the interface for terminal session persistence operations
+type TerminalSessionRepository interface {
+	// FindByID retrieves a session by its ID
+	FindByID(ctx context.Context, id uuid.UUID) (*model.TerminalSession, error)
+
+	// FindActiveByTerminalID retrieves the active sessions of a terminal
+	FindActiveByTerminalID(ctx context.Context, terminalID uuid.UUID) ([]*model.TerminalSession, error)
+
+	// FindIdle retrieves the active sessions with no activity since the given time
+	FindIdle(ctx context.Context, before time.Time) ([]*model.TerminalSession, error)
+
+	// Save persists a session (creates or updates)
+	Save(ctx context.Context, session *model.TerminalSession) error
+}
@@ -0,0 +1,447 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	cartDto "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services/dto"
+	cartModel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/kiosk/app/services"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/kiosk/app/services/dto"
+)
+
+// kioskBadRequestErrors lists the kiosk errors caused by invalid client input
+var kioskBadRequestErrors = map[string]bool{
+	"invalid terminal ID format":          true,
+	"invalid session ID format":           true,
+	"terminal name is required":           true,
+	"terminal name is too long":           true,
+	"invalid item ID format":              true,
+	"invalid product ID format":           true,
+	"quantity must be greater than zero":  true,
+	"price cannot be negative":            true,
+	"option name is required":             true,
+	"option value is required":            true,
+	"option value is too long":            true,
+	"option surcharge cannot be negative": true,
+	"duplicate option":                    true,
+	"barcode is required":                 true,
+	"invalid barcode":                     true,
+	"invalid barcode checksum":            true,
+}
+
+// kioskNotFoundErrors lists the kiosk errors for resources that do not exist
+var kioskNotFoundErrors = map[string]bool{
+	"terminal not found":     true,
+	"session not found":      true,
+	"item not found in cart": true,
+	"unknown barcode":        true,
+}
+
+// kioskConflictErrors lists the kiosk errors caused by the catalog state of a product
+var kioskConflictErrors = map[string]bool{
+	"product is no longer available": true,
+	"product is out of stock":        true,
+}
+
+// KioskHandler handles HTTP requests for kiosk terminals and their sessions
+type KioskHandler struct {
+	kioskService *services.KioskService
+	requireAdmin func(http.Handler) http.Handler
+}
+
+// NewKioskHandler creates a new kiosk handler. The terminal administration routes go through
+// requireAdmin, since terminals are registered from the back office.
+func NewKioskHandler(kioskService *services.KioskService, requireAdmin func(http.Handler) http.Handler) *KioskHandler {
+	return &KioskHandler{
+		kioskService: kioskService,
+		requireAdmin: requireAdmin,
+	}
+}
+
+// RegisterRoutes registers the kiosk routes on the given router
+func (h *KioskHandler) RegisterRoutes(router *mux.Router) {
+	kioskRouter := router.PathPrefix("/kiosk").Subrouter()
+
+	// Terminal administration is only available to the back office
+	terminalRouter := kioskRouter.PathPrefix("/terminals").Subrouter()
+	terminalRouter.Use(h.requireAdmin)
+
+	terminalRouter.HandleFunc("", h.RegisterTerminal).Methods("POST")
+	terminalRouter.HandleFunc("", h.ListTerminals).Methods("GET")
+	terminalRouter.HandleFunc("/{terminalId}/rotate-key", h.RotateTerminalKey).Methods("POST")
+	terminalRouter.HandleFunc("/{terminalId}", h.DisableTerminal).Methods("DELETE")
+
+	// Sessions are only available to terminals, which authenticate with their API key
+	sessionRouter := kioskRouter.PathPrefix("/sessions").Subrouter()
+	sessionRouter.Use(withTerminal(h.kioskService))
+
+	sessionRouter.HandleFunc("", h.StartSession).Methods("POST")
+	sessionRouter.HandleFunc("/{sessionId}", h.GetSession).Methods("GET")
+	sessionRouter.HandleFunc("/{sessionId}/end", h.EndSession).Methods("POST")
+	sessionRouter.HandleFunc("/{sessionId}/scan", h.ScanItem).Methods("POST")
+	sessionRouter.HandleFunc("/{sessionId}/items", h.AddItem).Methods("POST")
+	sessionRouter.HandleFunc("/{sessionId}/items/{itemId}", h.UpdateItem).Methods("PUT")
+	sessionRouter.HandleFunc("/{sessionId}/items/{itemId}", h.RemoveItem).Methods("DELETE")
+}
+
+// RegisterTerminal handles the request to register a kiosk terminal
+// @Summary Register terminal
+// @Description Register a kiosk terminal and issue its API key. The key is only returned once; store it on the terminal.
+// @Tags kiosk
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param request body dto.TerminalRequest true "Terminal"
+// @Success 201 {object} dto.TerminalKeyResponse "Terminal registered successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/kiosk/terminals [post]
+func (h *KioskHandler) RegisterTerminal(w http.ResponseWriter, r *http.Request) {
+	var req dto.TerminalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := h.kioskService.RegisterTerminal(r.Context(), &req)
+	if err != nil {
+		writeKioskError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// ListTerminals handles the request to list the kiosk terminals
+// @Summary List terminals
+// @Description List every registered kiosk terminal
+// @Tags kiosk
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Success 200 {array} dto.TerminalDTO "Terminals"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/kiosk/terminals [get]
+func (h *KioskHandler) ListTerminals(w http.ResponseWriter, r *http.Request) {
+	terminals, err := h.kioskService.ListTerminals(r.Context())
+	if err != nil {
+		writeKioskError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(terminals)
+}
+
+// RotateTerminalKey handles the request to issue a new API key for a terminal
+// @Summary Rotate terminal key
+// @Description Issue a new API key for a terminal. The previous key stops working immediately.
+// @Tags kiosk
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param terminalId path string true "Terminal ID" format(uuid)
+// @Success 200 {object} dto.TerminalKeyResponse "Key rotated successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid terminal ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 404 {object} errors.ErrorResponse "Terminal not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/kiosk/terminals/{terminalId}/rotate-key [post]
+func (h *KioskHandler) RotateTerminalKey(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	terminalID := vars["terminalId"]
+
+	response, err := h.kioskService.RotateTerminalKey(r.Context(), terminalID)
+	if err != nil {
+		writeKioskError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// DisableTerminal handles the request to disable a terminal
+// @Summary Disable terminal
+// @Description Stop a terminal from authenticating. Its open sessions are ended and their carts deleted.
+// @Tags kiosk
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param terminalId path string true "Terminal ID" format(uuid)
+// @Success 200 {object} dto.TerminalDTO "Terminal disabled successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid terminal ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 404 {object} errors.ErrorResponse "Terminal not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/kiosk/terminals/{terminalId} [delete]
+func (h *KioskHandler) DisableTerminal(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	terminalID := vars["terminalId"]
+
+	terminal, err := h.kioskService.DisableTerminal(r.Context(), terminalID)
+	if err != nil {
+		writeKioskError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(terminal)
+}
+
+// StartSession handles the request to start a shopping session at a terminal
+// @Summary Start session
+// @Description Start a session with an empty cart at the terminal. Sessions left open at the terminal are ended and their carts deleted.
+// @Tags kiosk
+// @Produce json
+// @Param X-Terminal-Key header string true "Terminal API key"
+// @Success 201 {object} dto.SessionResponse "Session started successfully"
+// @Failure 401 {object} errors.ErrorResponse "Missing or invalid terminal key"
+// @Failure 403 {object} errors.ErrorResponse "Terminal is disabled"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/kiosk/sessions [post]
+func (h *KioskHandler) StartSession(w http.ResponseWriter, r *http.Request) {
+	session, err := h.kioskService.StartSession(r.Context())
+	if err != nil {
+		writeKioskError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(session)
+}
+
+// GetSession handles the request to read a session and its cart
+// @Summary Get session
+// @Description Get a session of the terminal and its cart. Reading the session counts as activity.
+// @Tags kiosk
+// @Produce json
+// @Param X-Terminal-Key header string true "Terminal API key"
+// @Param sessionId path string true "Session ID" format(uuid)
+// @Success 200 {object} dto.SessionResponse "Session"
+// @Failure 400 {object} errors.ErrorResponse "Invalid session ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing or invalid terminal key"
+// @Failure 403 {object} errors.ErrorResponse "Terminal is disabled"
+// @Failure 404 {object} errors.ErrorResponse "Session not found"
+// @Failure 410 {object} errors.ErrorResponse "Session expired or ended"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/kiosk/sessions/{sessionId} [get]
+func (h *KioskHandler) GetSession(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+
+	session, err := h.kioskService.GetSession(r.Context(), sessionID)
+	if err != nil {
+		writeKioskError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+// EndSession handles the request to end a session
+// @Summary End session
+// @Description End a session and delete its cart, leaving nothing behind for the next student
+// @Tags kiosk
+// @Produce json
+// @Param X-Terminal-Key header string true "Terminal API key"
+// @Param sessionId path string true "Session ID" format(uuid)
+// @Success 200 {object} dto.SessionResponse "Session ended successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid session ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing or invalid terminal key"
+// @Failure 403 {object} errors.ErrorResponse "Terminal is disabled"
+// @Failure 404 {object} errors.ErrorResponse "Session not found"
+// @Failure 410 {object} errors.ErrorResponse "Session already ended"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/kiosk/sessions/{sessionId}/end [post]
+func (h *KioskHandler) EndSession(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+
+	session, err := h.kioskService.EndSession(r.Context(), sessionID)
+	if err != nil {
+		writeKioskError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(session)
+}
+
+// ScanItem handles the request to add a scanned product to the cart of a session
+// @Summary Scan item into session cart
+// @Description Add one unit of the product identified by a barcode or SKU to the cart of a session
+// @Tags kiosk
+// @Accept json
+// @Produce json
+// @Param X-Terminal-Key header string true "Terminal API key"
+// @Param sessionId path string true "Session ID" format(uuid)
+// @Param request body cartDto.CartScanRequest true "Scanned code"
+// @Success 200 {object} cartDto.CartScanResponse "Item added successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request or barcode checksum"
+// @Failure 401 {object} errors.ErrorResponse "Missing or invalid terminal key"
+// @Failure 403 {object} errors.ErrorResponse "Terminal is disabled"
+// @Failure 404 {object} errors.ErrorResponse "Session not found or unknown barcode"
+// @Failure 409 {object} errors.ErrorResponse "Product discontinued or out of stock"
+// @Failure 410 {object} errors.ErrorResponse "Session expired or ended"
+// @Failure 422 {object} errors.ValidationErrorResponse "Purchase rules violated"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/kiosk/sessions/{sessionId}/scan [post]
+func (h *KioskHandler) ScanItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+
+	var req cartDto.CartScanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	response, err := h.kioskService.ScanItem(r.Context(), sessionID, &req)
+	if err != nil {
+		writeKioskError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// AddItem handles the request to add an item to the cart of a session
+// @Summary Add item to session cart
+// @Description Add an item to the cart of a session
+// @Tags kiosk
+// @Accept json
+// @Produce json
+// @Param X-Terminal-Key header string true "Terminal API key"
+// @Param sessionId path string true "Session ID" format(uuid)
+// @Param request body cartDto.CartItemRequest true "Item"
+// @Success 200 {object} cartDto.CartResponse "Item added successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Missing or invalid terminal key"
+// @Failure 403 {object} errors.ErrorResponse "Terminal is disabled"
+// @Failure 404 {object} errors.ErrorResponse "Session not found"
+// @Failure 410 {object} errors.ErrorResponse "Session expired or ended"
+// @Failure 422 {object} errors.ValidationErrorResponse "Purchase rules violated"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/kiosk/sessions/{sessionId}/items [post]
+func (h *KioskHandler) AddItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+
+	var req cartDto.CartItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	cart, err := h.kioskService.AddItem(r.Context(), sessionID, &req)
+	if err != nil {
+		writeKioskError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cart)
+}
+
+// UpdateItem handles the request to change the quantity of an item in the cart of a session
+// @Summary Update session cart item
+// @Description Change the quantity of an item in the cart of a session
+// @Tags kiosk
+// @Accept json
+// @Produce json
+// @Param X-Terminal-Key header string true "Terminal API key"
+// @Param sessionId path string true "Session ID" format(uuid)
+// @Param itemId path string true "Item ID" format(uuid)
+// @Param request body cartDto.CartItemUpdateRequest true "Quantity"
+// @Success 200 {object} cartDto.CartResponse "Item updated successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Missing or invalid terminal key"
+// @Failure 403 {object} errors.ErrorResponse "Terminal is disabled"
+// @Failure 404 {object} errors.ErrorResponse "Session or item not found"
+// @Failure 410 {object} errors.ErrorResponse "Session expired or ended"
+// @Failure 422 {object} errors.ValidationErrorResponse "Purchase rules violated"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/kiosk/sessions/{sessionId}/items/{itemId} [put]
+func (h *KioskHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+	itemID := vars["itemId"]
+
+	var req cartDto.CartItemUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	cart, err := h.kioskService.UpdateItem(r.Context(), sessionID, itemID, &req)
+	if err != nil {
+		writeKioskError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cart)
+}
+
+// RemoveItem handles the request to remove an item from the cart of a session
+// @Summary Remove session cart item
+// @Description Remove an item from the cart of a session
+// @Tags kiosk
+// @Param X-Terminal-Key header string true "Terminal API key"
+// @Param sessionId path string true "Session ID" format(uuid)
+// @Param itemId path string true "Item ID" format(uuid)
+// @Success 204 "Item removed successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Missing or invalid terminal key"
+// @Failure 403 {object} errors.ErrorResponse "Terminal is disabled"
+// @Failure 404 {object} errors.ErrorResponse "Session or item not found"
+// @Failure 410 {object} errors.ErrorResponse "Session expired or ended"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/kiosk/sessions/{sessionId}/items/{itemId} [delete]
+func (h *KioskHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID := vars["sessionId"]
+	itemID := vars["itemId"]
+
+	if err := h.kioskService.RemoveItem(r.Context(), sessionID, itemID); err != nil {
+		writeKioskError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeKioskError writes the response for a kiosk error
+func writeKioskError(w http.ResponseWriter, err error) {
+	if violations, ok := err.(*cartModel.RuleViolationError); ok {
+		errors.WriteValidationErrorResponse(w, http.StatusUnprocessableEntity, "Purchase rules violated", cartDto.RuleViolationsFromDomain(violations))
+		return
+	}
+
+	switch {
+	case kioskBadRequestErrors[err.Error()]:
+		errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+	case err.Error() == "terminal key is required":
+		errors.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+	case kioskNotFoundErrors[err.Error()]:
+		errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+	case kioskConflictErrors[err.Error()]:
+		errors.WriteErrorResponse(w, http.StatusConflict, err.Error())
+	case err.Error() == "session expired" || err.Error() == "session has ended":
+		errors.WriteErrorResponse(w, http.StatusGone, err.Error())
+	default:
+		errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,35 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/kiosk/app/services"
+)
+
+// terminalKeyHeader carries the API key of the terminal making a session request. Terminal
+// keys are separate from user credentials: they identify the kiosk, not the student.
+const terminalKeyHeader = "X-Terminal-Key"
+
+// withTerminal authenticates the terminal named by the API key header and puts it in the
+// request context
+func withTerminal(kioskService *services.KioskService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			terminal, err := kioskService.AuthenticateTerminal(r.Context(), r.Header.Get(terminalKeyHeader))
+			if err != nil {
+				switch err.Error() {
+				case "terminal key is required", "invalid terminal key":
+					errors.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+				case "terminal is disabled":
+					errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+				default:
+					errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(services.WithTerminal(r.Context(), terminal)))
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package postgresql
+
+import (
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TerminalModel is the PostgreSQL representation of a kiosk terminal
+type TerminalModel struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Name       string    `gorm:"type:varchar(100);not null"`
+	Location   string    `gorm:"type:varchar(100);not null;default:''"`
+	KeyHash    string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	Active     bool      `gorm:"not null;default:true"`
+	CreatedAt  time.Time `gorm:"not null;default:now()"`
+	LastSeenAt *time.Time
+}
+
+// TableName overrides the table name for GORM
+func (TerminalModel) TableName() string {
+	return "kiosk_terminals"
+}
+
+// TerminalSessionModel is the PostgreSQL representation of a terminal session. The cart
+// lives in the cart context, so it is not a foreign key.
+type TerminalSessionModel struct {
+	ID             uuid.UUID     `gorm:"type:uuid;primaryKey"`
+	TerminalID     uuid.UUID     `gorm:"type:uuid;not null;index"`
+	Terminal       TerminalModel `gorm:"foreignKey:TerminalID;constraint:OnDelete:CASCADE"`
+	CartID         uuid.UUID     `gorm:"type:uuid;not null"`
+	Status         string        `gorm:"type:varchar(20);not null;index:idx_kiosk_sessions_activity,priority:1"`
+	StartedAt      time.Time     `gorm:"not null;default:now()"`
+	LastActivityAt time.Time     `gorm:"not null;default:now();index:idx_kiosk_sessions_activity,priority:2"`
+	EndedAt        *time.Time
+}
+
+// TableName overrides the table name for GORM
+func (TerminalSessionModel) TableName() string {
+	return "kiosk_sessions"
+}
+
+// MigrateKioskCarts marks the carts of the sessions started before kiosk carts were told
+// apart from the carts of users, so only terminals can use them. It is idempotent.
+func MigrateKioskCarts(db *gorm.DB) error {
+	result := db.Exec(`
+		UPDATE carts SET kiosk = true
+		WHERE NOT kiosk AND id IN (SELECT cart_id FROM kiosk_sessions)
+	`)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Marked %d kiosk session carts", result.RowsAffected)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/kiosk/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/kiosk/domain/repository"
+)
+
+// PostgreSQLTerminalSessionRepository implements the TerminalSessionRepository interface using PostgreSQL
+type PostgreSQLTerminalSessionRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLTerminalSessionRepository creates a new PostgreSQL repository for terminal sessions
+func NewPostgreSQLTerminalSessionRepository(db *sql.DB) repository.TerminalSessionRepository {
+	return &PostgreSQLTerminalSessionRepository{
+		db: db,
+	}
+}
+
+// FindByID retrieves a session by its ID
+func (r *PostgreSQLTerminalSessionRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.TerminalSession, error) {
+	query := `
+		SELECT id, terminal_id, cart_id, status, started_at, last_activity_at, ended_at
+		FROM kiosk_sessions
+		WHERE id = $1
+	`
+
+	session, err := scanSession(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("session not found")
+		}
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// FindActiveByTerminalID retrieves the active sessions of a terminal
+func (r *PostgreSQLTerminalSessionRepository) FindActiveByTerminalID(ctx context.Context, terminalID uuid.UUID) ([]*model.TerminalSession, error) {
+	query := `
+		SELECT id, terminal_id, cart_id, status, started_at, last_activity_at, ended_at
+		FROM kiosk_sessions
+		WHERE terminal_id = $1 AND status = $2
+		ORDER BY started_at
+	`
+
+	return r.findMany(ctx, query, terminalID, string(model.SessionStatusActive))
+}
+
+// FindIdle retrieves the active sessions with no activity since the given time
+func (r *PostgreSQLTerminalSessionRepository) FindIdle(ctx context.Context, before time.Time) ([]*model.TerminalSession, error) {
+	query := `
+		SELECT id, terminal_id, cart_id, status, started_at, last_activity_at, ended_at
+		FROM kiosk_sessions
+		WHERE status = $1 AND last_activity_at <= $2
+		ORDER BY last_activity_at
+	`
+
+	return r.findMany(ctx, query, string(model.SessionStatusActive), before)
+}
+
+// Save persists a session (creates or updates)
+func (r *PostgreSQLTerminalSessionRepository) Save(ctx context.Context, session *model.TerminalSession) error {
+	query := `
+		INSERT INTO kiosk_sessions (id, terminal_id, cart_id, status, started_at, last_activity_at, ended_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO UPDATE
+		SET cart_id = $3, status = $4, last_activity_at = $6, ended_at = $7
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		session.ID,
+		session.TerminalID,
+		session.CartID,
+		string(session.Status),
+		session.StartedAt,
+		session.LastActivityAt,
+		session.EndedAt,
+	)
+
+	return err
+}
+
+// findMany runs a session query that returns several rows
+func (r *PostgreSQLTerminalSessionRepository) findMany(ctx context.Context, query string, args ...interface{}) ([]*model.TerminalSession, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]*model.TerminalSession, 0)
+
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// scanSession reads a terminal session row
+func scanSession(row rowScanner) (*model.TerminalSession, error) {
+	var (
+		session model.TerminalSession
+		status  string
+		endedAt sql.NullTime
+	)
+
+	if err := row.Scan(
+		&session.ID,
+		&session.TerminalID,
+		&session.CartID,
+		&status,
+		&session.StartedAt,
+		&session.LastActivityAt,
+		&endedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	session.Status = model.SessionStatus(status)
+	if endedAt.Valid {
+		session.EndedAt = &endedAt.Time
+	}
+
+	return &session, nil
+}
@@ -0,0 +1,144 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/kiosk/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/kiosk/domain/repository"
+)
+
+// PostgreSQLTerminalRepository implements the TerminalRepository interface using PostgreSQL
+type PostgreSQLTerminalRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLTerminalRepository creates a new PostgreSQL repository for kiosk terminals
+func NewPostgreSQLTerminalRepository(db *sql.DB) repository.TerminalRepository {
+	return &PostgreSQLTerminalRepository{
+		db: db,
+	}
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// FindByID retrieves a terminal by its ID
+func (r *PostgreSQLTerminalRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Terminal, error) {
+	query := `
+		SELECT id, name, location, key_hash, active, created_at, last_seen_at
+		FROM kiosk_terminals
+		WHERE id = $1
+	`
+
+	return r.findOne(ctx, query, id)
+}
+
+// FindByKeyHash retrieves the terminal that holds an API key
+func (r *PostgreSQLTerminalRepository) FindByKeyHash(ctx context.Context, keyHash string) (*model.Terminal, error) {
+	query := `
+		SELECT id, name, location, key_hash, active, created_at, last_seen_at
+		FROM kiosk_terminals
+		WHERE key_hash = $1
+	`
+
+	return r.findOne(ctx, query, keyHash)
+}
+
+// FindAll retrieves every terminal
+func (r *PostgreSQLTerminalRepository) FindAll(ctx context.Context) ([]*model.Terminal, error) {
+	query := `
+		SELECT id, name, location, key_hash, active, created_at, last_seen_at
+		FROM kiosk_terminals
+		ORDER BY name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	terminals := make([]*model.Terminal, 0)
+
+	for rows.Next() {
+		terminal, err := scanTerminal(rows)
+		if err != nil {
+			return nil, err
+		}
+		terminals = append(terminals, terminal)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return terminals, nil
+}
+
+// Save persists a terminal (creates or updates)
+func (r *PostgreSQLTerminalRepository) Save(ctx context.Context, terminal *model.Terminal) error {
+	query := `
+		INSERT INTO kiosk_terminals (id, name, location, key_hash, active, created_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO UPDATE
+		SET name = $2, location = $3, key_hash = $4, active = $5, last_seen_at = $7
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		terminal.ID,
+		terminal.Name,
+		terminal.Location,
+		terminal.KeyHash,
+		terminal.Active,
+		terminal.CreatedAt,
+		terminal.LastSeenAt,
+	)
+
+	return err
+}
+
+// findOne runs a single-row terminal query
+func (r *PostgreSQLTerminalRepository) findOne(ctx context.Context, query string, arg interface{}) (*model.Terminal, error) {
+	terminal, err := scanTerminal(r.db.QueryRowContext(ctx, query, arg))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("terminal not found")
+		}
+		return nil, err
+	}
+
+	return terminal, nil
+}
+
+// scanTerminal reads a terminal row
+func scanTerminal(row rowScanner) (*model.Terminal, error) {
+	var (
+		terminal   model.Terminal
+		lastSeenAt sql.NullTime
+	)
+
+	if err := row.Scan(
+		&terminal.ID,
+		&terminal.Name,
+		&terminal.Location,
+		&terminal.KeyHash,
+		&terminal.Active,
+		&terminal.CreatedAt,
+		&lastSeenAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if lastSeenAt.Valid {
+		terminal.LastSeenAt = &lastSeenAt.Time
+	}
+
+	return &terminal, nil
+}
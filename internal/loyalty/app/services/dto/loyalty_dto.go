@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/loyalty/domain/model"
+)
+
+// LoyaltyEntryDTO represents a loyalty statement entry for API responses
+type LoyaltyEntryDTO struct {
+	ID          string `json:"id"`
+	CheckoutID  string `json:"checkoutId,omitempty"`
+	Type        string `json:"type"`
+	Points      int    `json:"points"`
+	Description string `json:"description"`
+	ExpiresAt   string `json:"expiresAt,omitempty"`
+	CreatedAt   string `json:"createdAt"`
+}
+
+// LoyaltyStatementDTO represents a user's loyalty balance and statement for API responses
+type LoyaltyStatementDTO struct {
+	UserID     string            `json:"userId"`
+	Balance    int               `json:"balance"`
+	PointValue float64           `json:"pointValue"`
+	Entries    []LoyaltyEntryDTO `json:"entries"`
+}
+
+// LoyaltyStatementFromDomain converts a loyalty account to a statement DTO
+func LoyaltyStatementFromDomain(account *model.LoyaltyAccount, rules *model.LoyaltyRules, now time.Time) *LoyaltyStatementDTO {
+	entries := make([]LoyaltyEntryDTO, len(account.Entries))
+	for i, entry := range account.Entries {
+		entries[i] = LoyaltyEntryDTO{
+			ID:          entry.ID.String(),
+			Type:        string(entry.Type),
+			Points:      entry.Points,
+			Description: entry.Description,
+			CreatedAt:   entry.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		}
+		if entry.CheckoutID != uuid.Nil {
+			entries[i].CheckoutID = entry.CheckoutID.String()
+		}
+		if entry.ExpiresAt != nil {
+			entries[i].ExpiresAt = entry.ExpiresAt.Format("2006-01-02T15:04:05Z")
+		}
+	}
+
+	return &LoyaltyStatementDTO{
+		UserID:     account.UserID.String(),
+		Balance:    account.Balance(now),
+		PointValue: rules.PointValue,
+		Entries:    entries,
+	}
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/auth"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/loyalty/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/loyalty/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/loyalty/domain/repository"
+)
+
+// LoyaltyService handles operations related to the loyalty program
+type LoyaltyService struct {
+	loyaltyRepository repository.LoyaltyRepository
+	rules             model.LoyaltyRules
+}
+
+// NewLoyaltyService creates a new loyalty service
+func NewLoyaltyService(loyaltyRepository repository.LoyaltyRepository, rules model.LoyaltyRules) *LoyaltyService {
+	return &LoyaltyService{
+		loyaltyRepository: loyaltyRepository,
+		rules:             rules,
+	}
+}
+
+// GetStatement retrieves the balance and statement of a user, recording any expired points first.
+// Only the user and the back office can read it.
+func (s *LoyaltyService) GetStatement(ctx context.Context, userID string) (*dto.LoyaltyStatementDTO, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+	if !auth.CanActFor(ctx, id) {
+		return nil, errors.New("loyalty access denied")
+	}
+
+	now := time.Now()
+	var account *model.LoyaltyAccount
+	if err := s.loyaltyRepository.Update(ctx, id, func(loaded *model.LoyaltyAccount) error {
+		loaded.Expire(now)
+		account = loaded
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return dto.LoyaltyStatementFromDomain(account, &s.rules, now), nil
+}
+
+// QuoteRedemption checks that the user has enough points and returns the discount they are worth
+func (s *LoyaltyService) QuoteRedemption(ctx context.Context, userID uuid.UUID, points int) (float64, error) {
+	if points <= 0 {
+		return 0, errors.New("points must be greater than zero")
+	}
+
+	account, err := s.loyaltyRepository.FindByUserID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if account.Balance(time.Now()) < points {
+		return 0, errors.New("insufficient loyalty points")
+	}
+
+	return s.rules.DiscountFor(points), nil
+}
+
+// RedeemForCheckout debits the points redeemed on a checkout
+func (s *LoyaltyService) RedeemForCheckout(ctx context.Context, userID, checkoutID uuid.UUID, points int) error {
+	return s.loyaltyRepository.Update(ctx, userID, func(account *model.LoyaltyAccount) error {
+		_, err := account.Redeem(checkoutID, points, time.Now())
+		return err
+	})
+}
+
+// EarnForCheckout credits the points earned by a completed checkout
+func (s *LoyaltyService) EarnForCheckout(ctx context.Context, userID, checkoutID uuid.UUID, lines []model.PurchaseLine) error {
+	points := s.rules.PointsFor(lines)
+	if points == 0 {
+		return nil
+	}
+
+	return s.loyaltyRepository.Update(ctx, userID, func(account *model.LoyaltyAccount) error {
+		_, err := account.Earn(checkoutID, points, s.rules.ExpirationFrom(time.Now()))
+		return err
+	})
+}
+
+// ReverseCheckout reverses the points earned and restores the points redeemed by a refunded checkout.
+// It fails if the points earned were already spent and the rest of the balance cannot cover them.
+func (s *LoyaltyService) ReverseCheckout(ctx context.Context, userID, checkoutID uuid.UUID) error {
+	now := time.Now()
+	return s.loyaltyRepository.Update(ctx, userID, func(account *model.LoyaltyAccount) error {
+		_, err := account.ReverseCheckout(checkoutID, s.rules.ExpirationFrom(now), now)
+		return err
+	})
+}
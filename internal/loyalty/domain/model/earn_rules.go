@@ -0,0 +1,52 @@
+package model
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// PurchaseLine represents the amount spent on a product category in a completed checkout
+type PurchaseLine struct {
+	Category string
+	Amount   float64
+}
+
+// LoyaltyRules holds the configurable parameters of the loyalty program
+type LoyaltyRules struct {
+	// DefaultEarnRate is the number of points earned per currency unit for categories without a specific rate
+	DefaultEarnRate float64
+	// CategoryEarnRates overrides the earn rate for specific product categories
+	CategoryEarnRates map[string]float64
+	// PointValue is the discount, in currency units, granted for each redeemed point
+	PointValue float64
+	// PointsLifetime is how long earned points remain valid
+	PointsLifetime time.Duration
+}
+
+// EarnRate returns the number of points earned per currency unit spent on the category
+func (r *LoyaltyRules) EarnRate(category string) float64 {
+	if rate, ok := r.CategoryEarnRates[strings.ToLower(category)]; ok {
+		return rate
+	}
+	return r.DefaultEarnRate
+}
+
+// PointsFor returns the points earned for a purchase, rounded down
+func (r *LoyaltyRules) PointsFor(lines []PurchaseLine) int {
+	points := 0.0
+	for _, line := range lines {
+		points += line.Amount * r.EarnRate(line.Category)
+	}
+	return int(math.Floor(points))
+}
+
+// DiscountFor returns the discount granted when redeeming the given points
+func (r *LoyaltyRules) DiscountFor(points int) float64 {
+	return math.Round(float64(points)*r.PointValue*100) / 100
+}
+
+// ExpirationFrom returns the expiry date of points earned at the given time
+func (r *LoyaltyRules) ExpirationFrom(now time.Time) time.Time {
+	return now.Add(r.PointsLifetime)
+}
@@ -0,0 +1,232 @@
+package model
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoyaltyEntryType represents the kind of movement recorded in a loyalty ledger
+type LoyaltyEntryType string
+
+const (
+	LoyaltyEntryEarn     LoyaltyEntryType = "EARN"
+	LoyaltyEntryRedeem   LoyaltyEntryType = "REDEEM"
+	LoyaltyEntryReversal LoyaltyEntryType = "REVERSAL"
+	LoyaltyEntryRestore  LoyaltyEntryType = "RESTORE"
+	LoyaltyEntryExpire   LoyaltyEntryType = "EXPIRE"
+)
+
+// LoyaltyEntry represents an append-only entry of a user's loyalty statement.
+// Points is signed. Entries that add points (EARN, RESTORE) are lots that keep
+// track of how many of their points are still available in Remaining.
+type LoyaltyEntry struct {
+	ID          uuid.UUID        `json:"id"`
+	UserID      uuid.UUID        `json:"userId"`
+	CheckoutID  uuid.UUID        `json:"checkoutId"`
+	Type        LoyaltyEntryType `json:"type"`
+	Points      int              `json:"points"`
+	Remaining   int              `json:"remaining"`
+	ExpiresAt   *time.Time       `json:"expiresAt"`
+	Description string           `json:"description"`
+	CreatedAt   time.Time        `json:"createdAt"`
+}
+
+// LoyaltyAccount represents the Loyalty aggregate root: the points ledger of a user
+type LoyaltyAccount struct {
+	UserID  uuid.UUID       `json:"userId"`
+	Entries []*LoyaltyEntry `json:"entries"`
+}
+
+// NewLoyaltyAccount creates an empty loyalty account for a user
+func NewLoyaltyAccount(userID uuid.UUID) *LoyaltyAccount {
+	return &LoyaltyAccount{
+		UserID:  userID,
+		Entries: make([]*LoyaltyEntry, 0),
+	}
+}
+
+// isLot returns true if the entry adds points that can later be consumed
+func (e *LoyaltyEntry) isLot() bool {
+	return e.Type == LoyaltyEntryEarn || e.Type == LoyaltyEntryRestore
+}
+
+// Balance returns the points available at the given time
+func (a *LoyaltyAccount) Balance(now time.Time) int {
+	balance := 0
+	for _, entry := range a.Entries {
+		if entry.isLot() && (entry.ExpiresAt == nil || now.Before(*entry.ExpiresAt)) {
+			balance += entry.Remaining
+		}
+	}
+	return balance
+}
+
+// Earn credits points for a completed checkout. The points expire at expiresAt.
+func (a *LoyaltyAccount) Earn(checkoutID uuid.UUID, points int, expiresAt time.Time) (*LoyaltyEntry, error) {
+	if points <= 0 {
+		return nil, errors.New("points must be greater than zero")
+	}
+	if a.hasEntry(checkoutID, LoyaltyEntryEarn) {
+		return nil, errors.New("points already earned for this checkout")
+	}
+
+	entry := a.append(checkoutID, LoyaltyEntryEarn, points, "Points earned on purchase")
+	entry.Remaining = points
+	entry.ExpiresAt = &expiresAt
+	return entry, nil
+}
+
+// Redeem consumes points for a checkout, oldest-expiring lots first
+func (a *LoyaltyAccount) Redeem(checkoutID uuid.UUID, points int, now time.Time) (*LoyaltyEntry, error) {
+	if points <= 0 {
+		return nil, errors.New("points must be greater than zero")
+	}
+
+	a.Expire(now)
+	if a.Balance(now) < points {
+		return nil, errors.New("insufficient loyalty points")
+	}
+
+	a.consume(points, now)
+
+	return a.append(checkoutID, LoyaltyEntryRedeem, -points, "Points redeemed at checkout"), nil
+}
+
+// ReverseCheckout undoes the movements of a refunded checkout: the points redeemed on it are
+// restored as a new lot expiring at restoreExpiresAt and the points it earned are taken back.
+// Earned points that were already spent are deducted from the rest of the balance, restored
+// points included; when the balance cannot cover them the checkout cannot be reversed.
+// Reversing the same checkout twice is a no-op.
+func (a *LoyaltyAccount) ReverseCheckout(checkoutID uuid.UUID, restoreExpiresAt time.Time, now time.Time) ([]*LoyaltyEntry, error) {
+	if a.hasEntry(checkoutID, LoyaltyEntryReversal) || a.hasEntry(checkoutID, LoyaltyEntryRestore) {
+		return nil, nil
+	}
+
+	a.Expire(now)
+
+	var earnLot *LoyaltyEntry
+	expiredPoints := 0
+	redeemedPoints := 0
+	for _, entry := range a.Entries {
+		if entry.CheckoutID != checkoutID {
+			continue
+		}
+		switch entry.Type {
+		case LoyaltyEntryEarn:
+			earnLot = entry
+		case LoyaltyEntryExpire:
+			// Until the checkout is reversed, only its EARN lot can expire under its ID
+			expiredPoints -= entry.Points
+		case LoyaltyEntryRedeem:
+			redeemedPoints -= entry.Points
+		}
+	}
+
+	unspentPoints := 0
+	spentPoints := 0
+	if earnLot != nil {
+		unspentPoints = earnLot.Remaining
+		spentPoints = earnLot.Points - earnLot.Remaining - expiredPoints
+	}
+	if spentPoints > a.Balance(now)-unspentPoints+redeemedPoints {
+		return nil, errors.New("loyalty points earned on the checkout were already spent")
+	}
+
+	var entries []*LoyaltyEntry
+	if redeemedPoints > 0 {
+		restore := a.append(checkoutID, LoyaltyEntryRestore, redeemedPoints, "Redeemed points restored on refund")
+		restore.Remaining = redeemedPoints
+		restore.ExpiresAt = &restoreExpiresAt
+		entries = append(entries, restore)
+	}
+	if earnLot != nil {
+		earnLot.Remaining = 0
+		a.consume(spentPoints, now)
+	}
+	if reversedPoints := unspentPoints + spentPoints; reversedPoints > 0 {
+		entries = append(entries, a.append(checkoutID, LoyaltyEntryReversal, -reversedPoints, "Points reversed on refund"))
+	}
+
+	return entries, nil
+}
+
+// Expire records the expiration of the points left in every lot whose expiry date has passed
+func (a *LoyaltyAccount) Expire(now time.Time) []*LoyaltyEntry {
+	var expiredLots []*LoyaltyEntry
+	for _, lot := range a.Entries {
+		if lot.isLot() && lot.Remaining > 0 && lot.ExpiresAt != nil && !now.Before(*lot.ExpiresAt) {
+			expiredLots = append(expiredLots, lot)
+		}
+	}
+
+	entries := make([]*LoyaltyEntry, 0, len(expiredLots))
+	for _, lot := range expiredLots {
+		entries = append(entries, a.append(lot.CheckoutID, LoyaltyEntryExpire, -lot.Remaining, "Points expired"))
+		lot.Remaining = 0
+	}
+	return entries
+}
+
+// consume takes points from the available lots, oldest-expiring first. The balance must cover them.
+func (a *LoyaltyAccount) consume(points int, now time.Time) {
+	pending := points
+	for _, lot := range a.availableLots(now) {
+		if pending == 0 {
+			break
+		}
+		used := lot.Remaining
+		if used > pending {
+			used = pending
+		}
+		lot.Remaining -= used
+		pending -= used
+	}
+}
+
+// availableLots returns the lots with points left, oldest-expiring first
+func (a *LoyaltyAccount) availableLots(now time.Time) []*LoyaltyEntry {
+	var lots []*LoyaltyEntry
+	for _, entry := range a.Entries {
+		if entry.isLot() && entry.Remaining > 0 && (entry.ExpiresAt == nil || now.Before(*entry.ExpiresAt)) {
+			lots = append(lots, entry)
+		}
+	}
+	sort.SliceStable(lots, func(i, j int) bool {
+		if lots[i].ExpiresAt == nil {
+			return false
+		}
+		if lots[j].ExpiresAt == nil {
+			return true
+		}
+		return lots[i].ExpiresAt.Before(*lots[j].ExpiresAt)
+	})
+	return lots
+}
+
+// hasEntry returns true if the account already has an entry of the type for the checkout
+func (a *LoyaltyAccount) hasEntry(checkoutID uuid.UUID, entryType LoyaltyEntryType) bool {
+	for _, entry := range a.Entries {
+		if entry.CheckoutID == checkoutID && entry.Type == entryType {
+			return true
+		}
+	}
+	return false
+}
+
+// append adds a new entry to the statement
+func (a *LoyaltyAccount) append(checkoutID uuid.UUID, entryType LoyaltyEntryType, points int, description string) *LoyaltyEntry {
+	entry := &LoyaltyEntry{
+		ID:          uuid.New(),
+		UserID:      a.UserID,
+		CheckoutID:  checkoutID,
+		Type:        entryType,
+		Points:      points,
+		Description: description,
+		CreatedAt:   time.Now(),
+	}
+	a.Entries = append(a.Entries, entry)
+	return entry
+}
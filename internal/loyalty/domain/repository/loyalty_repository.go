@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/loyalty/domain/model"
+)
+
+// LoyaltyRepository defines the interface for loyalty ledger persistence operations
+type LoyaltyRepository interface {
+	// FindByUserID retrieves the loyalty account of a user. A user without entries gets an empty account.
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*model.LoyaltyAccount, error)
+
+	// Update loads the account of a user, applies change to it and persists the result (appends
+	// new entries and updates remaining points of lots). The account stays locked until the
+	// result is saved, so concurrent changes to the same account are applied one after the
+	// other. Nothing is saved if change returns an error.
+	Update(ctx context.Context, userID uuid.UUID, change func(*model.LoyaltyAccount) error) error
+}
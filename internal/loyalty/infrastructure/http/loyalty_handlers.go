@@ -0,0 +1,71 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/loyalty/app/services"
+)
+
+// LoyaltyHandler handles HTTP requests for loyalty operations
+type LoyaltyHandler struct {
+	loyaltyService *services.LoyaltyService
+	requireUser    func(http.Handler) http.Handler
+}
+
+// NewLoyaltyHandler creates a new loyalty handler. Statements go through requireUser, so they
+// are only read by their own user or the back office.
+func NewLoyaltyHandler(loyaltyService *services.LoyaltyService, requireUser func(http.Handler) http.Handler) *LoyaltyHandler {
+	return &LoyaltyHandler{
+		loyaltyService: loyaltyService,
+		requireUser:    requireUser,
+	}
+}
+
+// RegisterRoutes registers the loyalty routes on the given router
+func (h *LoyaltyHandler) RegisterRoutes(router *mux.Router) {
+	// Create a subrouter for loyalty routes
+	loyaltyRouter := router.PathPrefix("/loyalty").Subrouter()
+
+	// Requests act on behalf of the user named in the X-User-ID header
+	loyaltyRouter.Use(h.requireUser)
+
+	// Register routes
+	loyaltyRouter.HandleFunc("/{userId}", h.GetStatement).Methods("GET")
+}
+
+// GetStatement handles the request to get a user's loyalty balance and statement
+// @Summary Get loyalty statement
+// @Description Get the points balance of a user and the statement of points earned, redeemed, reversed and expired. Expired points are recorded first.
+// @Tags loyalty
+// @Produce json
+// @Param X-User-ID header string false "Acting user; must be the statement user unless the admin key is given" format(uuid)
+// @Param X-Admin-Key header string false "Admin API key, to read the statement of any user"
+// @Param userId path string true "User ID" format(uuid)
+// @Success 200 {object} dto.LoyaltyStatementDTO "Loyalty statement"
+// @Failure 400 {object} errors.ErrorResponse "Invalid user ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing user ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the statement user, or invalid admin key"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/loyalty/{userId} [get]
+func (h *LoyaltyHandler) GetStatement(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := vars["userId"]
+
+	statement, err := h.loyaltyService.GetStatement(r.Context(), userID)
+	if err != nil {
+		if err.Error() == "invalid user ID format" {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "loyalty access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statement)
+}
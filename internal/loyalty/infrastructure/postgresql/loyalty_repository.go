@@ -0,0 +1,166 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/loyalty/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/loyalty/domain/repository"
+)
+
+// loyaltyEntryColumns lists the columns read into a loyalty entry, in scan order
+const loyaltyEntryColumns = `id, user_id, checkout_id, type, points, remaining, expires_at, description, created_at`
+
+// queryer is implemented by both *sql.DB and *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// PostgreSQLLoyaltyRepository implements the LoyaltyRepository interface using PostgreSQL
+type PostgreSQLLoyaltyRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLLoyaltyRepository creates a new PostgreSQL repository for loyalty accounts
+func NewPostgreSQLLoyaltyRepository(db *sql.DB) repository.LoyaltyRepository {
+	return &PostgreSQLLoyaltyRepository{
+		db: db,
+	}
+}
+
+// FindByUserID retrieves the loyalty account of a user
+func (r *PostgreSQLLoyaltyRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*model.LoyaltyAccount, error) {
+	query := `
+		SELECT ` + loyaltyEntryColumns + `
+		FROM loyalty_entries
+		WHERE user_id = $1
+		ORDER BY created_at ASC
+	`
+
+	return findAccount(ctx, r.db, query, userID)
+}
+
+// Update applies a change to the account of a user in a single transaction. The entries are
+// read with FOR UPDATE, so two redemptions cannot spend the same lot and a checkout cannot be
+// reversed twice. Only new entries and lots whose remaining points changed are written.
+func (r *PostgreSQLLoyaltyRepository) Update(ctx context.Context, userID uuid.UUID, change func(*model.LoyaltyAccount) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT ` + loyaltyEntryColumns + `
+		FROM loyalty_entries
+		WHERE user_id = $1
+		ORDER BY created_at ASC
+		FOR UPDATE
+	`
+
+	account, err := findAccount(ctx, tx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	loaded := make(map[uuid.UUID]int, len(account.Entries))
+	for _, entry := range account.Entries {
+		loaded[entry.ID] = entry.Remaining
+	}
+
+	if err := change(account); err != nil {
+		return err
+	}
+
+	insert := `
+		INSERT INTO loyalty_entries (id, user_id, checkout_id, type, points, remaining, expires_at, description, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	update := `UPDATE loyalty_entries SET remaining = $2 WHERE id = $1`
+
+	for _, entry := range account.Entries {
+		remaining, exists := loaded[entry.ID]
+		if exists {
+			if remaining != entry.Remaining {
+				if _, err := tx.ExecContext(ctx, update, entry.ID, entry.Remaining); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		var expiresAt sql.NullTime
+		if entry.ExpiresAt != nil {
+			expiresAt = sql.NullTime{Time: *entry.ExpiresAt, Valid: true}
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			insert,
+			entry.ID,
+			entry.UserID,
+			uuid.NullUUID{UUID: entry.CheckoutID, Valid: entry.CheckoutID != uuid.Nil},
+			entry.Type,
+			entry.Points,
+			entry.Remaining,
+			expiresAt,
+			entry.Description,
+			entry.CreatedAt,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// findAccount runs a query selecting loyaltyEntryColumns and builds the account of the user
+func findAccount(ctx context.Context, db queryer, query string, userID uuid.UUID) (*model.LoyaltyAccount, error) {
+	rows, err := db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	account := model.NewLoyaltyAccount(userID)
+
+	for rows.Next() {
+		var (
+			entry      model.LoyaltyEntry
+			checkoutID uuid.NullUUID
+			entryType  string
+			expiresAt  sql.NullTime
+		)
+
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&checkoutID,
+			&entryType,
+			&entry.Points,
+			&entry.Remaining,
+			&expiresAt,
+			&entry.Description,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		entry.Type = model.LoyaltyEntryType(entryType)
+		if checkoutID.Valid {
+			entry.CheckoutID = checkoutID.UUID
+		}
+		if expiresAt.Valid {
+			entry.ExpiresAt = &expiresAt.Time
+		}
+
+		account.Entries = append(account.Entries, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
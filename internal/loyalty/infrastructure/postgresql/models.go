@@ -0,0 +1,25 @@
+package postgresql
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoyaltyEntryModel is the PostgreSQL representation of a loyalty ledger entry
+type LoyaltyEntryModel struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index"`
+	CheckoutID  *uuid.UUID `gorm:"type:uuid;index"`
+	Type        string     `gorm:"type:varchar(20);not null"`
+	Points      int        `gorm:"type:integer;not null"`
+	Remaining   int        `gorm:"type:integer;not null;default:0"`
+	ExpiresAt   *time.Time `gorm:"type:timestamp with time zone"`
+	Description string     `gorm:"type:varchar(255)"`
+	CreatedAt   time.Time  `gorm:"not null;default:now()"`
+}
+
+// TableName overrides the table name for GORM
+func (LoyaltyEntryModel) TableName() string {
+	return "loyalty_entries"
+}
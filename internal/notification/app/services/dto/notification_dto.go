@@ -0,0 +1,74 @@
+package dto
+
+import (
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/domain/model"
+)
+
+// ContactRequest represents the request to set where and in which language a user is notified
+type ContactRequest struct {
+	Email  string `json:"email" validate:"required,email"`
+	Name   string `json:"name"`
+	Locale string `json:"locale" validate:"omitempty,oneof=es en"`
+}
+
+// ContactDTO represents a notification contact for API responses
+type ContactDTO struct {
+	UserID    string `json:"userId"`
+	Email     string `json:"email"`
+	Name      string `json:"name,omitempty"`
+	Locale    string `json:"locale"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// ContactFromDomain converts a domain contact to a DTO
+func ContactFromDomain(contact *model.Contact) *ContactDTO {
+	return &ContactDTO{
+		UserID:    contact.UserID.String(),
+		Email:     contact.Email,
+		Name:      contact.Name,
+		Locale:    string(contact.Locale),
+		UpdatedAt: contact.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// NotificationDTO represents a notification for API responses. The bodies are left out;
+// they can be large and the subject identifies the email.
+type NotificationDTO struct {
+	ID            string `json:"id"`
+	Kind          string `json:"kind"`
+	Reference     string `json:"reference"`
+	Recipient     string `json:"recipient"`
+	Locale        string `json:"locale"`
+	Subject       string `json:"subject"`
+	Status        string `json:"status"`
+	Attempts      int    `json:"attempts"`
+	NextAttemptAt string `json:"nextAttemptAt,omitempty"`
+	LastError     string `json:"lastError,omitempty"`
+	CreatedAt     string `json:"createdAt"`
+	SentAt        string `json:"sentAt,omitempty"`
+}
+
+// NotificationFromDomain converts a domain notification to a DTO
+func NotificationFromDomain(notification *model.Notification) *NotificationDTO {
+	result := &NotificationDTO{
+		ID:        notification.ID.String(),
+		Kind:      string(notification.Kind),
+		Reference: notification.Reference,
+		Recipient: notification.Recipient,
+		Locale:    string(notification.Locale),
+		Subject:   notification.Subject,
+		Status:    string(notification.Status),
+		Attempts:  notification.Attempts,
+		LastError: notification.LastError,
+		CreatedAt: notification.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	if notification.Status == model.StatusPending {
+		result.NextAttemptAt = notification.NextAttemptAt.Format("2006-01-02T15:04:05Z")
+	}
+	if notification.SentAt != nil {
+		result.SentAt = notification.SentAt.Format("2006-01-02T15:04:05Z")
+	}
+
+	return result
+}
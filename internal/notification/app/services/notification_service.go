@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/auth"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/domain/repository"
+)
+
+const (
+	// historyLimit is how many notifications are listed per user
+	historyLimit = 50
+	// deliveryBatchSize is how many due notifications are delivered per run
+	deliveryBatchSize = 100
+	// deliveryLease is how long the notifications claimed by a run are kept from other
+	// replicas; the ones the run did not save by then are delivered again
+	deliveryLease = 5 * time.Minute
+)
+
+// NotificationService handles the emails sent to shoppers at checkout milestones. Emails are
+// rendered and queued when the event happens and delivered in the background, so a mail
+// server outage never fails the operation that triggered them.
+type NotificationService struct {
+	notificationRepository repository.NotificationRepository
+	contactRepository      repository.ContactRepository
+	renderer               repository.TemplateRenderer
+	sender                 repository.Sender
+	retryPolicy            model.RetryPolicy
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(
+	notificationRepository repository.NotificationRepository,
+	contactRepository repository.ContactRepository,
+	renderer repository.TemplateRenderer,
+	sender repository.Sender,
+	retryPolicy model.RetryPolicy,
+) *NotificationService {
+	return &NotificationService{
+		notificationRepository: notificationRepository,
+		contactRepository:      contactRepository,
+		renderer:               renderer,
+		sender:                 sender,
+		retryPolicy:            retryPolicy,
+	}
+}
+
+// GetContact retrieves the notification contact of a user. Like the rest of a user's
+// notification data, only the user and the back office can use it.
+func (s *NotificationService) GetContact(ctx context.Context, userID string) (*dto.ContactDTO, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+	if !auth.CanActFor(ctx, id) {
+		return nil, errors.New("notification access denied")
+	}
+
+	contact, err := s.contactRepository.FindByUserID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.ContactFromDomain(contact), nil
+}
+
+// UpdateContact sets the email address and language a user is notified in from now on
+func (s *NotificationService) UpdateContact(ctx context.Context, userID string, req *dto.ContactRequest) (*dto.ContactDTO, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+	if !auth.CanActFor(ctx, id) {
+		return nil, errors.New("notification access denied")
+	}
+
+	contact, err := model.NewContact(id, req.Email, req.Name, model.Locale(req.Locale))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.contactRepository.Save(ctx, contact); err != nil {
+		return nil, err
+	}
+
+	return dto.ContactFromDomain(contact), nil
+}
+
+// DeleteContact removes the contact of a user, who stops getting notifications. Emails
+// already queued are still delivered.
+func (s *NotificationService) DeleteContact(ctx context.Context, userID string) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return errors.New("invalid user ID format")
+	}
+	if !auth.CanActFor(ctx, id) {
+		return errors.New("notification access denied")
+	}
+
+	return s.contactRepository.Delete(ctx, id)
+}
+
+// ListNotifications retrieves the latest notifications of a user, newest first
+func (s *NotificationService) ListNotifications(ctx context.Context, userID string) ([]*dto.NotificationDTO, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+	if !auth.CanActFor(ctx, id) {
+		return nil, errors.New("notification access denied")
+	}
+
+	notifications, err := s.notificationRepository.FindByUserID(ctx, id, historyLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.NotificationDTO, len(notifications))
+	for i, notification := range notifications {
+		result[i] = dto.NotificationFromDomain(notification)
+	}
+
+	return result, nil
+}
+
+// NotifyOrderConfirmed queues the confirmation of a completed order, or of an order waiting
+// to be paid at the counter
+func (s *NotificationService) NotifyOrderConfirmed(ctx context.Context, order *model.OrderSummary) error {
+	return s.enqueue(ctx, model.KindOrderConfirmation, order.CheckoutID.String(), order.UserID, &model.TemplateData{Order: order})
+}
+
+// NotifyOrderCancelled queues the notice of an order cancelled for not being paid in time
+func (s *NotificationService) NotifyOrderCancelled(ctx context.Context, order *model.OrderSummary) error {
+	return s.enqueue(ctx, model.KindOrderCancellation, order.CheckoutID.String(), order.UserID, &model.TemplateData{Order: order})
+}
+
+// NotifyOrderRefunded queues the notice of a refunded order
+func (s *NotificationService) NotifyOrderRefunded(ctx context.Context, order *model.OrderSummary) error {
+	return s.enqueue(ctx, model.KindOrderRefund, order.CheckoutID.String(), order.UserID, &model.TemplateData{Order: order})
+}
+
+// NotifyAbandonedCart queues a reminder of a cart left with items. A cart is reminded once
+// per change, so a shopper who comes back and leaves again gets a new reminder.
+func (s *NotificationService) NotifyAbandonedCart(ctx context.Context, cart *model.CartSummary) error {
+	reference := fmt.Sprintf("%s@%d", cart.CartID, cart.UpdatedAt.Unix())
+	return s.enqueue(ctx, model.KindAbandonedCart, reference, cart.UserID, &model.TemplateData{Cart: cart})
+}
+
+// enqueue renders the email of an event in the user's language and queues it. Users without
+// a contact are skipped, and an event already notified is not queued again.
+func (s *NotificationService) enqueue(ctx context.Context, kind model.Kind, reference string, userID uuid.UUID, data *model.TemplateData) error {
+	contact, err := s.contactRepository.FindByUserID(ctx, userID)
+	if err != nil {
+		if err.Error() == "contact not found" {
+			return nil
+		}
+		return err
+	}
+
+	data.RecipientName = contact.Name
+	content, err := s.renderer.Render(kind, contact.Locale, data)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.notificationRepository.Enqueue(ctx, model.NewNotification(kind, reference, contact, content))
+	return err
+}
+
+// DeliverDue sends the notifications whose next attempt is due. It returns how many were sent;
+// failed deliveries are rescheduled following the retry policy. The notifications are claimed
+// first, so several replicas can deliver at the same time without sending any twice.
+func (s *NotificationService) DeliverDue(ctx context.Context) (int, error) {
+	notifications, err := s.notificationRepository.ClaimDue(ctx, time.Now(), deliveryLease, deliveryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, notification := range notifications {
+		if err := s.sender.Send(ctx, notification); err != nil {
+			notification.MarkFailed(err, time.Now(), s.retryPolicy)
+			if notification.Status == model.StatusFailed {
+				log.Printf("Giving up on notification %s to %s: %v", notification.ID, notification.Recipient, err)
+			}
+		} else {
+			notification.MarkSent(time.Now())
+			sent++
+		}
+
+		if err := s.notificationRepository.Save(ctx, notification); err != nil {
+			return sent, err
+		}
+	}
+
+	return sent, nil
+}
+
+// RunDelivery delivers due notifications every interval until the context is cancelled
+func (s *NotificationService) RunDelivery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sent, err := s.DeliverDue(ctx)
+			if err != nil {
+				log.Printf("Failed to deliver notifications: %v", err)
+			}
+			if sent > 0 {
+				log.Printf("Sent %d notifications", sent)
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Locale represents the language notifications are written in
+type Locale string
+
+const (
+	LocaleSpanish Locale = "es"
+	LocaleEnglish Locale = "en"
+)
+
+// DefaultLocale is used for contacts that did not choose a language
+const DefaultLocale = LocaleSpanish
+
+// Contact represents where and in which language a user gets notifications. Users without
+// a contact are not notified.
+type Contact struct {
+	UserID    uuid.UUID `json:"userId"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Locale    Locale    `json:"locale"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// NewContact creates a contact, validating the email address. An empty locale gets DefaultLocale.
+func NewContact(userID uuid.UUID, email, name string, locale Locale) (*Contact, error) {
+	if userID == uuid.Nil {
+		return nil, errors.New("user ID is required")
+	}
+
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil, errors.New("email is required")
+	}
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return nil, errors.New("invalid email address")
+	}
+
+	name = strings.TrimSpace(name)
+	if len(name) > 100 {
+		return nil, errors.New("name is too long")
+	}
+
+	switch locale {
+	case "":
+		locale = DefaultLocale
+	case LocaleSpanish, LocaleEnglish:
+	default:
+		return nil, errors.New("unsupported locale")
+	}
+
+	return &Contact{
+		UserID:    userID,
+		Email:     email,
+		Name:      name,
+		Locale:    locale,
+		UpdatedAt: time.Now(),
+	}, nil
+}
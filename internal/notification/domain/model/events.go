@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderSummary represents a checkout as shown in order emails
+type OrderSummary struct {
+	CheckoutID uuid.UUID
+	UserID     uuid.UUID
+	OrderCode  string
+	Lines      []SummaryLine
+	Subtotal   float64
+	Discounts  float64
+	Shipping   float64
+	Tax        float64
+	Total      float64
+}
+
+// CartSummary represents a cart as shown in abandoned cart reminders
+type CartSummary struct {
+	CartID    uuid.UUID
+	UserID    uuid.UUID
+	CartName  string
+	Lines     []SummaryLine
+	Subtotal  float64
+	UpdatedAt time.Time
+}
+
+// SummaryLine represents an item line of an order or a cart
+type SummaryLine struct {
+	Name     string
+	Quantity int
+	Amount   float64
+}
+
+// TemplateData is the data the email templates are rendered with. Only one of Order and
+// Cart is set, depending on the kind of notification.
+type TemplateData struct {
+	RecipientName string
+	Order         *OrderSummary
+	Cart          *CartSummary
+}
@@ -0,0 +1,117 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kind represents the event a notification tells the shopper about
+type Kind string
+
+const (
+	KindOrderConfirmation Kind = "ORDER_CONFIRMATION"
+	KindOrderCancellation Kind = "ORDER_CANCELLATION"
+	KindOrderRefund       Kind = "ORDER_REFUND"
+	KindAbandonedCart     Kind = "ABANDONED_CART"
+)
+
+// Status represents the delivery status of a notification
+type Status string
+
+const (
+	// StatusPending notifications are waiting for their next delivery attempt
+	StatusPending Status = "PENDING"
+	// StatusSent notifications were accepted by the sender
+	StatusSent Status = "SENT"
+	// StatusFailed notifications ran out of delivery attempts
+	StatusFailed Status = "FAILED"
+)
+
+// maxRetryDelay caps the wait between two delivery attempts
+const maxRetryDelay = 24 * time.Hour
+
+// RetryPolicy decides how many times and how often a notification is retried. The wait
+// doubles after every failed attempt, starting at BaseDelay.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+}
+
+// Delay returns the wait before the attempt that follows the given number of failed attempts
+func (p RetryPolicy) Delay(failedAttempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < failedAttempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
+
+// Content represents the rendered subject and bodies of an email
+type Content struct {
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
+
+// Notification represents the Notification aggregate root: an email queued for a shopper.
+// Reference identifies the event it was sent for, so the same event is notified only once.
+type Notification struct {
+	ID            uuid.UUID  `json:"id"`
+	Kind          Kind       `json:"kind"`
+	Reference     string     `json:"reference"`
+	UserID        uuid.UUID  `json:"userId"`
+	Recipient     string     `json:"recipient"`
+	Locale        Locale     `json:"locale"`
+	Subject       string     `json:"subject"`
+	TextBody      string     `json:"textBody"`
+	HTMLBody      string     `json:"htmlBody"`
+	Status        Status     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"nextAttemptAt"`
+	LastError     string     `json:"lastError"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	SentAt        *time.Time `json:"sentAt"`
+}
+
+// NewNotification creates a notification for a contact, due right away
+func NewNotification(kind Kind, reference string, contact *Contact, content *Content) *Notification {
+	now := time.Now()
+	return &Notification{
+		ID:            uuid.New(),
+		Kind:          kind,
+		Reference:     reference,
+		UserID:        contact.UserID,
+		Recipient:     contact.Email,
+		Locale:        contact.Locale,
+		Subject:       content.Subject,
+		TextBody:      content.TextBody,
+		HTMLBody:      content.HTMLBody,
+		Status:        StatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
+
+// MarkSent records a successful delivery
+func (n *Notification) MarkSent(now time.Time) {
+	n.Attempts++
+	n.Status = StatusSent
+	n.LastError = ""
+	n.SentAt = &now
+}
+
+// MarkFailed records a failed delivery and schedules the next attempt, or gives up once
+// the policy runs out of attempts
+func (n *Notification) MarkFailed(cause error, now time.Time, policy RetryPolicy) {
+	n.Attempts++
+	n.LastError = cause.Error()
+	if n.Attempts >= policy.MaxAttempts {
+		n.Status = StatusFailed
+		return
+	}
+	n.NextAttemptAt = now.Add(policy.Delay(n.Attempts))
+}
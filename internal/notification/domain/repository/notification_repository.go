@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/domain/model"
+)
+
+// NotificationRepository defines the interface for notification persistence operations
+type NotificationRepository interface {
+	// FindByUserID retrieves the latest notifications of a user, newest first
+	FindByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]*model.Notification, error)
+
+	// ClaimDue claims the pending notifications whose next attempt is due, oldest first, by
+	// moving their next attempt to the end of the lease. Concurrent callers claim different
+	// notifications, and the ones not saved before the lease ends are due again.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.Notification, error)
+
+	// Enqueue stores a new notification. It returns false, without storing it, when a
+	// notification of the same kind and reference already exists.
+	Enqueue(ctx context.Context, notification *model.Notification) (bool, error)
+
+	// Save persists the delivery status of a notification
+	Save(ctx context.Context, notification *model.Notification) error
+}
+
+// ContactRepository defines the interface for notification contact persistence operations
+type ContactRepository interface {
+	// FindByUserID retrieves the contact of a user
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*model.Contact, error)
+
+	// Save persists a contact (creates or updates)
+	Save(ctx context.Context, contact *model.Contact) error
+
+	// Delete removes the contact of a user
+	Delete(ctx context.Context, userID uuid.UUID) error
+}
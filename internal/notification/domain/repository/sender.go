@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/domain/model"
+)
+
+// Sender defines the interface to deliver a notification email
+type Sender interface {
+	// Send delivers the notification to its recipient
+	Send(ctx context.Context, notification *model.Notification) error
+}
+
+// TemplateRenderer defines the interface to render the email of a kind of notification
+type TemplateRenderer interface {
+	// Render returns the subject and bodies of the email in the given locale
+	Render(kind model.Kind, locale model.Locale, data *model.TemplateData) (*model.Content, error)
+}
@@ -0,0 +1,189 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/app/services"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/app/services/dto"
+)
+
+// contactBadRequestErrors lists the contact errors caused by invalid client input
+var contactBadRequestErrors = map[string]bool{
+	"invalid user ID format": true,
+	"user ID is required":    true,
+	"email is required":      true,
+	"invalid email address":  true,
+	"name is too long":       true,
+	"unsupported locale":     true,
+}
+
+// NotificationHandler handles HTTP requests for notification operations
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+	requireUser         func(http.Handler) http.Handler
+}
+
+// NewNotificationHandler creates a new notification handler. Every route goes through
+// requireUser, so contacts and notifications are only used by their own user or the back office.
+func NewNotificationHandler(notificationService *services.NotificationService, requireUser func(http.Handler) http.Handler) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+		requireUser:         requireUser,
+	}
+}
+
+// RegisterRoutes registers the notification routes on the given router
+func (h *NotificationHandler) RegisterRoutes(router *mux.Router) {
+	// Create a subrouter for notification routes
+	notificationRouter := router.PathPrefix("/notifications").Subrouter()
+
+	// Requests act on behalf of the user named in the X-User-ID header
+	notificationRouter.Use(h.requireUser)
+
+	// Register routes
+	notificationRouter.HandleFunc("/contacts/{userId}", h.GetContact).Methods("GET")
+	notificationRouter.HandleFunc("/contacts/{userId}", h.UpdateContact).Methods("PUT")
+	notificationRouter.HandleFunc("/contacts/{userId}", h.DeleteContact).Methods("DELETE")
+	notificationRouter.HandleFunc("/users/{userId}", h.ListNotifications).Methods("GET")
+}
+
+// GetContact handles the request to get where and in which language a user is notified
+// @Summary Get notification contact
+// @Description Get the email address and language a user is notified in
+// @Tags notifications
+// @Produce json
+// @Param X-User-ID header string false "Acting user; must be the contact user unless the admin key is given" format(uuid)
+// @Param X-Admin-Key header string false "Admin API key, to read the contact of any user"
+// @Param userId path string true "User ID" format(uuid)
+// @Success 200 {object} dto.ContactDTO "Contact"
+// @Failure 400 {object} errors.ErrorResponse "Invalid user ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing user ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the contact user, or invalid admin key"
+// @Failure 404 {object} errors.ErrorResponse "Contact not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/notifications/contacts/{userId} [get]
+func (h *NotificationHandler) GetContact(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := vars["userId"]
+
+	contact, err := h.notificationService.GetContact(r.Context(), userID)
+	if err != nil {
+		writeContactError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(contact)
+}
+
+// UpdateContact handles the request to set where and in which language a user is notified
+// @Summary Set notification contact
+// @Description Set the email address and language (es or en) a user is notified in from now on
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string false "Acting user; must be the contact user unless the admin key is given" format(uuid)
+// @Param X-Admin-Key header string false "Admin API key, to set the contact of any user"
+// @Param userId path string true "User ID" format(uuid)
+// @Param request body dto.ContactRequest true "Contact"
+// @Success 200 {object} dto.ContactDTO "Contact saved successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Missing user ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the contact user, or invalid admin key"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/notifications/contacts/{userId} [put]
+func (h *NotificationHandler) UpdateContact(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := vars["userId"]
+
+	var req dto.ContactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	contact, err := h.notificationService.UpdateContact(r.Context(), userID, &req)
+	if err != nil {
+		writeContactError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(contact)
+}
+
+// DeleteContact handles the request to stop notifying a user
+// @Summary Delete notification contact
+// @Description Remove the contact of a user, who stops getting notifications. Emails already queued are still delivered.
+// @Tags notifications
+// @Param X-User-ID header string false "Acting user; must be the contact user unless the admin key is given" format(uuid)
+// @Param X-Admin-Key header string false "Admin API key, to delete the contact of any user"
+// @Param userId path string true "User ID" format(uuid)
+// @Success 204 "Contact deleted successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid user ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing user ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the contact user, or invalid admin key"
+// @Failure 404 {object} errors.ErrorResponse "Contact not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/notifications/contacts/{userId} [delete]
+func (h *NotificationHandler) DeleteContact(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := vars["userId"]
+
+	if err := h.notificationService.DeleteContact(r.Context(), userID); err != nil {
+		writeContactError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListNotifications handles the request to list the latest notifications of a user
+// @Summary List notifications
+// @Description List the latest notifications queued for a user, newest first, with their delivery status
+// @Tags notifications
+// @Produce json
+// @Param X-User-ID header string false "Acting user; must be the notified user unless the admin key is given" format(uuid)
+// @Param X-Admin-Key header string false "Admin API key, to list the notifications of any user"
+// @Param userId path string true "User ID" format(uuid)
+// @Success 200 {array} dto.NotificationDTO "Notifications"
+// @Failure 400 {object} errors.ErrorResponse "Invalid user ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing user ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the notified user, or invalid admin key"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/notifications/users/{userId} [get]
+func (h *NotificationHandler) ListNotifications(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := vars["userId"]
+
+	notifications, err := h.notificationService.ListNotifications(r.Context(), userID)
+	if err != nil {
+		if err.Error() == "invalid user ID format" {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else if err.Error() == "notification access denied" {
+			errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(notifications)
+}
+
+// writeContactError maps a contact error to its HTTP response
+func writeContactError(w http.ResponseWriter, err error) {
+	if err.Error() == "contact not found" {
+		errors.WriteErrorResponse(w, http.StatusNotFound, "Contact not found")
+	} else if err.Error() == "notification access denied" {
+		errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+	} else if contactBadRequestErrors[err.Error()] {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+	} else {
+		errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+	}
+}
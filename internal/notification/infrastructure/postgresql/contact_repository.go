@@ -0,0 +1,97 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/domain/repository"
+)
+
+// PostgreSQLContactRepository implements the ContactRepository interface using PostgreSQL
+type PostgreSQLContactRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLContactRepository creates a new PostgreSQL repository for notification contacts
+func NewPostgreSQLContactRepository(db *sql.DB) repository.ContactRepository {
+	return &PostgreSQLContactRepository{
+		db: db,
+	}
+}
+
+// FindByUserID retrieves the contact of a user
+func (r *PostgreSQLContactRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*model.Contact, error) {
+	query := `
+		SELECT user_id, email, name, locale, updated_at
+		FROM notification_contacts
+		WHERE user_id = $1
+	`
+
+	var (
+		contact model.Contact
+		name    sql.NullString
+		locale  string
+	)
+
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&contact.UserID,
+		&contact.Email,
+		&name,
+		&locale,
+		&contact.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("contact not found")
+		}
+		return nil, err
+	}
+
+	contact.Name = name.String
+	contact.Locale = model.Locale(locale)
+
+	return &contact, nil
+}
+
+// Save persists a contact (creates or updates)
+func (r *PostgreSQLContactRepository) Save(ctx context.Context, contact *model.Contact) error {
+	query := `
+		INSERT INTO notification_contacts (user_id, email, name, locale, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET email = $2, name = $3, locale = $4, updated_at = $5
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		contact.UserID,
+		contact.Email,
+		contact.Name,
+		string(contact.Locale),
+		contact.UpdatedAt,
+	)
+
+	return err
+}
+
+// Delete removes the contact of a user
+func (r *PostgreSQLContactRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM notification_contacts WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("contact not found")
+	}
+
+	return nil
+}
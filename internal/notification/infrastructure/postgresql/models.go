@@ -0,0 +1,45 @@
+package postgresql
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationModel is the PostgreSQL representation of a notification
+type NotificationModel struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	Kind          string     `gorm:"type:varchar(30);not null;uniqueIndex:idx_notifications_event"`
+	Reference     string     `gorm:"type:varchar(100);not null;uniqueIndex:idx_notifications_event"`
+	UserID        uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Recipient     string     `gorm:"type:varchar(255);not null"`
+	Locale        string     `gorm:"type:varchar(5);not null"`
+	Subject       string     `gorm:"type:varchar(255);not null"`
+	TextBody      string     `gorm:"type:text;not null"`
+	HTMLBody      string     `gorm:"column:html_body;type:text;not null"`
+	Status        string     `gorm:"type:varchar(20);not null;index:idx_notifications_due"`
+	Attempts      int        `gorm:"type:integer;not null;default:0"`
+	NextAttemptAt time.Time  `gorm:"not null;index:idx_notifications_due"`
+	LastError     string     `gorm:"type:text"`
+	CreatedAt     time.Time  `gorm:"not null;default:now()"`
+	SentAt        *time.Time `gorm:"type:timestamp with time zone"`
+}
+
+// TableName overrides the table name for GORM
+func (NotificationModel) TableName() string {
+	return "notifications"
+}
+
+// ContactModel is the PostgreSQL representation of a user's notification contact
+type ContactModel struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Email     string    `gorm:"type:varchar(255);not null"`
+	Name      string    `gorm:"type:varchar(100)"`
+	Locale    string    `gorm:"type:varchar(5);not null"`
+	UpdatedAt time.Time `gorm:"not null;default:now()"`
+}
+
+// TableName overrides the table name for GORM
+func (ContactModel) TableName() string {
+	return "notification_contacts"
+}
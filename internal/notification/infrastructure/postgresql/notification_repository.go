@@ -0,0 +1,213 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/domain/repository"
+)
+
+// notificationColumns lists the columns read by every notification query, in scanNotification order
+const notificationColumns = `
+	id, kind, reference, user_id, recipient, locale, subject, text_body, html_body, status,
+	attempts, next_attempt_at, last_error, created_at, sent_at
+`
+
+// PostgreSQLNotificationRepository implements the NotificationRepository interface using PostgreSQL
+type PostgreSQLNotificationRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLNotificationRepository creates a new PostgreSQL repository for notifications
+func NewPostgreSQLNotificationRepository(db *sql.DB) repository.NotificationRepository {
+	return &PostgreSQLNotificationRepository{
+		db: db,
+	}
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanNotification reads a notification selected with notificationColumns
+func scanNotification(row rowScanner) (*model.Notification, error) {
+	var (
+		notification model.Notification
+		kind         string
+		locale       string
+		status       string
+		lastError    sql.NullString
+		sentAt       sql.NullTime
+	)
+
+	if err := row.Scan(
+		&notification.ID,
+		&kind,
+		&notification.Reference,
+		&notification.UserID,
+		&notification.Recipient,
+		&locale,
+		&notification.Subject,
+		&notification.TextBody,
+		&notification.HTMLBody,
+		&status,
+		&notification.Attempts,
+		&notification.NextAttemptAt,
+		&lastError,
+		&notification.CreatedAt,
+		&sentAt,
+	); err != nil {
+		return nil, err
+	}
+
+	notification.Kind = model.Kind(kind)
+	notification.Locale = model.Locale(locale)
+	notification.Status = model.Status(status)
+	notification.LastError = lastError.String
+	if sentAt.Valid {
+		notification.SentAt = &sentAt.Time
+	}
+
+	return &notification, nil
+}
+
+// FindByUserID retrieves the latest notifications of a user, newest first
+func (r *PostgreSQLNotificationRepository) FindByUserID(ctx context.Context, userID uuid.UUID, limit int) ([]*model.Notification, error) {
+	query := `
+		SELECT ` + notificationColumns + `
+		FROM notifications
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	return r.findAll(ctx, query, userID, limit)
+}
+
+// ClaimDue claims the pending notifications whose next attempt is due, oldest first. Rows
+// locked by another claim are skipped, so replicas delivering at the same time never claim
+// the same notification.
+func (r *PostgreSQLNotificationRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.Notification, error) {
+	query := `
+		UPDATE notifications
+		SET next_attempt_at = $3
+		WHERE id IN (
+			SELECT id
+			FROM notifications
+			WHERE status = $1 AND next_attempt_at <= $2
+			ORDER BY next_attempt_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + notificationColumns + `
+	`
+
+	return r.findAll(ctx, query, string(model.StatusPending), now, now.Add(lease), limit)
+}
+
+// findAll runs a query that selects notificationColumns and reads every row
+func (r *PostgreSQLNotificationRepository) findAll(ctx context.Context, query string, args ...interface{}) ([]*model.Notification, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := make([]*model.Notification, 0)
+
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// Enqueue stores a new notification unless one of the same kind and reference exists. The
+// unique index on both columns makes the check safe against concurrent enqueues.
+func (r *PostgreSQLNotificationRepository) Enqueue(ctx context.Context, notification *model.Notification) (bool, error) {
+	query := `
+		INSERT INTO notifications (
+			id, kind, reference, user_id, recipient, locale, subject, text_body, html_body, status,
+			attempts, next_attempt_at, last_error, created_at, sent_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		ON CONFLICT (kind, reference) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		notification.ID,
+		string(notification.Kind),
+		notification.Reference,
+		notification.UserID,
+		notification.Recipient,
+		string(notification.Locale),
+		notification.Subject,
+		notification.TextBody,
+		notification.HTMLBody,
+		string(notification.Status),
+		notification.Attempts,
+		notification.NextAttemptAt,
+		notification.LastError,
+		notification.CreatedAt,
+		notification.SentAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// Save persists the delivery status of a notification
+func (r *PostgreSQLNotificationRepository) Save(ctx context.Context, notification *model.Notification) error {
+	query := `
+		UPDATE notifications
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5, sent_at = $6
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		notification.ID,
+		string(notification.Status),
+		notification.Attempts,
+		notification.NextAttemptAt,
+		notification.LastError,
+		notification.SentAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("notification not found")
+	}
+
+	return nil
+}
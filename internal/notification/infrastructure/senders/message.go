@@ -0,0 +1,82 @@
+package senders
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/domain/model"
+)
+
+// buildMessage returns the notification as an RFC 5322 message with a plain text and an
+// HTML alternative, ready to be handed to an SMTP server or written to an .eml file
+func buildMessage(from string, notification *model.Notification) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+
+	if err := writePart(parts, "text/plain; charset=UTF-8", notification.TextBody); err != nil {
+		return nil, err
+	}
+	if err := writePart(parts, "text/html; charset=UTF-8", notification.HTMLBody); err != nil {
+		return nil, err
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	domain := "localhost"
+	if address := envelopeAddress(from); strings.Contains(address, "@") {
+		domain = address[strings.LastIndex(address, "@")+1:]
+	}
+
+	var message bytes.Buffer
+	headers := [][2]string{
+		{"From", from},
+		{"To", (&mail.Address{Address: notification.Recipient}).String()},
+		{"Subject", mime.QEncoding.Encode("UTF-8", notification.Subject)},
+		{"Date", notification.CreatedAt.Format("Mon, 02 Jan 2006 15:04:05 -0700")},
+		{"Message-ID", fmt.Sprintf("<%s@%s>", notification.ID, domain)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", parts.Boundary())},
+	}
+	for _, header := range headers {
+		fmt.Fprintf(&message, "%s: %s\r\n", header[0], header[1])
+	}
+	message.WriteString("\r\n")
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
+
+// writePart adds a quoted-printable encoded part to a multipart body
+func writePart(parts *multipart.Writer, contentType, content string) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType)
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+
+	part, err := parts.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	encoder := quotedprintable.NewWriter(part)
+	if _, err := encoder.Write([]byte(content)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// envelopeAddress returns the bare address of a From header, e.g. "kiosko@example.com" for
+// "Kiosko <kiosko@example.com>"
+func envelopeAddress(from string) string {
+	address, err := mail.ParseAddress(from)
+	if err != nil {
+		return from
+	}
+	return address.Address
+}
@@ -0,0 +1,45 @@
+package senders
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/domain/repository"
+)
+
+// OutboxSender implements the Sender interface by writing every email as an .eml file to a
+// directory instead of sending it, so notifications can be checked locally without a mail server
+type OutboxSender struct {
+	dir  string
+	from string
+}
+
+// NewOutboxSender creates a new outbox sender writing to the given directory
+func NewOutboxSender(dir, from string) repository.Sender {
+	return &OutboxSender{
+		dir:  dir,
+		from: from,
+	}
+}
+
+// Send writes the notification to the outbox directory
+func (s *OutboxSender) Send(ctx context.Context, notification *model.Notification) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	message, err := buildMessage(s.from, notification)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", notification.CreatedAt.UTC().Format("20060102T150405"), notification.ID)
+	return os.WriteFile(filepath.Join(s.dir, name), message, 0o644)
+}
@@ -0,0 +1,47 @@
+package senders
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+	"strconv"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/domain/repository"
+)
+
+// SMTPSender implements the Sender interface by relaying the emails through an SMTP server
+type SMTPSender struct {
+	address string
+	auth    smtp.Auth
+	from    string
+}
+
+// NewSMTPSender creates a new SMTP sender. The server is authenticated against with PLAIN
+// auth when a username is given, which net/smtp only allows over TLS or to localhost.
+func NewSMTPSender(host string, port int, username, password, from string) repository.Sender {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPSender{
+		address: net.JoinHostPort(host, strconv.Itoa(port)),
+		auth:    auth,
+		from:    from,
+	}
+}
+
+// Send relays the notification to the SMTP server
+func (s *SMTPSender) Send(ctx context.Context, notification *model.Notification) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	message, err := buildMessage(s.from, notification)
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(s.address, s.auth, envelopeAddress(s.from), []string{notification.Recipient}, message)
+}
@@ -0,0 +1,133 @@
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmlTemplate "html/template"
+	"strings"
+	textTemplate "text/template"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/notification/domain/repository"
+)
+
+// files holds the email templates. Every kind has, per locale, a text template defining the
+// "subject" and "text" blocks and an HTML template defining the "content" block of the layout.
+//
+//go:embed layout.html.tmpl es/*.tmpl en/*.tmpl
+var files embed.FS
+
+// kinds lists the kinds of notification there are templates for
+var kinds = []model.Kind{
+	model.KindOrderConfirmation,
+	model.KindOrderCancellation,
+	model.KindOrderRefund,
+	model.KindAbandonedCart,
+}
+
+// locales lists the locales there are templates for
+var locales = []model.Locale{model.LocaleSpanish, model.LocaleEnglish}
+
+// templateSet holds the parsed templates of a kind in a locale
+type templateSet struct {
+	text *textTemplate.Template
+	html *htmlTemplate.Template
+}
+
+// TemplateRenderer implements the TemplateRenderer interface with the embedded Go templates
+type TemplateRenderer struct {
+	sets map[string]*templateSet
+}
+
+// NewTemplateRenderer creates a new renderer, parsing every template up front. The templates
+// are embedded in the binary, so a parse error is a programming error and panics.
+func NewTemplateRenderer() repository.TemplateRenderer {
+	renderer := &TemplateRenderer{
+		sets: make(map[string]*templateSet),
+	}
+
+	for _, locale := range locales {
+		funcs := formatFuncs(locale)
+		for _, kind := range kinds {
+			name := fmt.Sprintf("%s/%s", locale, strings.ToLower(string(kind)))
+			renderer.sets[setKey(kind, locale)] = &templateSet{
+				text: textTemplate.Must(textTemplate.New("").Funcs(funcs).ParseFS(files, name+".txt.tmpl")),
+				html: htmlTemplate.Must(htmlTemplate.New("").Funcs(funcs).ParseFS(files, "layout.html.tmpl", name+".html.tmpl")),
+			}
+		}
+	}
+
+	return renderer
+}
+
+// setKey returns the key of the templates of a kind in a locale
+func setKey(kind model.Kind, locale model.Locale) string {
+	return string(locale) + "/" + string(kind)
+}
+
+// Render returns the subject and bodies of the email of a kind of notification
+func (r *TemplateRenderer) Render(kind model.Kind, locale model.Locale, data *model.TemplateData) (*model.Content, error) {
+	set, ok := r.sets[setKey(kind, locale)]
+	if !ok {
+		set, ok = r.sets[setKey(kind, model.DefaultLocale)]
+	}
+	if !ok {
+		return nil, errors.New("no template for notification kind")
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := set.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, err
+	}
+	if err := set.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return nil, err
+	}
+	if err := set.html.ExecuteTemplate(&html, "layout", data); err != nil {
+		return nil, err
+	}
+
+	return &model.Content{
+		Subject:  strings.TrimSpace(subject.String()),
+		TextBody: strings.TrimSpace(text.String()) + "\n",
+		HTMLBody: html.String(),
+	}, nil
+}
+
+// formatFuncs returns the functions the templates format amounts with, following the
+// conventions of the locale
+func formatFuncs(locale model.Locale) map[string]interface{} {
+	return map[string]interface{}{
+		"money": func(amount float64) string {
+			return formatMoney(amount, locale)
+		},
+	}
+}
+
+// formatMoney formats an amount of pesos, e.g. "$ 1.234,50" in Spanish and "$1,234.50" in English
+func formatMoney(amount float64, locale model.Locale) string {
+	thousands, decimal, prefix := ".", ",", "$ "
+	if locale == model.LocaleEnglish {
+		thousands, decimal, prefix = ",", ".", "$"
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := fmt.Sprintf("%.2f", amount)
+	whole, cents := digits[:len(digits)-3], digits[len(digits)-2:]
+
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteString(thousands)
+		}
+		grouped.WriteRune(digit)
+	}
+
+	return sign + prefix + grouped.String() + decimal + cents
+}
@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/domain/model"
+)
+
+// RosterRowError describes a roster file row that could not be imported
+type RosterRowError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// RosterImportResult represents the outcome of a roster import for API responses
+type RosterImportResult struct {
+	Imported int              `json:"imported"`
+	Students int              `json:"students"`
+	Staff    int              `json:"staff"`
+	Errors   []RosterRowError `json:"errors"`
+}
+
+// MembershipVerifyRequest represents the request to verify a user's padrón or legajo number
+type MembershipVerifyRequest struct {
+	UserID         string `json:"userId" validate:"required,uuid"`
+	IdentifierType string `json:"identifierType" validate:"required,oneof=PADRON LEGAJO"`
+	Identifier     string `json:"identifier" validate:"required"`
+}
+
+// MembershipDTO represents a user's segment membership for API responses
+type MembershipDTO struct {
+	UserID         string `json:"userId"`
+	Segment        string `json:"segment"`
+	IdentifierType string `json:"identifierType"`
+	Identifier     string `json:"identifier"`
+	VerifiedAt     string `json:"verifiedAt"`
+}
+
+// PriceRuleRequest represents the request to create a segment price rule
+type PriceRuleRequest struct {
+	Segment         string  `json:"segment" validate:"required,oneof=STUDENT STAFF"`
+	ProductID       string  `json:"productId,omitempty" validate:"omitempty,uuid"`
+	Category        string  `json:"category,omitempty"`
+	DiscountPercent float64 `json:"discountPercent" validate:"gt=0,lt=100"`
+}
+
+// PriceRuleDTO represents a segment price rule for API responses
+type PriceRuleDTO struct {
+	ID              string  `json:"id"`
+	Segment         string  `json:"segment"`
+	ProductID       string  `json:"productId,omitempty"`
+	Category        string  `json:"category,omitempty"`
+	DiscountPercent float64 `json:"discountPercent"`
+	Active          bool    `json:"active"`
+	CreatedAt       string  `json:"createdAt"`
+}
+
+// MembershipFromDomain converts a membership domain model to a DTO
+func MembershipFromDomain(membership *model.Membership) *MembershipDTO {
+	return &MembershipDTO{
+		UserID:         membership.UserID.String(),
+		Segment:        string(membership.Segment),
+		IdentifierType: string(membership.IdentifierType),
+		Identifier:     membership.Identifier,
+		VerifiedAt:     membership.VerifiedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// PriceRuleFromDomain converts a price rule domain model to a DTO
+func PriceRuleFromDomain(rule *model.PriceRule) *PriceRuleDTO {
+	result := &PriceRuleDTO{
+		ID:              rule.ID.String(),
+		Segment:         string(rule.Segment),
+		Category:        rule.Category,
+		DiscountPercent: rule.DiscountPercent,
+		Active:          rule.Active,
+		CreatedAt:       rule.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+	if rule.ProductID != uuid.Nil {
+		result.ProductID = rule.ProductID.String()
+	}
+	return result
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/domain/model"
+)
+
+// rosterHeader is the optional header row of a roster file
+var rosterHeader = []string{"identifier_type", "identifier", "full_name", "valid_until"}
+
+// parseRoster reads a roster CSV file with the columns identifier_type (PADRON or LEGAJO),
+// identifier, full_name and an optional valid_until date (YYYY-MM-DD, exclusive).
+// Every invalid row is reported so the file can be fixed in a single pass.
+func parseRoster(r io.Reader) ([]*model.RosterMember, []dto.RosterRowError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	members := make([]*model.RosterMember, 0)
+	rowErrors := make([]dto.RosterRowError, 0)
+	seen := make(map[string]int)
+
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, errors.New("roster file is not a valid CSV file")
+		}
+
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), rosterHeader[0]) {
+			continue
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		member, err := parseRosterRecord(record)
+		if err != nil {
+			rowErrors = append(rowErrors, dto.RosterRowError{Line: line, Message: err.Error()})
+			continue
+		}
+
+		key := string(member.IdentifierType) + ":" + member.Identifier
+		if previous, ok := seen[key]; ok {
+			rowErrors = append(rowErrors, dto.RosterRowError{
+				Line:    line,
+				Message: fmt.Sprintf("duplicate identifier, already listed on line %d", previous),
+			})
+			continue
+		}
+		seen[key] = line
+
+		members = append(members, member)
+	}
+
+	return members, rowErrors, nil
+}
+
+// parseRosterRecord converts a CSV record into a roster entry
+func parseRosterRecord(record []string) (*model.RosterMember, error) {
+	if len(record) < 2 || len(record) > len(rosterHeader) {
+		return nil, fmt.Errorf("expected between 2 and %d columns", len(rosterHeader))
+	}
+
+	identifierType := model.IdentifierType(strings.ToUpper(strings.TrimSpace(record[0])))
+	fullName := ""
+	if len(record) > 2 {
+		fullName = record[2]
+	}
+
+	var validUntil *time.Time
+	if len(record) > 3 && strings.TrimSpace(record[3]) != "" {
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(record[3]))
+		if err != nil {
+			return nil, errors.New("valid_until must be a date formatted as YYYY-MM-DD")
+		}
+		validUntil = &date
+	}
+
+	return model.NewRosterMember(identifierType, record[1], fullName, validUntil)
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/domain/repository"
+)
+
+// ErrInvalidRoster is returned when a roster file has rows that cannot be imported
+var ErrInvalidRoster = errors.New("roster file has invalid rows")
+
+// SegmentService handles customer segment membership and segment prices
+type SegmentService struct {
+	rosterRepository     repository.RosterRepository
+	membershipRepository repository.MembershipRepository
+	priceRuleRepository  repository.PriceRuleRepository
+}
+
+// NewSegmentService creates a new segment service
+func NewSegmentService(
+	rosterRepository repository.RosterRepository,
+	membershipRepository repository.MembershipRepository,
+	priceRuleRepository repository.PriceRuleRepository,
+) *SegmentService {
+	return &SegmentService{
+		rosterRepository:     rosterRepository,
+		membershipRepository: membershipRepository,
+		priceRuleRepository:  priceRuleRepository,
+	}
+}
+
+// ImportRoster replaces the student and staff roster with the contents of a CSV file.
+// The file is imported only if every row is valid; otherwise the result lists the
+// invalid rows and ErrInvalidRoster is returned.
+func (s *SegmentService) ImportRoster(ctx context.Context, file io.Reader) (*dto.RosterImportResult, error) {
+	members, rowErrors, err := parseRoster(file)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.RosterImportResult{Errors: rowErrors}
+	if len(rowErrors) > 0 {
+		return result, ErrInvalidRoster
+	}
+	if len(members) == 0 {
+		return nil, errors.New("roster file is empty")
+	}
+
+	if err := s.rosterRepository.ReplaceAll(ctx, members); err != nil {
+		return nil, err
+	}
+
+	result.Imported = len(members)
+	for _, member := range members {
+		if member.Segment() == model.SegmentStudent {
+			result.Students++
+		} else {
+			result.Staff++
+		}
+	}
+
+	return result, nil
+}
+
+// VerifyMembership checks a padrón or legajo number against the roster and links the
+// verified segment to the user. A roster entry can only be linked to one user.
+func (s *SegmentService) VerifyMembership(ctx context.Context, req *dto.MembershipVerifyRequest) (*dto.MembershipDTO, error) {
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+
+	identifierType := model.IdentifierType(req.IdentifierType)
+	if _, err := model.SegmentFor(identifierType); err != nil {
+		return nil, err
+	}
+	identifier := model.NormalizeIdentifier(req.Identifier)
+
+	member, err := s.rosterRepository.FindMember(ctx, identifierType, identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.membershipRepository.FindByIdentifier(ctx, identifierType, identifier)
+	if err == nil && existing.UserID != userID {
+		return nil, errors.New("identifier is already linked to another user")
+	}
+
+	membership, err := model.NewMembership(userID, member, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.membershipRepository.Save(ctx, membership); err != nil {
+		return nil, err
+	}
+
+	return dto.MembershipFromDomain(membership), nil
+}
+
+// GetMembership retrieves the segment membership of a user
+func (s *SegmentService) GetMembership(ctx context.Context, userID string) (*dto.MembershipDTO, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+
+	membership, err := s.membershipRepository.FindByUserID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.MembershipFromDomain(membership), nil
+}
+
+// CreatePriceRule creates a price rule for a segment
+func (s *SegmentService) CreatePriceRule(ctx context.Context, req *dto.PriceRuleRequest) (*dto.PriceRuleDTO, error) {
+	productID := uuid.Nil
+	if req.ProductID != "" {
+		id, err := uuid.Parse(req.ProductID)
+		if err != nil {
+			return nil, errors.New("invalid product ID format")
+		}
+		productID = id
+	}
+
+	rule, err := model.NewPriceRule(model.Segment(req.Segment), productID, req.Category, req.DiscountPercent)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.priceRuleRepository.Save(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	return dto.PriceRuleFromDomain(rule), nil
+}
+
+// ListPriceRules retrieves every segment price rule
+func (s *SegmentService) ListPriceRules(ctx context.Context) ([]*dto.PriceRuleDTO, error) {
+	rules, err := s.priceRuleRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.PriceRuleDTO, len(rules))
+	for i, rule := range rules {
+		result[i] = dto.PriceRuleFromDomain(rule)
+	}
+
+	return result, nil
+}
+
+// DeactivatePriceRule stops applying a price rule
+func (s *SegmentService) DeactivatePriceRule(ctx context.Context, ruleID string) error {
+	id, err := uuid.Parse(ruleID)
+	if err != nil {
+		return errors.New("invalid price rule ID format")
+	}
+
+	rule, err := s.priceRuleRepository.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	rule.Active = false
+	return s.priceRuleRepository.Save(ctx, rule)
+}
+
+// PriceLines returns the segment price of each line for the user, or nil for the lines
+// without a discount. Users without a membership, or whose roster entry was removed or
+// has expired since it was verified, get no segment prices.
+func (s *SegmentService) PriceLines(ctx context.Context, userID uuid.UUID, lines []model.PriceLine) ([]*model.SegmentPrice, error) {
+	prices := make([]*model.SegmentPrice, len(lines))
+
+	membership, err := s.membershipRepository.FindByUserID(ctx, userID)
+	if err != nil {
+		if err.Error() == "membership not found" {
+			return prices, nil
+		}
+		return nil, err
+	}
+
+	member, err := s.rosterRepository.FindMember(ctx, membership.IdentifierType, membership.Identifier)
+	if err != nil {
+		if err.Error() == "roster member not found" {
+			return prices, nil
+		}
+		return nil, err
+	}
+	if !member.IsValid(time.Now()) {
+		return prices, nil
+	}
+
+	rules, err := s.priceRuleRepository.FindActiveBySegment(ctx, membership.Segment)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, line := range lines {
+		if rule := model.BestRule(rules, line); rule != nil {
+			prices[i] = rule.Apply(line)
+		}
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PriceRule represents a percentage discount granted to a customer segment. A rule can
+// target a single product, a product category, or every product when neither is set.
+type PriceRule struct {
+	ID              uuid.UUID `json:"id"`
+	Segment         Segment   `json:"segment"`
+	ProductID       uuid.UUID `json:"productId"`
+	Category        string    `json:"category"`
+	DiscountPercent float64   `json:"discountPercent"`
+	Active          bool      `json:"active"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// PriceLine represents a product price to be adjusted by the segment price rules
+type PriceLine struct {
+	ProductID uuid.UUID
+	Category  string
+	Price     float64
+}
+
+// SegmentPrice represents the price of a line after applying a segment price rule
+type SegmentPrice struct {
+	Segment         Segment `json:"segment"`
+	OriginalPrice   float64 `json:"originalPrice"`
+	Price           float64 `json:"price"`
+	DiscountPercent float64 `json:"discountPercent"`
+}
+
+// NewPriceRule creates an active price rule for a segment
+func NewPriceRule(segment Segment, productID uuid.UUID, category string, discountPercent float64) (*PriceRule, error) {
+	if !IsValidSegment(segment) {
+		return nil, errors.New("invalid segment")
+	}
+	if productID != uuid.Nil && category != "" {
+		return nil, errors.New("a price rule can target a product or a category, not both")
+	}
+	if discountPercent <= 0 || discountPercent >= 100 {
+		return nil, errors.New("discount percent must be greater than 0 and lower than 100")
+	}
+
+	return &PriceRule{
+		ID:              uuid.New(),
+		Segment:         segment,
+		ProductID:       productID,
+		Category:        strings.ToLower(strings.TrimSpace(category)),
+		DiscountPercent: discountPercent,
+		Active:          true,
+		CreatedAt:       time.Now(),
+	}, nil
+}
+
+// Matches returns true if the rule applies to the line
+func (r *PriceRule) Matches(line PriceLine) bool {
+	if !r.Active {
+		return false
+	}
+	if r.ProductID != uuid.Nil {
+		return r.ProductID == line.ProductID
+	}
+	if r.Category != "" {
+		return r.Category == strings.ToLower(line.Category)
+	}
+	return true
+}
+
+// specificity ranks product rules over category rules over general rules
+func (r *PriceRule) specificity() int {
+	switch {
+	case r.ProductID != uuid.Nil:
+		return 2
+	case r.Category != "":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Apply returns the segment price of the line
+func (r *PriceRule) Apply(line PriceLine) *SegmentPrice {
+	price := line.Price * (1 - r.DiscountPercent/100)
+	return &SegmentPrice{
+		Segment:         r.Segment,
+		OriginalPrice:   line.Price,
+		Price:           math.Round(price*100) / 100,
+		DiscountPercent: r.DiscountPercent,
+	}
+}
+
+// BestRule returns the rule to apply to a line: the most specific matching rule,
+// and among equally specific rules the one with the largest discount
+func BestRule(rules []*PriceRule, line PriceLine) *PriceRule {
+	var best *PriceRule
+	for _, rule := range rules {
+		if !rule.Matches(line) {
+			continue
+		}
+		if best == nil || rule.specificity() > best.specificity() ||
+			(rule.specificity() == best.specificity() && rule.DiscountPercent > best.DiscountPercent) {
+			best = rule
+		}
+	}
+	return best
+}
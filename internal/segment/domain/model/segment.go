@@ -0,0 +1,119 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Segment represents a customer segment that gets special prices at the kiosk
+type Segment string
+
+const (
+	SegmentStudent Segment = "STUDENT"
+	SegmentStaff   Segment = "STAFF"
+)
+
+// IdentifierType represents the kind of FIUBA number used to verify segment membership
+type IdentifierType string
+
+const (
+	// IdentifierPadron is the student registration number (padrón)
+	IdentifierPadron IdentifierType = "PADRON"
+	// IdentifierLegajo is the staff file number (legajo)
+	IdentifierLegajo IdentifierType = "LEGAJO"
+)
+
+// SegmentFor returns the segment verified by an identifier type
+func SegmentFor(identifierType IdentifierType) (Segment, error) {
+	switch identifierType {
+	case IdentifierPadron:
+		return SegmentStudent, nil
+	case IdentifierLegajo:
+		return SegmentStaff, nil
+	default:
+		return "", errors.New("invalid identifier type")
+	}
+}
+
+// IsValidSegment returns true if the segment is one of the known customer segments
+func IsValidSegment(segment Segment) bool {
+	return segment == SegmentStudent || segment == SegmentStaff
+}
+
+// NormalizeIdentifier removes the separators usually typed in padrón and legajo numbers
+func NormalizeIdentifier(identifier string) string {
+	return strings.NewReplacer(".", "", " ", "", "-", "", "/", "").Replace(strings.TrimSpace(identifier))
+}
+
+// RosterMember represents an entry of the imported student and staff roster
+type RosterMember struct {
+	IdentifierType IdentifierType `json:"identifierType"`
+	Identifier     string         `json:"identifier"`
+	FullName       string         `json:"fullName"`
+	ValidUntil     *time.Time     `json:"validUntil"`
+}
+
+// NewRosterMember creates a roster entry, normalizing and validating the identifier
+func NewRosterMember(identifierType IdentifierType, identifier, fullName string, validUntil *time.Time) (*RosterMember, error) {
+	if _, err := SegmentFor(identifierType); err != nil {
+		return nil, err
+	}
+
+	identifier = NormalizeIdentifier(identifier)
+	if len(identifier) == 0 || len(identifier) > 10 {
+		return nil, errors.New("identifier must have between 1 and 10 digits")
+	}
+	for _, r := range identifier {
+		if r < '0' || r > '9' {
+			return nil, errors.New("identifier must only contain digits")
+		}
+	}
+
+	return &RosterMember{
+		IdentifierType: identifierType,
+		Identifier:     identifier,
+		FullName:       strings.TrimSpace(fullName),
+		ValidUntil:     validUntil,
+	}, nil
+}
+
+// Segment returns the segment the roster entry belongs to
+func (m *RosterMember) Segment() Segment {
+	segment, _ := SegmentFor(m.IdentifierType)
+	return segment
+}
+
+// IsValid returns true if the roster entry has not expired at the given time
+func (m *RosterMember) IsValid(now time.Time) bool {
+	return m.ValidUntil == nil || now.Before(*m.ValidUntil)
+}
+
+// Membership links a user to the segment verified with a roster entry
+type Membership struct {
+	UserID         uuid.UUID      `json:"userId"`
+	Segment        Segment        `json:"segment"`
+	IdentifierType IdentifierType `json:"identifierType"`
+	Identifier     string         `json:"identifier"`
+	VerifiedAt     time.Time      `json:"verifiedAt"`
+}
+
+// NewMembership creates the membership of a user verified against a roster entry
+func NewMembership(userID uuid.UUID, member *RosterMember, now time.Time) (*Membership, error) {
+	if userID == uuid.Nil {
+		return nil, errors.New("user ID is required")
+	}
+	if !member.IsValid(now) {
+		return nil, errors.New("roster membership has expired")
+	}
+
+	return &Membership{
+		UserID:         userID,
+		Segment:        member.Segment(),
+		IdentifierType: member.IdentifierType,
+		Identifier:     member.Identifier,
+		VerifiedAt:     now,
+	}, nil
+}
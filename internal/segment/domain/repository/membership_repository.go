@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/domain/model"
+)
+
+// MembershipRepository defines the interface for segment membership persistence operations
+type MembershipRepository interface {
+	// FindByUserID retrieves the segment membership of a user
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*model.Membership, error)
+
+	// FindByIdentifier retrieves the membership verified with a roster identifier
+	FindByIdentifier(ctx context.Context, identifierType model.IdentifierType, identifier string) (*model.Membership, error)
+
+	// Save persists a membership (creates or updates)
+	Save(ctx context.Context, membership *model.Membership) error
+}
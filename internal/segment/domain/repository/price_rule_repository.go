@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/domain/model"
+)
+
+// PriceRuleRepository defines the interface for segment price rule persistence operations
+type PriceRuleRepository interface {
+	// FindByID retrieves a price rule by its ID
+	FindByID(ctx context.Context, id uuid.UUID) (*model.PriceRule, error)
+
+	// FindAll retrieves every price rule, active or not
+	FindAll(ctx context.Context) ([]*model.PriceRule, error)
+
+	// FindActiveBySegment retrieves the active price rules of a segment
+	FindActiveBySegment(ctx context.Context, segment model.Segment) ([]*model.PriceRule, error)
+
+	// Save persists a price rule (creates or updates)
+	Save(ctx context.Context, rule *model.PriceRule) error
+}
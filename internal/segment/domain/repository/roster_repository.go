@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/domain/model"
+)
+
+// RosterRepository defines the interface for student and staff roster persistence operations
+type RosterRepository interface {
+	// FindMember retrieves a roster entry by its identifier
+	FindMember(ctx context.Context, identifierType model.IdentifierType, identifier string) (*model.RosterMember, error)
+
+	// ReplaceAll replaces the whole roster with the given entries
+	ReplaceAll(ctx context.Context, members []*model.RosterMember) error
+}
@@ -0,0 +1,268 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/app/services"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/app/services/dto"
+)
+
+// maxRosterFileSize limits the size of an uploaded roster file
+const maxRosterFileSize = 10 << 20
+
+// segmentBadRequestErrors lists the segment errors caused by invalid client input
+var segmentBadRequestErrors = map[string]bool{
+	"invalid user ID format":                                     true,
+	"invalid product ID format":                                  true,
+	"invalid price rule ID format":                               true,
+	"invalid identifier type":                                    true,
+	"invalid segment":                                            true,
+	"a price rule can target a product or a category, not both":  true,
+	"discount percent must be greater than 0 and lower than 100": true,
+	"roster membership has expired":                              true,
+	"roster file is not a valid CSV file":                        true,
+	"roster file is empty":                                       true,
+}
+
+// SegmentHandler handles HTTP requests for customer segment operations
+type SegmentHandler struct {
+	segmentService *services.SegmentService
+	requireAdmin   func(http.Handler) http.Handler
+}
+
+// NewSegmentHandler creates a new segment handler. Roster imports and price rule changes go
+// through requireAdmin, since rosters and prices are managed from the back office.
+func NewSegmentHandler(segmentService *services.SegmentService, requireAdmin func(http.Handler) http.Handler) *SegmentHandler {
+	return &SegmentHandler{
+		segmentService: segmentService,
+		requireAdmin:   requireAdmin,
+	}
+}
+
+// RegisterRoutes registers the segment routes on the given router
+func (h *SegmentHandler) RegisterRoutes(router *mux.Router) {
+	// Create a subrouter for segment routes
+	segmentRouter := router.PathPrefix("/segments").Subrouter()
+
+	// Register routes
+	segmentRouter.HandleFunc("/verify", h.VerifyMembership).Methods("POST")
+	segmentRouter.HandleFunc("/users/{userId}", h.GetMembership).Methods("GET")
+	segmentRouter.HandleFunc("/price-rules", h.ListPriceRules).Methods("GET")
+
+	// Rosters and price rules are only managed from the back office
+	adminRouter := segmentRouter.NewRoute().Subrouter()
+	adminRouter.Use(h.requireAdmin)
+
+	adminRouter.HandleFunc("/roster", h.ImportRoster).Methods("POST")
+	adminRouter.HandleFunc("/price-rules", h.CreatePriceRule).Methods("POST")
+	adminRouter.HandleFunc("/price-rules/{ruleId}", h.DeactivatePriceRule).Methods("DELETE")
+}
+
+// ImportRoster handles the upload of a roster CSV file, either as a multipart "file"
+// field or as the raw request body
+// @Summary Import roster
+// @Description Replace the roster of padrón and legajo numbers with a CSV file, sent as a multipart "file" field or as the raw body (up to 10 MB). Nothing is imported if a row is invalid.
+// @Tags segments
+// @Accept multipart/form-data
+// @Accept text/csv
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param file formData file false "Roster CSV file"
+// @Success 200 {object} dto.RosterImportResult "Roster imported successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid roster file"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 422 {object} dto.RosterImportResult "Invalid rows"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/segments/roster [post]
+func (h *SegmentHandler) ImportRoster(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRosterFileSize)
+
+	var file io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		upload, _, err := r.FormFile("file")
+		if err != nil {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid roster file upload")
+			return
+		}
+		defer upload.Close()
+		file = upload
+	}
+
+	result, err := h.segmentService.ImportRoster(r.Context(), file)
+	if err != nil {
+		if err == services.ErrInvalidRoster {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			json.NewEncoder(w).Encode(result)
+		} else if segmentBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// VerifyMembership handles the request to verify a padrón or legajo number
+// @Summary Verify membership
+// @Description Verify a padrón or legajo number against the roster and link it to a user
+// @Tags segments
+// @Accept json
+// @Produce json
+// @Param request body dto.MembershipVerifyRequest true "Membership verification"
+// @Success 200 {object} dto.MembershipDTO "Membership verified"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 404 {object} errors.ErrorResponse "Roster member not found"
+// @Failure 409 {object} errors.ErrorResponse "Identifier already linked to another user"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/segments/verify [post]
+func (h *SegmentHandler) VerifyMembership(w http.ResponseWriter, r *http.Request) {
+	var req dto.MembershipVerifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	membership, err := h.segmentService.VerifyMembership(r.Context(), &req)
+	if err != nil {
+		if err.Error() == "roster member not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if err.Error() == "identifier is already linked to another user" {
+			errors.WriteErrorResponse(w, http.StatusConflict, err.Error())
+		} else if segmentBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(membership)
+}
+
+// GetMembership handles the request to get a user's segment membership
+// @Summary Get membership
+// @Description Get the segment membership of a user
+// @Tags segments
+// @Produce json
+// @Param userId path string true "User ID" format(uuid)
+// @Success 200 {object} dto.MembershipDTO "Membership"
+// @Failure 400 {object} errors.ErrorResponse "Invalid user ID"
+// @Failure 404 {object} errors.ErrorResponse "Membership not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/segments/users/{userId} [get]
+func (h *SegmentHandler) GetMembership(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID := vars["userId"]
+
+	membership, err := h.segmentService.GetMembership(r.Context(), userID)
+	if err != nil {
+		if err.Error() == "membership not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if segmentBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(membership)
+}
+
+// ListPriceRules handles the request to list the segment price rules
+// @Summary List price rules
+// @Description List the active segment price rules
+// @Tags segments
+// @Produce json
+// @Success 200 {array} dto.PriceRuleDTO "Price rules"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/segments/price-rules [get]
+func (h *SegmentHandler) ListPriceRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.segmentService.ListPriceRules(r.Context())
+	if err != nil {
+		errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rules)
+}
+
+// CreatePriceRule handles the request to create a segment price rule
+// @Summary Create price rule
+// @Description Create a percentage discount for a customer segment on a product, a category or every product
+// @Tags segments
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param request body dto.PriceRuleRequest true "Price rule"
+// @Success 201 {object} dto.PriceRuleDTO "Price rule created successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/segments/price-rules [post]
+func (h *SegmentHandler) CreatePriceRule(w http.ResponseWriter, r *http.Request) {
+	var req dto.PriceRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	rule, err := h.segmentService.CreatePriceRule(r.Context(), &req)
+	if err != nil {
+		if segmentBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rule)
+}
+
+// DeactivatePriceRule handles the request to stop applying a segment price rule
+// @Summary Deactivate price rule
+// @Description Stop applying a segment price rule
+// @Tags segments
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param ruleId path string true "Price rule ID" format(uuid)
+// @Success 204 "Price rule deactivated"
+// @Failure 400 {object} errors.ErrorResponse "Invalid price rule ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 404 {object} errors.ErrorResponse "Price rule not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/segments/price-rules/{ruleId} [delete]
+func (h *SegmentHandler) DeactivatePriceRule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	ruleID := vars["ruleId"]
+
+	if err := h.segmentService.DeactivatePriceRule(r.Context(), ruleID); err != nil {
+		if err.Error() == "price rule not found" {
+			errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		} else if segmentBadRequestErrors[err.Error()] {
+			errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		} else {
+			errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
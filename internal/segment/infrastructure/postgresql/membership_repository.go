@@ -0,0 +1,96 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/domain/repository"
+)
+
+// PostgreSQLMembershipRepository implements the MembershipRepository interface using PostgreSQL
+type PostgreSQLMembershipRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLMembershipRepository creates a new PostgreSQL repository for segment memberships
+func NewPostgreSQLMembershipRepository(db *sql.DB) repository.MembershipRepository {
+	return &PostgreSQLMembershipRepository{
+		db: db,
+	}
+}
+
+// FindByUserID retrieves the segment membership of a user
+func (r *PostgreSQLMembershipRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*model.Membership, error) {
+	query := `
+		SELECT user_id, segment, identifier_type, identifier, verified_at
+		FROM segment_memberships
+		WHERE user_id = $1
+	`
+
+	return r.findOne(ctx, query, userID)
+}
+
+// FindByIdentifier retrieves the membership verified with a roster identifier
+func (r *PostgreSQLMembershipRepository) FindByIdentifier(ctx context.Context, identifierType model.IdentifierType, identifier string) (*model.Membership, error) {
+	query := `
+		SELECT user_id, segment, identifier_type, identifier, verified_at
+		FROM segment_memberships
+		WHERE identifier_type = $1 AND identifier = $2
+	`
+
+	return r.findOne(ctx, query, identifierType, identifier)
+}
+
+// findOne runs a single-row membership query
+func (r *PostgreSQLMembershipRepository) findOne(ctx context.Context, query string, args ...interface{}) (*model.Membership, error) {
+	var (
+		membership model.Membership
+		segment    string
+		idType     string
+	)
+
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(
+		&membership.UserID,
+		&segment,
+		&idType,
+		&membership.Identifier,
+		&membership.VerifiedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("membership not found")
+		}
+		return nil, err
+	}
+
+	membership.Segment = model.Segment(segment)
+	membership.IdentifierType = model.IdentifierType(idType)
+
+	return &membership, nil
+}
+
+// Save persists a membership (creates or updates)
+func (r *PostgreSQLMembershipRepository) Save(ctx context.Context, membership *model.Membership) error {
+	query := `
+		INSERT INTO segment_memberships (user_id, segment, identifier_type, identifier, verified_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET segment = $2, identifier_type = $3, identifier = $4, verified_at = $5
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		membership.UserID,
+		membership.Segment,
+		membership.IdentifierType,
+		membership.Identifier,
+		membership.VerifiedAt,
+	)
+
+	return err
+}
@@ -0,0 +1,51 @@
+package postgresql
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RosterMemberModel is the PostgreSQL representation of a student or staff roster entry
+type RosterMemberModel struct {
+	IdentifierType string     `gorm:"type:varchar(10);primaryKey"`
+	Identifier     string     `gorm:"type:varchar(20);primaryKey"`
+	FullName       string     `gorm:"type:varchar(255)"`
+	ValidUntil     *time.Time `gorm:"type:timestamp with time zone"`
+	ImportedAt     time.Time  `gorm:"not null;default:now()"`
+}
+
+// TableName overrides the table name for GORM
+func (RosterMemberModel) TableName() string {
+	return "segment_roster_members"
+}
+
+// MembershipModel is the PostgreSQL representation of a user's segment membership
+type MembershipModel struct {
+	UserID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Segment        string    `gorm:"type:varchar(20);not null"`
+	IdentifierType string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_segment_memberships_identifier"`
+	Identifier     string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_segment_memberships_identifier"`
+	VerifiedAt     time.Time `gorm:"not null;default:now()"`
+}
+
+// TableName overrides the table name for GORM
+func (MembershipModel) TableName() string {
+	return "segment_memberships"
+}
+
+// PriceRuleModel is the PostgreSQL representation of a segment price rule
+type PriceRuleModel struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	Segment         string     `gorm:"type:varchar(20);not null;index"`
+	ProductID       *uuid.UUID `gorm:"type:uuid"`
+	Category        string     `gorm:"type:varchar(100)"`
+	DiscountPercent float64    `gorm:"type:decimal(5,2);not null"`
+	Active          bool       `gorm:"not null;default:true"`
+	CreatedAt       time.Time  `gorm:"not null;default:now()"`
+}
+
+// TableName overrides the table name for GORM
+func (PriceRuleModel) TableName() string {
+	return "segment_price_rules"
+}
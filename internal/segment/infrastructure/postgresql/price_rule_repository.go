@@ -0,0 +1,135 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/domain/repository"
+)
+
+// PostgreSQLPriceRuleRepository implements the PriceRuleRepository interface using PostgreSQL
+type PostgreSQLPriceRuleRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLPriceRuleRepository creates a new PostgreSQL repository for segment price rules
+func NewPostgreSQLPriceRuleRepository(db *sql.DB) repository.PriceRuleRepository {
+	return &PostgreSQLPriceRuleRepository{
+		db: db,
+	}
+}
+
+// FindByID retrieves a price rule by its ID
+func (r *PostgreSQLPriceRuleRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.PriceRule, error) {
+	query := `
+		SELECT id, segment, product_id, category, discount_percent, active, created_at
+		FROM segment_price_rules
+		WHERE id = $1
+	`
+
+	rules, err := r.findMany(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, errors.New("price rule not found")
+	}
+
+	return rules[0], nil
+}
+
+// FindAll retrieves every price rule, active or not
+func (r *PostgreSQLPriceRuleRepository) FindAll(ctx context.Context) ([]*model.PriceRule, error) {
+	query := `
+		SELECT id, segment, product_id, category, discount_percent, active, created_at
+		FROM segment_price_rules
+		ORDER BY created_at ASC
+	`
+
+	return r.findMany(ctx, query)
+}
+
+// FindActiveBySegment retrieves the active price rules of a segment
+func (r *PostgreSQLPriceRuleRepository) FindActiveBySegment(ctx context.Context, segment model.Segment) ([]*model.PriceRule, error) {
+	query := `
+		SELECT id, segment, product_id, category, discount_percent, active, created_at
+		FROM segment_price_rules
+		WHERE segment = $1 AND active = true
+		ORDER BY created_at ASC
+	`
+
+	return r.findMany(ctx, query, segment)
+}
+
+// findMany runs a price rule query
+func (r *PostgreSQLPriceRuleRepository) findMany(ctx context.Context, query string, args ...interface{}) ([]*model.PriceRule, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := make([]*model.PriceRule, 0)
+
+	for rows.Next() {
+		var (
+			rule      model.PriceRule
+			segment   string
+			productID uuid.NullUUID
+			category  sql.NullString
+		)
+
+		if err := rows.Scan(
+			&rule.ID,
+			&segment,
+			&productID,
+			&category,
+			&rule.DiscountPercent,
+			&rule.Active,
+			&rule.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		rule.Segment = model.Segment(segment)
+		if productID.Valid {
+			rule.ProductID = productID.UUID
+		}
+		rule.Category = category.String
+
+		rules = append(rules, &rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// Save persists a price rule (creates or updates)
+func (r *PostgreSQLPriceRuleRepository) Save(ctx context.Context, rule *model.PriceRule) error {
+	query := `
+		INSERT INTO segment_price_rules (id, segment, product_id, category, discount_percent, active, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO UPDATE
+		SET segment = $2, product_id = $3, category = $4, discount_percent = $5, active = $6
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		rule.ID,
+		rule.Segment,
+		uuid.NullUUID{UUID: rule.ProductID, Valid: rule.ProductID != uuid.Nil},
+		rule.Category,
+		rule.DiscountPercent,
+		rule.Active,
+		rule.CreatedAt,
+	)
+
+	return err
+}
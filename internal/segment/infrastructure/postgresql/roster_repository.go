@@ -0,0 +1,98 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/segment/domain/repository"
+)
+
+// PostgreSQLRosterRepository implements the RosterRepository interface using PostgreSQL
+type PostgreSQLRosterRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLRosterRepository creates a new PostgreSQL repository for the roster
+func NewPostgreSQLRosterRepository(db *sql.DB) repository.RosterRepository {
+	return &PostgreSQLRosterRepository{
+		db: db,
+	}
+}
+
+// FindMember retrieves a roster entry by its identifier
+func (r *PostgreSQLRosterRepository) FindMember(ctx context.Context, identifierType model.IdentifierType, identifier string) (*model.RosterMember, error) {
+	query := `
+		SELECT identifier_type, identifier, full_name, valid_until
+		FROM segment_roster_members
+		WHERE identifier_type = $1 AND identifier = $2
+	`
+
+	var (
+		member     model.RosterMember
+		idType     string
+		validUntil sql.NullTime
+	)
+
+	err := r.db.QueryRowContext(ctx, query, identifierType, identifier).Scan(
+		&idType,
+		&member.Identifier,
+		&member.FullName,
+		&validUntil,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("roster member not found")
+		}
+		return nil, err
+	}
+
+	member.IdentifierType = model.IdentifierType(idType)
+	if validUntil.Valid {
+		member.ValidUntil = &validUntil.Time
+	}
+
+	return &member, nil
+}
+
+// ReplaceAll replaces the whole roster with the given entries in a single transaction
+func (r *PostgreSQLRosterRepository) ReplaceAll(ctx context.Context, members []*model.RosterMember) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM segment_roster_members`); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO segment_roster_members (identifier_type, identifier, full_name, valid_until, imported_at)
+		VALUES ($1, $2, $3, $4, now())
+		ON CONFLICT (identifier_type, identifier) DO UPDATE
+		SET full_name = $3, valid_until = $4
+	`
+
+	for _, member := range members {
+		var validUntil sql.NullTime
+		if member.ValidUntil != nil {
+			validUntil = sql.NullTime{Time: *member.ValidUntil, Valid: true}
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			query,
+			member.IdentifierType,
+			member.Identifier,
+			member.FullName,
+			validUntil,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
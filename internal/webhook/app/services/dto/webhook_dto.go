@@ -0,0 +1,106 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/domain/model"
+)
+
+// SubscriptionRequest represents the request to create or update a webhook subscription
+type SubscriptionRequest struct {
+	URL         string   `json:"url" validate:"required,url"`
+	EventTypes  []string `json:"eventTypes" validate:"required,min=1"`
+	Description string   `json:"description"`
+	// Active pauses or resumes the subscription. It defaults to true on creation and to
+	// the current state on update.
+	Active *bool `json:"active,omitempty"`
+}
+
+// SubscriptionDTO represents a webhook subscription for API responses
+type SubscriptionDTO struct {
+	ID          string   `json:"id"`
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"eventTypes"`
+	Description string   `json:"description,omitempty"`
+	Active      bool     `json:"active"`
+	// Secret is only returned when the subscription is created and when the secret is rotated
+	Secret    string `json:"secret,omitempty"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+// SubscriptionFromDomain converts a domain subscription to a DTO, leaving out the secret
+func SubscriptionFromDomain(subscription *model.Subscription) *SubscriptionDTO {
+	eventTypes := make([]string, len(subscription.EventTypes))
+	for i, eventType := range subscription.EventTypes {
+		eventTypes[i] = string(eventType)
+	}
+
+	return &SubscriptionDTO{
+		ID:          subscription.ID.String(),
+		URL:         subscription.URL,
+		EventTypes:  eventTypes,
+		Description: subscription.Description,
+		Active:      subscription.Active,
+		CreatedAt:   subscription.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:   subscription.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// SubscriptionWithSecret converts a domain subscription to a DTO, including the secret
+func SubscriptionWithSecret(subscription *model.Subscription) *SubscriptionDTO {
+	result := SubscriptionFromDomain(subscription)
+	result.Secret = subscription.Secret
+	return result
+}
+
+// DeliveryDTO represents a webhook delivery for API responses
+type DeliveryDTO struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscriptionId"`
+	EventID        string          `json:"eventId"`
+	EventType      string          `json:"eventType"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  string          `json:"nextAttemptAt,omitempty"`
+	LastStatusCode int             `json:"lastStatusCode,omitempty"`
+	LastError      string          `json:"lastError,omitempty"`
+	ReplayOf       string          `json:"replayOf,omitempty"`
+	CreatedAt      string          `json:"createdAt"`
+	DeliveredAt    string          `json:"deliveredAt,omitempty"`
+	Payload        json.RawMessage `json:"payload,omitempty"`
+}
+
+// DeliveryFromDomain converts a domain delivery to a DTO, leaving out the payload
+func DeliveryFromDomain(delivery *model.Delivery) *DeliveryDTO {
+	result := &DeliveryDTO{
+		ID:             delivery.ID.String(),
+		SubscriptionID: delivery.SubscriptionID.String(),
+		EventID:        delivery.EventID.String(),
+		EventType:      string(delivery.EventType),
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+
+	if delivery.Status == model.DeliveryStatusPending {
+		result.NextAttemptAt = delivery.NextAttemptAt.Format("2006-01-02T15:04:05Z")
+	}
+	if delivery.ReplayOf != nil {
+		result.ReplayOf = delivery.ReplayOf.String()
+	}
+	if delivery.DeliveredAt != nil {
+		result.DeliveredAt = delivery.DeliveredAt.Format("2006-01-02T15:04:05Z")
+	}
+
+	return result
+}
+
+// DeliveryWithPayload converts a domain delivery to a DTO, including the payload
+func DeliveryWithPayload(delivery *model.Delivery) *DeliveryDTO {
+	result := DeliveryFromDomain(delivery)
+	result.Payload = delivery.Payload
+	return result
+}
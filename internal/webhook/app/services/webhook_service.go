@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/domain/repository"
+)
+
+const (
+	// deliveryLogLimit is how many deliveries are listed per subscription
+	deliveryLogLimit = 100
+	// deliveryBatchSize is how many due deliveries are attempted per run
+	deliveryBatchSize = 100
+	// deliveryLease is how long the deliveries claimed by a run are kept from other replicas;
+	// the ones the run did not save by then are attempted again. It covers a whole batch of
+	// requests running into the default timeout.
+	deliveryLease = 30 * time.Minute
+)
+
+// Headers sent with every delivery
+const (
+	headerEvent     = "X-Kiosko-Event"
+	headerEventID   = "X-Kiosko-Event-Id"
+	headerDelivery  = "X-Kiosko-Delivery"
+	headerSignature = "X-Kiosko-Signature"
+)
+
+// WebhookService handles the webhook subscriptions of partner systems and the delivery of
+// checkout and cart events to them. Events are queued when they happen and delivered in
+// the background, so a slow or failing subscriber never fails the operation that triggered them.
+type WebhookService struct {
+	subscriptionRepository repository.SubscriptionRepository
+	deliveryRepository     repository.DeliveryRepository
+	client                 repository.WebhookClient
+	retryPolicy            model.RetryPolicy
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(
+	subscriptionRepository repository.SubscriptionRepository,
+	deliveryRepository repository.DeliveryRepository,
+	client repository.WebhookClient,
+	retryPolicy model.RetryPolicy,
+) *WebhookService {
+	return &WebhookService{
+		subscriptionRepository: subscriptionRepository,
+		deliveryRepository:     deliveryRepository,
+		client:                 client,
+		retryPolicy:            retryPolicy,
+	}
+}
+
+// CreateSubscription subscribes an endpoint to the given event types. The response is the
+// only one that includes the signing secret.
+func (s *WebhookService) CreateSubscription(ctx context.Context, req *dto.SubscriptionRequest) (*dto.SubscriptionDTO, error) {
+	subscription, err := model.NewSubscription(req.URL, eventTypesFromRequest(req.EventTypes), req.Description)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Active != nil && !*req.Active {
+		subscription.Active = false
+	}
+
+	if err := s.subscriptionRepository.Save(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	return dto.SubscriptionWithSecret(subscription), nil
+}
+
+// ListSubscriptions retrieves every subscription
+func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]*dto.SubscriptionDTO, error) {
+	subscriptions, err := s.subscriptionRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.SubscriptionDTO, len(subscriptions))
+	for i, subscription := range subscriptions {
+		result[i] = dto.SubscriptionFromDomain(subscription)
+	}
+
+	return result, nil
+}
+
+// GetSubscription retrieves a subscription by ID
+func (s *WebhookService) GetSubscription(ctx context.Context, subscriptionID string) (*dto.SubscriptionDTO, error) {
+	subscription, err := s.findSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.SubscriptionFromDomain(subscription), nil
+}
+
+// UpdateSubscription changes the endpoint, the event filter, the description and whether the
+// subscription is active. Without an active flag, the subscription keeps its state.
+func (s *WebhookService) UpdateSubscription(ctx context.Context, subscriptionID string, req *dto.SubscriptionRequest) (*dto.SubscriptionDTO, error) {
+	subscription, err := s.findSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	active := subscription.Active
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	if err := subscription.Update(req.URL, eventTypesFromRequest(req.EventTypes), req.Description, active); err != nil {
+		return nil, err
+	}
+
+	if err := s.subscriptionRepository.Save(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	return dto.SubscriptionFromDomain(subscription), nil
+}
+
+// RotateSecret replaces the signing secret of a subscription and returns the new one
+func (s *WebhookService) RotateSecret(ctx context.Context, subscriptionID string) (*dto.SubscriptionDTO, error) {
+	subscription, err := s.findSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := subscription.RotateSecret(); err != nil {
+		return nil, err
+	}
+
+	if err := s.subscriptionRepository.Save(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	return dto.SubscriptionWithSecret(subscription), nil
+}
+
+// DeleteSubscription removes a subscription and its delivery log
+func (s *WebhookService) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	id, err := uuid.Parse(subscriptionID)
+	if err != nil {
+		return errors.New("invalid subscription ID format")
+	}
+
+	return s.subscriptionRepository.Delete(ctx, id)
+}
+
+// ListDeliveries retrieves the delivery log of a subscription, newest first, optionally only
+// the deliveries with the given status (e.g. DEAD for the dead letters)
+func (s *WebhookService) ListDeliveries(ctx context.Context, subscriptionID string, status string) ([]*dto.DeliveryDTO, error) {
+	subscription, err := s.findSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch model.DeliveryStatus(status) {
+	case "", model.DeliveryStatusPending, model.DeliveryStatusDelivered, model.DeliveryStatusDead:
+	default:
+		return nil, errors.New("invalid delivery status")
+	}
+
+	deliveries, err := s.deliveryRepository.FindBySubscriptionID(ctx, subscription.ID, model.DeliveryStatus(status), deliveryLogLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.DeliveryDTO, len(deliveries))
+	for i, delivery := range deliveries {
+		result[i] = dto.DeliveryFromDomain(delivery)
+	}
+
+	return result, nil
+}
+
+// GetDelivery retrieves a delivery by ID, payload included
+func (s *WebhookService) GetDelivery(ctx context.Context, deliveryID string) (*dto.DeliveryDTO, error) {
+	id, err := uuid.Parse(deliveryID)
+	if err != nil {
+		return nil, errors.New("invalid delivery ID format")
+	}
+
+	delivery, err := s.deliveryRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.DeliveryWithPayload(delivery), nil
+}
+
+// ReplayDelivery queues the event of a delivery again for its subscription, e.g. a dead letter
+// once the subscriber is fixed. The original delivery is kept in the log.
+func (s *WebhookService) ReplayDelivery(ctx context.Context, deliveryID string) (*dto.DeliveryDTO, error) {
+	id, err := uuid.Parse(deliveryID)
+	if err != nil {
+		return nil, errors.New("invalid delivery ID format")
+	}
+
+	delivery, err := s.deliveryRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if delivery.Status == model.DeliveryStatusPending {
+		return nil, errors.New("delivery is still pending")
+	}
+
+	replay := delivery.Replay()
+	if err := s.deliveryRepository.Create(ctx, replay); err != nil {
+		return nil, err
+	}
+
+	return dto.DeliveryFromDomain(replay), nil
+}
+
+// Publish queues an event for every active subscription that filters on its type. Data is
+// the resource the event is about, as the API returns it.
+func (s *WebhookService) Publish(ctx context.Context, eventType model.EventType, data interface{}) error {
+	subscriptions, err := s.subscriptionRepository.FindActiveByEventType(ctx, eventType)
+	if err != nil {
+		return err
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	event, err := model.NewEvent(eventType, data)
+	if err != nil {
+		return err
+	}
+
+	deliveries := make([]*model.Delivery, len(subscriptions))
+	for i, subscription := range subscriptions {
+		deliveries[i], err = model.NewDelivery(subscription, event)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.deliveryRepository.Create(ctx, deliveries...)
+}
+
+// DeliverDue attempts the deliveries whose next attempt is due. It returns how many were
+// delivered; failed attempts are rescheduled following the retry policy. The deliveries are
+// claimed first, so several replicas can deliver at the same time without sending any twice.
+// A delivery whose subscription cannot be loaded is skipped.
+func (s *WebhookService) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := s.deliveryRepository.ClaimDue(ctx, time.Now(), deliveryLease, deliveryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	subscriptions := make(map[uuid.UUID]*model.Subscription)
+	delivered := 0
+	for _, delivery := range deliveries {
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = s.subscriptionRepository.FindByID(ctx, delivery.SubscriptionID)
+			if err != nil {
+				// A subscription deleted since the claim took its deliveries with it; any other
+				// failure leaves the delivery claimed until the lease runs out, to be attempted
+				// again then. The rest of the batch is still attempted.
+				log.Printf("Skipped webhook delivery %s: %v", delivery.ID, err)
+				continue
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+
+		if s.attempt(ctx, subscription, delivery) {
+			delivered++
+		}
+
+		if err := s.deliveryRepository.Save(ctx, delivery); err != nil {
+			return delivered, err
+		}
+	}
+
+	return delivered, nil
+}
+
+// attempt POSTs a delivery to its subscription, signed with the current secret, and records
+// the outcome. It returns whether the subscriber accepted it.
+func (s *WebhookService) attempt(ctx context.Context, subscription *model.Subscription, delivery *model.Delivery) bool {
+	headers := map[string]string{
+		"Content-Type":  "application/json",
+		headerEvent:     string(delivery.EventType),
+		headerEventID:   delivery.EventID.String(),
+		headerDelivery:  delivery.ID.String(),
+		headerSignature: model.Sign(subscription.Secret, time.Now(), delivery.Payload),
+	}
+
+	statusCode, err := s.client.Post(ctx, subscription.URL, headers, delivery.Payload)
+	if err == nil && statusCode >= 200 && statusCode < 300 {
+		delivery.MarkDelivered(statusCode, time.Now())
+		return true
+	}
+
+	delivery.MarkFailed(statusCode, err, time.Now(), s.retryPolicy)
+	if delivery.Status == model.DeliveryStatusDead {
+		log.Printf("Dead-lettered webhook delivery %s to %s after %d attempts", delivery.ID, subscription.URL, delivery.Attempts)
+	}
+	return false
+}
+
+// RunDelivery attempts due deliveries every interval until the context is cancelled
+func (s *WebhookService) RunDelivery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			delivered, err := s.DeliverDue(ctx)
+			if err != nil {
+				log.Printf("Failed to deliver webhooks: %v", err)
+			}
+			if delivered > 0 {
+				log.Printf("Delivered %d webhooks", delivered)
+			}
+		}
+	}
+}
+
+// findSubscription parses a subscription ID and retrieves the subscription
+func (s *WebhookService) findSubscription(ctx context.Context, subscriptionID string) (*model.Subscription, error) {
+	id, err := uuid.Parse(subscriptionID)
+	if err != nil {
+		return nil, errors.New("invalid subscription ID format")
+	}
+
+	return s.subscriptionRepository.FindByID(ctx, id)
+}
+
+// eventTypesFromRequest converts the requested event type names to event types
+func eventTypesFromRequest(names []string) []model.EventType {
+	eventTypes := make([]model.EventType, len(names))
+	for i, name := range names {
+		eventTypes[i] = model.EventType(name)
+	}
+	return eventTypes
+}
@@ -0,0 +1,127 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeliveryStatus represents the status of a delivery
+type DeliveryStatus string
+
+const (
+	// DeliveryStatusPending deliveries are waiting for their next attempt
+	DeliveryStatusPending DeliveryStatus = "PENDING"
+	// DeliveryStatusDelivered deliveries got a 2xx response
+	DeliveryStatusDelivered DeliveryStatus = "DELIVERED"
+	// DeliveryStatusDead deliveries ran out of attempts. They are kept, dead-lettered, until replayed.
+	DeliveryStatusDead DeliveryStatus = "DEAD"
+)
+
+// maxRetryDelay caps the wait between two attempts
+const maxRetryDelay = 12 * time.Hour
+
+// RetryPolicy decides how many times and how often a delivery is attempted. The wait
+// doubles after every failed attempt, starting at BaseDelay.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+}
+
+// Delay returns the wait before the attempt that follows the given number of failed attempts
+func (p RetryPolicy) Delay(failedAttempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < failedAttempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
+
+// Delivery represents the Delivery aggregate root: an event to be POSTed to a subscription,
+// with the outcome of its last attempt
+type Delivery struct {
+	ID             uuid.UUID       `json:"id"`
+	SubscriptionID uuid.UUID       `json:"subscriptionId"`
+	EventID        uuid.UUID       `json:"eventId"`
+	EventType      EventType       `json:"eventType"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt"`
+	LastStatusCode int             `json:"lastStatusCode"`
+	LastError      string          `json:"lastError"`
+	// ReplayOf is the delivery this one replays, if any
+	ReplayOf    *uuid.UUID `json:"replayOf"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	DeliveredAt *time.Time `json:"deliveredAt"`
+}
+
+// NewDelivery creates a delivery of an event to a subscription, due right away
+func NewDelivery(subscription *Subscription, event *Event) (*Delivery, error) {
+	payload, err := event.Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &Delivery{
+		ID:             uuid.New(),
+		SubscriptionID: subscription.ID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		Payload:        payload,
+		Status:         DeliveryStatusPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}, nil
+}
+
+// MarkDelivered records an attempt answered with a 2xx status
+func (d *Delivery) MarkDelivered(statusCode int, now time.Time) {
+	d.Attempts++
+	d.Status = DeliveryStatusDelivered
+	d.LastStatusCode = statusCode
+	d.LastError = ""
+	d.DeliveredAt = &now
+}
+
+// MarkFailed records a failed attempt and schedules the next one, or dead-letters the
+// delivery once the policy runs out of attempts. The status code is 0 when no response came.
+func (d *Delivery) MarkFailed(statusCode int, cause error, now time.Time, policy RetryPolicy) {
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	if cause != nil {
+		d.LastError = cause.Error()
+	} else {
+		d.LastError = fmt.Sprintf("subscriber returned status %d", statusCode)
+	}
+
+	if d.Attempts >= policy.MaxAttempts {
+		d.Status = DeliveryStatusDead
+		return
+	}
+	d.NextAttemptAt = now.Add(policy.Delay(d.Attempts))
+}
+
+// Replay creates a new delivery of the same event, due right away. The event ID is kept,
+// so subscribers that already processed the event can skip it.
+func (d *Delivery) Replay() *Delivery {
+	now := time.Now()
+	replayOf := d.ID
+	return &Delivery{
+		ID:             uuid.New(),
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         DeliveryStatusPending,
+		NextAttemptAt:  now,
+		ReplayOf:       &replayOf,
+		CreatedAt:      now,
+	}
+}
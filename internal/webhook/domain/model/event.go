@@ -0,0 +1,72 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType represents something that happened to a checkout or a cart that subscribers
+// can be told about
+type EventType string
+
+const (
+	EventCheckoutInitiated       EventType = "checkout.initiated"
+	EventCheckoutAwaitingPayment EventType = "checkout.awaiting_payment"
+	EventCheckoutCompleted       EventType = "checkout.completed"
+	EventCheckoutCancelled       EventType = "checkout.cancelled"
+	EventCheckoutRefunded        EventType = "checkout.refunded"
+	EventCartCreated             EventType = "cart.created"
+	EventCartUpdated             EventType = "cart.updated"
+	EventCartDeleted             EventType = "cart.deleted"
+)
+
+// EventTypeAll subscribes to every event type, including the ones added later
+const EventTypeAll EventType = "*"
+
+// eventTypes lists the event types that can be subscribed to
+var eventTypes = map[EventType]bool{
+	EventCheckoutInitiated:       true,
+	EventCheckoutAwaitingPayment: true,
+	EventCheckoutCompleted:       true,
+	EventCheckoutCancelled:       true,
+	EventCheckoutRefunded:        true,
+	EventCartCreated:             true,
+	EventCartUpdated:             true,
+	EventCartDeleted:             true,
+}
+
+// IsValid checks if subscriptions can filter on the event type
+func (t EventType) IsValid() bool {
+	return t == EventTypeAll || eventTypes[t]
+}
+
+// Event represents an occurrence of an event type. Data holds the resource as the API
+// returns it, so subscribers get the same body they would get by polling.
+type Event struct {
+	ID         uuid.UUID       `json:"id"`
+	Type       EventType       `json:"type"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// NewEvent creates an event of the given type, encoding its data as JSON
+func NewEvent(eventType EventType, data interface{}) (*Event, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Event{
+		ID:         uuid.New(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Data:       encoded,
+	}, nil
+}
+
+// Payload returns the body POSTed to subscribers
+func (e *Event) Payload() ([]byte, error) {
+	return json.Marshal(e)
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	timestamp := time.Unix(1700000000, 0)
+	payload := []byte(`{"id":"evt_1"}`)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp time.Time
+		payload   []byte
+		want      string
+	}{
+		{
+			name:      "signs the timestamp and the body",
+			secret:    "whsec_test",
+			timestamp: timestamp,
+			payload:   payload,
+			want:      "t=1700000000,v1=c89214b5b5da833daed6f0b8c5bb6bd58cea9022bd80ccc78230f3942d632925",
+		},
+		{
+			name:      "depends on the secret",
+			secret:    "whsec_other",
+			timestamp: timestamp,
+			payload:   payload,
+			want:      "t=1700000000,v1=d8d091c76b586cff4dbd317fc47ebddff4b86de3ce18d3c03753c3c1901d475a",
+		},
+		{
+			name:      "timestamp in seconds whatever the time zone",
+			secret:    "whsec_test",
+			timestamp: timestamp.Add(500 * time.Millisecond).In(time.FixedZone("ART", -3*60*60)),
+			payload:   payload,
+			want:      "t=1700000000,v1=c89214b5b5da833daed6f0b8c5bb6bd58cea9022bd80ccc78230f3942d632925",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, tt.timestamp, tt.payload); got != tt.want {
+				t.Errorf("Sign() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// secretPrefix marks the signing secrets, so they are recognized if they leak into logs or code
+const secretPrefix = "whsec_"
+
+// Subscription represents the Subscription aggregate root: an endpoint of a partner system
+// that is POSTed the events of the types it filters on
+type Subscription struct {
+	ID          uuid.UUID   `json:"id"`
+	URL         string      `json:"url"`
+	EventTypes  []EventType `json:"eventTypes"`
+	Description string      `json:"description"`
+	// Secret signs the deliveries, so the subscriber can check they come from us
+	Secret    string    `json:"-"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// NewSubscription creates an active subscription with a new signing secret
+func NewSubscription(endpoint string, eventTypes []EventType, description string) (*Subscription, error) {
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	subscription := &Subscription{
+		ID:        uuid.New(),
+		Secret:    secret,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := subscription.Update(endpoint, eventTypes, description, true); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// Update changes the endpoint, the event filter and the description of the subscription.
+// An inactive subscription keeps its pending deliveries until it is activated again.
+// Endpoints on loopback, link-local or private addresses are rejected, so subscriptions
+// cannot be used to reach the internal network.
+func (s *Subscription) Update(endpoint string, eventTypes []EventType, description string, active bool) error {
+	endpoint = strings.TrimSpace(endpoint)
+	parsed, err := url.Parse(endpoint)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return errors.New("invalid webhook URL")
+	}
+	if isInternalHost(parsed.Hostname()) {
+		return errors.New("webhook URL must not point to an internal host")
+	}
+
+	if len(eventTypes) == 0 {
+		return errors.New("at least one event type is required")
+	}
+	filter := make([]EventType, 0, len(eventTypes))
+	seen := make(map[EventType]bool, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !eventType.IsValid() {
+			return errors.New("invalid event type")
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			filter = append(filter, eventType)
+		}
+	}
+
+	description = strings.TrimSpace(description)
+	if len(description) > 255 {
+		return errors.New("description is too long")
+	}
+
+	s.URL = endpoint
+	s.EventTypes = filter
+	s.Description = description
+	s.Active = active
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// RotateSecret replaces the signing secret. Deliveries are signed when they are sent, so
+// pending retries are signed with the new secret.
+func (s *Subscription) RotateSecret() error {
+	secret, err := newSecret()
+	if err != nil {
+		return err
+	}
+
+	s.Secret = secret
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// Matches checks if the subscription filters on the event type
+func (s *Subscription) Matches(eventType EventType) bool {
+	for _, filter := range s.EventTypes {
+		if filter == EventTypeAll || filter == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// isInternalHost returns true if the host names this machine or is an IP address of the
+// internal network. Names are resolved when the delivery is sent, and checked again then.
+func isInternalHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && IsInternalIP(ip)
+}
+
+// IsInternalIP returns true if deliveries must not be sent to the address: loopback,
+// link-local (which includes the cloud metadata endpoints), private and unspecified addresses
+func IsInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsPrivate() || ip.IsUnspecified()
+}
+
+// newSecret generates a random signing secret
+func newSecret() (string, error) {
+	bytes := make([]byte, 24)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return secretPrefix + hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,41 @@
+package model
+
+import "testing"
+
+func TestSubscriptionUpdateURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		wantErr string
+	}{
+		{name: "public https endpoint", url: "https://partner.example.com/hooks"},
+		{name: "public ip with port", url: "http://203.0.113.10:8080/hooks"},
+		{name: "unsupported scheme", url: "ftp://partner.example.com/hooks", wantErr: "invalid webhook URL"},
+		{name: "missing host", url: "https:///hooks", wantErr: "invalid webhook URL"},
+		{name: "localhost", url: "http://localhost:8080/hooks", wantErr: "webhook URL must not point to an internal host"},
+		{name: "localhost subdomain", url: "http://api.localhost/hooks", wantErr: "webhook URL must not point to an internal host"},
+		{name: "loopback ip", url: "http://127.0.0.1/hooks", wantErr: "webhook URL must not point to an internal host"},
+		{name: "ipv6 loopback", url: "http://[::1]:8080/hooks", wantErr: "webhook URL must not point to an internal host"},
+		{name: "cloud metadata endpoint", url: "http://169.254.169.254/latest/meta-data", wantErr: "webhook URL must not point to an internal host"},
+		{name: "private network", url: "https://10.0.0.5/hooks", wantErr: "webhook URL must not point to an internal host"},
+		{name: "private network 192.168", url: "https://192.168.1.20/hooks", wantErr: "webhook URL must not point to an internal host"},
+		{name: "ipv6 unique local", url: "https://[fd00::1]/hooks", wantErr: "webhook URL must not point to an internal host"},
+		{name: "unspecified address", url: "http://0.0.0.0/hooks", wantErr: "webhook URL must not point to an internal host"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscription := &Subscription{}
+			err := subscription.Update(tt.url, []EventType{EventTypeAll}, "", true)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Update(%q) error = %v", tt.url, err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Update(%q) error = %v, want %q", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+)
+
+// WebhookClient defines the interface to POST deliveries to subscriber endpoints
+type WebhookClient interface {
+	// Post sends the body with the given headers and returns the response status code. An
+	// error means no response came back.
+	Post(ctx context.Context, url string, headers map[string]string, body []byte) (int, error)
+}
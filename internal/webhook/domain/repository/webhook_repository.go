@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/domain/model"
+)
+
+// SubscriptionRepository defines the interface for webhook subscription persistence operations
+type SubscriptionRepository interface {
+	// FindByID retrieves a subscription by its ID
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Subscription, error)
+
+	// FindAll retrieves every subscription, oldest first
+	FindAll(ctx context.Context) ([]*model.Subscription, error)
+
+	// FindActiveByEventType retrieves the active subscriptions that filter on the event type
+	FindActiveByEventType(ctx context.Context, eventType model.EventType) ([]*model.Subscription, error)
+
+	// Save persists a subscription (creates or updates)
+	Save(ctx context.Context, subscription *model.Subscription) error
+
+	// Delete removes a subscription and its deliveries
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// DeliveryRepository defines the interface for webhook delivery persistence operations
+type DeliveryRepository interface {
+	// FindByID retrieves a delivery by its ID
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Delivery, error)
+
+	// FindBySubscriptionID retrieves the latest deliveries of a subscription, newest first,
+	// optionally only those with the given status
+	FindBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID, status model.DeliveryStatus, limit int) ([]*model.Delivery, error)
+
+	// ClaimDue claims the pending deliveries of active subscriptions whose next attempt is
+	// due, oldest first, by moving their next attempt to the end of the lease. Concurrent
+	// callers claim different deliveries, and the ones not saved before the lease ends are
+	// due again.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.Delivery, error)
+
+	// Create stores new deliveries in a single transaction
+	Create(ctx context.Context, deliveries ...*model.Delivery) error
+
+	// Save persists the outcome of a delivery attempt
+	Save(ctx context.Context, delivery *model.Delivery) error
+}
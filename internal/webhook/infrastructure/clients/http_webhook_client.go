@@ -0,0 +1,82 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/domain/repository"
+)
+
+// maxResponseBody is how much of a subscriber response is read before closing it, so the
+// connection can be reused without reading arbitrarily large bodies
+const maxResponseBody = 64 * 1024
+
+// HTTPWebhookClient implements the WebhookClient interface with net/http
+type HTTPWebhookClient struct {
+	httpClient *http.Client
+}
+
+// NewHTTPWebhookClient creates a new webhook client. Redirects are not followed: a
+// subscriber that moved must update its subscription. Connections to internal addresses are
+// refused once the endpoint name is resolved, so a name pointing to the internal network
+// cannot get past the check of the subscription URL.
+func NewHTTPWebhookClient(timeout time.Duration) repository.WebhookClient {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: refuseInternalAddresses,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &HTTPWebhookClient{
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Post sends a delivery to a subscriber endpoint
+func (c *HTTPWebhookClient) Post(ctx context.Context, url string, headers map[string]string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	return resp.StatusCode, nil
+}
+
+// refuseInternalAddresses fails the connections to the addresses deliveries must not reach
+func refuseInternalAddresses(network, address string, conn syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || model.IsInternalIP(ip) {
+		return errors.New("webhook endpoint resolves to an internal address")
+	}
+	return nil
+}
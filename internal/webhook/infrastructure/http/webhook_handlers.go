@@ -0,0 +1,341 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/app/services"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/app/services/dto"
+)
+
+// subscriptionBadRequestErrors lists the subscription errors caused by invalid client input
+var subscriptionBadRequestErrors = map[string]bool{
+	"invalid subscription ID format":                 true,
+	"invalid webhook URL":                            true,
+	"at least one event type is required":            true,
+	"invalid event type":                             true,
+	"description is too long":                        true,
+	"webhook URL must not point to an internal host": true,
+	"invalid delivery status":                        true,
+}
+
+// WebhookHandler handles HTTP requests for webhook operations
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+	requireAdmin   func(http.Handler) http.Handler
+}
+
+// NewWebhookHandler creates a new webhook handler. Every route goes through requireAdmin,
+// since subscriptions are managed from the back office.
+func NewWebhookHandler(webhookService *services.WebhookService, requireAdmin func(http.Handler) http.Handler) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		requireAdmin:   requireAdmin,
+	}
+}
+
+// RegisterRoutes registers the webhook routes on the given router
+func (h *WebhookHandler) RegisterRoutes(router *mux.Router) {
+	// Create a subrouter for webhook routes
+	webhookRouter := router.PathPrefix("/webhooks").Subrouter()
+
+	// Webhooks are only available to the back office
+	webhookRouter.Use(h.requireAdmin)
+
+	// Register routes
+	webhookRouter.HandleFunc("/subscriptions", h.CreateSubscription).Methods("POST")
+	webhookRouter.HandleFunc("/subscriptions", h.ListSubscriptions).Methods("GET")
+	webhookRouter.HandleFunc("/subscriptions/{subscriptionId}", h.GetSubscription).Methods("GET")
+	webhookRouter.HandleFunc("/subscriptions/{subscriptionId}", h.UpdateSubscription).Methods("PUT")
+	webhookRouter.HandleFunc("/subscriptions/{subscriptionId}", h.DeleteSubscription).Methods("DELETE")
+	webhookRouter.HandleFunc("/subscriptions/{subscriptionId}/rotate-secret", h.RotateSecret).Methods("POST")
+	webhookRouter.HandleFunc("/subscriptions/{subscriptionId}/deliveries", h.ListDeliveries).Methods("GET")
+	webhookRouter.HandleFunc("/deliveries/{deliveryId}", h.GetDelivery).Methods("GET")
+	webhookRouter.HandleFunc("/deliveries/{deliveryId}/replay", h.ReplayDelivery).Methods("POST")
+}
+
+// CreateSubscription handles the request to subscribe an endpoint to events
+// @Summary Create webhook subscription
+// @Description Subscribe an endpoint to event types ("*" for every event). The signing secret is only returned here and when it is rotated. Endpoints on loopback, link-local or private addresses are rejected.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param request body dto.SubscriptionRequest true "Subscription"
+// @Success 201 {object} dto.SubscriptionDTO "Subscription created successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/webhooks/subscriptions [post]
+func (h *WebhookHandler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	var req dto.SubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	subscription, err := h.webhookService.CreateSubscription(r.Context(), &req)
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// ListSubscriptions handles the request to list every subscription
+// @Summary List webhook subscriptions
+// @Description List every webhook subscription, without their secrets
+// @Tags webhooks
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Success 200 {array} dto.SubscriptionDTO "Subscriptions"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/webhooks/subscriptions [get]
+func (h *WebhookHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := h.webhookService.ListSubscriptions(r.Context())
+	if err != nil {
+		errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subscriptions)
+}
+
+// GetSubscription handles the request to get a subscription by ID
+// @Summary Get webhook subscription
+// @Description Get a webhook subscription, without its secret
+// @Tags webhooks
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param subscriptionId path string true "Subscription ID" format(uuid)
+// @Success 200 {object} dto.SubscriptionDTO "Subscription"
+// @Failure 400 {object} errors.ErrorResponse "Invalid subscription ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 404 {object} errors.ErrorResponse "Subscription not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/webhooks/subscriptions/{subscriptionId} [get]
+func (h *WebhookHandler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	subscriptionID := vars["subscriptionId"]
+
+	subscription, err := h.webhookService.GetSubscription(r.Context(), subscriptionID)
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// UpdateSubscription handles the request to change, pause or resume a subscription
+// @Summary Update webhook subscription
+// @Description Change the endpoint, event types or description of a subscription, or pause and resume it with active. Paused subscriptions keep their pending deliveries.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param subscriptionId path string true "Subscription ID" format(uuid)
+// @Param request body dto.SubscriptionRequest true "Subscription"
+// @Success 200 {object} dto.SubscriptionDTO "Subscription updated successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 404 {object} errors.ErrorResponse "Subscription not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/webhooks/subscriptions/{subscriptionId} [put]
+func (h *WebhookHandler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	subscriptionID := vars["subscriptionId"]
+
+	var req dto.SubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	subscription, err := h.webhookService.UpdateSubscription(r.Context(), subscriptionID, &req)
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// DeleteSubscription handles the request to remove a subscription
+// @Summary Delete webhook subscription
+// @Description Remove a subscription and its delivery log
+// @Tags webhooks
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param subscriptionId path string true "Subscription ID" format(uuid)
+// @Success 204 "Subscription deleted successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid subscription ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 404 {object} errors.ErrorResponse "Subscription not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/webhooks/subscriptions/{subscriptionId} [delete]
+func (h *WebhookHandler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	subscriptionID := vars["subscriptionId"]
+
+	if err := h.webhookService.DeleteSubscription(r.Context(), subscriptionID); err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RotateSecret handles the request to replace the signing secret of a subscription
+// @Summary Rotate webhook secret
+// @Description Replace the signing secret of a subscription. Pending retries are signed with the new secret.
+// @Tags webhooks
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param subscriptionId path string true "Subscription ID" format(uuid)
+// @Success 200 {object} dto.SubscriptionDTO "Subscription with its new secret"
+// @Failure 400 {object} errors.ErrorResponse "Invalid subscription ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 404 {object} errors.ErrorResponse "Subscription not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/webhooks/subscriptions/{subscriptionId}/rotate-secret [post]
+func (h *WebhookHandler) RotateSecret(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	subscriptionID := vars["subscriptionId"]
+
+	subscription, err := h.webhookService.RotateSecret(r.Context(), subscriptionID)
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subscription)
+}
+
+// ListDeliveries handles the request to get the delivery log of a subscription
+// @Summary List webhook deliveries
+// @Description Get the delivery log of a subscription, newest first, optionally filtered by status
+// @Tags webhooks
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param subscriptionId path string true "Subscription ID" format(uuid)
+// @Param status query string false "Delivery status" Enums(PENDING, DELIVERED, DEAD)
+// @Success 200 {array} dto.DeliveryDTO "Deliveries"
+// @Failure 400 {object} errors.ErrorResponse "Invalid subscription ID or status"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 404 {object} errors.ErrorResponse "Subscription not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/webhooks/subscriptions/{subscriptionId}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	subscriptionID := vars["subscriptionId"]
+	status := r.URL.Query().Get("status")
+
+	deliveries, err := h.webhookService.ListDeliveries(r.Context(), subscriptionID, status)
+	if err != nil {
+		writeSubscriptionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deliveries)
+}
+
+// GetDelivery handles the request to get a delivery, payload included
+// @Summary Get webhook delivery
+// @Description Get a delivery with its payload and the outcome of its last attempt
+// @Tags webhooks
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param deliveryId path string true "Delivery ID" format(uuid)
+// @Success 200 {object} dto.DeliveryDTO "Delivery"
+// @Failure 400 {object} errors.ErrorResponse "Invalid delivery ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 404 {object} errors.ErrorResponse "Delivery not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/webhooks/deliveries/{deliveryId} [get]
+func (h *WebhookHandler) GetDelivery(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	deliveryID := vars["deliveryId"]
+
+	delivery, err := h.webhookService.GetDelivery(r.Context(), deliveryID)
+	if err != nil {
+		writeDeliveryError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(delivery)
+}
+
+// ReplayDelivery handles the request to send the event of a delivery again
+// @Summary Replay webhook delivery
+// @Description Send the event of a delivered or dead-lettered delivery again, as a new delivery with the same event ID
+// @Tags webhooks
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param deliveryId path string true "Delivery ID" format(uuid)
+// @Success 201 {object} dto.DeliveryDTO "Replay scheduled"
+// @Failure 400 {object} errors.ErrorResponse "Invalid delivery ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing admin key"
+// @Failure 403 {object} errors.ErrorResponse "Invalid admin key"
+// @Failure 404 {object} errors.ErrorResponse "Delivery not found"
+// @Failure 409 {object} errors.ErrorResponse "Delivery still pending"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/webhooks/deliveries/{deliveryId}/replay [post]
+func (h *WebhookHandler) ReplayDelivery(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	deliveryID := vars["deliveryId"]
+
+	delivery, err := h.webhookService.ReplayDelivery(r.Context(), deliveryID)
+	if err != nil {
+		writeDeliveryError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(delivery)
+}
+
+// writeSubscriptionError maps a subscription error to its HTTP response
+func writeSubscriptionError(w http.ResponseWriter, err error) {
+	if err.Error() == "subscription not found" {
+		errors.WriteErrorResponse(w, http.StatusNotFound, "Subscription not found")
+	} else if subscriptionBadRequestErrors[err.Error()] {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+	} else {
+		errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// writeDeliveryError maps a delivery error to its HTTP response
+func writeDeliveryError(w http.ResponseWriter, err error) {
+	switch err.Error() {
+	case "delivery not found":
+		errors.WriteErrorResponse(w, http.StatusNotFound, "Delivery not found")
+	case "invalid delivery ID format":
+		errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+	case "delivery is still pending":
+		errors.WriteErrorResponse(w, http.StatusConflict, err.Error())
+	default:
+		errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+	}
+}
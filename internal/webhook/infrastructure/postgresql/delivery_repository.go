@@ -0,0 +1,231 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/domain/repository"
+)
+
+// deliveryColumns lists the columns read by every delivery query, in scanDelivery order
+const deliveryColumns = `
+	d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+	d.next_attempt_at, d.last_status_code, d.last_error, d.replay_of, d.created_at, d.delivered_at
+`
+
+// PostgreSQLDeliveryRepository implements the DeliveryRepository interface using PostgreSQL
+type PostgreSQLDeliveryRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLDeliveryRepository creates a new PostgreSQL repository for webhook deliveries
+func NewPostgreSQLDeliveryRepository(db *sql.DB) repository.DeliveryRepository {
+	return &PostgreSQLDeliveryRepository{
+		db: db,
+	}
+}
+
+// scanDelivery reads a delivery selected with deliveryColumns
+func scanDelivery(row rowScanner) (*model.Delivery, error) {
+	var (
+		delivery       model.Delivery
+		eventType      string
+		status         string
+		lastStatusCode sql.NullInt64
+		lastError      sql.NullString
+		replayOf       uuid.NullUUID
+		deliveredAt    sql.NullTime
+	)
+
+	if err := row.Scan(
+		&delivery.ID,
+		&delivery.SubscriptionID,
+		&delivery.EventID,
+		&eventType,
+		&delivery.Payload,
+		&status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&lastStatusCode,
+		&lastError,
+		&replayOf,
+		&delivery.CreatedAt,
+		&deliveredAt,
+	); err != nil {
+		return nil, err
+	}
+
+	delivery.EventType = model.EventType(eventType)
+	delivery.Status = model.DeliveryStatus(status)
+	delivery.LastStatusCode = int(lastStatusCode.Int64)
+	delivery.LastError = lastError.String
+	if replayOf.Valid {
+		delivery.ReplayOf = &replayOf.UUID
+	}
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+
+	return &delivery, nil
+}
+
+// FindByID retrieves a delivery by its ID
+func (r *PostgreSQLDeliveryRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Delivery, error) {
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries d
+		WHERE d.id = $1
+	`
+
+	delivery, err := scanDelivery(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("delivery not found")
+		}
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// FindBySubscriptionID retrieves the latest deliveries of a subscription, newest first. An
+// empty status matches every status.
+func (r *PostgreSQLDeliveryRepository) FindBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID, status model.DeliveryStatus, limit int) ([]*model.Delivery, error) {
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries d
+		WHERE d.subscription_id = $1 AND ($2 = '' OR d.status = $2)
+		ORDER BY d.created_at DESC
+		LIMIT $3
+	`
+
+	return r.findAll(ctx, query, subscriptionID, string(status), limit)
+}
+
+// ClaimDue claims the pending deliveries of active subscriptions whose next attempt is due,
+// oldest first. Deliveries of paused subscriptions wait until the subscription is activated.
+// Rows locked by another claim are skipped, so replicas delivering at the same time never
+// claim the same delivery.
+func (r *PostgreSQLDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.Delivery, error) {
+	query := `
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = $3
+		WHERE d.id IN (
+			SELECT due.id
+			FROM webhook_deliveries due
+			JOIN webhook_subscriptions s ON s.id = due.subscription_id
+			WHERE s.active AND due.status = $1 AND due.next_attempt_at <= $2
+			ORDER BY due.next_attempt_at
+			LIMIT $4
+			FOR UPDATE OF due SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns + `
+	`
+
+	return r.findAll(ctx, query, string(model.DeliveryStatusPending), now, now.Add(lease), limit)
+}
+
+// findAll runs a query that selects deliveryColumns and reads every row
+func (r *PostgreSQLDeliveryRepository) findAll(ctx context.Context, query string, args ...interface{}) ([]*model.Delivery, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]*model.Delivery, 0)
+
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// Create stores new deliveries in a single transaction
+func (r *PostgreSQLDeliveryRepository) Create(ctx context.Context, deliveries ...*model.Delivery) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO webhook_deliveries (
+			id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+			last_status_code, last_error, replay_of, created_at, delivered_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+
+	for _, delivery := range deliveries {
+		if _, err := tx.ExecContext(
+			ctx,
+			query,
+			delivery.ID,
+			delivery.SubscriptionID,
+			delivery.EventID,
+			string(delivery.EventType),
+			[]byte(delivery.Payload),
+			string(delivery.Status),
+			delivery.Attempts,
+			delivery.NextAttemptAt,
+			delivery.LastStatusCode,
+			delivery.LastError,
+			delivery.ReplayOf,
+			delivery.CreatedAt,
+			delivery.DeliveredAt,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Save persists the outcome of a delivery attempt
+func (r *PostgreSQLDeliveryRepository) Save(ctx context.Context, delivery *model.Delivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, next_attempt_at = $4, last_status_code = $5, last_error = $6, delivered_at = $7
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		delivery.ID,
+		string(delivery.Status),
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.LastStatusCode,
+		delivery.LastError,
+		delivery.DeliveredAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("delivery not found")
+	}
+
+	return nil
+}
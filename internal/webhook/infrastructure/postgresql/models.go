@@ -0,0 +1,47 @@
+package postgresql
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// SubscriptionModel is the PostgreSQL representation of a webhook subscription
+type SubscriptionModel struct {
+	ID          uuid.UUID      `gorm:"type:uuid;primaryKey"`
+	URL         string         `gorm:"type:varchar(2048);not null"`
+	EventTypes  pq.StringArray `gorm:"type:text[];not null"`
+	Description string         `gorm:"type:varchar(255)"`
+	Secret      string         `gorm:"type:varchar(100);not null"`
+	Active      bool           `gorm:"not null;default:true"`
+	CreatedAt   time.Time      `gorm:"not null;default:now()"`
+	UpdatedAt   time.Time      `gorm:"not null;default:now()"`
+}
+
+// TableName overrides the table name for GORM
+func (SubscriptionModel) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// DeliveryModel is the PostgreSQL representation of a webhook delivery
+type DeliveryModel struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	SubscriptionID uuid.UUID  `gorm:"type:uuid;not null;index:idx_webhook_deliveries_subscription"`
+	EventID        uuid.UUID  `gorm:"type:uuid;not null;index"`
+	EventType      string     `gorm:"type:varchar(50);not null"`
+	Payload        string     `gorm:"type:jsonb;not null"`
+	Status         string     `gorm:"type:varchar(20);not null;index:idx_webhook_deliveries_due"`
+	Attempts       int        `gorm:"type:integer;not null;default:0"`
+	NextAttemptAt  time.Time  `gorm:"not null;index:idx_webhook_deliveries_due"`
+	LastStatusCode int        `gorm:"type:integer"`
+	LastError      string     `gorm:"type:text"`
+	ReplayOf       *uuid.UUID `gorm:"type:uuid"`
+	CreatedAt      time.Time  `gorm:"not null;default:now();index:idx_webhook_deliveries_subscription"`
+	DeliveredAt    *time.Time `gorm:"type:timestamp with time zone"`
+}
+
+// TableName overrides the table name for GORM
+func (DeliveryModel) TableName() string {
+	return "webhook_deliveries"
+}
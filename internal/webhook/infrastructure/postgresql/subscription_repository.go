@@ -0,0 +1,188 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/webhook/domain/repository"
+	"github.com/lib/pq"
+)
+
+// subscriptionColumns lists the columns read by every subscription query, in scanSubscription order
+const subscriptionColumns = `id, url, event_types, description, secret, active, created_at, updated_at`
+
+// PostgreSQLSubscriptionRepository implements the SubscriptionRepository interface using PostgreSQL
+type PostgreSQLSubscriptionRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLSubscriptionRepository creates a new PostgreSQL repository for webhook subscriptions
+func NewPostgreSQLSubscriptionRepository(db *sql.DB) repository.SubscriptionRepository {
+	return &PostgreSQLSubscriptionRepository{
+		db: db,
+	}
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSubscription reads a subscription selected with subscriptionColumns
+func scanSubscription(row rowScanner) (*model.Subscription, error) {
+	var (
+		subscription model.Subscription
+		eventTypes   pq.StringArray
+		description  sql.NullString
+	)
+
+	if err := row.Scan(
+		&subscription.ID,
+		&subscription.URL,
+		&eventTypes,
+		&description,
+		&subscription.Secret,
+		&subscription.Active,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	subscription.EventTypes = make([]model.EventType, len(eventTypes))
+	for i, eventType := range eventTypes {
+		subscription.EventTypes[i] = model.EventType(eventType)
+	}
+	subscription.Description = description.String
+
+	return &subscription, nil
+}
+
+// FindByID retrieves a subscription by its ID
+func (r *PostgreSQLSubscriptionRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM webhook_subscriptions
+		WHERE id = $1
+	`
+
+	subscription, err := scanSubscription(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("subscription not found")
+		}
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// FindAll retrieves every subscription, oldest first
+func (r *PostgreSQLSubscriptionRepository) FindAll(ctx context.Context) ([]*model.Subscription, error) {
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM webhook_subscriptions
+		ORDER BY created_at
+	`
+
+	return r.findAll(ctx, query)
+}
+
+// FindActiveByEventType retrieves the active subscriptions that filter on the event type
+func (r *PostgreSQLSubscriptionRepository) FindActiveByEventType(ctx context.Context, eventType model.EventType) ([]*model.Subscription, error) {
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM webhook_subscriptions
+		WHERE active AND ($1 = ANY(event_types) OR $2 = ANY(event_types))
+		ORDER BY created_at
+	`
+
+	return r.findAll(ctx, query, string(eventType), string(model.EventTypeAll))
+}
+
+// findAll runs a query that selects subscriptionColumns and reads every row
+func (r *PostgreSQLSubscriptionRepository) findAll(ctx context.Context, query string, args ...interface{}) ([]*model.Subscription, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*model.Subscription, 0)
+
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// Save persists a subscription (creates or updates)
+func (r *PostgreSQLSubscriptionRepository) Save(ctx context.Context, subscription *model.Subscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (id, url, event_types, description, secret, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO UPDATE
+		SET url = $2, event_types = $3, description = $4, secret = $5, active = $6, updated_at = $8
+	`
+
+	eventTypes := make([]string, len(subscription.EventTypes))
+	for i, eventType := range subscription.EventTypes {
+		eventTypes[i] = string(eventType)
+	}
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		subscription.ID,
+		subscription.URL,
+		pq.Array(eventTypes),
+		subscription.Description,
+		subscription.Secret,
+		subscription.Active,
+		subscription.CreatedAt,
+		subscription.UpdatedAt,
+	)
+
+	return err
+}
+
+// Delete removes a subscription and its deliveries
+func (r *PostgreSQLSubscriptionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE subscription_id = $1`, id); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("subscription not found")
+	}
+
+	return tx.Commit()
+}
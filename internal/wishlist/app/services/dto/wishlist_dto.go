@@ -0,0 +1,146 @@
+package dto
+
+import (
+	cartDto "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/wishlist/domain/model"
+)
+
+// WishlistItemDTO represents a wishlist item for API responses
+type WishlistItemDTO struct {
+	ID        string          `json:"id"`
+	ProductID string          `json:"productId"`
+	VariantID string          `json:"variantId,omitempty"`
+	Options   []ItemOptionDTO `json:"options"`
+	Name      string          `json:"name"`
+	Price     float64         `json:"price"`
+	Quantity  int             `json:"quantity"`
+	ImageURL  string          `json:"imageUrl"`
+	Category  string          `json:"category,omitempty"`
+	AddedAt   string          `json:"addedAt"`
+}
+
+// ItemOptionDTO represents a customization option of a wishlist item
+type ItemOptionDTO struct {
+	Name      string  `json:"name"`
+	Value     string  `json:"value"`
+	Surcharge float64 `json:"surcharge"`
+}
+
+// WishlistResponse represents wishlist data for API responses. The share token is
+// only included for the owner.
+type WishlistResponse struct {
+	ID         string            `json:"id"`
+	UserID     string            `json:"userId,omitempty"`
+	Name       string            `json:"name"`
+	Visibility string            `json:"visibility"`
+	ShareToken string            `json:"shareToken,omitempty"`
+	Items      []WishlistItemDTO `json:"items"`
+	CreatedAt  string            `json:"createdAt"`
+	UpdatedAt  string            `json:"updatedAt"`
+}
+
+// WishlistCreateRequest represents the request to create a wishlist
+type WishlistCreateRequest struct {
+	UserID     string `json:"userId" validate:"required,uuid"`
+	Name       string `json:"name" validate:"required"`
+	Visibility string `json:"visibility,omitempty"`
+}
+
+// WishlistUpdateRequest represents the request to rename a wishlist or change its visibility.
+// Empty fields are left unchanged.
+type WishlistUpdateRequest struct {
+	Name       string `json:"name,omitempty"`
+	Visibility string `json:"visibility,omitempty"`
+}
+
+// WishlistItemRequest represents the request to add a product to a wishlist
+type WishlistItemRequest struct {
+	ProductID string          `json:"productId" validate:"required,uuid"`
+	VariantID string          `json:"variantId,omitempty"`
+	Options   []ItemOptionDTO `json:"options,omitempty"`
+	Name      string          `json:"name" validate:"required"`
+	Price     float64         `json:"price" validate:"gte=0"`
+	Quantity  int             `json:"quantity,omitempty"`
+	ImageURL  string          `json:"imageUrl"`
+	Category  string          `json:"category,omitempty"`
+}
+
+// AddToCartRequest represents the request to add every wishlist item to a cart
+type AddToCartRequest struct {
+	CartID string `json:"cartId" validate:"required,uuid"`
+}
+
+// AddToCartFailure describes a wishlist item that could not be added to the cart
+type AddToCartFailure struct {
+	ItemID     string                     `json:"itemId"`
+	ProductID  string                     `json:"productId"`
+	Message    string                     `json:"message"`
+	Violations []cartDto.RuleViolationDTO `json:"violations,omitempty"`
+}
+
+// AddToCartResponse represents the result of adding every wishlist item to a cart
+type AddToCartResponse struct {
+	Cart   *cartDto.CartResponse `json:"cart"`
+	Added  []string              `json:"added"`
+	Failed []AddToCartFailure    `json:"failed"`
+}
+
+// WishlistFromDomain converts a wishlist domain model to the owner's response DTO
+func WishlistFromDomain(wishlist *model.Wishlist) *WishlistResponse {
+	items := make([]WishlistItemDTO, len(wishlist.Items))
+	for i, item := range wishlist.Items {
+		options := make([]ItemOptionDTO, len(item.Options))
+		for j, option := range item.Options {
+			options[j] = ItemOptionDTO{
+				Name:      option.Name,
+				Value:     option.Value,
+				Surcharge: option.Surcharge,
+			}
+		}
+		items[i] = WishlistItemDTO{
+			ID:        item.ID.String(),
+			ProductID: item.ProductID.String(),
+			VariantID: item.VariantID,
+			Options:   options,
+			Name:      item.Name,
+			Price:     item.Price,
+			Quantity:  item.Quantity,
+			ImageURL:  item.ImageURL,
+			Category:  item.Category,
+			AddedAt:   item.AddedAt.Format("2006-01-02T15:04:05Z"),
+		}
+	}
+
+	return &WishlistResponse{
+		ID:         wishlist.ID.String(),
+		UserID:     wishlist.UserID.String(),
+		Name:       wishlist.Name,
+		Visibility: string(wishlist.Visibility),
+		ShareToken: wishlist.ShareToken,
+		Items:      items,
+		CreatedAt:  wishlist.CreatedAt.Format("2006-01-02T15:04:05Z"),
+		UpdatedAt:  wishlist.UpdatedAt.Format("2006-01-02T15:04:05Z"),
+	}
+}
+
+// SharedWishlistFromDomain converts a wishlist to the read-only view seen through a share
+// link, without the owner or the share token
+func SharedWishlistFromDomain(wishlist *model.Wishlist) *WishlistResponse {
+	response := WishlistFromDomain(wishlist)
+	response.UserID = ""
+	response.ShareToken = ""
+	return response
+}
+
+// ItemOptionsToDomain converts option DTOs to wishlist item options
+func ItemOptionsToDomain(options []ItemOptionDTO) []model.ItemOption {
+	result := make([]model.ItemOption, len(options))
+	for i, option := range options {
+		result[i] = model.ItemOption{
+			Name:      option.Name,
+			Value:     option.Value,
+			Surcharge: option.Surcharge,
+		}
+	}
+	return result
+}
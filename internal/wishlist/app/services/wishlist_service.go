@@ -0,0 +1,367 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	cartServices "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services"
+	cartDto "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/app/services/dto"
+	cartModel "github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/cart/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/auth"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/wishlist/app/services/dto"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/wishlist/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/wishlist/domain/repository"
+)
+
+// WishlistService handles operations related to wishlists
+type WishlistService struct {
+	wishlistRepository repository.WishlistRepository
+	cartService        *cartServices.CartService
+}
+
+// NewWishlistService creates a new wishlist service
+func NewWishlistService(wishlistRepository repository.WishlistRepository, cartService *cartServices.CartService) *WishlistService {
+	return &WishlistService{
+		wishlistRepository: wishlistRepository,
+		cartService:        cartService,
+	}
+}
+
+// CreateWishlist creates a new named wishlist for a user. Wishlists are private unless stated otherwise.
+func (s *WishlistService) CreateWishlist(ctx context.Context, req *dto.WishlistCreateRequest) (*dto.WishlistResponse, error) {
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+	if err := authorizeOwner(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	visibility := model.Visibility(req.Visibility)
+	if visibility == "" {
+		visibility = model.VisibilityPrivate
+	}
+
+	wishlist, err := model.NewWishlist(userID, req.Name, visibility)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.wishlistRepository.Save(ctx, wishlist); err != nil {
+		return nil, err
+	}
+
+	return dto.WishlistFromDomain(wishlist), nil
+}
+
+// ListWishlists retrieves every wishlist of a user, who must be the acting user
+func (s *WishlistService) ListWishlists(ctx context.Context, userID string) ([]*dto.WishlistResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+	if err := authorizeOwner(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.listWishlists(ctx, id, "", dto.WishlistFromDomain)
+}
+
+// ListPublicWishlists retrieves the public wishlists of a user, as seen by anyone
+func (s *WishlistService) ListPublicWishlists(ctx context.Context, userID string) ([]*dto.WishlistResponse, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID format")
+	}
+
+	return s.listWishlists(ctx, id, model.VisibilityPublic, dto.SharedWishlistFromDomain)
+}
+
+// listWishlists retrieves the wishlists of a user with the given visibility and converts them
+func (s *WishlistService) listWishlists(ctx context.Context, userID uuid.UUID, visibility model.Visibility, convert func(*model.Wishlist) *dto.WishlistResponse) ([]*dto.WishlistResponse, error) {
+	wishlists, err := s.wishlistRepository.FindByUserID(ctx, userID, visibility)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.WishlistResponse, len(wishlists))
+	for i, wishlist := range wishlists {
+		result[i] = convert(wishlist)
+	}
+
+	return result, nil
+}
+
+// GetWishlist retrieves a wishlist by ID, as seen by its owner
+func (s *WishlistService) GetWishlist(ctx context.Context, wishlistID string) (*dto.WishlistResponse, error) {
+	wishlist, err := s.findWishlist(ctx, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.WishlistFromDomain(wishlist), nil
+}
+
+// GetSharedWishlist retrieves the read-only view of a wishlist through its share token.
+// Private wishlists are reported as not found, so a token does not reveal they exist.
+func (s *WishlistService) GetSharedWishlist(ctx context.Context, token string) (*dto.WishlistResponse, error) {
+	wishlist, err := s.findSharedWishlist(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return dto.SharedWishlistFromDomain(wishlist), nil
+}
+
+// UpdateWishlist renames a wishlist or changes its visibility
+func (s *WishlistService) UpdateWishlist(ctx context.Context, wishlistID string, req *dto.WishlistUpdateRequest) (*dto.WishlistResponse, error) {
+	wishlist, err := s.findWishlist(ctx, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		if err := wishlist.Rename(req.Name); err != nil {
+			return nil, err
+		}
+	}
+	if req.Visibility != "" {
+		if err := wishlist.SetVisibility(model.Visibility(req.Visibility)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.wishlistRepository.Save(ctx, wishlist); err != nil {
+		return nil, err
+	}
+
+	return dto.WishlistFromDomain(wishlist), nil
+}
+
+// RegenerateShareToken replaces the share token of a wishlist, revoking the links shared so far
+func (s *WishlistService) RegenerateShareToken(ctx context.Context, wishlistID string) (*dto.WishlistResponse, error) {
+	wishlist, err := s.findWishlist(ctx, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := wishlist.RegenerateShareToken(); err != nil {
+		return nil, err
+	}
+
+	if err := s.wishlistRepository.Save(ctx, wishlist); err != nil {
+		return nil, err
+	}
+
+	return dto.WishlistFromDomain(wishlist), nil
+}
+
+// DeleteWishlist removes a wishlist
+func (s *WishlistService) DeleteWishlist(ctx context.Context, wishlistID string) error {
+	wishlist, err := s.findWishlist(ctx, wishlistID)
+	if err != nil {
+		return err
+	}
+
+	return s.wishlistRepository.Delete(ctx, wishlist.ID)
+}
+
+// AddItem adds a product to a wishlist
+func (s *WishlistService) AddItem(ctx context.Context, wishlistID string, req *dto.WishlistItemRequest) (*dto.WishlistResponse, error) {
+	wishlist, err := s.findWishlist(ctx, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	productID, err := uuid.Parse(req.ProductID)
+	if err != nil {
+		return nil, errors.New("invalid product ID format")
+	}
+
+	quantity := req.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+
+	if _, err := wishlist.AddItem(
+		productID,
+		req.VariantID,
+		dto.ItemOptionsToDomain(req.Options),
+		req.Name,
+		req.Price,
+		quantity,
+		req.ImageURL,
+		req.Category,
+	); err != nil {
+		return nil, err
+	}
+
+	if err := s.wishlistRepository.Save(ctx, wishlist); err != nil {
+		return nil, err
+	}
+
+	return dto.WishlistFromDomain(wishlist), nil
+}
+
+// RemoveItem removes an item from a wishlist
+func (s *WishlistService) RemoveItem(ctx context.Context, wishlistID string, itemID string) error {
+	wishlist, err := s.findWishlist(ctx, wishlistID)
+	if err != nil {
+		return err
+	}
+
+	itemUUID, err := uuid.Parse(itemID)
+	if err != nil {
+		return errors.New("invalid item ID format")
+	}
+
+	if err := wishlist.RemoveItem(itemUUID); err != nil {
+		return err
+	}
+
+	return s.wishlistRepository.Save(ctx, wishlist)
+}
+
+// AddAllToCart adds every item of a wishlist to a cart
+func (s *WishlistService) AddAllToCart(ctx context.Context, wishlistID string, req *dto.AddToCartRequest) (*dto.AddToCartResponse, error) {
+	wishlist, err := s.findWishlist(ctx, wishlistID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.addAllToCart(ctx, wishlist, req.CartID)
+}
+
+// AddSharedToCart adds every item of a wishlist shared by link to a cart
+func (s *WishlistService) AddSharedToCart(ctx context.Context, token string, req *dto.AddToCartRequest) (*dto.AddToCartResponse, error) {
+	wishlist, err := s.findSharedWishlist(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.addAllToCart(ctx, wishlist, req.CartID)
+}
+
+// addAllToCart adds the wishlist items one by one through the cart service, on behalf of the
+// acting user, so the cart's roles and purchase rules apply. Items that are rejected are
+// reported and do not stop the others; a cart that is missing or that the user cannot use
+// stops the operation.
+func (s *WishlistService) addAllToCart(ctx context.Context, wishlist *model.Wishlist, cartID string) (*dto.AddToCartResponse, error) {
+	if _, err := uuid.Parse(cartID); err != nil {
+		return nil, errors.New("invalid cart ID format")
+	}
+	if len(wishlist.Items) == 0 {
+		return nil, errors.New("wishlist is empty")
+	}
+
+	actor, ok := auth.UserFrom(ctx)
+	if !ok {
+		return nil, errors.New("user ID is required")
+	}
+	ctx = cartServices.WithActor(ctx, actor)
+
+	result := &dto.AddToCartResponse{
+		Added:  make([]string, 0),
+		Failed: make([]dto.AddToCartFailure, 0),
+	}
+
+	for _, item := range wishlist.Items {
+		options := make([]cartDto.ItemOptionDTO, len(item.Options))
+		for i, option := range item.Options {
+			options[i] = cartDto.ItemOptionDTO{
+				Name:      option.Name,
+				Value:     option.Value,
+				Surcharge: option.Surcharge,
+			}
+		}
+
+		cart, err := s.cartService.AddCartItem(ctx, cartID, &cartDto.CartItemRequest{
+			ProductID: item.ProductID.String(),
+			VariantID: item.VariantID,
+			Options:   options,
+			Name:      item.Name,
+			Price:     item.Price,
+			Quantity:  item.Quantity,
+			ImageURL:  item.ImageURL,
+			Category:  item.Category,
+		})
+		if err != nil {
+			if err.Error() == "cart not found" || err.Error() == "cart access denied" {
+				return nil, err
+			}
+
+			failure := dto.AddToCartFailure{
+				ItemID:    item.ID.String(),
+				ProductID: item.ProductID.String(),
+				Message:   err.Error(),
+			}
+			if violations, ok := err.(*cartModel.RuleViolationError); ok {
+				failure.Violations = cartDto.RuleViolationsFromDomain(violations)
+			}
+			result.Failed = append(result.Failed, failure)
+			continue
+		}
+
+		result.Cart = cart
+		result.Added = append(result.Added, item.ID.String())
+	}
+
+	if result.Cart == nil {
+		cart, err := s.cartService.GetCart(ctx, cartID)
+		if err != nil {
+			return nil, err
+		}
+		result.Cart = cart
+	}
+
+	return result, nil
+}
+
+// findWishlist loads a wishlist by ID, checking that the acting user owns it
+func (s *WishlistService) findWishlist(ctx context.Context, wishlistID string) (*model.Wishlist, error) {
+	id, err := uuid.Parse(wishlistID)
+	if err != nil {
+		return nil, errors.New("invalid wishlist ID format")
+	}
+
+	wishlist, err := s.wishlistRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeOwner(ctx, wishlist.UserID); err != nil {
+		return nil, err
+	}
+
+	return wishlist, nil
+}
+
+// authorizeOwner checks that the acting user is the given wishlist owner
+func authorizeOwner(ctx context.Context, userID uuid.UUID) error {
+	actor, ok := auth.UserFrom(ctx)
+	if !ok {
+		return errors.New("user ID is required")
+	}
+	if actor != userID {
+		return errors.New("wishlist access denied")
+	}
+	return nil
+}
+
+// findSharedWishlist loads a wishlist by share token, hiding private wishlists
+func (s *WishlistService) findSharedWishlist(ctx context.Context, token string) (*model.Wishlist, error) {
+	if token == "" {
+		return nil, errors.New("wishlist not found")
+	}
+
+	wishlist, err := s.wishlistRepository.FindByShareToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !wishlist.IsShared() {
+		return nil, errors.New("wishlist not found")
+	}
+
+	return wishlist, nil
+}
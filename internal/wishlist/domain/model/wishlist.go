@@ -0,0 +1,208 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Visibility represents who can see a wishlist
+type Visibility string
+
+const (
+	// VisibilityPrivate lists are only visible to their owner
+	VisibilityPrivate Visibility = "PRIVATE"
+	// VisibilitySharedByLink lists can be read by anyone who has the share token
+	VisibilitySharedByLink Visibility = "SHARED_BY_LINK"
+	// VisibilityPublic lists can be read through the share token and are listed on the owner's profile
+	VisibilityPublic Visibility = "PUBLIC"
+)
+
+// maxWishlistNameLength limits the length of a wishlist name
+const maxWishlistNameLength = 100
+
+// shareTokenBytes is the amount of random bytes in a share token
+const shareTokenBytes = 24
+
+// Wishlist represents the Wishlist aggregate root: a named list of products a user wants
+type Wishlist struct {
+	ID         uuid.UUID       `json:"id"`
+	UserID     uuid.UUID       `json:"userId"`
+	Name       string          `json:"name"`
+	Visibility Visibility      `json:"visibility"`
+	ShareToken string          `json:"shareToken"`
+	Items      []*WishlistItem `json:"items"`
+	CreatedAt  time.Time       `json:"createdAt"`
+	UpdatedAt  time.Time       `json:"updatedAt"`
+}
+
+// WishlistItem represents a product saved in a wishlist
+type WishlistItem struct {
+	ID        uuid.UUID    `json:"id"`
+	ProductID uuid.UUID    `json:"productId"`
+	VariantID string       `json:"variantId,omitempty"`
+	Options   []ItemOption `json:"options,omitempty"`
+	Name      string       `json:"name"`
+	Price     float64      `json:"price"`
+	Quantity  int          `json:"quantity"`
+	ImageURL  string       `json:"imageUrl"`
+	Category  string       `json:"category,omitempty"`
+	AddedAt   time.Time    `json:"addedAt"`
+}
+
+// ItemOption represents a customization chosen for a wishlist item (size, color, engraving text)
+type ItemOption struct {
+	Name      string  `json:"name"`
+	Value     string  `json:"value"`
+	Surcharge float64 `json:"surcharge,omitempty"`
+}
+
+// IsValidVisibility checks if a visibility is supported
+func IsValidVisibility(visibility Visibility) bool {
+	switch visibility {
+	case VisibilityPrivate, VisibilitySharedByLink, VisibilityPublic:
+		return true
+	}
+	return false
+}
+
+// NewWishlist creates a new empty wishlist with a random share token
+func NewWishlist(userID uuid.UUID, name string, visibility Visibility) (*Wishlist, error) {
+	if userID == uuid.Nil {
+		return nil, errors.New("user ID is required")
+	}
+
+	wishlist := &Wishlist{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Items:     make([]*WishlistItem, 0),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	if err := wishlist.Rename(name); err != nil {
+		return nil, err
+	}
+	if err := wishlist.SetVisibility(visibility); err != nil {
+		return nil, err
+	}
+	if err := wishlist.RegenerateShareToken(); err != nil {
+		return nil, err
+	}
+
+	return wishlist, nil
+}
+
+// Rename changes the name of the wishlist
+func (w *Wishlist) Rename(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("wishlist name is required")
+	}
+	if len(name) > maxWishlistNameLength {
+		return errors.New("wishlist name is too long")
+	}
+
+	w.Name = name
+	w.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetVisibility changes who can see the wishlist
+func (w *Wishlist) SetVisibility(visibility Visibility) error {
+	if !IsValidVisibility(visibility) {
+		return errors.New("invalid visibility")
+	}
+
+	w.Visibility = visibility
+	w.UpdatedAt = time.Now()
+	return nil
+}
+
+// RegenerateShareToken replaces the share token, revoking the links shared so far
+func (w *Wishlist) RegenerateShareToken() error {
+	token := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return err
+	}
+
+	w.ShareToken = base64.RawURLEncoding.EncodeToString(token)
+	w.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsShared returns true if the wishlist can be read through its share token
+func (w *Wishlist) IsShared() bool {
+	return w.Visibility == VisibilitySharedByLink || w.Visibility == VisibilityPublic
+}
+
+// AddItem adds a product to the wishlist. The same product, variant and options can only be added once.
+func (w *Wishlist) AddItem(productID uuid.UUID, variantID string, options []ItemOption, name string, price float64, quantity int, imageURL string, category string) (*WishlistItem, error) {
+	if productID == uuid.Nil {
+		return nil, errors.New("product ID is required")
+	}
+	if price < 0 {
+		return nil, errors.New("price cannot be negative")
+	}
+	if quantity <= 0 {
+		return nil, errors.New("quantity must be greater than zero")
+	}
+
+	variantID = strings.TrimSpace(variantID)
+	for _, item := range w.Items {
+		if item.ProductID == productID && item.VariantID == variantID && sameOptions(item.Options, options) {
+			return nil, errors.New("product already in wishlist")
+		}
+	}
+
+	item := &WishlistItem{
+		ID:        uuid.New(),
+		ProductID: productID,
+		VariantID: variantID,
+		Options:   options,
+		Name:      name,
+		Price:     price,
+		Quantity:  quantity,
+		ImageURL:  imageURL,
+		Category:  category,
+		AddedAt:   time.Now(),
+	}
+
+	w.Items = append(w.Items, item)
+	w.UpdatedAt = time.Now()
+	return item, nil
+}
+
+// RemoveItem removes an item from the wishlist
+func (w *Wishlist) RemoveItem(itemID uuid.UUID) error {
+	for i, item := range w.Items {
+		if item.ID == itemID {
+			w.Items = append(w.Items[:i], w.Items[i+1:]...)
+			w.UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return errors.New("item not found in wishlist")
+}
+
+// sameOptions returns true if both option sets have the same names and values, in any order
+func sameOptions(a, b []ItemOption) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	values := make(map[string]string, len(a))
+	for _, option := range a {
+		values[strings.ToLower(strings.TrimSpace(option.Name))] = strings.TrimSpace(option.Value)
+	}
+	for _, option := range b {
+		value, ok := values[strings.ToLower(strings.TrimSpace(option.Name))]
+		if !ok || value != strings.TrimSpace(option.Value) {
+			return false
+		}
+	}
+	return true
+}
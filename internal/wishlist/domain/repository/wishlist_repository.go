@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/wishlist/domain/model"
+)
+
+// WishlistRepository defines the interface for wishlist persistence operations
+type WishlistRepository interface {
+	// FindByID retrieves a wishlist by its ID
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Wishlist, error)
+
+	// FindByShareToken retrieves a wishlist by its share token
+	FindByShareToken(ctx context.Context, token string) (*model.Wishlist, error)
+
+	// FindByUserID retrieves the wishlists of a user, optionally only those with the given visibility
+	FindByUserID(ctx context.Context, userID uuid.UUID, visibility model.Visibility) ([]*model.Wishlist, error)
+
+	// Save persists a wishlist and its items (creates or updates)
+	Save(ctx context.Context, wishlist *model.Wishlist) error
+
+	// Delete removes a wishlist
+	Delete(ctx context.Context, id uuid.UUID) error
+}
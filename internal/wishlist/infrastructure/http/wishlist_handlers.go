@@ -0,0 +1,438 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/auth"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/common/errors"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/wishlist/app/services"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/wishlist/app/services/dto"
+)
+
+// wishlistBadRequestErrors lists the wishlist errors caused by invalid client input
+var wishlistBadRequestErrors = map[string]bool{
+	"invalid user ID format":             true,
+	"invalid wishlist ID format":         true,
+	"invalid item ID format":             true,
+	"invalid product ID format":          true,
+	"invalid cart ID format":             true,
+	"user ID is required":                true,
+	"product ID is required":             true,
+	"wishlist name is required":          true,
+	"wishlist name is too long":          true,
+	"invalid visibility":                 true,
+	"price cannot be negative":           true,
+	"quantity must be greater than zero": true,
+	"wishlist is empty":                  true,
+}
+
+// WishlistHandler handles HTTP requests for wishlist operations
+type WishlistHandler struct {
+	wishlistService *services.WishlistService
+}
+
+// NewWishlistHandler creates a new wishlist handler
+func NewWishlistHandler(wishlistService *services.WishlistService) *WishlistHandler {
+	return &WishlistHandler{
+		wishlistService: wishlistService,
+	}
+}
+
+// RegisterRoutes registers the wishlist routes on the given router
+func (h *WishlistHandler) RegisterRoutes(router *mux.Router) {
+	// Create a subrouter for wishlist routes
+	wishlistRouter := router.PathPrefix("/wishlists").Subrouter()
+
+	// Register routes. The fixed paths go first so they are not taken as wishlist IDs.
+	// Public and shared wishlists can be read by anyone.
+	wishlistRouter.HandleFunc("/public", h.ListPublicWishlists).Methods("GET")
+	wishlistRouter.HandleFunc("/shared/{token}", h.GetSharedWishlist).Methods("GET")
+
+	// Every other route acts on behalf of the user named in the X-User-ID header, who must
+	// own the wishlist; adding to a cart also goes through the cart's roles
+	userRouter := wishlistRouter.NewRoute().Subrouter()
+	userRouter.Use(auth.RequireUser)
+
+	userRouter.HandleFunc("", h.CreateWishlist).Methods("POST")
+	userRouter.HandleFunc("", h.ListWishlists).Methods("GET")
+	userRouter.HandleFunc("/shared/{token}/add-to-cart", h.AddSharedToCart).Methods("POST")
+	userRouter.HandleFunc("/{wishlistId}", h.GetWishlist).Methods("GET")
+	userRouter.HandleFunc("/{wishlistId}", h.UpdateWishlist).Methods("PATCH")
+	userRouter.HandleFunc("/{wishlistId}", h.DeleteWishlist).Methods("DELETE")
+	userRouter.HandleFunc("/{wishlistId}/share-token", h.RegenerateShareToken).Methods("POST")
+	userRouter.HandleFunc("/{wishlistId}/items", h.AddItem).Methods("POST")
+	userRouter.HandleFunc("/{wishlistId}/items/{itemId}", h.RemoveItem).Methods("DELETE")
+	userRouter.HandleFunc("/{wishlistId}/add-to-cart", h.AddAllToCart).Methods("POST")
+}
+
+// CreateWishlist handles the request to create a wishlist
+// @Summary Create wishlist
+// @Description Create a named wishlist for the acting user. Wishlists are private unless another visibility is given.
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "Acting user; must be the wishlist user" format(uuid)
+// @Param request body dto.WishlistCreateRequest true "Wishlist"
+// @Success 201 {object} dto.WishlistResponse "Wishlist created successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Missing user ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the wishlist owner"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/wishlists [post]
+func (h *WishlistHandler) CreateWishlist(w http.ResponseWriter, r *http.Request) {
+	var req dto.WishlistCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	wishlist, err := h.wishlistService.CreateWishlist(r.Context(), &req)
+	if err != nil {
+		writeWishlistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(wishlist)
+}
+
+// ListWishlists handles the request to list the wishlists of a user
+// @Summary List wishlists
+// @Description List every wishlist of the acting user
+// @Tags wishlists
+// @Produce json
+// @Param X-User-ID header string true "Acting user; must be the listed user" format(uuid)
+// @Param userId query string true "User ID" format(uuid)
+// @Success 200 {array} dto.WishlistResponse "Wishlists"
+// @Failure 400 {object} errors.ErrorResponse "Invalid user ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing user ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the listed user"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/wishlists [get]
+func (h *WishlistHandler) ListWishlists(w http.ResponseWriter, r *http.Request) {
+	wishlists, err := h.wishlistService.ListWishlists(r.Context(), r.URL.Query().Get("userId"))
+	if err != nil {
+		writeWishlistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wishlists)
+}
+
+// ListPublicWishlists handles the request to list the public wishlists of a user
+// @Summary List public wishlists
+// @Description List the public wishlists of a user, as seen by anyone
+// @Tags wishlists
+// @Produce json
+// @Param userId query string true "User ID" format(uuid)
+// @Success 200 {array} dto.WishlistResponse "Public wishlists"
+// @Failure 400 {object} errors.ErrorResponse "Invalid user ID"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/wishlists/public [get]
+func (h *WishlistHandler) ListPublicWishlists(w http.ResponseWriter, r *http.Request) {
+	wishlists, err := h.wishlistService.ListPublicWishlists(r.Context(), r.URL.Query().Get("userId"))
+	if err != nil {
+		writeWishlistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wishlists)
+}
+
+// GetSharedWishlist handles the request to read a wishlist through its share link
+// @Summary Get shared wishlist
+// @Description Get the read-only view of a wishlist shared by link or public. Private wishlists are reported as not found.
+// @Tags wishlists
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} dto.WishlistResponse "Wishlist"
+// @Failure 404 {object} errors.ErrorResponse "Wishlist not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/wishlists/shared/{token} [get]
+func (h *WishlistHandler) GetSharedWishlist(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	token := vars["token"]
+
+	wishlist, err := h.wishlistService.GetSharedWishlist(r.Context(), token)
+	if err != nil {
+		writeWishlistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wishlist)
+}
+
+// AddSharedToCart handles the request to add every item of a shared wishlist to a cart
+// @Summary Add shared wishlist to cart
+// @Description Add every item of a wishlist shared by link to a cart the acting user can edit. Items rejected by the cart are reported in failed.
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "Acting user; must be allowed to edit the cart" format(uuid)
+// @Param token path string true "Share token"
+// @Param request body dto.AddToCartRequest true "Target cart"
+// @Success 200 {object} dto.AddToCartResponse "Items added"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Missing user ID"
+// @Failure 403 {object} errors.ErrorResponse "Cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Wishlist or cart not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/wishlists/shared/{token}/add-to-cart [post]
+func (h *WishlistHandler) AddSharedToCart(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	token := vars["token"]
+
+	var req dto.AddToCartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.wishlistService.AddSharedToCart(r.Context(), token, &req)
+	if err != nil {
+		writeWishlistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// GetWishlist handles the request to get a wishlist by ID
+// @Summary Get wishlist
+// @Description Get a wishlist of the acting user
+// @Tags wishlists
+// @Produce json
+// @Param X-User-ID header string true "Acting user; must be the wishlist owner" format(uuid)
+// @Param wishlistId path string true "Wishlist ID" format(uuid)
+// @Success 200 {object} dto.WishlistResponse "Wishlist"
+// @Failure 400 {object} errors.ErrorResponse "Invalid wishlist ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing user ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the wishlist owner"
+// @Failure 404 {object} errors.ErrorResponse "Wishlist not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/wishlists/{wishlistId} [get]
+func (h *WishlistHandler) GetWishlist(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	wishlistID := vars["wishlistId"]
+
+	wishlist, err := h.wishlistService.GetWishlist(r.Context(), wishlistID)
+	if err != nil {
+		writeWishlistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wishlist)
+}
+
+// UpdateWishlist handles the request to rename a wishlist or change its visibility
+// @Summary Update wishlist
+// @Description Rename a wishlist or change its visibility
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "Acting user; must be the wishlist owner" format(uuid)
+// @Param wishlistId path string true "Wishlist ID" format(uuid)
+// @Param request body dto.WishlistUpdateRequest true "Wishlist changes"
+// @Success 200 {object} dto.WishlistResponse "Wishlist updated successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Missing user ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the wishlist owner"
+// @Failure 404 {object} errors.ErrorResponse "Wishlist not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/wishlists/{wishlistId} [patch]
+func (h *WishlistHandler) UpdateWishlist(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	wishlistID := vars["wishlistId"]
+
+	var req dto.WishlistUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	wishlist, err := h.wishlistService.UpdateWishlist(r.Context(), wishlistID, &req)
+	if err != nil {
+		writeWishlistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wishlist)
+}
+
+// DeleteWishlist handles the request to delete a wishlist
+// @Summary Delete wishlist
+// @Description Delete a wishlist of the acting user
+// @Tags wishlists
+// @Param X-User-ID header string true "Acting user; must be the wishlist owner" format(uuid)
+// @Param wishlistId path string true "Wishlist ID" format(uuid)
+// @Success 204 "Wishlist deleted successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid wishlist ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing user ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the wishlist owner"
+// @Failure 404 {object} errors.ErrorResponse "Wishlist not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/wishlists/{wishlistId} [delete]
+func (h *WishlistHandler) DeleteWishlist(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	wishlistID := vars["wishlistId"]
+
+	if err := h.wishlistService.DeleteWishlist(r.Context(), wishlistID); err != nil {
+		writeWishlistError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RegenerateShareToken handles the request to replace the share link of a wishlist
+// @Summary Regenerate share token
+// @Description Replace the share token of a wishlist, revoking the links shared so far
+// @Tags wishlists
+// @Produce json
+// @Param X-User-ID header string true "Acting user; must be the wishlist owner" format(uuid)
+// @Param wishlistId path string true "Wishlist ID" format(uuid)
+// @Success 200 {object} dto.WishlistResponse "Share token replaced"
+// @Failure 400 {object} errors.ErrorResponse "Invalid wishlist ID"
+// @Failure 401 {object} errors.ErrorResponse "Missing user ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the wishlist owner"
+// @Failure 404 {object} errors.ErrorResponse "Wishlist not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/wishlists/{wishlistId}/share-token [post]
+func (h *WishlistHandler) RegenerateShareToken(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	wishlistID := vars["wishlistId"]
+
+	wishlist, err := h.wishlistService.RegenerateShareToken(r.Context(), wishlistID)
+	if err != nil {
+		writeWishlistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wishlist)
+}
+
+// AddItem handles the request to add a product to a wishlist
+// @Summary Add wishlist item
+// @Description Add a product, with its variant and options, to a wishlist
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "Acting user; must be the wishlist owner" format(uuid)
+// @Param wishlistId path string true "Wishlist ID" format(uuid)
+// @Param request body dto.WishlistItemRequest true "Item"
+// @Success 200 {object} dto.WishlistResponse "Item added successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Missing user ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the wishlist owner"
+// @Failure 404 {object} errors.ErrorResponse "Wishlist not found"
+// @Failure 409 {object} errors.ErrorResponse "Product already in wishlist"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/wishlists/{wishlistId}/items [post]
+func (h *WishlistHandler) AddItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	wishlistID := vars["wishlistId"]
+
+	var req dto.WishlistItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	wishlist, err := h.wishlistService.AddItem(r.Context(), wishlistID, &req)
+	if err != nil {
+		writeWishlistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wishlist)
+}
+
+// RemoveItem handles the request to remove an item from a wishlist
+// @Summary Remove wishlist item
+// @Description Remove an item from a wishlist
+// @Tags wishlists
+// @Param X-User-ID header string true "Acting user; must be the wishlist owner" format(uuid)
+// @Param wishlistId path string true "Wishlist ID" format(uuid)
+// @Param itemId path string true "Item ID" format(uuid)
+// @Success 204 "Item removed successfully"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Missing user ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the wishlist owner"
+// @Failure 404 {object} errors.ErrorResponse "Wishlist or item not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/wishlists/{wishlistId}/items/{itemId} [delete]
+func (h *WishlistHandler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	wishlistID := vars["wishlistId"]
+	itemID := vars["itemId"]
+
+	if err := h.wishlistService.RemoveItem(r.Context(), wishlistID, itemID); err != nil {
+		writeWishlistError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddAllToCart handles the request to add every item of a wishlist to a cart
+// @Summary Add wishlist to cart
+// @Description Add every item of a wishlist to a cart the acting user can edit. Items rejected by the cart are reported in failed.
+// @Tags wishlists
+// @Accept json
+// @Produce json
+// @Param X-User-ID header string true "Acting user; must be the wishlist owner" format(uuid)
+// @Param wishlistId path string true "Wishlist ID" format(uuid)
+// @Param request body dto.AddToCartRequest true "Target cart"
+// @Success 200 {object} dto.AddToCartResponse "Items added"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Missing user ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the wishlist owner or cart access denied"
+// @Failure 404 {object} errors.ErrorResponse "Wishlist or cart not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/wishlists/{wishlistId}/add-to-cart [post]
+func (h *WishlistHandler) AddAllToCart(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	wishlistID := vars["wishlistId"]
+
+	var req dto.AddToCartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		errors.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.wishlistService.AddAllToCart(r.Context(), wishlistID, &req)
+	if err != nil {
+		writeWishlistError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// writeWishlistError maps a wishlist service error to its HTTP status
+func writeWishlistError(w http.ResponseWriter, err error) {
+	switch {
+	case err.Error() == "wishlist not found" || err.Error() == "item not found in wishlist" || err.Error() == "cart not found":
+		errors.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+	case err.Error() == "wishlist access denied" || err.Error() == "cart access denied":
+		errors.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+	case err.Error() == "product already in wishlist":
+		errors.WriteErrorResponse(w, http.StatusConflict, err.Error())
+	case wishlistBadRequestErrors[err.Error()]:
+		errors.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+	default:
+		errors.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,75 @@
+package postgresql
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WishlistModel is the PostgreSQL representation of a wishlist
+type WishlistModel struct {
+	ID         uuid.UUID           `gorm:"type:uuid;primaryKey"`
+	UserID     uuid.UUID           `gorm:"type:uuid;not null;index"`
+	Name       string              `gorm:"type:varchar(100);not null"`
+	Visibility string              `gorm:"type:varchar(20);not null;default:'PRIVATE'"`
+	ShareToken string              `gorm:"type:varchar(64);not null;uniqueIndex"`
+	Items      []WishlistItemModel `gorm:"foreignKey:WishlistID;constraint:OnDelete:CASCADE"`
+	CreatedAt  time.Time           `gorm:"not null;default:now()"`
+	UpdatedAt  time.Time           `gorm:"not null;default:now()"`
+}
+
+// TableName overrides the table name for GORM
+func (WishlistModel) TableName() string {
+	return "wishlists"
+}
+
+// WishlistItemModel is the PostgreSQL representation of a wishlist item
+type WishlistItemModel struct {
+	ID         uuid.UUID       `gorm:"type:uuid;primaryKey"`
+	WishlistID uuid.UUID       `gorm:"type:uuid;not null;index"`
+	ProductID  uuid.UUID       `gorm:"type:uuid;not null"`
+	VariantID  string          `gorm:"type:varchar(100);not null;default:''"`
+	Options    ItemOptionsJSON `gorm:"type:jsonb;not null;default:'[]'"`
+	Name       string          `gorm:"type:varchar(255);not null"`
+	Price      float64         `gorm:"type:decimal(10,2);not null"`
+	Quantity   int             `gorm:"type:integer;not null;default:1"`
+	ImageURL   string          `gorm:"type:text;not null;default:''"`
+	Category   string          `gorm:"type:varchar(100);not null;default:''"`
+	Position   int             `gorm:"type:integer;not null;default:0"`
+	AddedAt    time.Time       `gorm:"not null;default:now()"`
+}
+
+// TableName overrides the table name for GORM
+func (WishlistItemModel) TableName() string {
+	return "wishlist_items"
+}
+
+// ItemOptionsJSON is a custom type for storing the options of a wishlist item as JSON in PostgreSQL
+type ItemOptionsJSON []ItemOptionJSON
+
+// ItemOptionJSON is the JSON representation of a wishlist item option
+type ItemOptionJSON struct {
+	Name      string  `json:"name"`
+	Value     string  `json:"value"`
+	Surcharge float64 `json:"surcharge,omitempty"`
+}
+
+// Value implements the driver.Valuer interface for ItemOptionsJSON
+func (o ItemOptionsJSON) Value() (driver.Value, error) {
+	if o == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(o)
+}
+
+// Scan implements the sql.Scanner interface for ItemOptionsJSON
+func (o *ItemOptionsJSON) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(b, &o)
+}
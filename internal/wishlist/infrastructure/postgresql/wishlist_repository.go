@@ -0,0 +1,284 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/wishlist/domain/model"
+	"github.com/ingenieria-del-software-2/kiosko-fiuba-shopping-experience/internal/wishlist/domain/repository"
+	"github.com/lib/pq"
+)
+
+// PostgreSQLWishlistRepository implements the WishlistRepository interface using PostgreSQL
+type PostgreSQLWishlistRepository struct {
+	db *sql.DB
+}
+
+// NewPostgreSQLWishlistRepository creates a new PostgreSQL repository for wishlists
+func NewPostgreSQLWishlistRepository(db *sql.DB) repository.WishlistRepository {
+	return &PostgreSQLWishlistRepository{
+		db: db,
+	}
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// FindByID retrieves a wishlist by its ID
+func (r *PostgreSQLWishlistRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Wishlist, error) {
+	query := `
+		SELECT id, user_id, name, visibility, share_token, created_at, updated_at
+		FROM wishlists
+		WHERE id = $1
+	`
+
+	return r.findOne(ctx, query, id)
+}
+
+// FindByShareToken retrieves a wishlist by its share token
+func (r *PostgreSQLWishlistRepository) FindByShareToken(ctx context.Context, token string) (*model.Wishlist, error) {
+	query := `
+		SELECT id, user_id, name, visibility, share_token, created_at, updated_at
+		FROM wishlists
+		WHERE share_token = $1
+	`
+
+	return r.findOne(ctx, query, token)
+}
+
+// FindByUserID retrieves the wishlists of a user, optionally only those with the given visibility
+func (r *PostgreSQLWishlistRepository) FindByUserID(ctx context.Context, userID uuid.UUID, visibility model.Visibility) ([]*model.Wishlist, error) {
+	query := `
+		SELECT id, user_id, name, visibility, share_token, created_at, updated_at
+		FROM wishlists
+		WHERE user_id = $1 AND ($2 = '' OR visibility = $2)
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, string(visibility))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	wishlists := make([]*model.Wishlist, 0)
+
+	for rows.Next() {
+		wishlist, err := scanWishlist(rows)
+		if err != nil {
+			return nil, err
+		}
+		wishlists = append(wishlists, wishlist)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadItems(ctx, wishlists...); err != nil {
+		return nil, err
+	}
+
+	return wishlists, nil
+}
+
+// findOne runs a single-row wishlist query and loads the wishlist items
+func (r *PostgreSQLWishlistRepository) findOne(ctx context.Context, query string, arg interface{}) (*model.Wishlist, error) {
+	wishlist, err := scanWishlist(r.db.QueryRowContext(ctx, query, arg))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("wishlist not found")
+		}
+		return nil, err
+	}
+
+	if err := r.loadItems(ctx, wishlist); err != nil {
+		return nil, err
+	}
+
+	return wishlist, nil
+}
+
+// scanWishlist reads the wishlist columns of a row. Items are loaded separately.
+func scanWishlist(row rowScanner) (*model.Wishlist, error) {
+	var (
+		wishlist   model.Wishlist
+		visibility string
+	)
+
+	if err := row.Scan(
+		&wishlist.ID,
+		&wishlist.UserID,
+		&wishlist.Name,
+		&visibility,
+		&wishlist.ShareToken,
+		&wishlist.CreatedAt,
+		&wishlist.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	wishlist.Visibility = model.Visibility(visibility)
+	wishlist.Items = make([]*model.WishlistItem, 0)
+
+	return &wishlist, nil
+}
+
+// loadItems fills the items of the given wishlists with a single query
+func (r *PostgreSQLWishlistRepository) loadItems(ctx context.Context, wishlists ...*model.Wishlist) error {
+	if len(wishlists) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*model.Wishlist, len(wishlists))
+	ids := make([]string, len(wishlists))
+	for i, wishlist := range wishlists {
+		byID[wishlist.ID] = wishlist
+		ids[i] = wishlist.ID.String()
+	}
+
+	query := `
+		SELECT wishlist_id, id, product_id, variant_id, options, name, price, quantity, image_url, category, added_at
+		FROM wishlist_items
+		WHERE wishlist_id = ANY($1::uuid[])
+		ORDER BY wishlist_id, position
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			wishlistID uuid.UUID
+			item       model.WishlistItem
+			options    ItemOptionsJSON
+		)
+
+		if err := rows.Scan(
+			&wishlistID,
+			&item.ID,
+			&item.ProductID,
+			&item.VariantID,
+			&options,
+			&item.Name,
+			&item.Price,
+			&item.Quantity,
+			&item.ImageURL,
+			&item.Category,
+			&item.AddedAt,
+		); err != nil {
+			return err
+		}
+
+		for _, option := range options {
+			item.Options = append(item.Options, model.ItemOption{
+				Name:      option.Name,
+				Value:     option.Value,
+				Surcharge: option.Surcharge,
+			})
+		}
+
+		wishlist := byID[wishlistID]
+		wishlist.Items = append(wishlist.Items, &item)
+	}
+
+	return rows.Err()
+}
+
+// Save persists a wishlist and its items in a single transaction. Wishlists are small,
+// so the items are replaced as a whole.
+func (r *PostgreSQLWishlistRepository) Save(ctx context.Context, wishlist *model.Wishlist) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	wishlistQuery := `
+		INSERT INTO wishlists (id, user_id, name, visibility, share_token, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO UPDATE
+		SET name = $3, visibility = $4, share_token = $5, updated_at = $7
+	`
+
+	if _, err := tx.ExecContext(
+		ctx,
+		wishlistQuery,
+		wishlist.ID,
+		wishlist.UserID,
+		wishlist.Name,
+		wishlist.Visibility,
+		wishlist.ShareToken,
+		wishlist.CreatedAt,
+		wishlist.UpdatedAt,
+	); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM wishlist_items WHERE wishlist_id = $1`, wishlist.ID); err != nil {
+		return err
+	}
+
+	itemQuery := `
+		INSERT INTO wishlist_items (id, wishlist_id, product_id, variant_id, options, name, price, quantity, image_url, category, position, added_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	for position, item := range wishlist.Items {
+		options := make(ItemOptionsJSON, len(item.Options))
+		for i, option := range item.Options {
+			options[i] = ItemOptionJSON{
+				Name:      option.Name,
+				Value:     option.Value,
+				Surcharge: option.Surcharge,
+			}
+		}
+
+		if _, err := tx.ExecContext(
+			ctx,
+			itemQuery,
+			item.ID,
+			wishlist.ID,
+			item.ProductID,
+			item.VariantID,
+			options,
+			item.Name,
+			item.Price,
+			item.Quantity,
+			item.ImageURL,
+			item.Category,
+			position,
+			item.AddedAt,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes a wishlist. Its items are removed by the foreign key cascade.
+func (r *PostgreSQLWishlistRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM wishlists WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.New("wishlist not found")
+	}
+
+	return nil
+}